@@ -0,0 +1,62 @@
+// Command meshbake imports every model assimp loads under a directory and writes
+// it to the binary mesh cache, so the application never runs assimp at startup.
+// Models whose cache entry matches their source files are skipped.
+//
+// Run it from the repository root, the same directory the application runs from:
+//
+//	go run ./cmd/meshbake -dir data/models
+package main
+
+import (
+	"antry/graphic"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+var sModelDir = flag.String("dir", filepath.Join("data", "models"), "directory searched for models")
+var sCacheDir = flag.String("cache", filepath.Join("cache", "meshes"), "mesh cache directory")
+var bForce = flag.Bool("force", false, "rebuild entries that are up to date")
+
+func main() {
+	flag.Parse()
+	graphic.SetMeshCacheDirectory(*sCacheDir)
+
+	var vModels []string
+	err := filepath.WalkDir(*sModelDir, func(sPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && graphic.IsMeshCacheable(sPath) {
+			// Cache entries are keyed by slash path, the way the application names models
+			vModels = append(vModels, filepath.ToSlash(sPath))
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "meshbake:", err)
+		os.Exit(2)
+	}
+
+	var iBaked, iUpToDate, iErrors int
+	for _, sModel := range vModels {
+		bBaked, err := graphic.BakeMeshCache(sModel, *bForce)
+		switch {
+		case err != nil:
+			fmt.Printf("%s: error: %v\n", sModel, err)
+			iErrors++
+		case bBaked:
+			fmt.Printf("%s: baked\n", sModel)
+			iBaked++
+		default:
+			fmt.Printf("%s: up to date\n", sModel)
+			iUpToDate++
+		}
+	}
+	fmt.Printf("%d model(s) baked, %d up to date, %d error(s)\n", iBaked, iUpToDate, iErrors)
+	if iErrors > 0 {
+		os.Exit(1)
+	}
+}
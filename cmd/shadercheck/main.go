@@ -0,0 +1,283 @@
+// Command shadercheck compiles and links every shader program of the application
+// in a hidden OpenGL context and reports problems as file:line diagnostics.
+//
+// Run it from the repository root, e.g. with Mesa's software rasterizer:
+//
+//	go run ./cmd/shadercheck -software
+package main
+
+import (
+	"antry/graphic"
+	"flag"
+	"fmt"
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/veandco/go-sdl2/sdl"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var sShaderDir = flag.String("dir", filepath.Join("data", "shaders"), "directory with shader sources")
+var bSoftware = flag.Bool("software", false, "force Mesa software rasterizer (llvmpipe)")
+var iMajorVersion = flag.Int("major", 3, "OpenGL context major version")
+var iMinorVersion = flag.Int("minor", 3, "OpenGL context minor version")
+
+var iErrors, iWarnings int
+
+// Lines of compiler logs look differently per vendor, e.g. "0:12(5): error: ..." (Mesa),
+// "0(12) : error C0000: ..." (NVIDIA) or "ERROR: 0:12: ..." (AMD, Intel)
+var reLogLine = regexp.MustCompile(`^(?:(ERROR|WARNING): )?\d+[:(](\d+)\)?(?:\(\d+\))?\s*:?\s*(.*)$`)
+
+// Matches in/out variable declarations, that's what we compare between stages
+var reVarying = regexp.MustCompile(`^\s*((?:(?:smooth|flat|noperspective|centroid|sample|layout\s*\([^)]*\))\s+)*)(in|out)\s+(\w+)\s+(\w+)\s*(\[[^\]]*\])?\s*;`)
+
+// Stages in pipeline order, varyings are checked between neighbours
+var vStageOrder = []uint32{gl.VERTEX_SHADER, gl.TESS_CONTROL_SHADER, gl.TESS_EVALUATION_SHADER, gl.GEOMETRY_SHADER, gl.FRAGMENT_SHADER}
+
+func report(sSeverity string, loc graphic.CSourceLocation, sMessage string) {
+	if sSeverity == "error" {
+		iErrors++
+	} else {
+		iWarnings++
+	}
+	fmt.Printf("%s:%d: %s: %s\n", filepath.ToSlash(loc.File), loc.Line, sSeverity, sMessage)
+}
+
+// reportLog translates compiler log of given shader to diagnostics in original files.
+func reportLog(shShader *graphic.CShader, bFailed bool) {
+	for _, sLine := range strings.Split(shShader.GetInfoLog(), "\n") {
+		sLine = strings.TrimSpace(sLine)
+		if sLine == "" {
+			continue
+		}
+		var sSeverity string = "warning"
+		if bFailed {
+			sSeverity = "error"
+		}
+		vMatch := reLogLine.FindStringSubmatch(sLine)
+		if vMatch == nil {
+			report(sSeverity, graphic.CSourceLocation{File: shShader.GetFile()}, sLine)
+			continue
+		}
+		iLine, _ := strconv.Atoi(vMatch[2])
+		sMessage := vMatch[3]
+		if vMatch[1] == "ERROR" || strings.Contains(strings.ToLower(sMessage), "error") {
+			sSeverity = "error"
+		} else if vMatch[1] == "WARNING" {
+			sSeverity = "warning"
+		}
+		report(sSeverity, shShader.GetSourceLocation(iLine), sMessage)
+	}
+}
+
+type varying struct {
+	sType  string
+	sArray string
+	loc    graphic.CSourceLocation
+}
+
+// getVaryings collects variables with given storage qualifier ("in" or "out") declared by the shader.
+func getVaryings(shShader *graphic.CShader, sQualifier string) map[string]varying {
+	mResult := make(map[string]varying)
+	for i, sLine := range strings.Split(shShader.GetSource(), "\n") {
+		vMatch := reVarying.FindStringSubmatch(sLine)
+		if vMatch == nil || vMatch[2] != sQualifier {
+			continue
+		}
+		mResult[vMatch[4]] = varying{sType: vMatch[3], sArray: vMatch[5], loc: shShader.GetSourceLocation(i + 1)}
+	}
+	return mResult
+}
+
+// checkVaryings compares outputs of one stage with inputs of the next one.
+func checkVaryings(sProgram string, shProducer, shConsumer *graphic.CShader) {
+	mOut := getVaryings(shProducer, "out")
+	mIn := getVaryings(shConsumer, "in")
+	// Tessellation and geometry shaders take inputs as arrays, one element per vertex,
+	// and tessellation control shader also writes its outputs per vertex
+	var bArrayed bool = shConsumer.GetShaderType() != gl.FRAGMENT_SHADER || shProducer.GetShaderType() == gl.TESS_CONTROL_SHADER
+
+	vNames := make([]string, 0, len(mIn))
+	for sName := range mIn {
+		vNames = append(vNames, sName)
+	}
+	sort.Strings(vNames)
+	for _, sName := range vNames {
+		vIn := mIn[sName]
+		vOut, bFound := mOut[sName]
+		if !bFound {
+			report("error", vIn.loc, fmt.Sprintf("program %s: input %s %s is not written by %s", sProgram, vIn.sType, sName, filepath.Base(shProducer.GetFile())))
+			continue
+		}
+		if vIn.sType != vOut.sType || (!bArrayed && vIn.sArray != vOut.sArray) {
+			report("error", vIn.loc, fmt.Sprintf("program %s: input %s %s%s doesn't match output %s %s%s at %s:%d", sProgram,
+				vIn.sType, sName, vIn.sArray, vOut.sType, sName, vOut.sArray, filepath.ToSlash(vOut.loc.File), vOut.loc.Line))
+		}
+	}
+	for sName, vOut := range mOut {
+		if _, bFound := mIn[sName]; !bFound {
+			report("warning", vOut.loc, fmt.Sprintf("program %s: output %s is never read by %s", sProgram, sName, filepath.Base(shConsumer.GetFile())))
+		}
+	}
+}
+
+func isShaderFile(sFile string) bool {
+	switch filepath.Ext(sFile) {
+	case ".vert", ".tesc", ".tese", ".geom", ".frag", ".comp":
+		return true
+	}
+	return false
+}
+
+// getShaderKey identifies compiled shader by file and defines, so variants of one file don't clash
+func getShaderKey(sFile string, vDefines []string) string {
+	if len(vDefines) == 0 {
+		return sFile
+	}
+	return sFile + " [" + strings.Join(vDefines, ", ") + "]"
+}
+
+// compileShader compiles shader file with given defines and reports its problems, result goes to mShaders or mSkipped.
+func compileShader(sFile string, vDefines []string, mShaders map[string]*graphic.CShader, mSkipped map[string]bool) {
+	var sPath string = filepath.Join(*sShaderDir, sFile)
+	shShader := graphic.NewCShader()
+	if !shShader.ReadShaderWithDefines(sPath, graphic.GetShaderTypeFromFile(sPath), vDefines) {
+		report("error", graphic.CSourceLocation{File: sPath}, "couldn't read shader or its includes")
+		return
+	}
+	// Stages the context can't compile are skipped, not failed, so the tool stays usable on older drivers
+	if err := graphic.GetGLCapabilities().CheckShaderStage(shShader.GetShaderType()); err != nil {
+		report("warning", graphic.CSourceLocation{File: sPath, Line: 1}, "skipped: "+err.Error())
+		mSkipped[sFile] = true
+		return
+	}
+	var bCompiled bool = shShader.TryCompileShader()
+	reportLog(shShader, !bCompiled)
+	mShaders[getShaderKey(sFile, vDefines)] = shShader
+}
+
+func createHiddenContext() (window *sdl.Window, context sdl.GLContext, err error) {
+	if *bSoftware {
+		os.Setenv("LIBGL_ALWAYS_SOFTWARE", "1")
+		os.Setenv("GALLIUM_DRIVER", "llvmpipe")
+	}
+	if err = sdl.Init(sdl.INIT_VIDEO); err != nil {
+		return
+	}
+	sdl.GLSetAttribute(sdl.GL_CONTEXT_MAJOR_VERSION, *iMajorVersion)
+	sdl.GLSetAttribute(sdl.GL_CONTEXT_MINOR_VERSION, *iMinorVersion)
+	sdl.GLSetAttribute(sdl.GL_CONTEXT_PROFILE_MASK, sdl.GL_CONTEXT_PROFILE_CORE)
+	window, err = sdl.CreateWindow("shadercheck", 0, 0, 16, 16, sdl.WINDOW_OPENGL|sdl.WINDOW_HIDDEN)
+	if err != nil {
+		return
+	}
+	context, err = window.GLCreateContext()
+	if err != nil {
+		window.Destroy()
+		return
+	}
+	if err = gl.Init(); err != nil {
+		sdl.GLDeleteContext(context)
+		window.Destroy()
+	}
+	return
+}
+
+func main() {
+	flag.Parse()
+
+	window, context, err := createHiddenContext()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "shadercheck: couldn't create OpenGL context:", err)
+		os.Exit(2)
+	}
+	defer sdl.Quit()
+	defer window.Destroy()
+	defer sdl.GLDeleteContext(context)
+	fmt.Printf("Using %s, OpenGL %s\n", gl.GoStr(gl.GetString(gl.RENDERER)), gl.GoStr(gl.GetString(gl.VERSION)))
+
+	vEntries, err := os.ReadDir(*sShaderDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "shadercheck:", err)
+		os.Exit(2)
+	}
+
+	// Compile every shader file on its own first, so that even files no program uses get checked
+	mShaders := make(map[string]*graphic.CShader)
+	mSkipped := make(map[string]bool)
+	for _, entry := range vEntries {
+		if entry.IsDir() || !isShaderFile(entry.Name()) {
+			continue
+		}
+		compileShader(entry.Name(), nil, mShaders, mSkipped)
+	}
+
+	for _, desc := range graphic.GetShaderProgramDescs() {
+		var vShaders []*graphic.CShader
+		var bComplete bool = true
+		for _, sFile := range desc.Files {
+			var sKey string = getShaderKey(sFile, desc.Defines)
+			if _, bFound := mShaders[sKey]; !bFound && len(desc.Defines) > 0 && !mSkipped[sFile] {
+				// Variants are compiled once programs ask for them, plain files were compiled above
+				if _, bFound := mShaders[sFile]; bFound {
+					compileShader(sFile, desc.Defines, mShaders, mSkipped)
+				}
+			}
+			if mSkipped[sFile] {
+				bComplete = false
+				continue
+			}
+			shShader, bFound := mShaders[sKey]
+			if !bFound {
+				report("error", graphic.CSourceLocation{File: filepath.Join(*sShaderDir, sFile)}, "program "+desc.Name+" uses missing shader")
+				bComplete = false
+				continue
+			}
+			if !shShader.IsLoaded() {
+				bComplete = false // Already reported by compilation
+			}
+			vShaders = append(vShaders, shShader)
+		}
+		if !bComplete {
+			continue
+		}
+
+		spProgram := graphic.NewCShaderProgram()
+		spProgram.CreateProgram()
+		for _, shShader := range vShaders {
+			spProgram.AddShaderToProgram(shShader)
+		}
+		if !spProgram.TryLinkProgram() {
+			for _, sLine := range strings.Split(strings.TrimSpace(spProgram.GetInfoLog()), "\n") {
+				report("error", graphic.CSourceLocation{File: filepath.Join(*sShaderDir, desc.Files[0])}, "program "+desc.Name+": "+sLine)
+			}
+		}
+		gl.DeleteProgram(spProgram.GetProgramID())
+
+		// The main stage of each kind is the first file of it, other files of same kind are libraries linked in
+		var shPrev *graphic.CShader
+		for _, iStage := range vStageOrder {
+			for _, shShader := range vShaders {
+				if shShader.GetShaderType() == iStage {
+					if shPrev != nil {
+						checkVaryings(desc.Name, shPrev, shShader)
+					}
+					shPrev = shShader
+					break
+				}
+			}
+		}
+	}
+
+	for _, shShader := range mShaders {
+		shShader.DeleteShader()
+	}
+
+	fmt.Printf("%d error(s), %d warning(s)\n", iErrors, iWarnings)
+	if iErrors > 0 {
+		os.Exit(1)
+	}
+}
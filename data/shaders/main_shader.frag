@@ -4,11 +4,34 @@ smooth in vec2 vTexCoord;
 smooth in vec3 vNormal;
 smooth in vec4 vEyeSpacePos;
 smooth in vec3 vWorldPos;
+smooth in vec3 vTangent;
+smooth in vec3 vBitangent;
 out vec4 outputColor;
 
 uniform sampler2D gSampler;
 uniform vec4 vColor;
 
+// Optional maps of model materials, units match texture slots of models
+uniform sampler2D normalSampler;
+uniform sampler2D specularSampler;
+uniform sampler2D emissiveSampler;
+uniform bool bNormalMap;
+uniform bool bSpecularMap;
+uniform bool bEmissiveMap;
+
+uniform vec3 vEyePosition;
+
+// Colours multiply textures of their slots, diffuse alpha is opacity
+uniform struct Material
+{
+	vec4 vDiffuseColor;
+	vec3 vSpecularColor;
+	vec3 vEmissiveColor;
+	float fShininess;
+	bool bAlphaTest;
+	float fAlphaCutoff;
+} material;
+
 #include "dirLight.frag"
 
 uniform DirectionalLight sunLight;
@@ -16,11 +39,37 @@ uniform DirectionalLight sunLight;
 void main()
 {
 	vec3 vNormalized = normalize(vNormal);
-	
+
+	// Tangent-space normal map, vertices without tangents keep their normal
+	if(bNormalMap && dot(vTangent, vTangent) > 0.0 && dot(vBitangent, vBitangent) > 0.0)
+	{
+		mat3 mTBN = mat3(normalize(vTangent), normalize(vBitangent), vNormalized);
+		vec3 vMapNormal = texture2D(normalSampler, vTexCoord).xyz*2.0-1.0;
+		vNormalized = normalize(mTBN*vMapNormal);
+	}
+
 	vec4 vTexColor = texture2D(gSampler, vTexCoord);
 
-	vec4 vMixedColor = vTexColor*vColor;
-   vec4 vDirLightColor = GetDirectionalLightColor(sunLight, vNormalized);
+	vec4 vMixedColor = vTexColor*vColor*material.vDiffuseColor;
+	if(material.bAlphaTest && vMixedColor.a < material.fAlphaCutoff)
+		discard;
+	vec4 vDirLightColor = GetDirectionalLightColor(sunLight, vNormalized);
 
 	outputColor = vMixedColor*vDirLightColor;
-}
\ No newline at end of file
+
+	// Blinn-Phong highlight of sun, specular map scales it by its red channel
+	vec3 vSpecular = material.vSpecularColor;
+	if(bSpecularMap)
+		vSpecular *= texture2D(specularSampler, vTexCoord).r;
+	if(dot(vSpecular, vSpecular) > 0.0 && dot(vNormalized, -sunLight.vDirection) > 0.0)
+	{
+		vec3 vHalf = normalize(normalize(vEyePosition-vWorldPos)-sunLight.vDirection);
+		float fHighlight = pow(max(0.0, dot(vNormalized, vHalf)), max(material.fShininess, 1.0));
+		outputColor.rgb += sunLight.vColor*vSpecular*fHighlight;
+	}
+
+	vec3 vEmissive = material.vEmissiveColor;
+	if(bEmissiveMap)
+		vEmissive *= texture2D(emissiveSampler, vTexCoord).rgb;
+	outputColor.rgb += vEmissive;
+}
@@ -11,10 +11,22 @@ uniform struct Matrices
 layout (location = 0) in vec3 inPosition;
 layout (location = 1) in vec2 inCoord;
 layout (location = 2) in vec3 inNormal;
+layout (location = 3) in vec3 inTangent;
+layout (location = 4) in vec3 inBitangent;
+layout (location = 5) in uvec4 inJoints;
+layout (location = 6) in vec4 inWeights;
+
+// Must match MAX_SKINNING_BONES of models
+#define MAX_BONES 64
+
+uniform bool bSkinned;
+uniform mat4 mBones[MAX_BONES];
 
 smooth out vec3 vNormal;
 smooth out vec2 vTexCoord;
 smooth out vec3 vWorldPos;
+smooth out vec3 vTangent;
+smooth out vec3 vBitangent;
 
 smooth out vec4 vEyeSpacePos;
 
@@ -25,9 +37,27 @@ void main()
   
   vTexCoord = inCoord;
 
-  vEyeSpacePos = mMV*vec4(inPosition, 1.0);
-	gl_Position = mMVP*vec4(inPosition, 1.0);
+  // Skinned vertex is moved by up to four bones, vertex without weights stays in bind pose
+  vec4 vPosition = vec4(inPosition, 1.0);
+  vec3 vSkinnedNormal = inNormal;
+  vec3 vSkinnedTangent = inTangent;
+  vec3 vSkinnedBitangent = inBitangent;
+  if(bSkinned && dot(inWeights, vec4(1.0)) > 0.0)
+  {
+    mat4 mSkin = inWeights.x*mBones[inJoints.x] + inWeights.y*mBones[inJoints.y] +
+      inWeights.z*mBones[inJoints.z] + inWeights.w*mBones[inJoints.w];
+    vPosition = mSkin*vPosition;
+    vSkinnedNormal = (mSkin*vec4(inNormal, 0.0)).xyz;
+    vSkinnedTangent = (mSkin*vec4(inTangent, 0.0)).xyz;
+    vSkinnedBitangent = (mSkin*vec4(inBitangent, 0.0)).xyz;
+  }
+
+  vEyeSpacePos = mMV*vPosition;
+	gl_Position = mMVP*vPosition;
 
-  vNormal = (matrices.normalMatrix*vec4(inNormal, 1.0)).xyz;
-  vWorldPos = (matrices.modelMatrix*vec4(inPosition, 1.0)).xyz;
+  vNormal = (matrices.normalMatrix*vec4(vSkinnedNormal, 1.0)).xyz;
+  vWorldPos = (matrices.modelMatrix*vPosition).xyz;
+  // Zero tangents stay zero, fragment shader doesn't normal map them
+  vTangent = (matrices.modelMatrix*vec4(vSkinnedTangent, 0.0)).xyz;
+  vBitangent = (matrices.modelMatrix*vec4(vSkinnedBitangent, 0.0)).xyz;
 }
\ No newline at end of file
@@ -0,0 +1,17 @@
+#version 330
+
+smooth in vec3 vDirection;
+
+uniform samplerCube gCubemap;
+uniform vec4 vColor;
+
+#include "dirLight.frag"
+uniform DirectionalLight sunLight;
+
+out vec4 outputColor;
+
+void main()
+{
+	vec3 vNormal = -normalize(vDirection); // Sky is lit from inside, as the old skybox quads were
+	outputColor = texture(gCubemap, vDirection)*vColor*GetDirectionalLightColor(sunLight, vNormal);
+}
@@ -0,0 +1,15 @@
+#version 330
+
+uniform mat4 mViewProjection; // View matrix without translation, so the sky never gets closer
+
+layout (location = 0) in vec3 inPosition;
+
+smooth out vec3 vDirection;
+
+void main()
+{
+	vDirection = inPosition;
+	vec4 vPosition = mViewProjection*vec4(inPosition, 1.0);
+	// z = w puts the sky at far plane depth after perspective division, whatever the cube size is
+	gl_Position = vPosition.xyww;
+}
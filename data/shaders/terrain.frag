@@ -5,7 +5,16 @@ smooth in vec3 vNormal;
 smooth in vec3 vWorldPos;
 smooth in vec4 vEyeSpacePos;
 
+#ifdef TERRAIN_TEXTURE_ARRAY
+#define MAX_TERRAIN_LAYERS 32
+uniform sampler2DArray gLayers; // Height layers from lowest to highest, path texture can be one of them too
+uniform sampler2D gPathMap; // Where path should be and how intense
+uniform int iNumLayers;
+uniform int iPathLayer;
+uniform vec2 vLayerRanges[MAX_TERRAIN_LAYERS]; // Relative heights where layer is used alone, neighbours are blended between
+#else
 uniform sampler2D gSampler[5];
+#endif
 uniform sampler2D shadowMap;
 
 uniform vec4 vColor;
@@ -26,6 +35,31 @@ void main()
 	
 	float fScale = vWorldPos.y/fRenderHeight;
 
+#ifdef TERRAIN_TEXTURE_ARRAY
+	// Find layer and blend factor first, so that textures are sampled outside of non-uniform control flow
+	int iLayer = iNumLayers-1;
+	float fBlend = 0.0;
+	for(int i = 0; i < iNumLayers-1; i++)
+	{
+		if(fScale <= vLayerRanges[i].y)
+		{
+			iLayer = i;
+			break;
+		}
+		if(fScale < vLayerRanges[i+1].x)
+		{
+			iLayer = i;
+			fBlend = (fScale-vLayerRanges[i].y)/(vLayerRanges[i+1].x-vLayerRanges[i].y);
+			break;
+		}
+	}
+	vTexColor = mix(texture(gLayers, vec3(vTexCoord, iLayer)), texture(gLayers, vec3(vTexCoord, min(iLayer+1, iNumLayers-1))), fBlend);
+
+	vec2 vPathCoord = vec2(vTexCoord.x/fMaxTextureU, vTexCoord.y/fMaxTextureV);
+	fScale = texture(gPathMap, vPathCoord).x;
+
+	vec4 vPathColor = texture(gLayers, vec3(vTexCoord, iPathLayer)); // Black color means there is a path
+#else
 	const float fRange1 = 0.15f;
 	const float fRange2 = 0.3f;
 	const float fRange3 = 0.65f;
@@ -62,6 +96,7 @@ void main()
 	fScale = vPathIntensity.x;
   
 	vec4 vPathColor = texture2D(gSampler[3], vTexCoord); // Black color means there is a path
+#endif
 	vec4 vFinalTexColor = fScale*vTexColor+(1-fScale)*vPathColor;
 
 	vec4 vMixedColor = vFinalTexColor*vColor;
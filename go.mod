@@ -14,3 +14,6 @@ require (
 	github.com/veandco/go-sdl2 v0.5.0-alpha.6
 	golang.org/x/image v0.0.0-20190321063152-3fc05d484e9f
 )
+
+// Patched copy exporting material property keys and parsing animations, see third_party/assimp-go/PATCHES.md
+replace github.com/bloeys/assimp-go => ./third_party/assimp-go
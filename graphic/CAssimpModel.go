@@ -1,26 +1,51 @@
 package graphic
 
 import (
+	"antry/libs"
+	"fmt"
 	"github.com/bloeys/assimp-go/asig"
 	"github.com/bloeys/gglm/gglm"
 	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"path"
+	"path/filepath"
+	"strings"
 	"unsafe"
 )
 
-type CMaterial struct {
-	iTexture int
-}
-
-var vboModelData CVertexBufferObject
-var uiVAO uint32
-var CAtTextures []CTexture
-
 type CAssimpModel struct {
-	bLoaded           bool
-	iMeshStartIndices []int32
-	iMeshSizes        []int32
-	iMaterialIndices  []uint
-	iNumMaterials     int
+	bLoaded            bool
+	uiVAO              uint32
+	vboVertices        CVertexBufferObject
+	vboSkin            CVertexBufferObject // Joints and weights of each vertex, only skinned models have it
+	vboIndices         CVertexBufferObject
+	iMeshStartIndices  []int32 // First index of each mesh
+	iMeshSizes         []int32 // Number of indices of each mesh
+	iMeshFirstVertices []int32
+	iMeshNumVertices   []int32
+	iMaterialIndices   []uint
+	iNumMaterials      int
+	vTextures          [][NUM_TEXTURE_SLOTS]*CTexture // Textures of each material from texture manager, nil for empty slots
+	vMaterials         []CMaterial                    // Materials meshes render with, code may change them
+	vImportedMaterials []CMaterial                    // Materials as loaded, ResetMaterial goes back to them
+	vPBRMaterials      []SPBRMaterial
+	vMeshBoxes         []libs.AABB // Bounds of each mesh in bind pose, in model's space
+	vMeshSpheres       []libs.Sphere
+	boxModel           libs.AABB
+	sphereModel        libs.Sphere
+	bMeshVisible       []bool // Meshes culling left to draw, reused each frame
+
+	// Skeletal animation, skeleton is nil for static models
+	skeleton       *libs.Skeleton
+	vSkins         []libs.Skin
+	vAnimations    []*libs.AnimationClip
+	iMeshSkins     []int // Skin of each mesh, -1 for meshes that aren't skinned
+	apPlayer       *libs.AnimationPlayer
+	vJointMatrices [][]mgl32.Mat4 // Skinning matrices of each skin for the current pose
+	bBindVertices  []byte         // Vertices in bind pose, CPU skinning moves them
+	bSkinData      []byte
+	bSkinned       []byte // Vertices CPU skinning uploaded last
+	bCPUSkinning   bool
 }
 
 func GetDirectoryPath(sFilePath string) string {
@@ -36,178 +61,501 @@ func GetDirectoryPath(sFilePath string) string {
 	return sDirectory
 }
 
+// getModelTexturePath resolves texture name from material against model's directory
+func getModelTexturePath(sModelPath, sTextureName string) string {
+	sTextureName = strings.ReplaceAll(sTextureName, "\\", "/")
+	if filepath.IsAbs(sModelPath) {
+		return filepath.Join(filepath.Dir(sModelPath), filepath.FromSlash(sTextureName))
+	}
+	return libs.CleanAssetPath(path.Join(path.Dir(libs.CleanAssetPath(sModelPath)), sTextureName))
+}
+
 func NewCAssimpModel() *CAssimpModel {
 	this := CAssimpModel{}
 	this.bLoaded = false
 	return &this
 }
 
-func (this *CAssimpModel) LoadModelFromFile(sFilePath string) bool {
-	if vboModelData.GetBufferID() == 0 {
-		vboModelData.CreateVBO(0)
-		CAtTextures = make([]CTexture, 50)
+/*-----------------------------------------------
+
+  Name:	CModelData
+
+  Result:	Model imported on CPU, waiting to be put
+  		into models' VBO. It doesn't touch OpenGL,
+  		so it can be built on any goroutine.
+
+  /*---------------------------------------------*/
+
+type CModelData struct {
+	sPath              string
+	bVertexData        []byte   // Position, texture coordinate, normal, tangent and bitangent of each vertex
+	vIndices           []uint32 // Triangle lists of all meshes, pointing into bVertexData
+	iMeshStartIndices  []int32  // First index of each mesh
+	iMeshSizes         []int32  // Number of indices of each mesh
+	iMeshFirstVertices []int32  // Vertices each mesh uses
+	iMeshNumVertices   []int32
+	iMaterialIndices   []uint
+	vMaterials         []CMaterial                            // Material of each index, without textures
+	vTexturePaths      [][NUM_TEXTURE_SLOTS]string            // Textures of each material, empty for empty slots
+	vTextureData       [][NUM_TEXTURE_SLOTS]*libs.TextureData // Decoded textures, nil if they weren't decoded in advance
+	vPBRMaterials      []SPBRMaterial                         // Metallic-roughness parameters, only glTF models have them
+	vMeshBoxes         []libs.AABB                            // Bounds of each mesh in bind pose
+	vMeshSpheres       []libs.Sphere
+	boxModel           libs.AABB
+	sphereModel        libs.Sphere
+
+	skeleton    *libs.Skeleton // Nil if model has no skins
+	vSkins      []libs.Skin
+	vAnimations []*libs.AnimationClip
+	iMeshSkins  []int  // Skin of each mesh, -1 for meshes that aren't skinned
+	bSkinData   []byte // Joints and weights of each vertex, nil if no mesh is skinned
+}
+
+func (this *CModelData) GetPath() string {
+	return this.sPath
+}
+
+/*-----------------------------------------------
+
+  Name:	ImportModelData
+
+  Params:	sFilePath - model file
+
+  Result:	Imports model through assimp, or glTF
+  		loader for .gltf and .glb, and builds its
+  		vertex data, without OpenGL calls. Assimp
+  		models come from mesh cache when it has
+  		them and are cached after import.
+
+  /*---------------------------------------------*/
+
+func ImportModelData(sFilePath string) (*CModelData, error) {
+	if libs.IsGLTFFile(sFilePath) {
+		return ImportGLTFModelData(sFilePath)
 	}
-	scene, release, err := asig.ImportFile(sFilePath, asig.PostProcessCalcTangentSpace|
+	return mcMeshCache.ImportModelData(sFilePath)
+}
+
+// ImportAssimpModelData imports model through assimp, mesh cache isn't used.
+func ImportAssimpModelData(sFilePath string) (*CModelData, error) {
+	// Assimp reads only real files, models from archives are extracted first
+	var sLocalPath string = sFilePath
+	if !filepath.IsAbs(sFilePath) {
+		sExtracted, fnCleanup, err := libs.GetAssetVFS().ExtractLocal(sFilePath)
+		if err != nil {
+			return nil, err
+		}
+		defer fnCleanup()
+		sLocalPath = sExtracted
+	}
+	scene, release, err := asig.ImportFile(sLocalPath, asig.PostProcessCalcTangentSpace|
 		asig.PostProcessTriangulate|asig.PostProcessJoinIdenticalVertices|
-		asig.PostProcessSortByPType)
+		asig.PostProcessSortByPType|asig.PostProcessLimitBoneWeights)
 	if err != nil {
-		panic(err)
-		//buttons := []sdl.MessageBoxButtonData{
-		//	{0, 0, "no"},
-		//	{sdl.MESSAGEBOX_BUTTON_RETURNKEY_DEFAULT, 1, "yes"},
-		//	{sdl.MESSAGEBOX_BUTTON_ESCAPEKEY_DEFAULT, 2, "cancel"},
-		//}
-		//
-		//colorScheme := sdl.MessageBoxColorScheme{
-		//	Colors: [5]sdl.MessageBoxColor{
-		//		sdl.MessageBoxColor{255, 0, 0},
-		//		sdl.MessageBoxColor{0, 255, 0},
-		//		sdl.MessageBoxColor{255, 255, 0},
-		//		sdl.MessageBoxColor{0, 0, 255},
-		//		sdl.MessageBoxColor{255, 0, 255},
-		//	},
-		//}
-		//sdl.ShowMessageBox(&sdl.MessageBoxData{
-		//	Flags:       sdl.MESSAGEBOX_INFORMATION,
-		//	Window:      nil,
-		//	Title:       "Couldn't load model ",
-		//	Message:     "Error Importing Asset",
-		//	Buttons:     buttons,
-		//	ColorScheme: &colorScheme}) //AppMain.hWnd, "Couldn't load model ", "Error Importing Asset", MB_ICONERROR
-		return false
+		return nil, err
 	}
 	defer release()
-	const iVertexTotalSize int32 = int32(unsafe.Sizeof(gglm.Vec3{})*2 + unsafe.Sizeof(gglm.Vec2{}))
-	var iTotalVertices int = 0
+	data := &CModelData{sPath: sFilePath}
+	for _, mesh := range scene.Meshes {
+		if len(mesh.Bones) > 0 {
+			if data.skeleton, err = importAssimpSkeleton(scene.RootNode); err != nil {
+				return nil, err
+			}
+			data.vAnimations = importAssimpAnimations(scene.Animations, data.skeleton)
+			break
+		}
+	}
+	var vboData CVertexBufferObject
 	for i := 0; i < len(scene.Meshes); i++ {
 		var mesh *asig.Mesh = scene.Meshes[i]
 		var iMeshFaces int = len(mesh.Faces)
-		this.iMaterialIndices = append(this.iMaterialIndices, mesh.MaterialIndex)
-		var iSizeBefore int32 = vboModelData.GetCurrentSize()
-		this.iMeshStartIndices = append(this.iMeshStartIndices, iSizeBefore/iVertexTotalSize)
+		data.iMaterialIndices = append(data.iMaterialIndices, mesh.MaterialIndex)
+		// Bones of mesh become its skin, every vertex gets up to four of them
+		var vVertexSkins []libs.VertexSkin
+		data.iMeshSkins = append(data.iMeshSkins, -1)
+		if len(mesh.Bones) > 0 {
+			var skin libs.Skin
+			skin, vVertexSkins = importAssimpSkin(mesh, data.skeleton)
+			data.iMeshSkins[i] = len(data.vSkins)
+			data.vSkins = append(data.vSkins, skin)
+		}
+		var iBaseVertex int32 = vboData.GetCurrentSize() / MODEL_VERTEX_SIZE
+		data.iMeshFirstVertices = append(data.iMeshFirstVertices, iBaseVertex)
+		data.iMeshNumVertices = append(data.iMeshNumVertices, int32(len(mesh.Vertices)))
+		for j := range mesh.Vertices {
+			// Renderer reads two texture coordinates, assimp gives three
+			var uv gglm.Vec2
+			if len(mesh.TexCoords[0]) > 0 {
+				uv.Data = [2]float32{mesh.TexCoords[0][j].Data[0], mesh.TexCoords[0][j].Data[1]}
+			}
+			var normal *gglm.Vec3
+			if len(mesh.Normals) > 0 {
+				normal = &mesh.Normals[j]
+			} else {
+				normal = gglm.NewVec3(1.0, 1.0, 1.0)
+			}
+			// Tangent space from PostProcessCalcTangentSpace, zeros for meshes without texture coordinates
+			var tangent, bitangent gglm.Vec3
+			if len(mesh.Tangents) > 0 && len(mesh.BitTangents) > 0 {
+				tangent, bitangent = mesh.Tangents[j], mesh.BitTangents[j]
+			}
+			vboData.AddData(EncodeToBytes(mesh.Vertices[j]), int32(unsafe.Sizeof(gglm.Vec3{})))
+			vboData.AddData(EncodeToBytes(uv), int32(unsafe.Sizeof(gglm.Vec2{})))
+			vboData.AddData(EncodeToBytes(normal), int32(unsafe.Sizeof(gglm.Vec3{})))
+			vboData.AddData(EncodeToBytes(tangent), int32(unsafe.Sizeof(gglm.Vec3{})))
+			vboData.AddData(EncodeToBytes(bitangent), int32(unsafe.Sizeof(gglm.Vec3{})))
+			if data.skeleton != nil {
+				var vertexSkin libs.VertexSkin
+				if vVertexSkins != nil {
+					vertexSkin = vVertexSkins[j]
+				}
+				data.bSkinData = appendVertexSkin(data.bSkinData, &vertexSkin)
+			}
+		}
+		// Indices point into model's vertex buffer, points and lines are left out
+		var iFirstIndex int32 = int32(len(data.vIndices))
+		data.iMeshStartIndices = append(data.iMeshStartIndices, iFirstIndex)
 		for j := 0; j < iMeshFaces; j++ {
 			var face *asig.Face = &mesh.Faces[j]
+			if len(face.Indices) != 3 {
+				continue
+			}
 			for k := 0; k < 3; k++ {
-				var pos gglm.Vec3 = mesh.Vertices[face.Indices[k]]
-				var uv gglm.Vec3 = mesh.TexCoords[0][face.Indices[k]]
-				var normal *gglm.Vec3
-				if len(mesh.Normals) > 0 {
-					normal = &mesh.Normals[face.Indices[k]]
-				} else {
-					normal = gglm.NewVec3(1.0, 1.0, 1.0)
-				}
-				vboModelData.AddData(EncodeToBytes(pos), int32(unsafe.Sizeof(gglm.Vec3{})))
-				vboModelData.AddData(EncodeToBytes(uv), int32(unsafe.Sizeof(gglm.Vec3{})))
-				vboModelData.AddData(EncodeToBytes(normal), int32(unsafe.Sizeof(gglm.Vec3{})))
+				data.vIndices = append(data.vIndices, uint32(iBaseVertex)+uint32(face.Indices[k]))
 			}
 		}
-		var iMeshVertices int = len(mesh.Vertices)
-		iTotalVertices += iMeshVertices
-		this.iMeshSizes = append(this.iMeshSizes, (vboModelData.GetCurrentSize()-iSizeBefore)/iVertexTotalSize)
+		data.iMeshSizes = append(data.iMeshSizes, int32(len(data.vIndices))-iFirstIndex)
 	}
+	data.bVertexData = vboData.data
+	data.computeBounds()
 
-	this.iNumMaterials = len(scene.Materials)
-
-	materialRemap := make([]uint, this.iNumMaterials)
-
-	for i := 0; i < this.iNumMaterials; i++ {
+	data.vTexturePaths = make([][NUM_TEXTURE_SLOTS]string, len(scene.Materials))
+	data.vTextureData = make([][NUM_TEXTURE_SLOTS]*libs.TextureData, len(scene.Materials))
+	var bWavefront bool = strings.EqualFold(filepath.Ext(sFilePath), ".obj")
+	for i := 0; i < len(scene.Materials); i++ {
 		var material *asig.Material = scene.Materials[i]
 		var texIndex uint = 0
-		info, err := asig.GetMaterialTexture(material, asig.TextureTypeDiffuse, texIndex)
-		if err == nil {
-			var sDir string = GetDirectoryPath(sFilePath)
+		data.vMaterials = append(data.vMaterials, importAssimpMaterial(material, bWavefront))
+		for iSlot, textureType := range assimpTextureTypes {
+			info, err := asig.GetMaterialTexture(material, textureType, texIndex)
+			if err == nil {
+				data.vTexturePaths[i][iSlot] = getModelTexturePath(sFilePath, info.Path)
+			}
+		}
+	}
+	return data, nil
+}
 
-			var sTextureName string = info.Path
+// DecodeTextures decodes all textures of model, so uploading them doesn't read files. Textures that fail stay nil.
+func (this *CModelData) DecodeTextures() {
+	for i := range this.vTexturePaths {
+		for iSlot, sPath := range this.vTexturePaths[i] {
+			if sPath == "" || this.vTextureData[i][iSlot] != nil {
+				continue
+			}
+			texData, err := DecodeTextureFile(sPath)
+			if err != nil {
+				fmt.Println("Couldn't decode texture", sPath+":", err)
+				continue
+			}
+			this.vTextureData[i][iSlot] = texData
+		}
+	}
+}
 
-			var sFullPath string = sDir + sTextureName
+/*-----------------------------------------------
 
-			var iTexFound int = -1
-			for j := 0; j < len(CAtTextures); j++ {
-				if sFullPath == CAtTextures[j].GetPath() {
-					iTexFound = int(j)
-					break
-				}
+  Name:	CreateFromModelData
+
+  Params:	data - model from ImportModelData
+
+  Result:	Uploads model's vertices and indices to
+  		its own buffers and gets its textures from
+  		texture manager. Model loaded before is
+  		released. Must be called on OpenGL thread.
+
+  /*---------------------------------------------*/
+
+func (this *CAssimpModel) CreateFromModelData(data *CModelData) bool {
+	if this.bLoaded {
+		this.ReleaseModel()
+	}
+	this.iMeshStartIndices = append([]int32(nil), data.iMeshStartIndices...)
+	this.iMeshSizes = append([]int32(nil), data.iMeshSizes...)
+	this.iMeshFirstVertices = append([]int32(nil), data.iMeshFirstVertices...)
+	this.iMeshNumVertices = append([]int32(nil), data.iMeshNumVertices...)
+	this.iMaterialIndices = append([]uint(nil), data.iMaterialIndices...)
+	this.iNumMaterials = len(data.vMaterials)
+	this.vPBRMaterials = data.vPBRMaterials
+	this.vMeshBoxes = append([]libs.AABB(nil), data.vMeshBoxes...)
+	this.vMeshSpheres = append([]libs.Sphere(nil), data.vMeshSpheres...)
+	this.boxModel, this.sphereModel = data.boxModel, data.sphereModel
+	this.setSkinning(data)
+	this.createBuffers(data)
+	if this.bCPUSkinning {
+		this.skinVerticesOnCPU()
+	}
+
+	this.vTextures = make([][NUM_TEXTURE_SLOTS]*CTexture, this.iNumMaterials)
+	for i := range data.vTexturePaths {
+		for iSlot, sFullPath := range data.vTexturePaths[i] {
+			if sFullPath == "" {
+				continue
 			}
-			if iTexFound != -1 {
-				materialRemap[i] = uint(iTexFound)
+			// Manager loads each file once, models sharing it get the same texture
+			usage := ETextureSlot(iSlot).GetUsage()
+			if data.vTextureData[i][iSlot] != nil {
+				this.vTextures[i][iSlot] = tmTextures.AcquireDecodedTextureEx(sFullPath, data.vTextureData[i][iSlot], true, usage)
 			} else {
-				var tNew CTexture
-				tNew.LoadTexture2D(sFullPath, true)
-				materialRemap[i] = uint(len(CAtTextures))
-				CAtTextures = append(CAtTextures, tNew)
+				this.vTextures[i][iSlot] = tmTextures.AcquireTextureEx(sFullPath, true, usage)
 			}
 		}
 	}
-
-	for i := 0; i < len(this.iMeshSizes); i++ {
-		var iOldIndex uint = this.iMaterialIndices[i]
-		this.iMaterialIndices[i] = materialRemap[iOldIndex]
+	this.vImportedMaterials = append([]CMaterial(nil), data.vMaterials...)
+	for i := range this.vImportedMaterials {
+		if i < len(this.vTextures) {
+			this.vImportedMaterials[i].vTextures = this.vTextures[i]
+		}
 	}
+	this.vMaterials = append([]CMaterial(nil), this.vImportedMaterials...)
 	this.bLoaded = true
 	return this.bLoaded
 }
 
+func (this *CAssimpModel) LoadModelFromFile(sFilePath string) bool {
+	data, err := ImportModelData(sFilePath)
+	if err != nil {
+		fmt.Println("Couldn't load model", sFilePath+":", err)
+		return false
+	}
+	return this.CreateFromModelData(data)
+}
+
 /*-----------------------------------------------
 
-  Name:	FinalizeVBO
+  Name:	createBuffers
 
-  Params: none
+  Params: data - imported model
 
-  Result: Uploads all loaded model data in one global
-  		models' VBO.
+  Result: Creates model's VAO with vertex, skin and
+  		index buffers. Data isn't kept in memory,
+  		except bind pose of skinned models.
 
   /*---------------------------------------------*/
 
-func FinalizeVBO() {
-	gl.GenVertexArrays(1, &uiVAO)
-	gl.BindVertexArray(uiVAO)
-	vboModelData.BindVBO(gl.ARRAY_BUFFER)
-	vboModelData.UploadDataToGPU(gl.STATIC_DRAW)
+func (this *CAssimpModel) createBuffers(data *CModelData) {
+	gl.GenVertexArrays(1, &this.uiVAO)
+	gl.BindVertexArray(this.uiVAO)
+
+	// CPU skinning rewrites vertices every frame
+	var iUsage uint32 = gl.STATIC_DRAW
+	if this.bCPUSkinning {
+		iUsage = gl.DYNAMIC_DRAW
+	}
+	this.vboVertices.CreateVBO(0)
+	this.vboVertices.AddData(data.bVertexData, int32(len(data.bVertexData)))
+	this.vboVertices.BindVBO(gl.ARRAY_BUFFER)
+	this.vboVertices.UploadDataToGPU(iUsage)
 	// Vertex positions
 	gl.EnableVertexAttribArray(0)
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, int32(2*unsafe.Sizeof(gglm.Vec3{})+unsafe.Sizeof(gglm.Vec2{})), nil)
+	gl.VertexAttribPointerWithOffset(0, 3, gl.FLOAT, false, MODEL_VERTEX_SIZE, 0)
 	// Texture coordinates
 	gl.EnableVertexAttribArray(1)
-	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, int32(2*unsafe.Sizeof(gglm.Vec3{})+unsafe.Sizeof(gglm.Vec2{})),
-		unsafe.Pointer(unsafe.Sizeof(gglm.Vec3{})))
+	gl.VertexAttribPointerWithOffset(1, 2, gl.FLOAT, false, MODEL_VERTEX_SIZE, unsafe.Sizeof(gglm.Vec3{}))
 	// Normal vectors
 	gl.EnableVertexAttribArray(2)
-	gl.VertexAttribPointer(2, 3, gl.FLOAT, false, int32(2*unsafe.Sizeof(gglm.Vec3{})+unsafe.Sizeof(gglm.Vec2{})),
-		unsafe.Pointer(unsafe.Sizeof(gglm.Vec3{})+unsafe.Sizeof(gglm.Vec2{})))
+	gl.VertexAttribPointerWithOffset(2, 3, gl.FLOAT, false, MODEL_VERTEX_SIZE, unsafe.Sizeof(gglm.Vec3{})+unsafe.Sizeof(gglm.Vec2{}))
+	// Tangents and bitangents
+	gl.EnableVertexAttribArray(3)
+	gl.VertexAttribPointerWithOffset(3, 3, gl.FLOAT, false, MODEL_VERTEX_SIZE, 2*unsafe.Sizeof(gglm.Vec3{})+unsafe.Sizeof(gglm.Vec2{}))
+	gl.EnableVertexAttribArray(4)
+	gl.VertexAttribPointerWithOffset(4, 3, gl.FLOAT, false, MODEL_VERTEX_SIZE, 3*unsafe.Sizeof(gglm.Vec3{})+unsafe.Sizeof(gglm.Vec2{}))
+
+	// Joint indices and weights, static models leave them disabled
+	if this.apPlayer != nil {
+		this.vboSkin.CreateVBO(0)
+		this.vboSkin.AddData(data.bSkinData, int32(len(data.bSkinData)))
+		this.vboSkin.BindVBO(gl.ARRAY_BUFFER)
+		this.vboSkin.UploadDataToGPU(gl.STATIC_DRAW)
+		gl.EnableVertexAttribArray(5)
+		gl.VertexAttribIPointerWithOffset(5, libs.MAX_BONE_INFLUENCES, gl.UNSIGNED_SHORT, MODEL_SKIN_VERTEX_SIZE, 0)
+		gl.EnableVertexAttribArray(6)
+		gl.VertexAttribPointerWithOffset(6, libs.MAX_BONE_INFLUENCES, gl.FLOAT, false, MODEL_SKIN_VERTEX_SIZE,
+			2*libs.MAX_BONE_INFLUENCES)
+	}
+
+	// Index buffer is part of VAO's state
+	this.vboIndices.CreateVBO(0)
+	if len(data.vIndices) > 0 {
+		this.vboIndices.AddData(EncodeToBytes(data.vIndices), int32(len(data.vIndices))*int32(unsafe.Sizeof(uint32(0))))
+	}
+	this.vboIndices.BindVBO(gl.ELEMENT_ARRAY_BUFFER)
+	this.vboIndices.UploadDataToGPU(gl.STATIC_DRAW)
+
+	// Models may be created in the middle of a frame, nobody else should change this VAO
+	gl.BindVertexArray(0)
 }
 
 /*-----------------------------------------------
 
-  Name:	BindModelsVAO
+  Name:	RenderModel
 
   Params: none
 
-  Result: Binds VAO of models with their VBO.
+  Result: Guess what it does ^^. Skinning uniforms
+  		go to main shader program.
 
   /*---------------------------------------------*/
 
-func BindModelsVAO() {
-	gl.BindVertexArray(uiVAO)
+func (this *CAssimpModel) RenderModel() {
+	this.RenderModelEx(&spMain)
 }
 
 /*-----------------------------------------------
 
-  Name:	RenderModel
+  Name:	RenderModelEx
 
-  Params: none
+  Params: spProgram - program in use
 
-  Result: Guess what it does ^^.
+  Result: Renders opaque meshes first and then
+  		transparent ones blended over them. Gives
+  		program material of each mesh and bone
+  		matrices of skinned meshes.
 
   /*---------------------------------------------*/
 
-func (this *CAssimpModel) RenderModel() {
+func (this *CAssimpModel) RenderModelEx(spProgram *CShaderProgram) {
 	if !this.bLoaded {
 		return
 	}
-	var iNumMeshes int = len(this.iMeshSizes)
-	for i := 0; i < iNumMeshes; i++ {
-		var iMatIndex uint = this.iMaterialIndices[i]
-		CAtTextures[iMatIndex].BindTexture(0)
-		gl.DrawArrays(gl.TRIANGLES, this.iMeshStartIndices[i], this.iMeshSizes[i])
+	this.renderMeshes(spProgram, nil)
+}
+
+// renderMeshes draws meshes bMeshVisible allows, all of them if it's nil
+func (this *CAssimpModel) renderMeshes(spProgram *CShaderProgram, bMeshVisible []bool) {
+	gl.BindVertexArray(this.uiVAO)
+	var bCulling bool = gl.IsEnabled(gl.CULL_FACE)
+	for _, bTransparentPass := range []bool{false, true} {
+		if bTransparentPass {
+			gl.Enable(gl.BLEND)
+			gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+			gl.DepthMask(false)
+		}
+		for i := range this.iMeshSizes {
+			material := this.GetMeshMaterial(i)
+			if material.IsTransparent() != bTransparentPass || (bMeshVisible != nil && !bMeshVisible[i]) {
+				continue
+			}
+			this.setSkinningUniforms(spProgram, i)
+			material.SetUniformData(spProgram, "material")
+			if bCulling && material.IsDoubleSided() {
+				gl.Disable(gl.CULL_FACE)
+			}
+			gl.DrawElementsWithOffset(gl.TRIANGLES, this.iMeshSizes[i], gl.UNSIGNED_INT,
+				uintptr(this.iMeshStartIndices[i])*unsafe.Sizeof(uint32(0)))
+			if bCulling && material.IsDoubleSided() {
+				gl.Enable(gl.CULL_FACE)
+			}
+		}
 	}
+	gl.DepthMask(true)
+	gl.Disable(gl.BLEND)
+	// Other objects drawn with the program aren't skinned and have default material
+	spProgram.SetUniformI32("bSkinned", 0)
+	mtDefault.SetUniformData(spProgram, "material")
+}
+
+func (this *CAssimpModel) GetNumMaterials() int {
+	return len(this.vMaterials)
+}
+
+// GetMaterial returns material by index for reading or changing it at runtime, nil if there's no such material.
+func (this *CAssimpModel) GetMaterial(iMaterial int) *CMaterial {
+	if iMaterial < 0 || iMaterial >= len(this.vMaterials) {
+		return nil
+	}
+	return &this.vMaterials[iMaterial]
+}
+
+// FindMaterial returns material with name, nil if model has none.
+func (this *CAssimpModel) FindMaterial(sName string) *CMaterial {
+	for i := range this.vMaterials {
+		if this.vMaterials[i].sName == sName {
+			return &this.vMaterials[i]
+		}
+	}
+	return nil
+}
+
+// GetMeshMaterial returns material mesh renders with, default one if its index is out of range.
+func (this *CAssimpModel) GetMeshMaterial(iMesh int) *CMaterial {
+	if material := this.GetMaterial(int(this.iMaterialIndices[iMesh])); material != nil {
+		return material
+	}
+	return mtDefault
+}
+
+// ResetMaterial undoes runtime changes of material, returns false if there's no such material.
+func (this *CAssimpModel) ResetMaterial(iMaterial int) bool {
+	if iMaterial < 0 || iMaterial >= len(this.vMaterials) {
+		return false
+	}
+	this.vMaterials[iMaterial] = this.vImportedMaterials[iMaterial]
+	return true
+}
+
+// IsLoaded tells whether model has been created and not released yet.
+func (this *CAssimpModel) IsLoaded() bool {
+	return this.bLoaded
+}
+
+/*-----------------------------------------------
+
+  Name:	ReleaseModel
+
+  Params: none
+
+  Result: Deletes model's buffers and releases its
+  		textures, the texture manager deletes those
+  		no other model uses. Model can be created
+  		again afterwards.
+
+  /*---------------------------------------------*/
+
+func (this *CAssimpModel) ReleaseModel() {
+	for i := range this.vTextures {
+		for _, texture := range this.vTextures[i] {
+			if texture != nil {
+				tmTextures.ReleaseTexture(texture)
+			}
+		}
+	}
+	if this.uiVAO != 0 {
+		gl.DeleteVertexArrays(1, &this.uiVAO)
+		this.uiVAO = 0
+		this.vboVertices.DeleteVBO()
+		this.vboIndices.DeleteVBO()
+	}
+	if this.vboSkin.GetBufferID() != 0 {
+		this.vboSkin.DeleteVBO()
+		this.vboSkin = CVertexBufferObject{}
+	}
+	this.vboVertices = CVertexBufferObject{}
+	this.vboIndices = CVertexBufferObject{}
+	this.vTextures = nil
+	this.vMaterials = nil
+	this.vImportedMaterials = nil
+	this.vPBRMaterials = nil
+	this.vMeshBoxes = nil
+	this.vMeshSpheres = nil
+	this.boxModel, this.sphereModel = libs.AABB{}, libs.Sphere{}
+	this.bMeshVisible = nil
+	this.releaseSkinning()
+	this.iMeshStartIndices = nil
+	this.iMeshSizes = nil
+	this.iMeshFirstVertices = nil
+	this.iMeshNumVertices = nil
+	this.iMaterialIndices = nil
+	this.iNumMaterials = 0
+	this.bLoaded = false
 }
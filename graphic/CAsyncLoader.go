@@ -0,0 +1,536 @@
+package graphic
+
+import (
+	"antry/libs"
+	"errors"
+	"fmt"
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"image"
+	"image/color"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type EAssetState int32
+
+const (
+	ASSET_STATE_DECODING  EAssetState = iota // Waiting for or running on worker goroutine
+	ASSET_STATE_UPLOADING                    // Decoded, waiting in OpenGL upload queue
+	ASSET_STATE_READY
+	ASSET_STATE_FAILED
+)
+
+// assetHandle is state shared by all asynchronously loaded assets
+type assetHandle struct {
+	sPath  string
+	iState int32 // EAssetState, changed by workers, so accessed atomically
+	err    error // Set before state becomes ASSET_STATE_FAILED
+}
+
+func (this *assetHandle) GetState() EAssetState {
+	return EAssetState(atomic.LoadInt32(&this.iState))
+}
+
+func (this *assetHandle) setState(state EAssetState) {
+	atomic.StoreInt32(&this.iState, int32(state))
+}
+
+func (this *assetHandle) IsReady() bool {
+	return this.GetState() == ASSET_STATE_READY
+}
+
+// IsDone returns true once the asset is ready or failed.
+func (this *assetHandle) IsDone() bool {
+	state := this.GetState()
+	return state == ASSET_STATE_READY || state == ASSET_STATE_FAILED
+}
+
+// GetError returns why loading failed, nil otherwise.
+func (this *assetHandle) GetError() error {
+	if this.GetState() != ASSET_STATE_FAILED {
+		return nil
+	}
+	return this.err
+}
+
+func (this *assetHandle) GetPath() string {
+	return this.sPath
+}
+
+// CTextureHandle is texture being loaded by CAsyncLoader, it shows placeholder until it's uploaded
+type CTextureHandle struct {
+	assetHandle
+	tTexture         CTexture
+	iTarget          uint32 // Placeholder of the same target is shown, so samplers get what they expect
+	bGenerateMipMaps bool
+	sdSampler        *SamplerDesc      // Applied on upload if set before it
+	texData          *libs.TextureData // Decoded texture, dropped after upload
+	floatImage       *libs.FloatImage  // Decoded HDR image, dropped after upload
+	vImages          []image.Image     // Decoded array layers or cubemap faces, dropped after upload
+}
+
+// GetTexture returns the texture when it's ready, shared placeholder texture otherwise.
+func (this *CTextureHandle) GetTexture() *CTexture {
+	if this.IsReady() {
+		return &this.tTexture
+	}
+	return getPlaceholderTexture(this.iTarget)
+}
+
+func (this *CTextureHandle) BindTexture(iTextureUnit uint32) {
+	this.GetTexture().BindTexture(iTextureUnit)
+}
+
+// SetSamplerDesc sets sampler state of the texture now or once it's uploaded, it must be called on OpenGL thread.
+func (this *CTextureHandle) SetSamplerDesc(desc SamplerDesc) {
+	this.sdSampler = &desc
+	if this.IsReady() {
+		this.tTexture.SetSamplerDesc(desc)
+	}
+}
+
+// DeleteTexture deletes uploaded texture, placeholders are shared and stay.
+func (this *CTextureHandle) DeleteTexture() {
+	this.tTexture.DeleteTexture()
+}
+
+// CModelHandle is model being loaded by CAsyncLoader, model isn't rendered until it's uploaded
+type CModelHandle struct {
+	assetHandle
+	model *CAssimpModel
+	data  *CModelData // Imported model, dropped after upload
+}
+
+func (this *CModelHandle) GetModel() *CAssimpModel {
+	return this.model
+}
+
+var tPlaceholder, tPlaceholderArray, tPlaceholderCubemap CTexture
+
+/*-----------------------------------------------
+
+  Name:	getPlaceholderTexture
+
+  Params:	iTarget - TEXTURE_2D, TEXTURE_2D_ARRAY or
+  		TEXTURE_CUBE_MAP
+
+  Result:	Returns small grey checkerboard of given
+  		target, it's created the first time it's
+  		needed.
+
+  /*---------------------------------------------*/
+
+func getPlaceholderTexture(iTarget uint32) *CTexture {
+	var tTexture *CTexture = &tPlaceholder
+	switch iTarget {
+	case gl.TEXTURE_2D_ARRAY:
+		tTexture = &tPlaceholderArray
+	case gl.TEXTURE_CUBE_MAP:
+		tTexture = &tPlaceholderCubemap
+	}
+	if tTexture.uiTexture != 0 {
+		return tTexture
+	}
+	imgChecker := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	for i := 0; i < 4; i++ {
+		var iGrey uint8 = 96
+		if i%2 == i/2 {
+			iGrey = 160
+		}
+		imgChecker.SetNRGBA(i%2, i/2, color.NRGBA{iGrey, iGrey, iGrey, 255})
+	}
+	switch iTarget {
+	case gl.TEXTURE_2D_ARRAY:
+		tTexture.CreateTextureArray([]image.Image{imgChecker}, false)
+	case gl.TEXTURE_CUBE_MAP:
+		tTexture.CreateCubemap([6]image.Image{imgChecker, imgChecker, imgChecker, imgChecker, imgChecker, imgChecker}, false)
+	default:
+		tTexture.CreateFromData(gl.Ptr(imgChecker.Pix), 2, 2, 32, gl.RGBA, false)
+	}
+	tTexture.SetFiltering(TEXTURE_FILTER_MAG_NEAREST, TEXTURE_FILTER_MIN_NEAREST)
+	return tTexture
+}
+
+func releasePlaceholderTextures() {
+	tPlaceholder.DeleteTexture()
+	tPlaceholderArray.DeleteTexture()
+	tPlaceholderCubemap.DeleteTexture()
+}
+
+/*-----------------------------------------------
+
+  Name:	CAsyncLoader
+
+  Result:	Loads assets in two phases. Files are read
+  		and decoded on worker goroutines, results go
+  		to upload queue, which OpenGL thread drains
+  		by calling ProcessUploads every frame.
+
+  /*---------------------------------------------*/
+
+type CAsyncLoader struct {
+	chJobs    chan func()
+	wgWorkers sync.WaitGroup
+	mutexJobs sync.RWMutex // Guards bClosed, so nothing is sent to closed chJobs
+	bClosed   bool
+
+	mutexUploads sync.Mutex
+	vUploads     []assetUpload // Run on OpenGL thread, in order assets were decoded
+
+	iTotal  int32 // Assets ever requested, accessed atomically
+	iDone   int32 // Assets ready or failed, accessed atomically
+	iFailed int32
+}
+
+/*-----------------------------------------------
+
+  Name:	NewCAsyncLoader
+
+  Params:	iWorkers - number of decoding goroutines,
+  		0 uses one per CPU
+
+  Result:	Creates loader and starts its workers.
+
+  /*---------------------------------------------*/
+
+func NewCAsyncLoader(iWorkers int) *CAsyncLoader {
+	if iWorkers <= 0 {
+		iWorkers = runtime.NumCPU()
+	}
+	this := CAsyncLoader{}
+	this.chJobs = make(chan func(), 256)
+	for i := 0; i < iWorkers; i++ {
+		this.wgWorkers.Add(1)
+		go func() {
+			defer this.wgWorkers.Done()
+			for job := range this.chJobs {
+				job()
+			}
+		}()
+	}
+	return &this
+}
+
+// queueJob hands job over to workers, it fails once Shutdown was called
+func (this *CAsyncLoader) queueJob(job func()) error {
+	this.mutexJobs.RLock()
+	defer this.mutexJobs.RUnlock()
+	if this.bClosed {
+		return errors.New("async loader is shut down")
+	}
+	atomic.AddInt32(&this.iTotal, 1)
+	this.chJobs <- job
+	return nil
+}
+
+// assetUpload is decoded asset waiting for OpenGL thread, handle is finished by fnUpload
+type assetUpload struct {
+	handle   *assetHandle
+	fnUpload func()
+}
+
+// queueUpload hands decoded asset over to OpenGL thread
+func (this *CAsyncLoader) queueUpload(handle *assetHandle, fnUpload func()) {
+	this.mutexUploads.Lock()
+	this.vUploads = append(this.vUploads, assetUpload{handle: handle, fnUpload: fnUpload})
+	this.mutexUploads.Unlock()
+}
+
+func (this *CAsyncLoader) finish(handle *assetHandle, err error) {
+	if err != nil {
+		handle.err = err
+		handle.setState(ASSET_STATE_FAILED)
+		atomic.AddInt32(&this.iFailed, 1)
+		fmt.Println("Couldn't load", handle.sPath+":", err)
+	} else {
+		handle.setState(ASSET_STATE_READY)
+	}
+	atomic.AddInt32(&this.iDone, 1)
+}
+
+/*-----------------------------------------------
+
+  Name:	loadTextureAsync
+
+  Params:	handle - handle with target and mipmap
+  		setting filled
+  		fnDecode - reads files into handle, runs on
+  		worker
+  		fnCreate - creates handle.tTexture from
+  		decoded data, runs on OpenGL thread
+
+  Result:	Queues texture job shared by all texture
+  		kinds. Fails after Shutdown.
+
+  /*---------------------------------------------*/
+
+func (this *CAsyncLoader) loadTextureAsync(handle *CTextureHandle, fnDecode func() error, fnCreate func() bool) (*CTextureHandle, error) {
+	err := this.queueJob(func() {
+		if err := fnDecode(); err != nil {
+			this.finish(&handle.assetHandle, err)
+			return
+		}
+		handle.setState(ASSET_STATE_UPLOADING)
+		this.queueUpload(&handle.assetHandle, func() {
+			bOK := fnCreate()
+			handle.texData = nil
+			handle.floatImage = nil
+			handle.vImages = nil
+			if !bOK {
+				this.finish(&handle.assetHandle, fmt.Errorf("upload of %s failed", handle.sPath))
+				return
+			}
+			if handle.bGenerateMipMaps {
+				handle.tTexture.SetFiltering(TEXTURE_FILTER_MAG_BILINEAR, TEXTURE_FILTER_MIN_TRILINEAR)
+			} else {
+				handle.tTexture.SetFiltering(TEXTURE_FILTER_MAG_BILINEAR, TEXTURE_FILTER_MIN_BILINEAR)
+			}
+			if handle.sdSampler != nil {
+				handle.tTexture.SetSamplerDesc(*handle.sdSampler)
+			}
+			this.finish(&handle.assetHandle, nil)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return handle, nil
+}
+
+// LoadTextureAsync loads colour texture, see LoadTextureAsyncEx.
+func (this *CAsyncLoader) LoadTextureAsync(sPath string, bGenerateMipMaps bool) (*CTextureHandle, error) {
+	return this.LoadTextureAsyncEx(sPath, bGenerateMipMaps, TEXTURE_USAGE_COLOR)
+}
+
+/*-----------------------------------------------
+
+  Name:	LoadTextureAsyncEx
+
+  Params:	sPath - image or texture container file
+  		bGenerateMipMaps - whether to create mipmaps
+  		usage - what texels hold
+
+  Result:	Returns handle right away, texture gets
+  		decoded on worker and uploaded later by
+  		ProcessUploads. Fails after Shutdown.
+
+  /*---------------------------------------------*/
+
+func (this *CAsyncLoader) LoadTextureAsyncEx(sPath string, bGenerateMipMaps bool, usage ETextureUsage) (*CTextureHandle, error) {
+	handle := &CTextureHandle{iTarget: gl.TEXTURE_2D, bGenerateMipMaps: bGenerateMipMaps}
+	handle.sPath = sPath
+	handle.tTexture.SetUsage(usage)
+	return this.loadTextureAsync(handle, func() error {
+		var err error
+		// HDR images stay float, TextureData holds only 8 bit or compressed texels
+		if strings.EqualFold(filepath.Ext(sPath), ".hdr") {
+			handle.floatImage, err = libs.LoadHDRFile(sPath)
+		} else {
+			handle.texData, err = DecodeTextureFile(sPath)
+		}
+		return err
+	}, func() bool {
+		if handle.floatImage != nil {
+			handle.tTexture.CreateFromFloatImage(handle.floatImage, handle.bGenerateMipMaps)
+		} else if !handle.tTexture.CreateFromTextureData(handle.texData, handle.bGenerateMipMaps) {
+			return false
+		}
+		handle.tTexture.sPath = sPath
+		return true
+	})
+}
+
+// decodeImages loads every file into handle.vImages, used for array layers and cubemap faces
+func (this *CTextureHandle) decodeImages(vPaths []string) error {
+	this.vImages = make([]image.Image, len(vPaths))
+	for i, sPath := range vPaths {
+		img, err := loadImageFile(sPath)
+		if err != nil {
+			return fmt.Errorf("%s: %v", sPath, err)
+		}
+		this.vImages[i] = img
+	}
+	return nil
+}
+
+/*-----------------------------------------------
+
+  Name:	LoadTextureArrayAsync
+
+  Params:	vPaths - one image per layer
+  		bGenerateMipMaps - whether to create mipmaps
+
+  Result:	Works like LoadTextureArray, but images
+  		get decoded on worker. Texture array
+  		placeholder is shown until upload.
+
+  /*---------------------------------------------*/
+
+func (this *CAsyncLoader) LoadTextureArrayAsync(vPaths []string, bGenerateMipMaps bool) (*CTextureHandle, error) {
+	handle := &CTextureHandle{iTarget: gl.TEXTURE_2D_ARRAY, bGenerateMipMaps: bGenerateMipMaps}
+	handle.sPath = strings.Join(vPaths, ", ")
+	vPaths = append([]string(nil), vPaths...)
+	return this.loadTextureAsync(handle, func() error {
+		return handle.decodeImages(vPaths)
+	}, func() bool {
+		if !handle.tTexture.CreateTextureArray(handle.vImages, handle.bGenerateMipMaps) {
+			return false
+		}
+		handle.tTexture.vLayerPaths = vPaths
+		return true
+	})
+}
+
+/*-----------------------------------------------
+
+  Name:	LoadCubemapAsync
+
+  Params:	vPaths - face files in libs.CUBE_FACE_*
+  		order
+  		bGenerateMipMaps - whether to create mipmaps
+
+  Result:	Works like CreateCubemap of loaded faces,
+  		but they get decoded on worker. Cubemap
+  		placeholder is shown until upload.
+
+  /*---------------------------------------------*/
+
+func (this *CAsyncLoader) LoadCubemapAsync(vPaths [6]string, bGenerateMipMaps bool) (*CTextureHandle, error) {
+	handle := &CTextureHandle{iTarget: gl.TEXTURE_CUBE_MAP, bGenerateMipMaps: bGenerateMipMaps}
+	handle.sPath = strings.Join(vPaths[:], ", ")
+	return this.loadTextureAsync(handle, func() error {
+		return handle.decodeImages(vPaths[:])
+	}, func() bool {
+		var vFaces [6]image.Image
+		copy(vFaces[:], handle.vImages)
+		return handle.tTexture.CreateCubemap(vFaces, handle.bGenerateMipMaps)
+	})
+}
+
+/*-----------------------------------------------
+
+  Name:	LoadModelAsync
+
+  Params:	sPath - model file
+  		model - model to fill, it renders nothing
+  		until it's uploaded
+
+  Result:	Returns handle right away, model and its
+  		textures get imported and decoded on worker
+  		and uploaded later by ProcessUploads. Fails
+  		after Shutdown.
+
+  /*---------------------------------------------*/
+
+func (this *CAsyncLoader) LoadModelAsync(sPath string, model *CAssimpModel) (*CModelHandle, error) {
+	handle := &CModelHandle{model: model}
+	handle.sPath = sPath
+	err := this.queueJob(func() {
+		data, err := ImportModelData(sPath)
+		if err != nil {
+			this.finish(&handle.assetHandle, err)
+			return
+		}
+		data.DecodeTextures()
+		handle.data = data
+		handle.setState(ASSET_STATE_UPLOADING)
+		this.queueUpload(&handle.assetHandle, func() {
+			bOK := handle.model.CreateFromModelData(handle.data)
+			handle.data = nil
+			if !bOK {
+				this.finish(&handle.assetHandle, fmt.Errorf("upload of %s failed", sPath))
+				return
+			}
+			this.finish(&handle.assetHandle, nil)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return handle, nil
+}
+
+/*-----------------------------------------------
+
+  Name:	ProcessUploads
+
+  Params:	durBudget - time uploads may take, at least
+  		one upload is done if any is waiting
+
+  Result:	Uploads decoded assets to OpenGL, must be
+  		called on OpenGL thread. Returns number of
+  		uploaded assets.
+
+  /*---------------------------------------------*/
+
+func (this *CAsyncLoader) ProcessUploads(durBudget time.Duration) int {
+	tStart := time.Now()
+	var iUploaded int = 0
+	for {
+		this.mutexUploads.Lock()
+		if len(this.vUploads) == 0 {
+			this.mutexUploads.Unlock()
+			break
+		}
+		upload := this.vUploads[0]
+		this.vUploads[0] = assetUpload{}
+		this.vUploads = this.vUploads[1:]
+		this.mutexUploads.Unlock()
+
+		upload.fnUpload()
+		iUploaded++
+		if time.Since(tStart) >= durBudget {
+			break
+		}
+	}
+	return iUploaded
+}
+
+// GetProgress returns number of finished (ready or failed) assets and number of all requested assets.
+func (this *CAsyncLoader) GetProgress() (int, int) {
+	return int(atomic.LoadInt32(&this.iDone)), int(atomic.LoadInt32(&this.iTotal))
+}
+
+// GetProgressFraction returns finished part of requested assets in 0..1, 1 when nothing is requested.
+func (this *CAsyncLoader) GetProgressFraction() float32 {
+	iDone, iTotal := this.GetProgress()
+	if iTotal == 0 {
+		return 1
+	}
+	return float32(iDone) / float32(iTotal)
+}
+
+func (this *CAsyncLoader) GetNumFailed() int {
+	return int(atomic.LoadInt32(&this.iFailed))
+}
+
+// IsIdle returns true when every requested asset is ready or failed.
+func (this *CAsyncLoader) IsIdle() bool {
+	iDone, iTotal := this.GetProgress()
+	return iDone == iTotal
+}
+
+// Shutdown stops workers after they finish queued jobs, assets still waiting for upload are dropped
+// and fail, so progress gets complete. Later requests fail and calling it again does nothing.
+func (this *CAsyncLoader) Shutdown() {
+	this.mutexJobs.Lock()
+	if this.bClosed {
+		this.mutexJobs.Unlock()
+		return
+	}
+	this.bClosed = true
+	close(this.chJobs)
+	this.mutexJobs.Unlock()
+	this.wgWorkers.Wait()
+	this.mutexUploads.Lock()
+	vDropped := this.vUploads
+	this.vUploads = nil
+	this.mutexUploads.Unlock()
+	for _, upload := range vDropped {
+		this.finish(upload.handle, errors.New("async loader was shut down before upload"))
+	}
+}
@@ -0,0 +1,526 @@
+package graphic
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// renderFormatInfo describes internal format framebuffer attachments can have
+type renderFormatInfo struct {
+	iFormat  uint32 // Pixel format and type for creating the texture without data
+	iType    uint32
+	iBPP     int32
+	bDepth   bool
+	bStencil bool
+	bFloat   bool
+	bInteger bool // Must be sampled with nearest filtering
+	sName    string
+}
+
+var mRenderFormats = map[uint32]renderFormatInfo{
+	gl.R8:                 {iFormat: gl.RED, iType: gl.UNSIGNED_BYTE, iBPP: 8, sName: "R8"},
+	gl.RG8:                {iFormat: gl.RG, iType: gl.UNSIGNED_BYTE, iBPP: 16, sName: "RG8"},
+	gl.RGB8:               {iFormat: gl.RGB, iType: gl.UNSIGNED_BYTE, iBPP: 24, sName: "RGB8"},
+	gl.RGBA8:              {iFormat: gl.RGBA, iType: gl.UNSIGNED_BYTE, iBPP: 32, sName: "RGBA8"},
+	gl.SRGB8_ALPHA8:       {iFormat: gl.RGBA, iType: gl.UNSIGNED_BYTE, iBPP: 32, sName: "SRGB8_ALPHA8"},
+	gl.RGB10_A2:           {iFormat: gl.RGBA, iType: gl.UNSIGNED_INT_2_10_10_10_REV, iBPP: 32, sName: "RGB10_A2"},
+	gl.R11F_G11F_B10F:     {iFormat: gl.RGB, iType: gl.UNSIGNED_INT_10F_11F_11F_REV, iBPP: 32, bFloat: true, sName: "R11F_G11F_B10F"},
+	gl.R16F:               {iFormat: gl.RED, iType: gl.FLOAT, iBPP: 16, bFloat: true, sName: "R16F"},
+	gl.RG16F:              {iFormat: gl.RG, iType: gl.FLOAT, iBPP: 32, bFloat: true, sName: "RG16F"},
+	gl.RGB16F:             {iFormat: gl.RGB, iType: gl.FLOAT, iBPP: 48, bFloat: true, sName: "RGB16F"},
+	gl.RGBA16F:            {iFormat: gl.RGBA, iType: gl.FLOAT, iBPP: 64, bFloat: true, sName: "RGBA16F"},
+	gl.R32F:               {iFormat: gl.RED, iType: gl.FLOAT, iBPP: 32, bFloat: true, sName: "R32F"},
+	gl.RG32F:              {iFormat: gl.RG, iType: gl.FLOAT, iBPP: 64, bFloat: true, sName: "RG32F"},
+	gl.RGBA32F:            {iFormat: gl.RGBA, iType: gl.FLOAT, iBPP: 128, bFloat: true, sName: "RGBA32F"},
+	gl.R32UI:              {iFormat: gl.RED_INTEGER, iType: gl.UNSIGNED_INT, iBPP: 32, bInteger: true, sName: "R32UI"},
+	gl.R32I:               {iFormat: gl.RED_INTEGER, iType: gl.INT, iBPP: 32, bInteger: true, sName: "R32I"},
+	gl.DEPTH_COMPONENT16:  {iFormat: gl.DEPTH_COMPONENT, iType: gl.UNSIGNED_SHORT, iBPP: 16, bDepth: true, sName: "DEPTH_COMPONENT16"},
+	gl.DEPTH_COMPONENT24:  {iFormat: gl.DEPTH_COMPONENT, iType: gl.UNSIGNED_INT, iBPP: 32, bDepth: true, sName: "DEPTH_COMPONENT24"},
+	gl.DEPTH_COMPONENT32F: {iFormat: gl.DEPTH_COMPONENT, iType: gl.FLOAT, iBPP: 32, bDepth: true, bFloat: true, sName: "DEPTH_COMPONENT32F"},
+	gl.DEPTH24_STENCIL8:   {iFormat: gl.DEPTH_STENCIL, iType: gl.UNSIGNED_INT_24_8, iBPP: 32, bDepth: true, bStencil: true, sName: "DEPTH24_STENCIL8"},
+	gl.DEPTH32F_STENCIL8:  {iFormat: gl.DEPTH_STENCIL, iType: gl.FLOAT_32_UNSIGNED_INT_24_8_REV, iBPP: 64, bDepth: true, bStencil: true, bFloat: true, sName: "DEPTH32F_STENCIL8"},
+}
+
+func getRenderFormatInfo(iInternalFormat uint32) (renderFormatInfo, error) {
+	info, bOK := mRenderFormats[iInternalFormat]
+	if !bOK {
+		return info, fmt.Errorf("internal format 0x%X can't be used as framebuffer attachment", iInternalFormat)
+	}
+	return info, nil
+}
+
+/*-----------------------------------------------
+
+  Name:	CreateRenderTarget
+
+  Params:	iWidth, iHeight - texture size
+  		iInternalFormat - colour or depth format
+  		from framebuffer formats
+
+  Result:	Creates empty texture to render into, it
+  		has no mipmaps and clamps to edge.
+
+  /*---------------------------------------------*/
+
+func (this *CTexture) CreateRenderTarget(iWidth, iHeight int32, iInternalFormat uint32) error {
+	info, err := getRenderFormatInfo(iInternalFormat)
+	if err != nil {
+		return err
+	}
+	gl.GenTextures(1, &this.uiTexture)
+	gl.BindTexture(gl.TEXTURE_2D, this.uiTexture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, int32(iInternalFormat), iWidth, iHeight, 0, info.iFormat, info.iType, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAX_LEVEL, 0)
+
+	sdTarget := DefaultSamplerDesc()
+	sdTarget.SetWrap(gl.CLAMP_TO_EDGE)
+	if info.bInteger {
+		sdTarget.MagFilter, sdTarget.MinFilter = TEXTURE_FILTER_MAG_NEAREST, TEXTURE_FILTER_MIN_NEAREST
+	}
+	this.SetSamplerDesc(sdTarget)
+
+	this.iTarget = 0
+	this.iLayers = 0
+	this.sPath = ""
+	this.vLayerPaths = nil
+	this.bCompressed = false
+	this.bFloat = info.bFloat
+	this.bMipMapsGenerated = false
+	this.iWidth = iWidth
+	this.iHeight = iHeight
+	this.iBPP = info.iBPP
+	this.tuUsage = TEXTURE_USAGE_DATA
+	return nil
+}
+
+/*-----------------------------------------------
+
+  Name:	CFramebuffer
+
+  Result:	Offscreen render target with any number
+  		of colour attachments and optional depth
+  		or depth-stencil one. Multisampled ones
+  		render into renderbuffers and Resolve copies
+  		them into textures.
+
+  /*---------------------------------------------*/
+
+type CFramebuffer struct {
+	uiFramebuffer uint32
+	iWidth        int32
+	iHeight       int32
+	iSamples      int32 // 0 when not multisampled
+
+	vColorFormats []uint32
+	iDepthFormat  uint32 // 0 for no depth attachment
+	bDepthTexture bool   // Depth can be sampled after rendering, otherwise it's a renderbuffer
+
+	vColorTextures      []CTexture
+	tDepthTexture       CTexture
+	uiDepthRenderbuffer uint32
+
+	// Multisampled framebuffer renders here and is resolved into textures of uiResolveFramebuffer
+	vColorRenderbuffers  []uint32
+	uiResolveFramebuffer uint32
+
+	bFollowViewport bool
+	fViewportScale  float32
+}
+
+func NewCFramebuffer() *CFramebuffer {
+	this := CFramebuffer{}
+	this.fViewportScale = 1
+	return &this
+}
+
+// Framebuffers resized along with window viewport
+var vViewportFramebuffers []*CFramebuffer
+
+/*-----------------------------------------------
+
+  Name:	CreateFramebuffer
+
+  Params:	iWidth, iHeight - size of attachments
+  		vColorFormats - internal format of each
+  		colour attachment, e.g. gl.RGBA16F
+  		iDepthFormat - depth or depth-stencil
+  		format, 0 for none
+  		bDepthTexture - make depth sampleable
+  		iSamples - MSAA samples, 0 or 1 for none
+
+  Result:	Creates framebuffer and checks it's
+  		complete, error says what's wrong.
+
+  /*---------------------------------------------*/
+
+func (this *CFramebuffer) CreateFramebuffer(iWidth, iHeight int32, vColorFormats []uint32, iDepthFormat uint32, bDepthTexture bool, iSamples int32) error {
+	if iWidth <= 0 || iHeight <= 0 {
+		return fmt.Errorf("framebuffer size %dx%d isn't valid", iWidth, iHeight)
+	}
+	if len(vColorFormats) == 0 && iDepthFormat == 0 {
+		return errors.New("framebuffer needs at least one attachment")
+	}
+	caps := GetGLCapabilities()
+	if len(vColorFormats) > caps.GetMaxColorAttachments() {
+		return fmt.Errorf("framebuffer has %d colour attachments, driver allows %d", len(vColorFormats), caps.GetMaxColorAttachments())
+	}
+	for _, iFormat := range vColorFormats {
+		info, err := getRenderFormatInfo(iFormat)
+		if err != nil {
+			return err
+		}
+		if info.bDepth || info.bStencil {
+			return fmt.Errorf("%s is a depth or stencil format, it can't be colour attachment", info.sName)
+		}
+	}
+	if iDepthFormat != 0 {
+		info, err := getRenderFormatInfo(iDepthFormat)
+		if err != nil {
+			return err
+		}
+		if !info.bDepth {
+			return fmt.Errorf("%s isn't a depth or depth-stencil format", info.sName)
+		}
+	}
+	if iSamples <= 1 {
+		iSamples = 0
+	} else if iSamples > int32(caps.GetMaxSamples()) {
+		fmt.Printf("Framebuffer wants %d samples, driver allows %d\n", iSamples, caps.GetMaxSamples())
+		iSamples = int32(caps.GetMaxSamples())
+	}
+
+	this.DeleteAttachments()
+	this.iWidth, this.iHeight, this.iSamples = iWidth, iHeight, iSamples
+	this.vColorFormats = append([]uint32(nil), vColorFormats...)
+	this.iDepthFormat = iDepthFormat
+	this.bDepthTexture = bDepthTexture
+	return this.createAttachments()
+}
+
+func getDepthAttachmentPoint(iDepthFormat uint32) uint32 {
+	info, _ := getRenderFormatInfo(iDepthFormat)
+	if info.bStencil {
+		return gl.DEPTH_STENCIL_ATTACHMENT
+	}
+	return gl.DEPTH_ATTACHMENT
+}
+
+// createAttachments creates GL objects for current size and formats
+func (this *CFramebuffer) createAttachments() error {
+	gl.GenFramebuffers(1, &this.uiFramebuffer)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, this.uiFramebuffer)
+	defer gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	this.vColorTextures = make([]CTexture, len(this.vColorFormats))
+	if this.iSamples > 0 {
+		// Renderbuffers are rendered to, textures live in resolve framebuffer
+		this.vColorRenderbuffers = make([]uint32, len(this.vColorFormats))
+		for i, iFormat := range this.vColorFormats {
+			gl.GenRenderbuffers(1, &this.vColorRenderbuffers[i])
+			gl.BindRenderbuffer(gl.RENDERBUFFER, this.vColorRenderbuffers[i])
+			gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, this.iSamples, iFormat, this.iWidth, this.iHeight)
+			gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0+uint32(i), gl.RENDERBUFFER, this.vColorRenderbuffers[i])
+		}
+		if this.iDepthFormat != 0 {
+			gl.GenRenderbuffers(1, &this.uiDepthRenderbuffer)
+			gl.BindRenderbuffer(gl.RENDERBUFFER, this.uiDepthRenderbuffer)
+			gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, this.iSamples, this.iDepthFormat, this.iWidth, this.iHeight)
+			gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, getDepthAttachmentPoint(this.iDepthFormat), gl.RENDERBUFFER, this.uiDepthRenderbuffer)
+		}
+		this.setDrawBuffers()
+		if err := checkFramebufferStatus("multisampled framebuffer"); err != nil {
+			return err
+		}
+
+		gl.GenFramebuffers(1, &this.uiResolveFramebuffer)
+		gl.BindFramebuffer(gl.FRAMEBUFFER, this.uiResolveFramebuffer)
+	}
+
+	for i, iFormat := range this.vColorFormats {
+		if err := this.vColorTextures[i].CreateRenderTarget(this.iWidth, this.iHeight, iFormat); err != nil {
+			return err
+		}
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0+uint32(i), gl.TEXTURE_2D, this.vColorTextures[i].GetTextureID(), 0)
+	}
+	if this.iDepthFormat != 0 {
+		if this.bDepthTexture {
+			if err := this.tDepthTexture.CreateRenderTarget(this.iWidth, this.iHeight, this.iDepthFormat); err != nil {
+				return err
+			}
+			gl.FramebufferTexture2D(gl.FRAMEBUFFER, getDepthAttachmentPoint(this.iDepthFormat), gl.TEXTURE_2D, this.tDepthTexture.GetTextureID(), 0)
+		} else if this.iSamples == 0 {
+			gl.GenRenderbuffers(1, &this.uiDepthRenderbuffer)
+			gl.BindRenderbuffer(gl.RENDERBUFFER, this.uiDepthRenderbuffer)
+			gl.RenderbufferStorage(gl.RENDERBUFFER, this.iDepthFormat, this.iWidth, this.iHeight)
+			gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, getDepthAttachmentPoint(this.iDepthFormat), gl.RENDERBUFFER, this.uiDepthRenderbuffer)
+		}
+	}
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	this.setDrawBuffers()
+	if this.iSamples > 0 {
+		return checkFramebufferStatus("resolve framebuffer")
+	}
+	return checkFramebufferStatus("framebuffer")
+}
+
+// setDrawBuffers routes fragment outputs to colour attachments of bound framebuffer
+func (this *CFramebuffer) setDrawBuffers() {
+	if len(this.vColorFormats) == 0 {
+		gl.DrawBuffer(gl.NONE)
+		gl.ReadBuffer(gl.NONE)
+		return
+	}
+	vBuffers := make([]uint32, len(this.vColorFormats))
+	for i := range vBuffers {
+		vBuffers[i] = gl.COLOR_ATTACHMENT0 + uint32(i)
+	}
+	gl.DrawBuffers(int32(len(vBuffers)), &vBuffers[0])
+}
+
+/*-----------------------------------------------
+
+  Name:	checkFramebufferStatus
+
+  Params:	sWhat - name of framebuffer for message
+
+  Result:	Returns error explaining why bound
+  		framebuffer isn't complete, or nil.
+
+  /*---------------------------------------------*/
+
+func checkFramebufferStatus(sWhat string) error {
+	var sReason string
+	switch gl.CheckFramebufferStatus(gl.FRAMEBUFFER) {
+	case gl.FRAMEBUFFER_COMPLETE:
+		return nil
+	case gl.FRAMEBUFFER_UNDEFINED:
+		sReason = "default framebuffer doesn't exist"
+	case gl.FRAMEBUFFER_INCOMPLETE_ATTACHMENT:
+		sReason = "an attachment is incomplete, its size is zero or its format isn't renderable"
+	case gl.FRAMEBUFFER_INCOMPLETE_MISSING_ATTACHMENT:
+		sReason = "it has no attachments"
+	case gl.FRAMEBUFFER_INCOMPLETE_DRAW_BUFFER:
+		sReason = "a draw buffer points to missing attachment"
+	case gl.FRAMEBUFFER_INCOMPLETE_READ_BUFFER:
+		sReason = "read buffer points to missing attachment"
+	case gl.FRAMEBUFFER_UNSUPPORTED:
+		sReason = "driver doesn't support this combination of formats"
+	case gl.FRAMEBUFFER_INCOMPLETE_MULTISAMPLE:
+		sReason = "attachments have different sample counts"
+	case gl.FRAMEBUFFER_INCOMPLETE_LAYER_TARGETS:
+		sReason = "layered and non-layered attachments are mixed"
+	default:
+		sReason = "unknown status"
+	}
+	return fmt.Errorf("%s isn't complete: %s", sWhat, sReason)
+}
+
+/*-----------------------------------------------
+
+  Name:	BindFramebuffer
+
+  Params:	bSetViewport - set viewport to whole
+  		framebuffer
+
+  Result:	Makes framebuffer render target.
+
+  /*---------------------------------------------*/
+
+func (this *CFramebuffer) BindFramebuffer(bSetViewport bool) {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, this.uiFramebuffer)
+	if bSetViewport {
+		gl.Viewport(0, 0, this.iWidth, this.iHeight)
+	}
+}
+
+// BindDefaultFramebuffer makes window render target again, viewport is restored by ResizeOpenGLViewportFull.
+func BindDefaultFramebuffer() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+// Resolve copies multisampled attachments into textures, framebuffers without samples don't need it.
+func (this *CFramebuffer) Resolve() {
+	if this.iSamples == 0 {
+		return
+	}
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, this.uiFramebuffer)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, this.uiResolveFramebuffer)
+	// Colour attachments are blitted one by one, blit reads only one buffer
+	for i := range this.vColorFormats {
+		var iAttachment uint32 = gl.COLOR_ATTACHMENT0 + uint32(i)
+		gl.ReadBuffer(iAttachment)
+		gl.DrawBuffers(1, &iAttachment)
+		gl.BlitFramebuffer(0, 0, this.iWidth, this.iHeight, 0, 0, this.iWidth, this.iHeight, gl.COLOR_BUFFER_BIT, gl.NEAREST)
+	}
+	if this.iDepthFormat != 0 && this.bDepthTexture {
+		info, _ := getRenderFormatInfo(this.iDepthFormat)
+		var iMask uint32 = gl.DEPTH_BUFFER_BIT
+		if info.bStencil {
+			iMask |= gl.STENCIL_BUFFER_BIT
+		}
+		gl.BlitFramebuffer(0, 0, this.iWidth, this.iHeight, 0, 0, this.iWidth, this.iHeight, iMask, gl.NEAREST)
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, this.uiResolveFramebuffer)
+	this.setDrawBuffers()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+/*-----------------------------------------------
+
+  Name:	Resize
+
+  Params:	iWidth, iHeight - new size
+
+  Result:	Recreates attachments in new size, their
+  		content is lost. CTextures of attachments
+  		stay at the same addresses.
+
+  /*---------------------------------------------*/
+
+func (this *CFramebuffer) Resize(iWidth, iHeight int32) error {
+	if iWidth == this.iWidth && iHeight == this.iHeight {
+		return nil
+	}
+	if iWidth <= 0 || iHeight <= 0 {
+		return fmt.Errorf("framebuffer size %dx%d isn't valid", iWidth, iHeight)
+	}
+	this.releaseObjects()
+	this.iWidth, this.iHeight = iWidth, iHeight
+	return this.recreateTextures()
+}
+
+// recreateTextures is createAttachments, but keeps CTexture slice, so pointers from GetColorTexture stay valid
+func (this *CFramebuffer) recreateTextures() error {
+	vOldTextures := this.vColorTextures
+	err := this.createAttachments()
+	if len(vOldTextures) == len(this.vColorTextures) {
+		copy(vOldTextures, this.vColorTextures)
+		this.vColorTextures = vOldTextures
+	}
+	return err
+}
+
+/*-----------------------------------------------
+
+  Name:	FollowViewport
+
+  Params:	bFollow - resize with window viewport
+  		fScale - size relative to viewport, e.g.
+  		0.5 for half resolution effects
+
+  Result:	Framebuffer gets resized whenever
+  		ResizeOpenGLViewportFull changes size.
+
+  /*---------------------------------------------*/
+
+func (this *CFramebuffer) FollowViewport(bFollow bool, fScale float32) {
+	this.fViewportScale = fScale
+	if bFollow == this.bFollowViewport {
+		return
+	}
+	this.bFollowViewport = bFollow
+	if bFollow {
+		vViewportFramebuffers = append(vViewportFramebuffers, this)
+		return
+	}
+	for i, framebuffer := range vViewportFramebuffers {
+		if framebuffer == this {
+			vViewportFramebuffers = append(vViewportFramebuffers[:i], vViewportFramebuffers[i+1:]...)
+			break
+		}
+	}
+}
+
+// resizeViewportFramebuffers is called when window viewport changes
+func resizeViewportFramebuffers(iWidth, iHeight int32) {
+	for _, framebuffer := range vViewportFramebuffers {
+		if framebuffer.uiFramebuffer == 0 {
+			continue
+		}
+		iScaledWidth := int32(float32(iWidth)*framebuffer.fViewportScale + 0.5)
+		iScaledHeight := int32(float32(iHeight)*framebuffer.fViewportScale + 0.5)
+		if iScaledWidth < 1 {
+			iScaledWidth = 1
+		}
+		if iScaledHeight < 1 {
+			iScaledHeight = 1
+		}
+		if err := framebuffer.Resize(iScaledWidth, iScaledHeight); err != nil {
+			fmt.Println("Couldn't resize framebuffer:", err)
+		}
+	}
+}
+
+// GetColorTexture returns texture of colour attachment, resolved one for multisampled framebuffers.
+func (this *CFramebuffer) GetColorTexture(iIndex int) *CTexture {
+	if iIndex < 0 || iIndex >= len(this.vColorTextures) {
+		return nil
+	}
+	return &this.vColorTextures[iIndex]
+}
+
+// GetDepthTexture returns depth texture, nil if depth isn't sampleable.
+func (this *CFramebuffer) GetDepthTexture() *CTexture {
+	if this.iDepthFormat == 0 || !this.bDepthTexture {
+		return nil
+	}
+	return &this.tDepthTexture
+}
+
+func (this *CFramebuffer) GetNumColorAttachments() int {
+	return len(this.vColorFormats)
+}
+
+func (this *CFramebuffer) GetWidth() int32 {
+	return this.iWidth
+}
+
+func (this *CFramebuffer) GetHeight() int32 {
+	return this.iHeight
+}
+
+func (this *CFramebuffer) GetSamples() int32 {
+	return this.iSamples
+}
+
+func (this *CFramebuffer) GetFramebufferID() uint32 {
+	return this.uiFramebuffer
+}
+
+// releaseObjects deletes GL objects but keeps formats, so they can be created again
+func (this *CFramebuffer) releaseObjects() {
+	for i := range this.vColorTextures {
+		this.vColorTextures[i].DeleteTexture()
+	}
+	this.tDepthTexture.DeleteTexture()
+	if len(this.vColorRenderbuffers) > 0 {
+		gl.DeleteRenderbuffers(int32(len(this.vColorRenderbuffers)), &this.vColorRenderbuffers[0])
+		this.vColorRenderbuffers = nil
+	}
+	if this.uiDepthRenderbuffer != 0 {
+		gl.DeleteRenderbuffers(1, &this.uiDepthRenderbuffer)
+		this.uiDepthRenderbuffer = 0
+	}
+	if this.uiResolveFramebuffer != 0 {
+		gl.DeleteFramebuffers(1, &this.uiResolveFramebuffer)
+		this.uiResolveFramebuffer = 0
+	}
+	if this.uiFramebuffer != 0 {
+		gl.DeleteFramebuffers(1, &this.uiFramebuffer)
+		this.uiFramebuffer = 0
+	}
+}
+
+// DeleteAttachments deletes GL objects of framebuffer, it can be created again by CreateFramebuffer.
+func (this *CFramebuffer) DeleteAttachments() {
+	this.releaseObjects()
+	this.vColorTextures = nil
+}
+
+func (this *CFramebuffer) DeleteFramebuffer() {
+	this.FollowViewport(false, this.fViewportScale)
+	this.DeleteAttachments()
+	this.vColorFormats = nil
+	this.iDepthFormat = 0
+}
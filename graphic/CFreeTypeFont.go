@@ -1,6 +1,7 @@
 package graphic
 
 import (
+	"antry/libs"
 	"fmt"
 	"github.com/flopp/go-findfont"
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -8,7 +9,6 @@ import (
 	"github.com/golang/freetype/truetype"
 	"golang.org/x/image/font"
 	"log"
-	"os"
 	"unsafe"
 )
 
@@ -119,7 +119,7 @@ func (this *CFreeTypeFont) CreateChar(iIndex int) {
   /*---------------------------------------------*/
 
 func (this *CFreeTypeFont) LoadFont(sFile string, iPXSize float64) bool {
-	fontBytes, err := os.ReadFile(sFile)
+	fontBytes, err := libs.ReadAsset(sFile)
 	if err != nil {
 		log.Println(err)
 		return false
@@ -129,8 +129,8 @@ func (this *CFreeTypeFont) LoadFont(sFile string, iPXSize float64) bool {
 
 	this.iLoadedPixelSize = iPXSize
 
-	gl.GenVertexArrays(1, &uiVAO)
-	gl.BindVertexArray(uiVAO)
+	gl.GenVertexArrays(1, &this.uiVAO)
+	gl.BindVertexArray(this.uiVAO)
 	this.vboData = NewCVertexBufferObject()
 	this.vboData.CreateVBO(0)
 	this.vboData.BindVBO(gl.ARRAY_BUFFER)
@@ -211,7 +211,7 @@ func (this *CFreeTypeFont) Print(sText string, x, y, iPXSize int32) {
 		return
 	}
 
-	gl.BindVertexArray(uiVAO)
+	gl.BindVertexArray(this.uiVAO)
 	this.shShaderProgram.SetUniformI32("gSampler", 0)
 	gl.Enable(gl.BLEND)
 	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
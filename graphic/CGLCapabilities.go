@@ -0,0 +1,196 @@
+package graphic
+
+import (
+	"antry/libs"
+	"fmt"
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// CGLCapabilities describes what current OpenGL context can do, it's queried once a context exists
+type CGLCapabilities struct {
+	bQueried bool
+
+	iMajorVersion int
+	iMinorVersion int
+	sRenderer     string
+
+	iMaxArrayTextureLayers int
+	fMaxAnisotropy         float32 // 1 without anisotropic filtering
+	fMaxLODBias            float32
+	iMaxSamples            int
+	iMaxColorAttachments   int
+
+	mExtensions map[string]bool
+}
+
+var glCaps CGLCapabilities
+
+/*-----------------------------------------------
+
+  Name:	GetGLCapabilities
+
+  Params:	none
+
+  Result:	Returns capabilities of current context,
+  		querying them on first call.
+
+  /*---------------------------------------------*/
+
+func GetGLCapabilities() *CGLCapabilities {
+	if !glCaps.bQueried {
+		glCaps.query()
+	}
+	return &glCaps
+}
+
+func (this *CGLCapabilities) query() {
+	var iMajor, iMinor int32
+	gl.GetIntegerv(gl.MAJOR_VERSION, &iMajor)
+	gl.GetIntegerv(gl.MINOR_VERSION, &iMinor)
+	this.iMajorVersion = int(iMajor)
+	this.iMinorVersion = int(iMinor)
+	this.sRenderer = gl.GoStr(gl.GetString(gl.RENDERER))
+	var iMaxLayers int32
+	gl.GetIntegerv(gl.MAX_ARRAY_TEXTURE_LAYERS, &iMaxLayers)
+	this.iMaxArrayTextureLayers = int(iMaxLayers)
+	gl.GetFloatv(gl.MAX_TEXTURE_LOD_BIAS, &this.fMaxLODBias)
+	var iMaxSamples, iMaxColorAttachments, iMaxDrawBuffers int32
+	gl.GetIntegerv(gl.MAX_SAMPLES, &iMaxSamples)
+	gl.GetIntegerv(gl.MAX_COLOR_ATTACHMENTS, &iMaxColorAttachments)
+	gl.GetIntegerv(gl.MAX_DRAW_BUFFERS, &iMaxDrawBuffers)
+	this.iMaxSamples = int(iMaxSamples)
+	// All attachments are drawn to at once, so draw buffers limit them too
+	if iMaxDrawBuffers < iMaxColorAttachments {
+		iMaxColorAttachments = iMaxDrawBuffers
+	}
+	this.iMaxColorAttachments = int(iMaxColorAttachments)
+
+	this.mExtensions = make(map[string]bool)
+	var iNumExtensions int32
+	gl.GetIntegerv(gl.NUM_EXTENSIONS, &iNumExtensions)
+	for i := int32(0); i < iNumExtensions; i++ {
+		this.mExtensions[gl.GoStr(gl.GetStringi(gl.EXTENSIONS, uint32(i)))] = true
+	}
+	this.fMaxAnisotropy = 1
+	if this.SupportsAnisotropicFiltering() {
+		gl.GetFloatv(gl.MAX_TEXTURE_MAX_ANISOTROPY, &this.fMaxAnisotropy)
+	}
+	this.bQueried = true
+}
+
+func (this *CGLCapabilities) GetVersion() (int, int) {
+	return this.iMajorVersion, this.iMinorVersion
+}
+
+// IsVersionAtLeast tells whether context version is at least iMajor.iMinor.
+func (this *CGLCapabilities) IsVersionAtLeast(iMajor, iMinor int) bool {
+	return this.iMajorVersion > iMajor || (this.iMajorVersion == iMajor && this.iMinorVersion >= iMinor)
+}
+
+func (this *CGLCapabilities) HasExtension(sName string) bool {
+	return this.mExtensions[sName]
+}
+
+// GetMaxArrayTextureLayers returns how many layers a texture array can have, at least 256 since OpenGL 3.0.
+func (this *CGLCapabilities) GetMaxArrayTextureLayers() int {
+	return this.iMaxArrayTextureLayers
+}
+
+// GetMaxAnisotropy returns highest anisotropy samplers can use, 1 if anisotropic filtering isn't supported.
+func (this *CGLCapabilities) GetMaxAnisotropy() float32 {
+	return this.fMaxAnisotropy
+}
+
+func (this *CGLCapabilities) GetMaxLODBias() float32 {
+	return this.fMaxLODBias
+}
+
+// GetMaxSamples returns highest MSAA sample count of renderbuffers.
+func (this *CGLCapabilities) GetMaxSamples() int {
+	return this.iMaxSamples
+}
+
+// GetMaxColorAttachments returns how many colour attachments a framebuffer can have and draw to at once.
+func (this *CGLCapabilities) GetMaxColorAttachments() int {
+	return this.iMaxColorAttachments
+}
+
+// Support checks for features that are core since some version, or available through an extension before
+
+func (this *CGLCapabilities) SupportsGeometryShaders() bool {
+	return this.IsVersionAtLeast(3, 2) || this.HasExtension("GL_ARB_geometry_shader4")
+}
+
+func (this *CGLCapabilities) SupportsTessellation() bool {
+	return this.IsVersionAtLeast(4, 0) || this.HasExtension("GL_ARB_tessellation_shader")
+}
+
+func (this *CGLCapabilities) SupportsComputeShaders() bool {
+	return this.IsVersionAtLeast(4, 3) || this.HasExtension("GL_ARB_compute_shader")
+}
+
+func (this *CGLCapabilities) SupportsStorageBuffers() bool {
+	return this.IsVersionAtLeast(4, 3) || this.HasExtension("GL_ARB_shader_storage_buffer_object")
+}
+
+// SupportsAnisotropicFiltering checks for core 4.6 feature, ARB and EXT extensions use the same enums
+func (this *CGLCapabilities) SupportsAnisotropicFiltering() bool {
+	return this.IsVersionAtLeast(4, 6) || this.HasExtension("GL_ARB_texture_filter_anisotropic") ||
+		this.HasExtension("GL_EXT_texture_filter_anisotropic")
+}
+
+// SupportsTexelFormat tells whether textures can be uploaded and sampled in given format without decompression
+func (this *CGLCapabilities) SupportsTexelFormat(format libs.TexelFormat) bool {
+	switch format {
+	case libs.TEXEL_FORMAT_RGBA8:
+		return true
+	case libs.TEXEL_FORMAT_BC1, libs.TEXEL_FORMAT_BC2, libs.TEXEL_FORMAT_BC3:
+		// S3TC never became core because of patents, but every desktop driver has it
+		return this.HasExtension("GL_EXT_texture_compression_s3tc")
+	case libs.TEXEL_FORMAT_BC4, libs.TEXEL_FORMAT_BC4_SIGNED, libs.TEXEL_FORMAT_BC5, libs.TEXEL_FORMAT_BC5_SIGNED:
+		return this.IsVersionAtLeast(3, 0) || this.HasExtension("GL_ARB_texture_compression_rgtc")
+	case libs.TEXEL_FORMAT_BC6H, libs.TEXEL_FORMAT_BC6H_SIGNED, libs.TEXEL_FORMAT_BC7:
+		return this.IsVersionAtLeast(4, 2) || this.HasExtension("GL_ARB_texture_compression_bptc")
+	}
+	return false
+}
+
+/*-----------------------------------------------
+
+  Name:	CheckShaderStage
+
+  Params:	iType - GL_VERTEX_SHADER, GL_COMPUTE_SHADER...
+
+  Result:	Returns error describing why the stage
+  		can't be used with this context, or nil.
+
+  /*---------------------------------------------*/
+
+func (this *CGLCapabilities) CheckShaderStage(iType uint32) error {
+	var bSupported bool = true
+	var sRequirement string
+	switch iType {
+	case gl.VERTEX_SHADER, gl.FRAGMENT_SHADER:
+	case gl.GEOMETRY_SHADER:
+		bSupported, sRequirement = this.SupportsGeometryShaders(), "OpenGL 3.2"
+	case gl.TESS_CONTROL_SHADER, gl.TESS_EVALUATION_SHADER:
+		bSupported, sRequirement = this.SupportsTessellation(), "OpenGL 4.0 or GL_ARB_tessellation_shader"
+	case gl.COMPUTE_SHADER:
+		bSupported, sRequirement = this.SupportsComputeShaders(), "OpenGL 4.3 or GL_ARB_compute_shader"
+	default:
+		return fmt.Errorf("unknown shader stage 0x%X", iType)
+	}
+	if !bSupported {
+		return fmt.Errorf("%s shaders need %s, but context is OpenGL %d.%d (%s)", GetShaderStageName(iType), sRequirement,
+			this.iMajorVersion, this.iMinorVersion, this.sRenderer)
+	}
+	return nil
+}
+
+func (this *CGLCapabilities) CheckStorageBuffers() error {
+	if !this.SupportsStorageBuffers() {
+		return fmt.Errorf("shader storage buffers need OpenGL 4.3 or GL_ARB_shader_storage_buffer_object, but context is OpenGL %d.%d (%s)",
+			this.iMajorVersion, this.iMinorVersion, this.sRenderer)
+	}
+	return nil
+}
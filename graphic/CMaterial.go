@@ -0,0 +1,316 @@
+package graphic
+
+import (
+	"encoding/binary"
+	"github.com/bloeys/assimp-go/asig"
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+)
+
+// ETextureSlot is kind of texture material has, slot is also texture unit it's bound to
+type ETextureSlot int
+
+const (
+	TEXTURE_SLOT_DIFFUSE  ETextureSlot = iota
+	TEXTURE_SLOT_NORMAL                // Tangent-space normal map
+	TEXTURE_SLOT_SPECULAR              // Specular intensity in red channel
+	TEXTURE_SLOT_EMISSIVE
+	NUM_TEXTURE_SLOTS
+)
+
+// Sampler and flag uniforms of slots in main shader, diffuse sampler is always used
+var sTextureSlotSamplers = [NUM_TEXTURE_SLOTS]string{"gSampler", "normalSampler", "specularSampler", "emissiveSampler"}
+var sTextureSlotFlags = [NUM_TEXTURE_SLOTS]string{"", "bNormalMap", "bSpecularMap", "bEmissiveMap"}
+
+// GetUsage tells how texture of slot is sampled, colours are sRGB and the rest is data.
+func (this ETextureSlot) GetUsage() ETextureUsage {
+	if this == TEXTURE_SLOT_NORMAL || this == TEXTURE_SLOT_SPECULAR {
+		return TEXTURE_USAGE_DATA
+	}
+	return TEXTURE_USAGE_COLOR
+}
+
+// assimp texture types read into each slot
+var assimpTextureTypes = [NUM_TEXTURE_SLOTS]asig.TextureType{asig.TextureTypeDiffuse, asig.TextureTypeNormal,
+	asig.TextureTypeSpecular, asig.TextureTypeEmissive}
+
+/*-----------------------------------------------
+
+  Name:	CMaterial
+
+  Result:	Surface of model's meshes. Colours multiply
+  		textures of their slots, opacity below one
+  		is blended and alpha test discards texels
+  		under the cutoff.
+
+  /*---------------------------------------------*/
+
+type CMaterial struct {
+	sName          string
+	vDiffuseColor  mgl32.Vec3
+	vSpecularColor mgl32.Vec3
+	vEmissiveColor mgl32.Vec3
+	fShininess     float32 // Blinn-Phong exponent, roughness is computed from it
+	fOpacity       float32
+	bDoubleSided   bool
+	bAlphaTest     bool
+	fAlphaCutoff   float32
+	vTextures      [NUM_TEXTURE_SLOTS]*CTexture // Nil for empty slots
+}
+
+func NewCMaterial() *CMaterial {
+	this := CMaterial{}
+	this.vDiffuseColor = mgl32.Vec3{1.0, 1.0, 1.0}
+	this.fShininess = 32.0
+	this.fOpacity = 1.0
+	this.fAlphaCutoff = 0.5
+	return &this
+}
+
+// Material of objects that set none, white without highlights
+var mtDefault *CMaterial = NewCMaterial()
+
+func (this *CMaterial) GetName() string {
+	return this.sName
+}
+
+func (this *CMaterial) SetDiffuseColor(a_vColor mgl32.Vec3) {
+	this.vDiffuseColor = a_vColor
+}
+
+func (this *CMaterial) GetDiffuseColor() mgl32.Vec3 {
+	return this.vDiffuseColor
+}
+
+func (this *CMaterial) SetSpecularColor(a_vColor mgl32.Vec3) {
+	this.vSpecularColor = a_vColor
+}
+
+func (this *CMaterial) GetSpecularColor() mgl32.Vec3 {
+	return this.vSpecularColor
+}
+
+func (this *CMaterial) SetEmissiveColor(a_vColor mgl32.Vec3) {
+	this.vEmissiveColor = a_vColor
+}
+
+func (this *CMaterial) GetEmissiveColor() mgl32.Vec3 {
+	return this.vEmissiveColor
+}
+
+func (this *CMaterial) SetShininess(a_fShininess float32) {
+	this.fShininess = float32(math.Max(float64(a_fShininess), 1.0))
+}
+
+func (this *CMaterial) GetShininess() float32 {
+	return this.fShininess
+}
+
+// SetRoughness sets shininess matching roughness from 0 to 1, exponent is 2/a^2-2 where a is roughness squared.
+func (this *CMaterial) SetRoughness(a_fRoughness float32) {
+	fRoughness := math.Min(math.Max(float64(a_fRoughness), 0.01), 1.0)
+	this.SetShininess(float32(2.0/(fRoughness*fRoughness*fRoughness*fRoughness) - 2.0))
+}
+
+func (this *CMaterial) GetRoughness() float32 {
+	return float32(math.Pow(2.0/(float64(this.fShininess)+2.0), 0.25))
+}
+
+// SetOpacity sets opacity from 0 to 1, materials below one are blended.
+func (this *CMaterial) SetOpacity(a_fOpacity float32) {
+	this.fOpacity = float32(math.Min(math.Max(float64(a_fOpacity), 0.0), 1.0))
+}
+
+func (this *CMaterial) GetOpacity() float32 {
+	return this.fOpacity
+}
+
+func (this *CMaterial) IsTransparent() bool {
+	return this.fOpacity < 1.0
+}
+
+// SetDoubleSided turns off back face culling for meshes with material.
+func (this *CMaterial) SetDoubleSided(bDoubleSided bool) {
+	this.bDoubleSided = bDoubleSided
+}
+
+func (this *CMaterial) IsDoubleSided() bool {
+	return this.bDoubleSided
+}
+
+// SetAlphaTest makes shader discard texels with alpha below fCutoff.
+func (this *CMaterial) SetAlphaTest(bAlphaTest bool, fCutoff float32) {
+	this.bAlphaTest = bAlphaTest
+	this.fAlphaCutoff = fCutoff
+}
+
+func (this *CMaterial) IsAlphaTested() bool {
+	return this.bAlphaTest
+}
+
+func (this *CMaterial) GetAlphaCutoff() float32 {
+	return this.fAlphaCutoff
+}
+
+// SetTexture puts texture into slot, nil empties it. Caller keeps ownership of texture.
+func (this *CMaterial) SetTexture(slot ETextureSlot, texture *CTexture) {
+	this.vTextures[slot] = texture
+}
+
+func (this *CMaterial) GetTexture(slot ETextureSlot) *CTexture {
+	return this.vTextures[slot]
+}
+
+/*-----------------------------------------------
+
+  Name:	SetUniformData
+
+  Params:	spProgram - shader program
+  		sMaterialVarName - name of material variable
+
+  Result:	Sets all material data and binds its
+  		textures to units of their slots.
+
+  /*---------------------------------------------*/
+
+func (this *CMaterial) SetUniformData(spProgram *CShaderProgram, sMaterialVarName string) {
+	spProgram.SetUniformV4(sMaterialVarName+".vDiffuseColor", this.vDiffuseColor.Vec4(this.fOpacity))
+	spProgram.SetUniformV3(sMaterialVarName+".vSpecularColor", this.vSpecularColor)
+	spProgram.SetUniformV3(sMaterialVarName+".vEmissiveColor", this.vEmissiveColor)
+	spProgram.SetUniformF32(sMaterialVarName+".fShininess", this.fShininess)
+	var iAlphaTest int32 = 0
+	if this.bAlphaTest {
+		iAlphaTest = 1
+	}
+	spProgram.SetUniformI32(sMaterialVarName+".bAlphaTest", iAlphaTest)
+	spProgram.SetUniformF32(sMaterialVarName+".fAlphaCutoff", this.fAlphaCutoff)
+
+	for iSlot := TEXTURE_SLOT_DIFFUSE; iSlot < NUM_TEXTURE_SLOTS; iSlot++ {
+		texture := this.vTextures[iSlot]
+		if texture != nil {
+			texture.BindTexture(uint32(iSlot))
+		} else if iSlot == TEXTURE_SLOT_DIFFUSE {
+			gl.ActiveTexture(gl.TEXTURE0)
+			gl.BindTexture(gl.TEXTURE_2D, 0)
+		}
+		if iSlot != TEXTURE_SLOT_DIFFUSE {
+			var iHasTexture int32 = 0
+			if texture != nil {
+				iHasTexture = 1
+			}
+			spProgram.SetUniformI32(sTextureSlotSamplers[iSlot], int32(iSlot))
+			spProgram.SetUniformI32(sTextureSlotFlags[iSlot], iHasTexture)
+		}
+	}
+}
+
+/*-----------------------------------------------
+
+  Name:	importAssimpMaterial
+
+  Params:	material - assimp material
+  		bWavefront - material comes from OBJ file
+
+  Result:	Reads colours, shininess, opacity and
+  		flags of material. Textures are loaded
+  		later from paths of its slots.
+
+  /*---------------------------------------------*/
+
+func importAssimpMaterial(material *asig.Material, bWavefront bool) CMaterial {
+	result := *NewCMaterial()
+	for _, property := range material.Properties {
+		// Texture properties share keys, only material-wide values are read
+		if property.Semantic != asig.TextureTypeNone {
+			continue
+		}
+		switch property.Key() {
+		case "?mat.name":
+			result.sName = readAssimpString(property)
+		case "$clr.diffuse":
+			if vColor, bOK := readAssimpColor(property); bOK {
+				result.vDiffuseColor = vColor
+			}
+		case "$clr.specular":
+			if vColor, bOK := readAssimpColor(property); bOK {
+				result.vSpecularColor = vColor
+			}
+		case "$clr.emissive":
+			if vColor, bOK := readAssimpColor(property); bOK {
+				result.vEmissiveColor = vColor
+			}
+		case "$mat.shininess":
+			if fValue, bOK := readAssimpFloat(property); bOK {
+				result.SetShininess(fValue)
+			}
+		case "$mat.roughnessFactor":
+			if fValue, bOK := readAssimpFloat(property); bOK {
+				result.SetRoughness(fValue)
+			}
+		case "$mat.opacity":
+			if fValue, bOK := readAssimpFloat(property); bOK {
+				result.SetOpacity(fValue)
+			}
+		case "$mat.twosided":
+			if fValue, bOK := readAssimpFloat(property); bOK {
+				result.bDoubleSided = fValue != 0
+			}
+		}
+	}
+	// Opacity map means cut-out surface like leaves or fur, its alpha is tested instead of blended
+	bOpacityMap := asig.GetMaterialTextureCount(material, asig.TextureTypeOpacity) > 0
+	if bOpacityMap {
+		result.bAlphaTest = true
+	}
+	// Some exporters write "d 0" for opacity they didn't set. Only illum 4 and up make OBJ materials
+	// transparent, assimp doesn't pass illum on, so invisible material without opacity map is taken as opaque
+	if bWavefront && result.fOpacity == 0 && !bOpacityMap {
+		result.fOpacity = 1.0
+	}
+	// Maps multiply colours, formats without colour of the slot still use the map
+	if result.vSpecularColor == (mgl32.Vec3{}) && asig.GetMaterialTextureCount(material, asig.TextureTypeSpecular) > 0 {
+		result.vSpecularColor = mgl32.Vec3{1.0, 1.0, 1.0}
+	}
+	if result.vEmissiveColor == (mgl32.Vec3{}) && asig.GetMaterialTextureCount(material, asig.TextureTypeEmissive) > 0 {
+		result.vEmissiveColor = mgl32.Vec3{1.0, 1.0, 1.0}
+	}
+	return result
+}
+
+// readAssimpFloat reads first number of property stored as float, double or int
+func readAssimpFloat(property *asig.MaterialProperty) (float32, bool) {
+	switch {
+	case property.TypeInfo == asig.MatPropTypeInfoFloat32 && len(property.Data) >= 4:
+		return math.Float32frombits(binary.LittleEndian.Uint32(property.Data)), true
+	case property.TypeInfo == asig.MatPropTypeInfoFloat64 && len(property.Data) >= 8:
+		return float32(math.Float64frombits(binary.LittleEndian.Uint64(property.Data))), true
+	case property.TypeInfo == asig.MatPropTypeInfoInt32 && len(property.Data) >= 4:
+		return float32(int32(binary.LittleEndian.Uint32(property.Data))), true
+	}
+	return 0, false
+}
+
+// readAssimpColor reads RGB of colour stored as three or four floats
+func readAssimpColor(property *asig.MaterialProperty) (mgl32.Vec3, bool) {
+	var vColor mgl32.Vec3
+	if property.TypeInfo != asig.MatPropTypeInfoFloat32 || len(property.Data) < 12 {
+		return vColor, false
+	}
+	for c := range vColor {
+		vColor[c] = math.Float32frombits(binary.LittleEndian.Uint32(property.Data[4*c:]))
+	}
+	return vColor, true
+}
+
+// readAssimpString reads aiString, which is 32-bit length followed by characters
+func readAssimpString(property *asig.MaterialProperty) string {
+	if property.TypeInfo != asig.MatPropTypeInfoString || len(property.Data) < 4 {
+		return ""
+	}
+	iLength := int(binary.LittleEndian.Uint32(property.Data))
+	if iLength > len(property.Data)-4 {
+		iLength = len(property.Data) - 4
+	}
+	return string(property.Data[4 : 4+iLength])
+}
@@ -0,0 +1,605 @@
+package graphic
+
+import (
+	"antry/libs"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-gl/mathgl/mgl32"
+	"hash/crc32"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Cached mesh files start with this magic, followed by format version, source hash, payload length and CRC32 of payload
+const MESH_CACHE_MAGIC = "AMSH"
+
+// Bump whenever payload layout or imported data changes, older entries are rebuilt then
+const MESH_CACHE_VERSION uint32 = 3
+
+const meshCacheHeaderSize = len(MESH_CACHE_MAGIC) + 4 + sha256.Size + 8
+
+// Extensions of files assimp imports that are worth caching, glTF has its own fast loader
+var vMeshCacheExtensions = []string{".obj", ".3ds", ".fbx", ".dae", ".blend", ".ply", ".stl", ".x", ".ms3d", ".lwo", ".ase", ".md2", ".md5mesh", ".off"}
+
+/*-----------------------------------------------
+
+  Name:	CMeshCache
+
+  Result:	Stores imported models in compact binary
+  		files, so assimp runs only the first time.
+  		Entries remember hash of source files and
+  		are rebuilt when it changes.
+
+  /*---------------------------------------------*/
+
+type CMeshCache struct {
+	sDirectory string
+	bEnabled   bool
+}
+
+var mcMeshCache = CMeshCache{sDirectory: filepath.Join("cache", "meshes"), bEnabled: true}
+
+func SetMeshCacheDirectory(sDirectory string) {
+	mcMeshCache.sDirectory = sDirectory
+}
+
+func EnableMeshCache(bEnabled bool) {
+	mcMeshCache.bEnabled = bEnabled
+}
+
+// ClearMeshCache deletes all cached meshes, so every model is imported again on next load.
+func ClearMeshCache() error {
+	vFiles, err := filepath.Glob(filepath.Join(mcMeshCache.sDirectory, "*.mesh"))
+	if err != nil {
+		return err
+	}
+	for _, sFile := range vFiles {
+		if err := os.Remove(sFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsMeshCacheable tells whether model file is imported through assimp and so goes to mesh cache.
+func IsMeshCacheable(sPath string) bool {
+	if libs.IsGLTFFile(sPath) {
+		return false
+	}
+	sExtension := strings.ToLower(filepath.Ext(sPath))
+	for _, sCacheable := range vMeshCacheExtensions {
+		if sExtension == sCacheable {
+			return true
+		}
+	}
+	return false
+}
+
+// BakeMeshCache imports model into mesh cache unless it already has valid entry, bForce imports it anyway. Returns whether entry was written.
+func BakeMeshCache(sPath string, bForce bool) (bool, error) {
+	vHash, err := getModelSourceHash(sPath)
+	if err != nil {
+		return false, err
+	}
+	if !bForce {
+		if _, err := mcMeshCache.LoadModelData(sPath, vHash); err == nil {
+			return false, nil
+		}
+	}
+	data, err := ImportAssimpModelData(sPath)
+	if err != nil {
+		return false, err
+	}
+	if err := mcMeshCache.SaveModelData(data, vHash); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (this *CMeshCache) getPath(sModelPath string) string {
+	vKey := sha256.Sum256([]byte(CanonicalTexturePath(sModelPath)))
+	return filepath.Join(this.sDirectory, hex.EncodeToString(vKey[:])+".mesh")
+}
+
+// readModelSource reads file the way ImportModelData finds it, absolute paths from disk and the rest from asset VFS
+func readModelSource(sPath string) ([]byte, error) {
+	if filepath.IsAbs(sPath) {
+		return os.ReadFile(sPath)
+	}
+	return libs.GetAssetVFS().ReadFile(sPath)
+}
+
+/*-----------------------------------------------
+
+  Name:	getModelSourceHash
+
+  Params:	sPath - model file
+
+  Result:	Hashes model file together with material
+  		libraries OBJ model references, missing
+  		libraries count as empty.
+
+  /*---------------------------------------------*/
+
+func getModelSourceHash(sPath string) ([sha256.Size]byte, error) {
+	var vHash [sha256.Size]byte
+	bModel, err := readModelSource(sPath)
+	if err != nil {
+		return vHash, err
+	}
+	hHash := sha256.New()
+	fmt.Fprintf(hHash, "%d\x00", len(bModel))
+	hHash.Write(bModel)
+	if strings.EqualFold(filepath.Ext(sPath), ".obj") {
+		scanner := bufio.NewScanner(bytes.NewReader(bModel))
+		for scanner.Scan() {
+			sLine := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(sLine, "mtllib ") {
+				continue
+			}
+			sLibrary := strings.TrimSpace(strings.TrimPrefix(sLine, "mtllib "))
+			bLibrary, _ := readModelSource(getModelTexturePath(sPath, sLibrary))
+			fmt.Fprintf(hHash, "%s\x00%d\x00", sLibrary, len(bLibrary))
+			hHash.Write(bLibrary)
+		}
+	}
+	copy(vHash[:], hHash.Sum(nil))
+	return vHash, nil
+}
+
+/*-----------------------------------------------
+
+  Name:	ImportModelData
+
+  Params:	sPath - model imported through assimp
+
+  Result:	Returns model from cache if its entry is
+  		valid for current source files, otherwise
+  		imports it and writes new entry. Broken
+  		and stale entries are replaced.
+
+  /*---------------------------------------------*/
+
+func (this *CMeshCache) ImportModelData(sPath string) (*CModelData, error) {
+	if !this.bEnabled {
+		return ImportAssimpModelData(sPath)
+	}
+	vHash, err := getModelSourceHash(sPath)
+	if err != nil {
+		return ImportAssimpModelData(sPath)
+	}
+	data, err := this.LoadModelData(sPath, vHash)
+	if err == nil {
+		return data, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("Mesh cache entry of %s is rebuilt: %v\n", sPath, err)
+	}
+	if data, err = ImportAssimpModelData(sPath); err != nil {
+		return nil, err
+	}
+	if err := this.SaveModelData(data, vHash); err != nil {
+		fmt.Println("Couldn't write mesh cache entry:", err)
+	}
+	return data, nil
+}
+
+// LoadModelData reads cached model, it fails if entry is missing, broken or made from other source or format version.
+func (this *CMeshCache) LoadModelData(sPath string, vSourceHash [sha256.Size]byte) (*CModelData, error) {
+	bData, err := os.ReadFile(this.getPath(sPath))
+	if err != nil {
+		return nil, err
+	}
+	if len(bData) < meshCacheHeaderSize || string(bData[:len(MESH_CACHE_MAGIC)]) != MESH_CACHE_MAGIC {
+		return nil, errors.New("bad header")
+	}
+	bHeader := bData[len(MESH_CACHE_MAGIC):]
+	if iVersion := binary.LittleEndian.Uint32(bHeader); iVersion != MESH_CACHE_VERSION {
+		return nil, fmt.Errorf("version %d, current is %d", iVersion, MESH_CACHE_VERSION)
+	}
+	if !bytes.Equal(bHeader[4:4+sha256.Size], vSourceHash[:]) {
+		return nil, errors.New("source files changed")
+	}
+	iLength := binary.LittleEndian.Uint32(bHeader[4+sha256.Size:])
+	iChecksum := binary.LittleEndian.Uint32(bHeader[8+sha256.Size:])
+	bPayload := bData[meshCacheHeaderSize:]
+	if int(iLength) != len(bPayload) {
+		return nil, errors.New("truncated payload")
+	}
+	if crc32.ChecksumIEEE(bPayload) != iChecksum {
+		return nil, errors.New("checksum mismatch")
+	}
+	return decodeModelData(sPath, bPayload)
+}
+
+// SaveModelData writes model as cache entry of its path, temporary file is renamed so readers never see half of it.
+func (this *CMeshCache) SaveModelData(data *CModelData, vSourceHash [sha256.Size]byte) error {
+	bPayload := encodeModelData(data)
+	var bufFile bytes.Buffer
+	bufFile.WriteString(MESH_CACHE_MAGIC)
+	binary.Write(&bufFile, binary.LittleEndian, MESH_CACHE_VERSION)
+	bufFile.Write(vSourceHash[:])
+	binary.Write(&bufFile, binary.LittleEndian, uint32(len(bPayload)))
+	binary.Write(&bufFile, binary.LittleEndian, crc32.ChecksumIEEE(bPayload))
+	bufFile.Write(bPayload)
+
+	if err := os.MkdirAll(this.sDirectory, 0755); err != nil {
+		return err
+	}
+	fileTmp, err := os.CreateTemp(this.sDirectory, "*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = fileTmp.Write(bufFile.Bytes())
+	if errClose := fileTmp.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(fileTmp.Name(), this.getPath(data.sPath))
+	}
+	if err != nil {
+		os.Remove(fileTmp.Name())
+	}
+	return err
+}
+
+// meshCacheWriter appends little endian values to payload
+type meshCacheWriter struct {
+	bData []byte
+}
+
+func (this *meshCacheWriter) writeU32(iValue uint32) {
+	this.bData = binary.LittleEndian.AppendUint32(this.bData, iValue)
+}
+
+func (this *meshCacheWriter) writeI32(iValue int32) {
+	this.writeU32(uint32(iValue))
+}
+
+func (this *meshCacheWriter) writeF32(vValues ...float32) {
+	for _, fValue := range vValues {
+		this.writeU32(math.Float32bits(fValue))
+	}
+}
+
+func (this *meshCacheWriter) writeBytes(bValue []byte) {
+	this.writeU32(uint32(len(bValue)))
+	this.bData = append(this.bData, bValue...)
+}
+
+func (this *meshCacheWriter) writeString(sValue string) {
+	this.writeBytes([]byte(sValue))
+}
+
+func (this *meshCacheWriter) writeF32Slice(vValues []float32) {
+	this.writeU32(uint32(len(vValues)))
+	this.writeF32(vValues...)
+}
+
+func (this *meshCacheWriter) writeBounds(box libs.AABB, sphere libs.Sphere) {
+	this.writeF32(box.Min[:]...)
+	this.writeF32(box.Max[:]...)
+	this.writeF32(sphere.Center[:]...)
+	this.writeF32(sphere.Radius)
+}
+
+// meshCacheReader reads payload back, the first error sticks and later reads give zeros
+type meshCacheReader struct {
+	bData []byte
+	iPos  int
+	err   error
+}
+
+func (this *meshCacheReader) take(iSize int) []byte {
+	if this.err != nil {
+		return nil
+	}
+	if iSize < 0 || iSize > len(this.bData)-this.iPos {
+		this.err = errors.New("payload ends too early")
+		return nil
+	}
+	bResult := this.bData[this.iPos : this.iPos+iSize]
+	this.iPos += iSize
+	return bResult
+}
+
+func (this *meshCacheReader) readU32() uint32 {
+	if bValue := this.take(4); bValue != nil {
+		return binary.LittleEndian.Uint32(bValue)
+	}
+	return 0
+}
+
+func (this *meshCacheReader) readI32() int32 {
+	return int32(this.readU32())
+}
+
+func (this *meshCacheReader) readF32() float32 {
+	return math.Float32frombits(this.readU32())
+}
+
+func (this *meshCacheReader) readVec3() mgl32.Vec3 {
+	return mgl32.Vec3{this.readF32(), this.readF32(), this.readF32()}
+}
+
+// readCount reads number of items, each taking at least iItemSize bytes, so broken counts don't allocate much
+func (this *meshCacheReader) readCount(iItemSize int) int {
+	iCount := int(this.readU32())
+	if this.err == nil && iCount*iItemSize > len(this.bData)-this.iPos {
+		this.err = errors.New("count exceeds payload")
+		return 0
+	}
+	return iCount
+}
+
+func (this *meshCacheReader) readBytes() []byte {
+	return append([]byte(nil), this.take(this.readCount(1))...)
+}
+
+func (this *meshCacheReader) readString() string {
+	return string(this.take(this.readCount(1)))
+}
+
+func (this *meshCacheReader) readF32Slice() []float32 {
+	vValues := make([]float32, this.readCount(4))
+	for i := range vValues {
+		vValues[i] = this.readF32()
+	}
+	return vValues
+}
+
+func (this *meshCacheReader) readBounds() (libs.AABB, libs.Sphere) {
+	var box libs.AABB
+	var sphere libs.Sphere
+	box.Min = this.readVec3()
+	box.Max = this.readVec3()
+	sphere.Center = this.readVec3()
+	sphere.Radius = this.readF32()
+	return box, sphere
+}
+
+/*-----------------------------------------------
+
+  Name:	encodeModelData
+
+  Params:	data - imported model
+
+  Result:	Returns payload of cache entry: vertex
+  		and index buffers, mesh table with bounds,
+  		material table, skin data, skeleton, skins
+  		and animation clips.
+
+  /*---------------------------------------------*/
+
+func encodeModelData(data *CModelData) []byte {
+	var writer meshCacheWriter
+	writer.writeU32(MODEL_VERTEX_SIZE)
+	writer.writeU32(MODEL_SKIN_VERTEX_SIZE)
+	writer.writeU32(uint32(NUM_TEXTURE_SLOTS))
+	writer.writeBytes(data.bVertexData)
+	writer.writeU32(uint32(len(data.vIndices)))
+	for _, iIndex := range data.vIndices {
+		writer.writeU32(iIndex)
+	}
+
+	writer.writeU32(uint32(len(data.iMeshSizes)))
+	for i := range data.iMeshSizes {
+		writer.writeI32(data.iMeshStartIndices[i])
+		writer.writeI32(data.iMeshSizes[i])
+		writer.writeI32(data.iMeshFirstVertices[i])
+		writer.writeI32(data.iMeshNumVertices[i])
+		writer.writeU32(uint32(data.iMaterialIndices[i]))
+		writer.writeI32(int32(data.iMeshSkins[i]))
+		writer.writeBounds(data.vMeshBoxes[i], data.vMeshSpheres[i])
+	}
+	writer.writeBounds(data.boxModel, data.sphereModel)
+
+	writer.writeU32(uint32(len(data.vMaterials)))
+	for i := range data.vMaterials {
+		material := &data.vMaterials[i]
+		writer.writeString(material.sName)
+		writer.writeF32(material.vDiffuseColor[:]...)
+		writer.writeF32(material.vSpecularColor[:]...)
+		writer.writeF32(material.vEmissiveColor[:]...)
+		writer.writeF32(material.fShininess, material.fOpacity, material.fAlphaCutoff)
+		var iFlags uint32
+		if material.bDoubleSided {
+			iFlags |= 1
+		}
+		if material.bAlphaTest {
+			iFlags |= 2
+		}
+		writer.writeU32(iFlags)
+		for _, sTexturePath := range data.vTexturePaths[i] {
+			writer.writeString(sTexturePath)
+		}
+	}
+
+	writer.writeBytes(data.bSkinData)
+	var vNodes []libs.SkeletonNode
+	if data.skeleton != nil {
+		vNodes = data.skeleton.Nodes
+	}
+	writer.writeU32(uint32(len(vNodes)))
+	for _, node := range vNodes {
+		writer.writeString(node.Name)
+		writer.writeI32(int32(node.Parent))
+		writer.writeF32(node.RestPose.Translation[:]...)
+		writer.writeF32(node.RestPose.Rotation.W)
+		writer.writeF32(node.RestPose.Rotation.V[:]...)
+		writer.writeF32(node.RestPose.Scale[:]...)
+	}
+	writer.writeU32(uint32(len(data.vSkins)))
+	for _, skin := range data.vSkins {
+		writer.writeString(skin.Name)
+		writer.writeU32(uint32(len(skin.Joints)))
+		for i, iJoint := range skin.Joints {
+			writer.writeI32(int32(iJoint))
+			writer.writeF32(skin.InverseBind[i][:]...)
+		}
+	}
+	writer.writeU32(uint32(len(data.vAnimations)))
+	for _, clip := range data.vAnimations {
+		writer.writeString(clip.Name)
+		writer.writeU32(uint32(len(clip.Channels)))
+		for _, channel := range clip.Channels {
+			writer.writeI32(int32(channel.Node))
+			writer.writeU32(uint32(channel.Path))
+			writer.writeU32(uint32(channel.Interpolation))
+			writer.writeF32Slice(channel.Times)
+			writer.writeF32Slice(channel.Values)
+		}
+	}
+	return writer.bData
+}
+
+// decodeModelData builds model from payload written by encodeModelData
+func decodeModelData(sPath string, bPayload []byte) (*CModelData, error) {
+	reader := &meshCacheReader{bData: bPayload}
+	if reader.readU32() != MODEL_VERTEX_SIZE || reader.readU32() != MODEL_SKIN_VERTEX_SIZE || reader.readU32() != uint32(NUM_TEXTURE_SLOTS) {
+		return nil, errors.New("different vertex layout")
+	}
+	data := &CModelData{sPath: sPath}
+	data.bVertexData = reader.readBytes()
+	data.vIndices = make([]uint32, reader.readCount(4))
+	for i := range data.vIndices {
+		data.vIndices[i] = reader.readU32()
+	}
+
+	iNumMeshes := reader.readCount(24 + 40)
+	for i := 0; i < iNumMeshes; i++ {
+		data.iMeshStartIndices = append(data.iMeshStartIndices, reader.readI32())
+		data.iMeshSizes = append(data.iMeshSizes, reader.readI32())
+		data.iMeshFirstVertices = append(data.iMeshFirstVertices, reader.readI32())
+		data.iMeshNumVertices = append(data.iMeshNumVertices, reader.readI32())
+		data.iMaterialIndices = append(data.iMaterialIndices, uint(reader.readU32()))
+		data.iMeshSkins = append(data.iMeshSkins, int(reader.readI32()))
+		box, sphere := reader.readBounds()
+		data.vMeshBoxes = append(data.vMeshBoxes, box)
+		data.vMeshSpheres = append(data.vMeshSpheres, sphere)
+	}
+	data.boxModel, data.sphereModel = reader.readBounds()
+
+	iNumMaterials := reader.readCount(4 + 52 + 4*int(NUM_TEXTURE_SLOTS))
+	data.vTexturePaths = make([][NUM_TEXTURE_SLOTS]string, iNumMaterials)
+	data.vTextureData = make([][NUM_TEXTURE_SLOTS]*libs.TextureData, iNumMaterials)
+	for i := 0; i < iNumMaterials; i++ {
+		material := *NewCMaterial()
+		material.sName = reader.readString()
+		material.vDiffuseColor = reader.readVec3()
+		material.vSpecularColor = reader.readVec3()
+		material.vEmissiveColor = reader.readVec3()
+		material.fShininess = reader.readF32()
+		material.fOpacity = reader.readF32()
+		material.fAlphaCutoff = reader.readF32()
+		iFlags := reader.readU32()
+		material.bDoubleSided = iFlags&1 != 0
+		material.bAlphaTest = iFlags&2 != 0
+		data.vMaterials = append(data.vMaterials, material)
+		for iSlot := range data.vTexturePaths[i] {
+			data.vTexturePaths[i][iSlot] = reader.readString()
+		}
+	}
+
+	data.bSkinData = reader.readBytes()
+	if len(data.bSkinData) == 0 {
+		data.bSkinData = nil
+	}
+	vNodes := make([]libs.SkeletonNode, reader.readCount(8+40))
+	for i := range vNodes {
+		vNodes[i].Name = reader.readString()
+		vNodes[i].Parent = int(reader.readI32())
+		vNodes[i].RestPose.Translation = reader.readVec3()
+		vNodes[i].RestPose.Rotation.W = reader.readF32()
+		vNodes[i].RestPose.Rotation.V = reader.readVec3()
+		vNodes[i].RestPose.Scale = reader.readVec3()
+	}
+	data.vSkins = make([]libs.Skin, reader.readCount(8))
+	for i := range data.vSkins {
+		data.vSkins[i].Name = reader.readString()
+		for j, iNumJoints := 0, reader.readCount(4+64); j < iNumJoints; j++ {
+			data.vSkins[i].Joints = append(data.vSkins[i].Joints, int(reader.readI32()))
+			var mInverseBind mgl32.Mat4
+			for k := range mInverseBind {
+				mInverseBind[k] = reader.readF32()
+			}
+			data.vSkins[i].InverseBind = append(data.vSkins[i].InverseBind, mInverseBind)
+		}
+	}
+	iNumClips := reader.readCount(8)
+	for i := 0; i < iNumClips && reader.err == nil; i++ {
+		sName := reader.readString()
+		vChannels := make([]libs.AnimationChannel, reader.readCount(20))
+		for j := range vChannels {
+			vChannels[j].Node = int(reader.readI32())
+			vChannels[j].Path = libs.EChannelPath(reader.readU32())
+			vChannels[j].Interpolation = libs.EInterpolation(reader.readU32())
+			vChannels[j].Times = reader.readF32Slice()
+			vChannels[j].Values = reader.readF32Slice()
+		}
+		if reader.err != nil {
+			break
+		}
+		clip, err := libs.NewAnimationClip(sName, vChannels)
+		if err != nil {
+			return nil, err
+		}
+		data.vAnimations = append(data.vAnimations, clip)
+	}
+	if reader.err != nil {
+		return nil, reader.err
+	}
+	if reader.iPos != len(bPayload) {
+		return nil, errors.New("unexpected data after payload")
+	}
+
+	if len(vNodes) > 0 {
+		var err error
+		if data.skeleton, err = libs.NewSkeleton(vNodes); err != nil {
+			return nil, err
+		}
+	}
+	return data, data.validate()
+}
+
+// validate checks that mesh table points inside buffers, so broken entry can't make draws read past them
+func (this *CModelData) validate() error {
+	iNumVertices := len(this.bVertexData) / MODEL_VERTEX_SIZE
+	if len(this.bVertexData)%MODEL_VERTEX_SIZE != 0 {
+		return errors.New("vertex data isn't whole vertices")
+	}
+	if this.bSkinData != nil && len(this.bSkinData) != iNumVertices*MODEL_SKIN_VERTEX_SIZE {
+		return errors.New("skin data doesn't match vertices")
+	}
+	for _, iIndex := range this.vIndices {
+		if int(iIndex) >= iNumVertices {
+			return fmt.Errorf("index %d out of %d vertices", iIndex, iNumVertices)
+		}
+	}
+	for i := range this.iMeshSizes {
+		if this.iMeshStartIndices[i] < 0 || this.iMeshSizes[i] < 0 || int(this.iMeshStartIndices[i]+this.iMeshSizes[i]) > len(this.vIndices) {
+			return fmt.Errorf("mesh %d indices out of range", i)
+		}
+		if this.iMeshFirstVertices[i] < 0 || this.iMeshNumVertices[i] < 0 || int(this.iMeshFirstVertices[i]+this.iMeshNumVertices[i]) > iNumVertices {
+			return fmt.Errorf("mesh %d vertices out of range", i)
+		}
+		if this.iMeshSkins[i] >= len(this.vSkins) || (this.iMeshSkins[i] >= 0 && this.skeleton == nil) {
+			return fmt.Errorf("mesh %d has invalid skin", i)
+		}
+	}
+	for _, skin := range this.vSkins {
+		for _, iJoint := range skin.Joints {
+			if this.skeleton == nil || iJoint < 0 || iJoint >= this.skeleton.GetNumNodes() {
+				return fmt.Errorf("skin %s has invalid joint", skin.Name)
+			}
+		}
+	}
+	return nil
+}
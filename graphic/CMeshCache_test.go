@@ -0,0 +1,186 @@
+package graphic
+
+import (
+	"antry/libs"
+	"crypto/sha256"
+	"encoding/binary"
+	"github.com/go-gl/mathgl/mgl32"
+	"os"
+	"reflect"
+	"testing"
+)
+
+/*-----------------------------------------------
+
+  Name:	newCachedModel
+
+  Params:	none
+
+  Result:	Skinned triangle with one material, two
+  		node skeleton and one clip. Every part of
+  		payload has something in it.
+
+  /*---------------------------------------------*/
+
+func newCachedModel(t *testing.T) *CModelData {
+	data := &CModelData{sPath: "models/triangle.fbx"}
+	data.bVertexData = make([]byte, 3*MODEL_VERTEX_SIZE)
+	for i := range data.bVertexData {
+		data.bVertexData[i] = byte(i * 7)
+	}
+	data.vIndices = []uint32{0, 2, 1}
+	data.iMeshStartIndices = []int32{0}
+	data.iMeshSizes = []int32{3}
+	data.iMeshFirstVertices = []int32{0}
+	data.iMeshNumVertices = []int32{3}
+	data.iMaterialIndices = []uint{0}
+	data.iMeshSkins = []int{0}
+	box := libs.AABBFromPoints([]mgl32.Vec3{{-1, 0, 0}, {1, 2, 0.5}})
+	data.vMeshBoxes = []libs.AABB{box}
+	data.vMeshSpheres = []libs.Sphere{{Center: box.GetCenter(), Radius: box.GetHalfSize().Len()}}
+	data.boxModel, data.sphereModel = data.vMeshBoxes[0], data.vMeshSpheres[0]
+
+	material := *NewCMaterial()
+	material.sName = "skin"
+	material.vDiffuseColor = mgl32.Vec3{0.5, 0.25, 1}
+	material.vEmissiveColor = mgl32.Vec3{0, 0, 2}
+	material.fOpacity = 0.75
+	material.bDoubleSided = true
+	material.bAlphaTest = true
+	data.vMaterials = []CMaterial{material}
+	data.vTexturePaths = [][NUM_TEXTURE_SLOTS]string{{"models/skin.png", "", "", "models/glow.png"}}
+	data.vTextureData = make([][NUM_TEXTURE_SLOTS]*libs.TextureData, 1)
+
+	for i := 0; i < 3; i++ {
+		var skin libs.VertexSkin
+		skin.AddWeight(i%2, 0.75)
+		skin.AddWeight(1-i%2, 0.25)
+		data.bSkinData = appendVertexSkin(data.bSkinData, &skin)
+	}
+	pose := libs.IdentityPose()
+	pose.Translation = mgl32.Vec3{0, 1, 0}
+	var err error
+	if data.skeleton, err = libs.NewSkeleton([]libs.SkeletonNode{{Name: "root", Parent: -1, RestPose: libs.IdentityPose()},
+		{Name: "arm", Parent: 0, RestPose: pose}}); err != nil {
+		t.Fatal(err)
+	}
+	data.vSkins = []libs.Skin{{Name: "body", Joints: []int{0, 1}, InverseBind: []mgl32.Mat4{mgl32.Ident4(), mgl32.Translate3D(0, -1, 0)}}}
+	clip, err := libs.NewAnimationClip("wave", []libs.AnimationChannel{{Node: 1, Path: libs.CHANNEL_ROTATION,
+		Interpolation: libs.INTERPOLATION_LINEAR, Times: []float32{0, 0.5}, Values: []float32{0, 0, 0, 1, 0, 0, 1, 0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data.vAnimations = []*libs.AnimationClip{clip}
+	return data
+}
+
+func TestMeshCachePayloadRoundTrip(t *testing.T) {
+	unskinned := newCachedModel(t)
+	unskinned.bSkinData, unskinned.skeleton, unskinned.vAnimations = nil, nil, nil
+	unskinned.iMeshSkins = []int{-1}
+	// Decoded skin list is empty rather than nil
+	unskinned.vSkins = []libs.Skin{}
+
+	for _, data := range []*CModelData{newCachedModel(t), unskinned} {
+		decoded, err := decodeModelData(data.sPath, encodeModelData(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Texture data is never cached, decoded model has empty slots for it
+		data.vTextureData = make([][NUM_TEXTURE_SLOTS]*libs.TextureData, len(data.vMaterials))
+		if !reflect.DeepEqual(decoded, data) {
+			t.Fatalf("decoded model differs:\n%+v\nwant\n%+v", decoded, data)
+		}
+	}
+}
+
+func TestMeshCachePayloadTruncated(t *testing.T) {
+	bPayload := encodeModelData(newCachedModel(t))
+	for iLength := 0; iLength < len(bPayload); iLength++ {
+		if _, err := decodeModelData("truncated", bPayload[:iLength]); err == nil {
+			t.Fatalf("payload cut to %d of %d bytes was decoded", iLength, len(bPayload))
+		}
+	}
+}
+
+func TestMeshCachePayloadCorrupted(t *testing.T) {
+	vCases := []struct {
+		sName    string
+		fnChange func(data *CModelData)
+	}{
+		{"index past vertices", func(data *CModelData) { data.vIndices[1] = 3 }},
+		{"mesh past indices", func(data *CModelData) { data.iMeshSizes[0] = 4 }},
+		{"negative mesh start", func(data *CModelData) { data.iMeshStartIndices[0] = -1 }},
+		{"mesh past vertices", func(data *CModelData) { data.iMeshNumVertices[0] = 4 }},
+		{"partial vertex", func(data *CModelData) { data.bVertexData = data.bVertexData[:len(data.bVertexData)-1] }},
+		{"skin data size", func(data *CModelData) { data.bSkinData = data.bSkinData[:MODEL_SKIN_VERTEX_SIZE] }},
+		{"missing skin", func(data *CModelData) { data.iMeshSkins[0] = 1 }},
+		{"joint past skeleton", func(data *CModelData) { data.vSkins[0].Joints[1] = 2 }},
+		{"parent after child", func(data *CModelData) { data.skeleton.Nodes[0].Parent = 1 }},
+		{"clip values", func(data *CModelData) { data.vAnimations[0].Channels[0].Values = []float32{0, 0, 0, 1} }},
+	}
+	for _, testCase := range vCases {
+		data := newCachedModel(t)
+		testCase.fnChange(data)
+		if _, err := decodeModelData(testCase.sName, encodeModelData(data)); err == nil {
+			t.Errorf("%s: payload was decoded", testCase.sName)
+		}
+	}
+
+	// Vertex size, vertex data length and index count come first, huge counts must fail before allocating
+	bPayload := encodeModelData(newCachedModel(t))
+	vRawCases := []struct {
+		sName   string
+		iOffset int
+		iValue  uint32
+	}{
+		{"vertex layout", 0, MODEL_VERTEX_SIZE + 4},
+		{"vertex data length", 12, 0xFFFFFFFF},
+		{"index count", 16 + 3*MODEL_VERTEX_SIZE, 0x7FFFFFFF},
+	}
+	for _, testCase := range vRawCases {
+		bCorrupted := append([]byte(nil), bPayload...)
+		binary.LittleEndian.PutUint32(bCorrupted[testCase.iOffset:], testCase.iValue)
+		if _, err := decodeModelData(testCase.sName, bCorrupted); err == nil {
+			t.Errorf("%s: payload was decoded", testCase.sName)
+		}
+	}
+	if _, err := decodeModelData("trailing", append(bPayload, 0)); err == nil {
+		t.Error("payload with trailing byte was decoded")
+	}
+}
+
+func TestMeshCacheEntry(t *testing.T) {
+	cache := &CMeshCache{sDirectory: t.TempDir(), bEnabled: true}
+	data := newCachedModel(t)
+	vHash := sha256.Sum256([]byte("source"))
+	if err := cache.SaveModelData(data, vHash); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.LoadModelData(data.sPath, vHash); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.LoadModelData(data.sPath, sha256.Sum256([]byte("changed source"))); err == nil {
+		t.Error("entry of other source was loaded")
+	}
+
+	sPath := cache.getPath(data.sPath)
+	bEntry, err := os.ReadFile(sPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vCorrupted := map[string][]byte{
+		"flipped payload byte": append(append([]byte(nil), bEntry[:len(bEntry)-5]...), append([]byte{bEntry[len(bEntry)-5] ^ 1}, bEntry[len(bEntry)-4:]...)...),
+		"truncated entry":      bEntry[:len(bEntry)-1],
+		"other version":        append(append([]byte(MESH_CACHE_MAGIC), 0xFF, 0xFF, 0xFF, 0xFF), bEntry[len(MESH_CACHE_MAGIC)+4:]...),
+		"bad magic":            append([]byte("XMSH"), bEntry[len(MESH_CACHE_MAGIC):]...),
+	}
+	for sName, bCorrupted := range vCorrupted {
+		if err := os.WriteFile(sPath, bCorrupted, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cache.LoadModelData(data.sPath, vHash); err == nil {
+			t.Errorf("%s: entry was loaded", sName)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package graphic
 
 import (
+	"antry/libs"
 	"fmt"
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
@@ -8,12 +9,14 @@ import (
 	"image"
 	"image/color"
 	_ "image/png"
-	"os"
 	"unsafe"
 )
 
 const NUMTERRAINSHADERS = 3
 
+// Must match MAX_TERRAIN_LAYERS in terrain.frag
+const MAX_TERRAIN_LAYERS = 32
+
 type CMultiLayeredHeightmap struct {
 	uiVAO uint32
 
@@ -24,6 +27,9 @@ type CMultiLayeredHeightmap struct {
 
 	vRenderScale mgl32.Vec3
 
+	vLayerRanges []mgl32.Vec2 // Relative heights where each layer of texture array is used alone
+	iPathLayer   int32        // Layer of texture array with path texture
+
 	vboHeightmapData    *CVertexBufferObject
 	vboHeightmapIndices *CVertexBufferObject
 }
@@ -31,9 +37,27 @@ type CMultiLayeredHeightmap struct {
 var spTerrain CShaderProgram
 var shTerrainShaders [NUMTERRAINSHADERS]CShader
 
+// Whether terrain samples its layers from one texture array instead of separate textures in gSampler[0..4]
+var bTerrainTextureArray bool = false
+
+// Same look as the separate textures have, layers 0, 1, 2 by height and path texture as layer 3
+var vDefaultTerrainLayerRanges = []mgl32.Vec2{{0.0, 0.15}, {0.3, 0.65}, {0.85, 1.0}}
+
+const DEFAULT_TERRAIN_PATH_LAYER = 3
+
+// EnableTerrainTextureArray chooses terrain shader variant, it must be called before LoadTerrainShaderProgram.
+func EnableTerrainTextureArray(bEnabled bool) {
+	bTerrainTextureArray = bEnabled
+}
+
+func IsTerrainTextureArrayEnabled() bool {
+	return bTerrainTextureArray
+}
+
 func NewCMultiLayeredHeightmap() *CMultiLayeredHeightmap {
 	this := CMultiLayeredHeightmap{}
 	this.vRenderScale = mgl32.Vec3{1.0, 1.0, 1.0}
+	this.iPathLayer = DEFAULT_TERRAIN_PATH_LAYER
 	return &this
 }
 
@@ -49,8 +73,9 @@ func (this *CMultiLayeredHeightmap) LoadHeightMapFromImage(sImagePath string) bo
 		this.bLoaded = false
 		this.ReleaseHeightmap()
 	}
-	f, err := os.Open(sImagePath)
+	f, err := libs.OpenAsset(sImagePath)
 	if err != nil {
+		fmt.Println("Couldn't open heightmap", sImagePath+":", err)
 		return false
 	}
 	defer f.Close()
@@ -240,8 +265,8 @@ func (this *CMultiLayeredHeightmap) LoadHeightMapFromImage(sImagePath string) bo
 		this.vboHeightmapIndices.AddData(EncodeToBytes(iPrimitiveRestartIndex), int32(unsafe.Sizeof(int32(0))))
 	}
 
-	gl.GenVertexArrays(1, &uiVAO)
-	gl.BindVertexArray(uiVAO)
+	gl.GenVertexArrays(1, &this.uiVAO)
+	gl.BindVertexArray(this.uiVAO)
 	// Attach vertex data to this VAO
 	this.vboHeightmapData.BindVBO(gl.ARRAY_BUFFER)
 	this.vboHeightmapData.UploadDataToGPU(gl.STATIC_DRAW)
@@ -267,18 +292,57 @@ func (this *CMultiLayeredHeightmap) LoadHeightMapFromImage(sImagePath string) bo
 	return true
 }
 func LoadTerrainShaderProgram() bool {
-	bOK := true
-	bOK = bOK && shShaders[0].LoadShader("data\\shaders\\terrain.vert", gl.VERTEX_SHADER)
-	bOK = bOK && shShaders[1].LoadShader("data\\shaders\\terrain.frag", gl.FRAGMENT_SHADER)
-	bOK = bOK && shShaders[2].LoadShader("data\\shaders\\dirLight.frag", gl.FRAGMENT_SHADER)
-
-	spTerrain.CreateProgram()
+	var desc *CShaderProgramDesc = getShaderProgramDesc("terrain")
+	if bTerrainTextureArray {
+		desc = getShaderProgramDesc("terrain_array")
+	}
+	var vFiles []string = desc.Files
 	for i := 0; i < NUMTERRAINSHADERS; i++ {
-		spTerrain.AddShaderToProgram(&shShaders[i])
+		if !shTerrainShaders[i].ReadShaderWithDefines("data/shaders/"+vFiles[i], GetShaderTypeFromFile(vFiles[i]), desc.Defines) {
+			return false
+		}
+	}
+
+	return spTerrain.LinkProgramCached(findShaderByName(shTerrainShaders[:], vFiles, vFiles))
+}
+
+/*-----------------------------------------------
+
+  Name:	SetLayerRanges
+
+  Params:	vRanges - for every layer of texture array,
+  		from lowest to highest, relative heights
+  		(0..1) where it's used alone
+  		iPathLayer - layer with path texture
+
+  Result:	Sets how texture array layers are spread
+  		over terrain, between two ranges neighbour
+  		layers are blended.
+
+  /*---------------------------------------------*/
+
+func (this *CMultiLayeredHeightmap) SetLayerRanges(vRanges []mgl32.Vec2, iPathLayer int32) bool {
+	if len(vRanges) == 0 || len(vRanges) > MAX_TERRAIN_LAYERS {
+		fmt.Printf("Terrain must have 1 to %d layers\n", MAX_TERRAIN_LAYERS)
+		return false
+	}
+	this.vLayerRanges = append([]mgl32.Vec2(nil), vRanges...)
+	this.iPathLayer = iPathLayer
+	return true
+}
+
+func (this *CMultiLayeredHeightmap) GetLayerRanges() []mgl32.Vec2 {
+	if this.vLayerRanges == nil {
+		return vDefaultTerrainLayerRanges
 	}
-	spTerrain.LinkProgram()
+	return this.vLayerRanges
+}
 
-	return bOK
+func (this *CMultiLayeredHeightmap) GetPathLayer() int32 {
+	if this.vLayerRanges == nil {
+		return DEFAULT_TERRAIN_PATH_LAYER
+	}
+	return this.iPathLayer
 }
 
 func (this *CMultiLayeredHeightmap) SetRenderSize3(fRenderX, fHeight, fRenderZ float32) {
@@ -288,6 +352,13 @@ func (this *CMultiLayeredHeightmap) SetRenderSize3(fRenderX, fHeight, fRenderZ f
 func (this *CMultiLayeredHeightmap) SetRenderSize(fQuadSize, fHeight float32) {
 	this.vRenderScale = mgl32.Vec3{float32(this.iCols) * fQuadSize, fHeight, float32(this.iRows) * fQuadSize}
 }
+
+// GetBoundingBox returns box terrain fills at its render size, it's centered at origin and rises from zero.
+func (this *CMultiLayeredHeightmap) GetBoundingBox() libs.AABB {
+	vHalf := mgl32.Vec3{this.vRenderScale.X() * 0.5, 0, this.vRenderScale.Z() * 0.5}
+	return libs.AABB{Min: mgl32.Vec3{-vHalf.X(), 0, -vHalf.Z()}, Max: mgl32.Vec3{vHalf.X(), this.vRenderScale.Y(), vHalf.Z()}}
+}
+
 func (this *CMultiLayeredHeightmap) RenderHeightmap() {
 	spTerrain.UseProgram()
 
@@ -296,9 +367,15 @@ func (this *CMultiLayeredHeightmap) RenderHeightmap() {
 	spTerrain.SetUniformF32("fMaxTextureV", float32(this.iRows)*float32(0.1))
 
 	spTerrain.SetUniformM4("HeightmapScaleMatrix", mgl32.Scale3D(this.vRenderScale.X(), this.vRenderScale.Y(), this.vRenderScale.Z()))
+	if bTerrainTextureArray {
+		vRanges := this.GetLayerRanges()
+		spTerrain.SetUniformI32("iNumLayers", int32(len(vRanges)))
+		spTerrain.SetUniformV2N("vLayerRanges", &vRanges[0], int32(len(vRanges)))
+		spTerrain.SetUniformI32("iPathLayer", this.GetPathLayer())
+	}
 
 	// Now we're ready to render - we are drawing set of triangle strips using one call, but we g otta enable primitive restart
-	gl.BindVertexArray(uiVAO)
+	gl.BindVertexArray(this.uiVAO)
 	gl.Enable(gl.PRIMITIVE_RESTART)
 	gl.PrimitiveRestartIndex(uint32(this.iRows * this.iCols))
 
@@ -311,7 +388,7 @@ func (this *CMultiLayeredHeightmap) ReleaseHeightmap() {
 	}
 	this.vboHeightmapData.DeleteVBO()
 	this.vboHeightmapIndices.DeleteVBO()
-	gl.DeleteVertexArrays(1, &uiVAO)
+	gl.DeleteVertexArrays(1, &this.uiVAO)
 	this.bLoaded = false
 }
 func GetShaderProgram() *CShaderProgram {
@@ -320,7 +397,7 @@ func GetShaderProgram() *CShaderProgram {
 func ReleaseTerrainShaderProgram() {
 	spTerrain.DeleteProgram()
 	for i := 0; i < NUMTERRAINSHADERS; i++ {
-		shShaders[i].DeleteShader()
+		shTerrainShaders[i].DeleteShader()
 	}
 }
 func (this *CMultiLayeredHeightmap) GetNumHeightmapRows() int {
@@ -12,6 +12,20 @@ import (
 var bClassRegistered bool = false
 var bGlewInitialized bool = false
 
+// Whether window framebuffer should encode linear shader output to sRGB
+var bSRGBFramebufferRequested bool = false
+var bSRGBFramebuffer bool = false
+
+// RequestSRGBFramebuffer must be called before OpenGL is initialized, colour textures are sRGB only if the request succeeds.
+func RequestSRGBFramebuffer(bEnabled bool) {
+	bSRGBFramebufferRequested = bEnabled
+}
+
+// IsSRGBFramebuffer tells whether default framebuffer encodes to sRGB, so shaders work with linear colours.
+func IsSRGBFramebuffer() bool {
+	return bSRGBFramebuffer
+}
+
 type Greeting func()
 type GreetingEx func(oglControl *COpenGLControl)
 type COpenGLControl struct {
@@ -70,6 +84,9 @@ func (this *COpenGLControl) InitGLEW() bool {
 	}
 
 	this.RegisterSimpleOpenGLClass()
+	if bSRGBFramebufferRequested {
+		sdl.GLSetAttribute(sdl.GL_FRAMEBUFFER_SRGB_CAPABLE, 1)
+	}
 	var err error
 	this.SdlWindow, err = sdl.CreateWindow(SIMPLE_OPENGL_CLASS_NAME, sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED,
 		800, 600, sdl.WINDOW_OPENGL) //sdl.WINDOW_SHOWN) //"FAKE", WS_OVERLAPPEDWINDOW | WS_MAXIMIZE | WS_CLIPCHILDREN,
@@ -85,6 +102,7 @@ func (this *COpenGLControl) InitGLEW() bool {
 		fmt.Println("无法初始化OpenGL:", err)
 		return false
 	}
+	this.initSRGBFramebuffer()
 
 	//this.hDC = GetDC(hWndFake);
 
@@ -132,6 +150,23 @@ func (this *COpenGLControl) InitGLEW() bool {
 	return true
 }
 
+// initSRGBFramebuffer enables sRGB encoding if requested and the window got sRGB capable framebuffer
+func (this *COpenGLControl) initSRGBFramebuffer() {
+	bSRGBFramebuffer = false
+	if !bSRGBFramebufferRequested {
+		gl.Disable(gl.FRAMEBUFFER_SRGB)
+		return
+	}
+	var iEncoding int32
+	gl.GetFramebufferAttachmentParameteriv(gl.FRAMEBUFFER, gl.BACK_LEFT, gl.FRAMEBUFFER_ATTACHMENT_COLOR_ENCODING, &iEncoding)
+	if iEncoding != gl.SRGB {
+		fmt.Println("Window framebuffer isn't sRGB capable, colour textures stay linear")
+		return
+	}
+	gl.Enable(gl.FRAMEBUFFER_SRGB)
+	bSRGBFramebuffer = true
+}
+
 /*-----------------------------------------------
 
   Name:	InitOpenGL
@@ -268,6 +303,7 @@ func (this *COpenGLControl) ResizeOpenGLViewportFull() {
 	gl.Viewport(0, 0, w, h)
 	this.iViewportWidth = w
 	this.iViewportHeight = h
+	resizeViewportFramebuffers(w, h)
 }
 
 /*-----------------------------------------------
@@ -0,0 +1,224 @@
+package graphic
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"unsafe"
+)
+
+// Cached program files start with this magic, followed by binary format, payload length and CRC32 of payload
+const PROGRAM_CACHE_MAGIC = "APB1"
+
+type CProgramCache struct {
+	sDirectory string
+	bEnabled   bool
+
+	bChecked   bool   // Whether driver support was already queried
+	bSupported bool   // Whether driver reports at least one program binary format
+	sDriverID  string // Vendor, renderer and version strings, binaries are only valid for the same driver
+}
+
+var pcProgramCache = CProgramCache{sDirectory: filepath.Join("cache", "programs"), bEnabled: true}
+
+/*-----------------------------------------------
+
+  Name:	SetProgramCacheDirectory
+
+  Params:	sDirectory - where linked program binaries
+  		are stored
+
+  Result:	Changes program binary cache location.
+
+  /*---------------------------------------------*/
+
+func SetProgramCacheDirectory(sDirectory string) {
+	pcProgramCache.sDirectory = sDirectory
+}
+
+func EnableProgramCache(bEnabled bool) {
+	pcProgramCache.bEnabled = bEnabled
+}
+
+/*-----------------------------------------------
+
+  Name:	ClearProgramCache
+
+  Params:	none
+
+  Result:	Deletes all cached program binaries, so
+  		every program is compiled from source on
+  		next start.
+
+  /*---------------------------------------------*/
+
+func ClearProgramCache() error {
+	vFiles, err := filepath.Glob(filepath.Join(pcProgramCache.sDirectory, "*.bin"))
+	if err != nil {
+		return err
+	}
+	for _, sFile := range vFiles {
+		if err := os.Remove(sFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *CProgramCache) isUsable() bool {
+	if !this.bEnabled {
+		return false
+	}
+	if !this.bChecked {
+		this.bChecked = true
+		var iNumFormats int32
+		gl.GetIntegerv(gl.NUM_PROGRAM_BINARY_FORMATS, &iNumFormats)
+		this.bSupported = iNumFormats > 0
+		this.sDriverID = gl.GoStr(gl.GetString(gl.VENDOR)) + "\n" + gl.GoStr(gl.GetString(gl.RENDERER)) + "\n" +
+			gl.GoStr(gl.GetString(gl.VERSION)) + "\n" + gl.GoStr(gl.GetString(gl.SHADING_LANGUAGE_VERSION))
+	}
+	return this.bSupported
+}
+
+// GetKey hashes the driver strings together with type and preprocessed source of every shader stage.
+func (this *CProgramCache) GetKey(vShaders []*CShader) string {
+	hHash := sha256.New()
+	hHash.Write([]byte(this.sDriverID))
+	for _, shShader := range vShaders {
+		fmt.Fprintf(hHash, "\x00%d\x00%d\x00", shShader.GetShaderType(), len(shShader.GetSource()))
+		hHash.Write([]byte(shShader.GetSource()))
+	}
+	return hex.EncodeToString(hHash.Sum(nil))
+}
+
+func (this *CProgramCache) getPath(sKey string) string {
+	return filepath.Join(this.sDirectory, sKey+".bin")
+}
+
+// LoadProgram tries to restore program from the cache, returns false if there is no valid binary for it.
+func (this *CProgramCache) LoadProgram(uiProgram uint32, sKey string) bool {
+	bData, err := os.ReadFile(this.getPath(sKey))
+	if err != nil {
+		return false
+	}
+	iFormat, bBinary, err := this.decode(bData)
+	if err == nil {
+		gl.ProgramBinary(uiProgram, iFormat, unsafe.Pointer(&bBinary[0]), int32(len(bBinary)))
+		var iLinkStatus int32
+		gl.GetProgramiv(uiProgram, gl.LINK_STATUS, &iLinkStatus)
+		if iLinkStatus == gl.TRUE {
+			return true
+		}
+		err = errors.New("driver rejected the binary")
+	}
+	// Stale or broken entry, it gets replaced once the program is linked from source
+	fmt.Printf("Program cache entry %s is invalid: %v\n", sKey, err)
+	os.Remove(this.getPath(sKey))
+	return false
+}
+
+// SaveProgram stores binary of successfully linked program under given key.
+func (this *CProgramCache) SaveProgram(uiProgram uint32, sKey string) {
+	var iLength int32
+	gl.GetProgramiv(uiProgram, gl.PROGRAM_BINARY_LENGTH, &iLength)
+	if iLength <= 0 {
+		return
+	}
+	bBinary := make([]byte, iLength)
+	var iFormat uint32
+	gl.GetProgramBinary(uiProgram, iLength, &iLength, &iFormat, unsafe.Pointer(&bBinary[0]))
+	bBinary = bBinary[:iLength]
+
+	var bufFile bytes.Buffer
+	bufFile.WriteString(PROGRAM_CACHE_MAGIC)
+	binary.Write(&bufFile, binary.LittleEndian, iFormat)
+	binary.Write(&bufFile, binary.LittleEndian, uint32(len(bBinary)))
+	binary.Write(&bufFile, binary.LittleEndian, crc32.ChecksumIEEE(bBinary))
+	bufFile.Write(bBinary)
+
+	if err := os.MkdirAll(this.sDirectory, 0755); err != nil {
+		fmt.Println("Couldn't create program cache directory:", err)
+		return
+	}
+	// Write to temporary file first, so that a crash never leaves half-written binary behind
+	sTmpPath := this.getPath(sKey) + ".tmp"
+	if err := os.WriteFile(sTmpPath, bufFile.Bytes(), 0644); err != nil {
+		fmt.Println("Couldn't write program cache entry:", err)
+		return
+	}
+	if err := os.Rename(sTmpPath, this.getPath(sKey)); err != nil {
+		fmt.Println("Couldn't write program cache entry:", err)
+		os.Remove(sTmpPath)
+	}
+}
+
+func (this *CProgramCache) decode(bData []byte) (uint32, []byte, error) {
+	const iHeaderSize = len(PROGRAM_CACHE_MAGIC) + 12
+	if len(bData) < iHeaderSize || string(bData[:len(PROGRAM_CACHE_MAGIC)]) != PROGRAM_CACHE_MAGIC {
+		return 0, nil, errors.New("bad header")
+	}
+	bHeader := bData[len(PROGRAM_CACHE_MAGIC):]
+	iFormat := binary.LittleEndian.Uint32(bHeader[0:])
+	iLength := binary.LittleEndian.Uint32(bHeader[4:])
+	iChecksum := binary.LittleEndian.Uint32(bHeader[8:])
+	bBinary := bData[iHeaderSize:]
+	if iLength == 0 || int(iLength) != len(bBinary) {
+		return 0, nil, errors.New("truncated binary")
+	}
+	if crc32.ChecksumIEEE(bBinary) != iChecksum {
+		return 0, nil, errors.New("checksum mismatch")
+	}
+	return iFormat, bBinary, nil
+}
+
+/*-----------------------------------------------
+
+  Name:	LinkProgramCached
+
+  Params:	vShaders - shaders read by ReadShader
+  		(or already compiled ones)
+
+  Result:	Restores program from the binary cache if
+  		possible, otherwise compiles the shaders,
+  		links the program and stores its binary.
+
+  /*---------------------------------------------*/
+
+func (this *CShaderProgram) LinkProgramCached(vShaders []*CShader) bool {
+	if this.uiProgram == 0 {
+		this.CreateProgram()
+	}
+	var bCache bool = pcProgramCache.isUsable()
+	var sKey string
+	if bCache {
+		sKey = pcProgramCache.GetKey(vShaders)
+		if pcProgramCache.LoadProgram(this.uiProgram, sKey) {
+			this.bLinked = true
+			return true
+		}
+	}
+
+	for _, shShader := range vShaders {
+		if !shShader.IsLoaded() && !shShader.CompileShader() {
+			return false
+		}
+		this.AddShaderToProgram(shShader)
+	}
+	if bCache {
+		gl.ProgramParameteri(this.uiProgram, gl.PROGRAM_BINARY_RETRIEVABLE_HINT, gl.TRUE)
+	}
+	if !this.LinkProgram() {
+		return false
+	}
+	if bCache {
+		pcProgramCache.SaveProgram(this.uiProgram, sKey)
+	}
+	return true
+}
@@ -0,0 +1,224 @@
+package graphic
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*-----------------------------------------------
+
+  Name:	SamplerDesc
+
+  Result:	Complete sampler state. Equal descriptions
+  		give the same GL sampler object from
+  		sampler cache. Start from DefaultSamplerDesc,
+  		zero value isn't a usable sampler.
+
+  /*---------------------------------------------*/
+
+type SamplerDesc struct {
+	MagFilter ETextureFiltering // TEXTURE_FILTER_MAG_*
+	MinFilter ETextureFiltering // TEXTURE_FILTER_MIN_*
+
+	Anisotropy float32 // Maximum anisotropy, 1 disables it, clamped to what driver supports
+
+	WrapS, WrapT, WrapR uint32     // gl.REPEAT, gl.MIRRORED_REPEAT, gl.CLAMP_TO_EDGE or gl.CLAMP_TO_BORDER, 0 means gl.REPEAT
+	BorderColor         [4]float32 // Used only with gl.CLAMP_TO_BORDER
+
+	MinLOD, MaxLOD float32
+	LODBias        float32
+
+	CompareMode bool   // Compare texels with reference value, for shadow samplers
+	CompareFunc uint32 // gl.LEQUAL, gl.GREATER... 0 means gl.LEQUAL
+}
+
+// DefaultSamplerDesc returns OpenGL's default sampler state with bilinear filtering.
+func DefaultSamplerDesc() SamplerDesc {
+	return SamplerDesc{
+		MagFilter:  TEXTURE_FILTER_MAG_BILINEAR,
+		MinFilter:  TEXTURE_FILTER_MIN_BILINEAR,
+		Anisotropy: 1,
+		WrapS:      gl.REPEAT,
+		WrapT:      gl.REPEAT,
+		WrapR:      gl.REPEAT,
+		MinLOD:     -1000,
+		MaxLOD:     1000,
+	}
+}
+
+// SetWrap sets the same wrap mode on all axes.
+func (this *SamplerDesc) SetWrap(iWrapMode uint32) {
+	this.WrapS, this.WrapT, this.WrapR = iWrapMode, iWrapMode, iWrapMode
+}
+
+func (this *SamplerDesc) usesBorder() bool {
+	return this.WrapS == gl.CLAMP_TO_BORDER || this.WrapT == gl.CLAMP_TO_BORDER || this.WrapR == gl.CLAMP_TO_BORDER
+}
+
+// normalize replaces defaults and clamps values, so descriptions sampling the same way are equal
+func (this SamplerDesc) normalize() SamplerDesc {
+	for _, iWrap := range []*uint32{&this.WrapS, &this.WrapT, &this.WrapR} {
+		if *iWrap == 0 {
+			*iWrap = gl.REPEAT
+		}
+	}
+	if !this.usesBorder() {
+		this.BorderColor = [4]float32{}
+	}
+	caps := GetGLCapabilities()
+	if this.Anisotropy < 1 || !caps.SupportsAnisotropicFiltering() {
+		this.Anisotropy = 1
+	} else if this.Anisotropy > caps.GetMaxAnisotropy() {
+		this.Anisotropy = caps.GetMaxAnisotropy()
+	}
+	if fMaxBias := caps.GetMaxLODBias(); fMaxBias > 0 {
+		if this.LODBias > fMaxBias {
+			this.LODBias = fMaxBias
+		} else if this.LODBias < -fMaxBias {
+			this.LODBias = -fMaxBias
+		}
+	}
+	if !this.CompareMode {
+		this.CompareFunc = 0
+	} else if this.CompareFunc == 0 {
+		this.CompareFunc = gl.LEQUAL
+	}
+	return this
+}
+
+func getGLMagFilter(tfMagnification ETextureFiltering) int32 {
+	if tfMagnification == TEXTURE_FILTER_MAG_NEAREST {
+		return gl.NEAREST
+	}
+	return gl.LINEAR
+}
+
+func getGLMinFilter(tfMinification ETextureFiltering) int32 {
+	switch tfMinification {
+	case TEXTURE_FILTER_MIN_NEAREST:
+		return gl.NEAREST
+	case TEXTURE_FILTER_MIN_NEAREST_MIPMAP:
+		return gl.NEAREST_MIPMAP_NEAREST
+	case TEXTURE_FILTER_MIN_BILINEAR_MIPMAP:
+		return gl.LINEAR_MIPMAP_NEAREST
+	case TEXTURE_FILTER_MIN_TRILINEAR:
+		return gl.LINEAR_MIPMAP_LINEAR
+	}
+	return gl.LINEAR
+}
+
+// applySamplerDesc sets every parameter of description on GL sampler
+func applySamplerDesc(uiSampler uint32, desc SamplerDesc) {
+	gl.SamplerParameteri(uiSampler, gl.TEXTURE_MAG_FILTER, getGLMagFilter(desc.MagFilter))
+	gl.SamplerParameteri(uiSampler, gl.TEXTURE_MIN_FILTER, getGLMinFilter(desc.MinFilter))
+	gl.SamplerParameteri(uiSampler, gl.TEXTURE_WRAP_S, int32(desc.WrapS))
+	gl.SamplerParameteri(uiSampler, gl.TEXTURE_WRAP_T, int32(desc.WrapT))
+	gl.SamplerParameteri(uiSampler, gl.TEXTURE_WRAP_R, int32(desc.WrapR))
+	gl.SamplerParameterfv(uiSampler, gl.TEXTURE_BORDER_COLOR, &desc.BorderColor[0])
+	gl.SamplerParameterf(uiSampler, gl.TEXTURE_MIN_LOD, desc.MinLOD)
+	gl.SamplerParameterf(uiSampler, gl.TEXTURE_MAX_LOD, desc.MaxLOD)
+	gl.SamplerParameterf(uiSampler, gl.TEXTURE_LOD_BIAS, desc.LODBias)
+	if desc.CompareMode {
+		gl.SamplerParameteri(uiSampler, gl.TEXTURE_COMPARE_MODE, gl.COMPARE_REF_TO_TEXTURE)
+		gl.SamplerParameteri(uiSampler, gl.TEXTURE_COMPARE_FUNC, int32(desc.CompareFunc))
+	} else {
+		gl.SamplerParameteri(uiSampler, gl.TEXTURE_COMPARE_MODE, gl.NONE)
+	}
+	if GetGLCapabilities().SupportsAnisotropicFiltering() {
+		gl.SamplerParameterf(uiSampler, gl.TEXTURE_MAX_ANISOTROPY, desc.Anisotropy)
+	}
+}
+
+// cachedSampler is GL sampler with number of textures using it
+type cachedSampler struct {
+	uiSampler uint32
+	desc      SamplerDesc
+	iRefCount int
+}
+
+/*-----------------------------------------------
+
+  Name:	CSamplerCache
+
+  Result:	Keeps one GL sampler object per distinct
+  		SamplerDesc, shared by all textures that
+  		sample the same way.
+
+  /*---------------------------------------------*/
+
+type CSamplerCache struct {
+	mSamplers map[SamplerDesc]*cachedSampler
+	mByName   map[uint32]*cachedSampler
+}
+
+var scSamplers CSamplerCache
+
+func GetSamplerCache() *CSamplerCache {
+	return &scSamplers
+}
+
+/*-----------------------------------------------
+
+  Name:	AcquireSampler
+
+  Params:	desc - sampler state
+
+  Result:	Returns GL sampler for description and
+  		counts caller as its user. Must be paired
+  		with ReleaseSampler.
+
+  /*---------------------------------------------*/
+
+func (this *CSamplerCache) AcquireSampler(desc SamplerDesc) uint32 {
+	if this.mSamplers == nil {
+		this.mSamplers = make(map[SamplerDesc]*cachedSampler)
+		this.mByName = make(map[uint32]*cachedSampler)
+	}
+	desc = desc.normalize()
+	if sampler, bOK := this.mSamplers[desc]; bOK {
+		sampler.iRefCount++
+		return sampler.uiSampler
+	}
+	sampler := &cachedSampler{desc: desc, iRefCount: 1}
+	gl.GenSamplers(1, &sampler.uiSampler)
+	applySamplerDesc(sampler.uiSampler, desc)
+	this.mSamplers[desc] = sampler
+	this.mByName[sampler.uiSampler] = sampler
+	return sampler.uiSampler
+}
+
+// ReleaseSampler drops one user of sampler, last one deletes it. Returns false if the sampler isn't from this cache.
+func (this *CSamplerCache) ReleaseSampler(uiSampler uint32) bool {
+	sampler, bOK := this.mByName[uiSampler]
+	if !bOK {
+		return false
+	}
+	sampler.iRefCount--
+	if sampler.iRefCount <= 0 {
+		gl.DeleteSamplers(1, &sampler.uiSampler)
+		delete(this.mByName, uiSampler)
+		delete(this.mSamplers, sampler.desc)
+	}
+	return true
+}
+
+// GetSamplerDesc returns normalized description of cached sampler.
+func (this *CSamplerCache) GetSamplerDesc(uiSampler uint32) (SamplerDesc, bool) {
+	if sampler, bOK := this.mByName[uiSampler]; bOK {
+		return sampler.desc, true
+	}
+	return SamplerDesc{}, false
+}
+
+// GetNumSamplers returns number of GL samplers that currently exist in cache.
+func (this *CSamplerCache) GetNumSamplers() int {
+	return len(this.mSamplers)
+}
+
+// ReleaseAll deletes all samplers, even those textures still use.
+func (this *CSamplerCache) ReleaseAll() {
+	for _, sampler := range this.mSamplers {
+		gl.DeleteSamplers(1, &sampler.uiSampler)
+	}
+	this.mSamplers = nil
+	this.mByName = nil
+}
@@ -0,0 +1,434 @@
+package graphic
+
+import (
+	"antry/libs"
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"sort"
+)
+
+/*-----------------------------------------------
+
+  Name:	CSceneNode
+
+  Result:	Object in scene with local translation,
+  		rotation and scale relative to its parent.
+  		World matrix is cached and recomputed only
+  		after node or one of its parents moved.
+  		Node can carry model, terrain or light.
+
+  /*---------------------------------------------*/
+
+type CSceneNode struct {
+	sName        string
+	vTranslation mgl32.Vec3
+	qRotation    mgl32.Quat
+	vScale       mgl32.Vec3
+	parent       *CSceneNode
+	vChildren    []*CSceneNode
+	mWorld       mgl32.Mat4
+	bDirty       bool // World matrix must be recomputed, then also all children are dirty
+	bVisible     bool
+	iRenderOrder int // Lower goes first, nodes with the same order go in hierarchy order
+
+	model     *CAssimpModel
+	heightmap *CMultiLayeredHeightmap
+	light     *CDirectionalLight
+}
+
+func NewCSceneNode(sName string) *CSceneNode {
+	this := CSceneNode{}
+	this.sName = sName
+	this.qRotation = mgl32.QuatIdent()
+	this.vScale = mgl32.Vec3{1.0, 1.0, 1.0}
+	this.mWorld = mgl32.Ident4()
+	this.bDirty = true
+	this.bVisible = true
+	return &this
+}
+
+func (this *CSceneNode) GetName() string {
+	return this.sName
+}
+
+// markDirty invalidates world matrices of node and its subtree, dirty node never has clean children
+func (this *CSceneNode) markDirty() {
+	if this.bDirty {
+		return
+	}
+	this.bDirty = true
+	for _, child := range this.vChildren {
+		child.markDirty()
+	}
+}
+
+func (this *CSceneNode) SetTranslation(a_vTranslation mgl32.Vec3) {
+	this.vTranslation = a_vTranslation
+	this.markDirty()
+}
+
+func (this *CSceneNode) GetTranslation() mgl32.Vec3 {
+	return this.vTranslation
+}
+
+func (this *CSceneNode) SetRotation(a_qRotation mgl32.Quat) {
+	this.qRotation = a_qRotation.Normalize()
+	this.markDirty()
+}
+
+// SetRotationEuler sets rotation from angles in degrees around X, Y and Z, applied in this order.
+func (this *CSceneNode) SetRotationEuler(fAngleX, fAngleY, fAngleZ float32) {
+	qRotation := mgl32.QuatRotate(mgl32.DegToRad(fAngleZ), mgl32.Vec3{0, 0, 1})
+	qRotation = qRotation.Mul(mgl32.QuatRotate(mgl32.DegToRad(fAngleY), mgl32.Vec3{0, 1, 0}))
+	this.SetRotation(qRotation.Mul(mgl32.QuatRotate(mgl32.DegToRad(fAngleX), mgl32.Vec3{1, 0, 0})))
+}
+
+func (this *CSceneNode) GetRotation() mgl32.Quat {
+	return this.qRotation
+}
+
+func (this *CSceneNode) SetScale(a_vScale mgl32.Vec3) {
+	this.vScale = a_vScale
+	this.markDirty()
+}
+
+func (this *CSceneNode) GetScale() mgl32.Vec3 {
+	return this.vScale
+}
+
+// GetLocalMatrix returns transformation to parent's space, scale goes first, then rotation and translation.
+func (this *CSceneNode) GetLocalMatrix() mgl32.Mat4 {
+	mLocal := mgl32.Translate3D(this.vTranslation.X(), this.vTranslation.Y(), this.vTranslation.Z())
+	mLocal = mLocal.Mul4(this.qRotation.Mat4())
+	return mLocal.Mul4(mgl32.Scale3D(this.vScale.X(), this.vScale.Y(), this.vScale.Z()))
+}
+
+// GetWorldMatrix returns transformation to world space, parents are updated first if they moved.
+func (this *CSceneNode) GetWorldMatrix() mgl32.Mat4 {
+	if this.bDirty {
+		this.mWorld = this.GetLocalMatrix()
+		if this.parent != nil {
+			this.mWorld = this.parent.GetWorldMatrix().Mul4(this.mWorld)
+		}
+		this.bDirty = false
+	}
+	return this.mWorld
+}
+
+func (this *CSceneNode) GetWorldPosition() mgl32.Vec3 {
+	return this.GetWorldMatrix().Col(3).Vec3()
+}
+
+func (this *CSceneNode) IsDirty() bool {
+	return this.bDirty
+}
+
+// SetVisible hides or shows node together with its children.
+func (this *CSceneNode) SetVisible(bVisible bool) {
+	this.bVisible = bVisible
+}
+
+func (this *CSceneNode) IsVisible() bool {
+	return this.bVisible
+}
+
+func (this *CSceneNode) SetRenderOrder(iRenderOrder int) {
+	this.iRenderOrder = iRenderOrder
+}
+
+func (this *CSceneNode) GetRenderOrder() int {
+	return this.iRenderOrder
+}
+
+func (this *CSceneNode) GetParent() *CSceneNode {
+	return this.parent
+}
+
+func (this *CSceneNode) GetChildren() []*CSceneNode {
+	return this.vChildren
+}
+
+// IsAncestorOf tells whether node is somewhere above other node in hierarchy.
+func (this *CSceneNode) IsAncestorOf(node *CSceneNode) bool {
+	for parent := node.parent; parent != nil; parent = parent.parent {
+		if parent == this {
+			return true
+		}
+	}
+	return false
+}
+
+/*-----------------------------------------------
+
+  Name:	AddChild
+
+  Params:	child - node to put under this one
+
+  Result:	Moves child from its old parent under
+  		this node, its local transformation stays
+  		the same. Returns false if it would make
+  		a cycle.
+
+  /*---------------------------------------------*/
+
+func (this *CSceneNode) AddChild(child *CSceneNode) bool {
+	if child == nil || child == this || child.IsAncestorOf(this) {
+		return false
+	}
+	if child.parent != nil {
+		child.parent.RemoveChild(child)
+	}
+	child.parent = this
+	this.vChildren = append(this.vChildren, child)
+	child.markDirty()
+	return true
+}
+
+// RemoveChild detaches child with its subtree, returns false if it isn't child of this node.
+func (this *CSceneNode) RemoveChild(child *CSceneNode) bool {
+	for i, node := range this.vChildren {
+		if node == child {
+			this.vChildren = append(this.vChildren[:i], this.vChildren[i+1:]...)
+			child.parent = nil
+			child.markDirty()
+			return true
+		}
+	}
+	return false
+}
+
+// FindChild returns first node with name in subtree, searched depth first, nil if there's none.
+func (this *CSceneNode) FindChild(sName string) *CSceneNode {
+	for _, child := range this.vChildren {
+		if child.sName == sName {
+			return child
+		}
+		if node := child.FindChild(sName); node != nil {
+			return node
+		}
+	}
+	return nil
+}
+
+func (this *CSceneNode) AttachModel(model *CAssimpModel) {
+	this.model = model
+}
+
+func (this *CSceneNode) GetModel() *CAssimpModel {
+	return this.model
+}
+
+func (this *CSceneNode) AttachHeightmap(heightmap *CMultiLayeredHeightmap) {
+	this.heightmap = heightmap
+}
+
+func (this *CSceneNode) GetHeightmap() *CMultiLayeredHeightmap {
+	return this.heightmap
+}
+
+// AttachLight puts light to node, its direction is turned by node's world rotation.
+func (this *CSceneNode) AttachLight(light *CDirectionalLight) {
+	this.light = light
+}
+
+func (this *CSceneNode) GetLight() *CDirectionalLight {
+	return this.light
+}
+
+// GetWorldLight returns copy of attached light with direction in world space, nil if node has no light.
+func (this *CSceneNode) GetWorldLight() *CDirectionalLight {
+	if this.light == nil {
+		return nil
+	}
+	worldLight := *this.light
+	vDirection := this.GetWorldMatrix().Mul4x1(this.light.vDirection.Vec4(0)).Vec3()
+	if vDirection.Len() > 0 {
+		worldLight.vDirection = vDirection.Normalize()
+	}
+	return &worldLight
+}
+
+// IsRenderable tells whether node draws something, lights alone don't.
+func (this *CSceneNode) IsRenderable() bool {
+	return (this.model != nil && this.model.IsLoaded()) || this.heightmap != nil
+}
+
+// GetLocalBounds returns box around attached model or terrain in node's space, false if node has nothing to hit.
+func (this *CSceneNode) GetLocalBounds() (libs.AABB, bool) {
+	if this.model != nil && this.model.IsLoaded() {
+		return this.model.GetBoundingBox(), true
+	}
+	if this.heightmap != nil {
+		return this.heightmap.GetBoundingBox(), true
+	}
+	return libs.AABB{}, false
+}
+
+/*-----------------------------------------------
+
+  Name:	CSceneGraph
+
+  Result:	Hierarchy of scene nodes under one root.
+  		Gives renderable nodes in render order and
+  		picks node hit by ray.
+
+  /*---------------------------------------------*/
+
+type CSceneGraph struct {
+	root *CSceneNode
+}
+
+func NewCSceneGraph() *CSceneGraph {
+	this := CSceneGraph{}
+	this.root = NewCSceneNode("root")
+	return &this
+}
+
+func (this *CSceneGraph) GetRoot() *CSceneNode {
+	return this.root
+}
+
+// CreateNode makes new node under parent, or under root if parent is nil.
+func (this *CSceneGraph) CreateNode(sName string, parent *CSceneNode) *CSceneNode {
+	node := NewCSceneNode(sName)
+	if parent == nil {
+		parent = this.root
+	}
+	parent.AddChild(node)
+	return node
+}
+
+func (this *CSceneGraph) FindNode(sName string) *CSceneNode {
+	if this.root.sName == sName {
+		return this.root
+	}
+	return this.root.FindChild(sName)
+}
+
+// RemoveNode detaches node with its subtree from graph, root can't be removed.
+func (this *CSceneGraph) RemoveNode(node *CSceneNode) bool {
+	if node == nil || node.parent == nil || !this.root.IsAncestorOf(node) {
+		return false
+	}
+	return node.parent.RemoveChild(node)
+}
+
+// Traverse calls fnVisit for visible nodes, parents before children. Returning false skips node's children.
+func (this *CSceneGraph) Traverse(fnVisit func(node *CSceneNode) bool) {
+	var visit func(node *CSceneNode)
+	visit = func(node *CSceneNode) {
+		if !node.bVisible || !fnVisit(node) {
+			return
+		}
+		for _, child := range node.vChildren {
+			visit(child)
+		}
+	}
+	visit(this.root)
+}
+
+// UpdateWorldMatrices recomputes world matrices of all moved nodes at once.
+func (this *CSceneGraph) UpdateWorldMatrices() {
+	this.Traverse(func(node *CSceneNode) bool {
+		node.GetWorldMatrix()
+		return true
+	})
+}
+
+/*-----------------------------------------------
+
+  Name:	GetRenderQueue
+
+  Params:	none
+
+  Result:	Returns visible nodes that draw something,
+  		sorted by render order. Nodes with the same
+  		order keep hierarchy order.
+
+  /*---------------------------------------------*/
+
+func (this *CSceneGraph) GetRenderQueue() []*CSceneNode {
+	var vQueue []*CSceneNode
+	this.Traverse(func(node *CSceneNode) bool {
+		if node.IsRenderable() {
+			vQueue = append(vQueue, node)
+		}
+		return true
+	})
+	sort.SliceStable(vQueue, func(i, j int) bool {
+		return vQueue[i].iRenderOrder < vQueue[j].iRenderOrder
+	})
+	return vQueue
+}
+
+// GetLights returns visible nodes with lights in hierarchy order.
+func (this *CSceneGraph) GetLights() []*CSceneNode {
+	var vLights []*CSceneNode
+	this.Traverse(func(node *CSceneNode) bool {
+		if node.light != nil {
+			vLights = append(vLights, node)
+		}
+		return true
+	})
+	return vLights
+}
+
+/*-----------------------------------------------
+
+  Name:	Pick
+
+  Params:	vOrigin - start of ray in world space
+  		vDirection - direction of ray
+
+  Result:	Returns visible node whose bounding box
+  		ray hits first and distance along ray to
+  		the hit, nil if it misses everything.
+
+  /*---------------------------------------------*/
+
+func (this *CSceneGraph) Pick(vOrigin, vDirection mgl32.Vec3) (*CSceneNode, float32) {
+	if vDirection.Len() == 0 {
+		return nil, 0
+	}
+	vDirection = vDirection.Normalize()
+	var picked *CSceneNode
+	var fNearest float32 = float32(math.Inf(1))
+	this.Traverse(func(node *CSceneNode) bool {
+		box, bOK := node.GetLocalBounds()
+		if !bOK {
+			return true
+		}
+		// Box is tested in node's space, where it's axis aligned
+		mWorld := node.GetWorldMatrix()
+		if mWorld.Det() == 0 {
+			return true
+		}
+		mInverse := mWorld.Inv()
+		vLocalOrigin := mInverse.Mul4x1(vOrigin.Vec4(1)).Vec3()
+		vLocalDirection := mInverse.Mul4x1(vDirection.Vec4(0)).Vec3()
+		fParam, bHit := box.IntersectRay(vLocalOrigin, vLocalDirection)
+		if !bHit {
+			return true
+		}
+		fDistance := mWorld.Mul4x1(vLocalDirection.Mul(fParam).Vec4(0)).Vec3().Len()
+		if fDistance < fNearest {
+			fNearest = fDistance
+			picked = node
+		}
+		return true
+	})
+	if picked == nil {
+		return nil, 0
+	}
+	return picked, fNearest
+}
+
+// PickScreen picks node under window point with origin at bottom left, as OpenGL viewport has it.
+func (this *CSceneGraph) PickScreen(fX, fY float32, mProjection, mView mgl32.Mat4, iWidth, iHeight int32) (*CSceneNode, float32) {
+	vNear, errNear := mgl32.UnProject(mgl32.Vec3{fX, fY, 0}, mView, mProjection, 0, 0, int(iWidth), int(iHeight))
+	vFar, errFar := mgl32.UnProject(mgl32.Vec3{fX, fY, 1}, mView, mProjection, 0, 0, int(iWidth), int(iHeight))
+	if errNear != nil || errFar != nil {
+		return nil, 0
+	}
+	return this.Pick(vNear, vFar.Sub(vNear))
+}
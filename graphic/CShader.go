@@ -1,11 +1,11 @@
 package graphic
 
 import (
+	"antry/libs"
 	"bufio"
 	"fmt"
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
-	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -15,14 +15,33 @@ type CShader struct {
 	uiShader uint32 // ID of shader
 	iType    uint32 // GL_VERTEX_SHADER, GL_FRAGMENT_SHADER...
 	bLoaded  bool   // Whether shader was loaded and compiled
+
+	sFile    string // File the shader was read from
+	sSource  string // Source after resolving includes, what actually goes to the compiler
+	vOrigins []CSourceLocation
+	sInfoLog string // Compiler output of last compilation
+}
+
+// CSourceLocation tells where a line of preprocessed shader source came from
+type CSourceLocation struct {
+	File string
+	Line int
+}
+
+// CShaderProgramDesc lists shader files (relative to shaders directory) that make up one program
+type CShaderProgramDesc struct {
+	Name    string
+	Files   []string
+	Defines []string // Macros defined in every file of the program, for variants of the same sources
 }
 
 type CShaderProgram struct {
 	uiProgram uint32 // ID of program
 	bLinked   bool   // Whether program was linked and is ready to use
+	sInfoLog  string // Linker output of last link
 }
 
-const NUMSHADERS = 6
+const NUMSHADERS = 8
 
 func NewCShader() *CShader {
 	this := CShader{}
@@ -31,58 +50,118 @@ func NewCShader() *CShader {
 }
 
 var shShaders [NUMSHADERS]CShader
-var spMain, spOrtho2D, spFont2D CShaderProgram
+var spMain, spOrtho2D, spFont2D, spSkybox CShaderProgram
 
-func PrepareShaderPrograms() bool {
-	// Load shaders and create shader program
+var sShaderFileNames = []string{"main_shader.vert", "main_shader.frag", "ortho2D.vert",
+	"ortho2D.frag", "font2D.frag", "dirLight.frag", "skybox.vert", "skybox.frag",
+}
+
+// All programs the application builds, first ones are in the same order as programs in PrepareShaderPrograms
+var vShaderProgramDescs = []CShaderProgramDesc{
+	{Name: "main", Files: []string{"main_shader.vert", "main_shader.frag", "dirLight.frag"}},
+	{Name: "ortho2D", Files: []string{"ortho2D.vert", "ortho2D.frag"}},
+	{Name: "font2D", Files: []string{"ortho2D.vert", "font2D.frag"}},
+	{Name: "terrain", Files: []string{"terrain.vert", "terrain.frag", "dirLight.frag"}},
+	{Name: "terrain_array", Files: []string{"terrain.vert", "terrain.frag", "dirLight.frag"}, Defines: []string{"TERRAIN_TEXTURE_ARRAY"}},
+	{Name: "skybox", Files: []string{"skybox.vert", "skybox.frag", "dirLight.frag"}},
+}
 
-	var sShaderFileNames []string = []string{"main_shader.vert", "main_shader.frag", "ortho2D.vert",
-		"ortho2D.frag", "font2D.frag", "dirLight.frag",
+func GetShaderProgramDescs() []CShaderProgramDesc {
+	return vShaderProgramDescs
+}
+
+func getShaderProgramDesc(sName string) *CShaderProgramDesc {
+	for i := range vShaderProgramDescs {
+		if vShaderProgramDescs[i].Name == sName {
+			return &vShaderProgramDescs[i]
+		}
+	}
+	return nil
+}
+
+// GetShaderTypeFromFile determines shader stage from file extension, returns 0 for unknown extensions.
+func GetShaderTypeFromFile(sFile string) uint32 {
+	switch filepath.Ext(sFile) {
+	case ".vert":
+		return gl.VERTEX_SHADER
+	case ".tesc":
+		return gl.TESS_CONTROL_SHADER
+	case ".tese":
+		return gl.TESS_EVALUATION_SHADER
+	case ".geom":
+		return gl.GEOMETRY_SHADER
+	case ".frag":
+		return gl.FRAGMENT_SHADER
+	case ".comp":
+		return gl.COMPUTE_SHADER
+	}
+	return 0
+}
+
+func GetShaderStageName(iType uint32) string {
+	switch iType {
+	case gl.VERTEX_SHADER:
+		return "vertex"
+	case gl.TESS_CONTROL_SHADER:
+		return "tessellation control"
+	case gl.TESS_EVALUATION_SHADER:
+		return "tessellation evaluation"
+	case gl.GEOMETRY_SHADER:
+		return "geometry"
+	case gl.FRAGMENT_SHADER:
+		return "fragment"
+	case gl.COMPUTE_SHADER:
+		return "compute"
 	}
+	return fmt.Sprintf("unknown (0x%X)", iType)
+}
 
-	for i := 0; i < NUMSHADERS; i++ {
-		var sExt string = filepath.Ext(sShaderFileNames[i])
-		var iShaderType uint32 = 0
-		if sExt == ".vert" {
-			iShaderType = gl.VERTEX_SHADER
-		} else {
-			if sExt == ".frag" {
-				iShaderType = gl.FRAGMENT_SHADER
-			} else {
-				iShaderType = gl.GEOMETRY_SHADER
+// findShaderByName picks shaders from vShaders (read from files vNames) in the order given by vFiles
+func findShaderByName(vShaders []CShader, vNames []string, vFiles []string) []*CShader {
+	var vResult []*CShader
+	for _, sFile := range vFiles {
+		for i := range vNames {
+			if vNames[i] == sFile {
+				vResult = append(vResult, &vShaders[i])
+				break
 			}
 		}
-		shShaders[i].LoadShader("data\\shaders\\"+sShaderFileNames[i], iShaderType)
 	}
+	return vResult
+}
 
-	// Create shader programs
+func PrepareShaderPrograms() bool {
+	// Load shaders and create shader program
+
+	for i := 0; i < NUMSHADERS; i++ {
+		// Only read the sources here, shaders get compiled when a program misses the binary cache
+		if !shShaders[i].ReadShader("data/shaders/"+sShaderFileNames[i], GetShaderTypeFromFile(sShaderFileNames[i])) {
+			return false
+		}
+	}
 
-	spMain.CreateProgram()
-	spMain.AddShaderToProgram(&shShaders[0])
-	spMain.AddShaderToProgram(&shShaders[1])
-	spMain.AddShaderToProgram(&shShaders[5])
+	// Create shader programs
 
-	if !spMain.LinkProgram() {
+	if !spMain.LinkProgramCached(findShaderByName(shShaders[:], sShaderFileNames, vShaderProgramDescs[0].Files)) {
 		panic("spMain.LinkProgram")
 		return false
 	}
 
-	spOrtho2D.CreateProgram()
-	spOrtho2D.AddShaderToProgram(&shShaders[3])
-	spOrtho2D.AddShaderToProgram(&shShaders[3])
-	if !spOrtho2D.LinkProgram() {
+	if !spOrtho2D.LinkProgramCached(findShaderByName(shShaders[:], sShaderFileNames, vShaderProgramDescs[1].Files)) {
 		panic("spOrtho2D.LinkProgram")
 		return false
 	}
 
-	spFont2D.CreateProgram()
-	spFont2D.AddShaderToProgram(&shShaders[2])
-	spFont2D.AddShaderToProgram(&shShaders[4])
-	if !spFont2D.LinkProgram() {
+	if !spFont2D.LinkProgramCached(findShaderByName(shShaders[:], sShaderFileNames, vShaderProgramDescs[2].Files)) {
 		panic("spFont2D.LinkProgram")
 		return false
 	}
 
+	if !spSkybox.LinkProgramCached(findShaderByName(shShaders[:], sShaderFileNames, getShaderProgramDesc("skybox").Files)) {
+		panic("spSkybox.LinkProgram")
+		return false
+	}
+
 	return true
 }
 
@@ -110,42 +189,80 @@ func PrepareShaderPrograms() bool {
 //		return source, nil
 //	}
 func (this *CShader) LoadShader(sFile string, a_iType uint32) bool {
+	if !this.ReadShader(sFile, a_iType) {
+		panic("err")
+		return false
+	}
+	return this.CompileShader()
+}
+
+// ReadShader reads the shader file and resolves its includes without compiling it.
+func (this *CShader) ReadShader(sFile string, a_iType uint32) bool {
+	return this.ReadShaderWithDefines(sFile, a_iType, nil)
+}
+
+/*-----------------------------------------------
+
+  Name:	ReadShaderWithDefines
+
+  Params:	sFile - shader file
+  		a_iType - shader stage
+  		vDefines - macros to define, "NAME" or
+  		"NAME VALUE"
+
+  Result:	Reads the shader like ReadShader and puts
+  		#define lines right after #version.
+
+  /*---------------------------------------------*/
+
+func (this *CShader) ReadShaderWithDefines(sFile string, a_iType uint32, vDefines []string) bool {
 	//src, err := loadShaderSource(sFile)
 	//if err != nil {
 	//	return false
 	//}
+	if a_iType == 0 {
+		fmt.Printf("Can't determine shader stage of %s\n", sFile)
+		return false
+	}
 	var sLines []string
+	var vOrigins []CSourceLocation
 	//
-	if !this.GetLinesFromFile(sFile, false, &sLines) {
-		panic("err")
+	if !this.getLinesFromFile(sFile, false, &sLines, &vOrigins) {
 		return false
 	}
-	sstr := string(strings.Join(sLines, "\n"))
-	//fmt.Println(sstr)
-	//fmt.Println("################################")
-	glSrcs, freeFn := gl.Strs(sstr + "\x00")
-	defer freeFn()
-	//sProgram := make([][]uint8, len(sLines))
-	//for i := 0; i < len(sLines); i++ {
-	//	sProgram[i] = sLines[i]
-	//}
-
-	this.uiShader = gl.CreateShader(a_iType)
-
-	gl.ShaderSource(this.uiShader, 1, glSrcs, nil) //int32(len(sLines)) (*string)(unsafe.Pointer(&sProgram[0]))
-	gl.CompileShader(this.uiShader)
-
-	//sProgram = nil
-
-	var iCompilationStatus int32
-	gl.GetShaderiv(this.uiShader, gl.COMPILE_STATUS, &iCompilationStatus)
+	if len(vDefines) > 0 {
+		// #version must stay first, defines get location of the line they follow
+		var iInsert int = 0
+		for i, sLine := range sLines {
+			if strings.HasPrefix(strings.TrimSpace(sLine), "#version") {
+				iInsert = i + 1
+				break
+			}
+		}
+		vDefineLines := make([]string, len(vDefines))
+		vDefineOrigins := make([]CSourceLocation, len(vDefines))
+		for i, sDefine := range vDefines {
+			vDefineLines[i] = "#define " + sDefine
+			vDefineOrigins[i] = CSourceLocation{File: sFile}
+			if iInsert > 0 {
+				vDefineOrigins[i] = vOrigins[iInsert-1]
+			}
+		}
+		sLines = append(sLines[:iInsert], append(vDefineLines, sLines[iInsert:]...)...)
+		vOrigins = append(vOrigins[:iInsert], append(vDefineOrigins, vOrigins[iInsert:]...)...)
+	}
+	this.sFile = sFile
+	this.vOrigins = vOrigins
+	this.sSource = string(strings.Join(sLines, "\n"))
+	this.iType = a_iType
+	return true
+}
 
-	if iCompilationStatus == gl.FALSE {
-		sInfoLog := make([]uint8, 1024)
+// CompileShader compiles the source previously read by ReadShader.
+func (this *CShader) CompileShader() bool {
+	if !this.TryCompileShader() {
 		var sFinalMessage string
-		var iLogLength int32
-		gl.GetShaderInfoLog(this.uiShader, 1024, &iLogLength, &sInfoLog[0])
-		sFinalMessage = fmt.Sprintf("Error! Shader file %s wasn't compiled! The compiler returned:\n\n%s", sFile, sInfoLog)
+		sFinalMessage = fmt.Sprintf("Error! Shader file %s wasn't compiled! The compiler returned:\n\n%s", this.sFile, this.sInfoLog)
 		panic(sFinalMessage)
 		//buttons := []sdl.MessageBoxButtonData{
 		//	{0, 0, "no"},
@@ -171,26 +288,75 @@ func (this *CShader) LoadShader(sFile string, a_iType uint32) bool {
 		//	ColorScheme: &colorScheme})
 		return false
 	}
-	this.iType = a_iType
+	return true
+}
+
+// TryCompileShader compiles the shader, on failure the compiler output is kept in GetInfoLog instead of panicking.
+func (this *CShader) TryCompileShader() bool {
+	// Don't even try stages the driver doesn't have, compiler messages about that are rarely helpful
+	if err := GetGLCapabilities().CheckShaderStage(this.iType); err != nil {
+		this.sInfoLog = err.Error()
+		return false
+	}
+	//fmt.Println(this.sSource)
+	//fmt.Println("################################")
+	glSrcs, freeFn := gl.Strs(this.sSource + "\x00")
+	defer freeFn()
+	//sProgram := make([][]uint8, len(sLines))
+	//for i := 0; i < len(sLines); i++ {
+	//	sProgram[i] = sLines[i]
+	//}
+
+	this.uiShader = gl.CreateShader(this.iType)
+
+	gl.ShaderSource(this.uiShader, 1, glSrcs, nil) //int32(len(sLines)) (*string)(unsafe.Pointer(&sProgram[0]))
+	gl.CompileShader(this.uiShader)
+
+	//sProgram = nil
+
+	var iCompilationStatus int32
+	gl.GetShaderiv(this.uiShader, gl.COMPILE_STATUS, &iCompilationStatus)
+
+	var iLogLength int32
+	gl.GetShaderiv(this.uiShader, gl.INFO_LOG_LENGTH, &iLogLength)
+	this.sInfoLog = ""
+	if iLogLength > 1 {
+		sInfoLog := make([]uint8, iLogLength)
+		gl.GetShaderInfoLog(this.uiShader, iLogLength, nil, &sInfoLog[0])
+		this.sInfoLog = gl.GoStr(&sInfoLog[0])
+	}
+
+	if iCompilationStatus == gl.FALSE {
+		gl.DeleteShader(this.uiShader)
+		this.uiShader = 0
+		return false
+	}
 	this.bLoaded = true
 
 	return true
 }
 
 func (this *CShader) GetLinesFromFile(sFile string, bIncludePart bool, vResult *[]string) bool {
-	fp, err := os.Open(sFile) //, "rt"
+	return this.getLinesFromFile(sFile, bIncludePart, vResult, nil)
+}
+
+// getLinesFromFile works as GetLinesFromFile, and if vOrigins isn't nil, it records file and line of every returned line
+func (this *CShader) getLinesFromFile(sFile string, bIncludePart bool, vResult *[]string, vOrigins *[]CSourceLocation) bool {
+	fp, err := libs.OpenAsset(sFile)
 	if err != nil {
 		fmt.Println(err.Error())
 		return false
 	}
 	defer fp.Close()
 
+	// Includes are relative to including file, asset paths may still use backslashes
 	var sDirectory string
-	sDirectory = filepath.Dir(sFile)
+	sDirectory = path.Dir(strings.ReplaceAll(sFile, "\\", "/"))
 
 	// Get all lines from a file
 
 	var bInIncludePart bool = false
+	var iLine int = 0
 
 	fileScanner := bufio.NewScanner(fp)
 
@@ -198,13 +364,21 @@ func (this *CShader) GetLinesFromFile(sFile string, bIncludePart bool, vResult *
 
 	for fileScanner.Scan() {
 		sLine := fileScanner.Text()
+		iLine++
 		fields := strings.Fields(sLine)
 		if len(fields) > 0 {
 			if fields[0] == "#include" {
+				if len(fields) < 2 {
+					fmt.Printf("%s:%d: #include without file name\n", sFile, iLine)
+					return false
+				}
 				var sFileName string = fields[1]
 				if len(sFileName) > 0 && strings.HasPrefix(sFileName, "\"") && strings.HasSuffix(sFileName, "\"") {
 					sFileName = strings.Replace(sFileName, "\"", "", -1)
-					this.GetLinesFromFile(path.Join(sDirectory, sFileName), true, vResult)
+					if !this.getLinesFromFile(path.Join(sDirectory, sFileName), true, vResult, vOrigins) {
+						fmt.Printf("%s:%d: couldn't include %s\n", sFile, iLine, sFileName)
+						return false
+					}
 				}
 
 			} else if fields[0] == "#include_part" {
@@ -213,6 +387,9 @@ func (this *CShader) GetLinesFromFile(sFile string, bIncludePart bool, vResult *
 				bInIncludePart = false
 			} else if !bIncludePart || (bIncludePart && bInIncludePart) {
 				*vResult = append(*vResult, sLine)
+				if vOrigins != nil {
+					*vOrigins = append(*vOrigins, CSourceLocation{File: sFile, Line: iLine})
+				}
 			}
 		}
 	}
@@ -228,6 +405,30 @@ func (this *CShader) GetShaderID() uint32 {
 	return this.uiShader
 }
 
+func (this *CShader) GetShaderType() uint32 {
+	return this.iType
+}
+
+func (this *CShader) GetSource() string {
+	return this.sSource
+}
+
+func (this *CShader) GetFile() string {
+	return this.sFile
+}
+
+func (this *CShader) GetInfoLog() string {
+	return this.sInfoLog
+}
+
+// GetSourceLocation maps 1-based line of preprocessed source (as reported by compiler) back to the file it came from.
+func (this *CShader) GetSourceLocation(iLine int) CSourceLocation {
+	if iLine < 1 || iLine > len(this.vOrigins) {
+		return CSourceLocation{File: this.sFile, Line: iLine}
+	}
+	return this.vOrigins[iLine-1]
+}
+
 func (this *CShader) DeleteShader() {
 	if !this.IsLoaded() {
 		return
@@ -256,23 +457,37 @@ func (this *CShaderProgram) AddShaderToProgram(shShader *CShader) bool {
 	return true
 }
 func (this *CShaderProgram) LinkProgram() bool {
+	if !this.TryLinkProgram() {
+		fmt.Printf("无法链接程序: %v\n", this.sInfoLog)
+		panic(this.sInfoLog)
+	}
+
+	return this.bLinked
+}
+
+// TryLinkProgram links the program, on failure the linker output is kept in GetInfoLog instead of panicking.
+func (this *CShaderProgram) TryLinkProgram() bool {
 	gl.LinkProgram(this.uiProgram)
 	var iLinkStatus int32
 	gl.GetProgramiv(this.uiProgram, gl.LINK_STATUS, &iLinkStatus)
 	this.bLinked = iLinkStatus == gl.TRUE
+	this.sInfoLog = ""
 	if iLinkStatus == gl.FALSE {
 		var logLength int32
 		gl.GetProgramiv(this.uiProgram, gl.INFO_LOG_LENGTH, &logLength)
 
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetProgramInfoLog(this.uiProgram, logLength, nil, gl.Str(log))
-
-		fmt.Printf("无法链接程序: %v\n", log)
-		panic(log)
+		this.sInfoLog = strings.TrimRight(log, "\x00")
 	}
 
 	return this.bLinked
 }
+
+func (this *CShaderProgram) GetInfoLog() string {
+	return this.sInfoLog
+}
+
 func (this *CShaderProgram) DeleteProgram() {
 	if !this.bLinked {
 		return
@@ -289,6 +504,96 @@ func (this *CShaderProgram) GetProgramID() uint32 {
 	return this.uiProgram
 }
 
+/*-----------------------------------------------
+
+  Name:	DispatchCompute
+
+  Params:	iGroupsX, iGroupsY, iGroupsZ - number of
+  		work groups in each dimension
+
+  Result:	Binds compute program and runs it.
+
+  /*---------------------------------------------*/
+
+func (this *CShaderProgram) DispatchCompute(iGroupsX, iGroupsY, iGroupsZ uint32) bool {
+	if !this.bLinked {
+		return false
+	}
+	if err := GetGLCapabilities().CheckShaderStage(gl.COMPUTE_SHADER); err != nil {
+		fmt.Println(err)
+		return false
+	}
+	gl.UseProgram(this.uiProgram)
+	gl.DispatchCompute(iGroupsX, iGroupsY, iGroupsZ)
+	return true
+}
+
+// GetComputeWorkGroupSize returns local_size_x/y/z declared in compute shader of the program.
+func (this *CShaderProgram) GetComputeWorkGroupSize() [3]int32 {
+	var iSize [3]int32
+	gl.GetProgramiv(this.uiProgram, gl.COMPUTE_WORK_GROUP_SIZE, &iSize[0])
+	return iSize
+}
+
+// DispatchComputeForSize runs enough work groups to cover iSizeX*iSizeY*iSizeZ invocations.
+func (this *CShaderProgram) DispatchComputeForSize(iSizeX, iSizeY, iSizeZ uint32) bool {
+	if !this.bLinked {
+		return false
+	}
+	var iLocal [3]int32 = this.GetComputeWorkGroupSize()
+	var iGroups [3]uint32
+	var iSizes [3]uint32 = [3]uint32{iSizeX, iSizeY, iSizeZ}
+	for i := 0; i < 3; i++ {
+		var iLocalSize uint32 = uint32(iLocal[i])
+		if iLocalSize == 0 {
+			iLocalSize = 1
+		}
+		iGroups[i] = (iSizes[i] + iLocalSize - 1) / iLocalSize
+	}
+	return this.DispatchCompute(iGroups[0], iGroups[1], iGroups[2])
+}
+
+// ComputeMemoryBarrier makes writes of previous dispatches visible, e.g. gl.SHADER_STORAGE_BARRIER_BIT.
+func ComputeMemoryBarrier(iBarriers uint32) {
+	gl.MemoryBarrier(iBarriers)
+}
+
+/*-----------------------------------------------
+
+  Name:	SetStorageBlockBinding
+
+  Params:	sBlockName - name of buffer block in shader
+  		iBinding - shader storage buffer binding point
+
+  Result:	Connects shader storage block of program
+  		with binding point, returns false if there's
+  		no such block.
+
+  /*---------------------------------------------*/
+
+func (this *CShaderProgram) SetStorageBlockBinding(sBlockName string, iBinding uint32) bool {
+	if err := GetGLCapabilities().CheckStorageBuffers(); err != nil {
+		fmt.Println(err)
+		return false
+	}
+	var iIndex uint32 = gl.GetProgramResourceIndex(this.uiProgram, gl.SHADER_STORAGE_BLOCK, gl.Str(sBlockName+"\x00"))
+	if iIndex == gl.INVALID_INDEX {
+		return false
+	}
+	gl.ShaderStorageBlockBinding(this.uiProgram, iIndex, iBinding)
+	return true
+}
+
+// SetPatchVertices sets number of vertices per patch for tessellation programs.
+func SetPatchVertices(iVertices int32) bool {
+	if err := GetGLCapabilities().CheckShaderStage(gl.TESS_CONTROL_SHADER); err != nil {
+		fmt.Println(err)
+		return false
+	}
+	gl.PatchParameteri(gl.PATCH_VERTICES, iVertices)
+	return true
+}
+
 func (this *CShaderProgram) SetUniformF32N(sName string, fValues *float32, iCount int32) {
 	var iLoc int32 = gl.GetUniformLocation(this.uiProgram, gl.Str(sName+"\x00"))
 	gl.Uniform1fv(iLoc, iCount, fValues)
@@ -3,17 +3,23 @@ package graphic
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
+	"image"
 	"unsafe"
+
+	"antry/libs"
 )
 
 type CSkybox struct {
 	uiVAO                                       uint32
 	vboRenderData                               *CVertexBufferObject
-	tTextures                                   [6]CTexture
+	tCubemap                                    CTexture
+	thCubemap                                   *CTextureHandle // Cubemap loaded by LoadSkyboxAsync, used instead of tCubemap
 	sDirectory                                  string
 	sFront, sBack, sLeft, sRight, sTop, sBottom string
+	sFile                                       string // Panorama, cross or cubemap container, if loaded from one file
 }
 
 func EncodeToBytes(p interface{}) []byte {
@@ -24,97 +30,250 @@ func EncodeToBytes(p interface{}) []byte {
 	}
 	return bin_buf.Bytes()
 }
-func (this *CSkybox) LoadSkybox(a_sDirectory, a_sFront, a_sBack, a_sLeft, a_sRight, a_sTop, a_sBottom string) {
-	this.tTextures[0].LoadTexture2D(a_sDirectory+a_sFront, false)
-	this.tTextures[1].LoadTexture2D(a_sDirectory+a_sBack, false)
-	this.tTextures[2].LoadTexture2D(a_sDirectory+a_sLeft, false)
-	this.tTextures[3].LoadTexture2D(a_sDirectory+a_sRight, false)
-	this.tTextures[4].LoadTexture2D(a_sDirectory+a_sTop, false)
-	this.tTextures[5].LoadTexture2D(a_sDirectory+a_sBottom, false)
 
-	this.sDirectory = a_sDirectory
+/*-----------------------------------------------
+
+  Name:	LoadSkybox
+
+  Params:	a_sDirectory - directory with faces
+  		a_sFront .. a_sBottom - face files, front
+  		looks along +Z
+
+  Result:	Loads six face images into one cubemap.
+
+  /*---------------------------------------------*/
+
+func (this *CSkybox) LoadSkybox(a_sDirectory, a_sFront, a_sBack, a_sLeft, a_sRight, a_sTop, a_sBottom string) bool {
+	var vFaces [6]image.Image
+	for i, sPath := range getSkyboxFacePaths(a_sDirectory, a_sFront, a_sBack, a_sLeft, a_sRight, a_sTop, a_sBottom) {
+		img, err := loadImageFile(sPath)
+		if err != nil {
+			fmt.Println("Couldn't load skybox face", sPath, ":", err)
+			return false
+		}
+		vFaces[i] = img
+	}
+	if !this.tCubemap.CreateCubemap(vFaces, false) {
+		return false
+	}
+	this.setFaceFiles(a_sDirectory, a_sFront, a_sBack, a_sLeft, a_sRight, a_sTop, a_sBottom)
+	this.thCubemap = nil
+	this.createGeometry()
+	return true
+}
+
+/*-----------------------------------------------
+
+  Name:	LoadSkyboxAsync
+
+  Params:	alLoader - loader decoding the faces
+  		a_sDirectory .. a_sBottom - same as in
+  		LoadSkybox
+
+  Result:	Works like LoadSkybox, but faces get
+  		decoded on worker, cubemap placeholder is
+  		rendered until they're uploaded.
+
+  /*---------------------------------------------*/
 
+func (this *CSkybox) LoadSkyboxAsync(alLoader *CAsyncLoader, a_sDirectory, a_sFront, a_sBack, a_sLeft, a_sRight, a_sTop, a_sBottom string) error {
+	thCubemap, err := alLoader.LoadCubemapAsync(getSkyboxFacePaths(a_sDirectory, a_sFront, a_sBack, a_sLeft, a_sRight, a_sTop, a_sBottom), false)
+	if err != nil {
+		return err
+	}
+	this.thCubemap = thCubemap
+	this.setFaceFiles(a_sDirectory, a_sFront, a_sBack, a_sLeft, a_sRight, a_sTop, a_sBottom)
+	this.createGeometry()
+	return nil
+}
+
+// getSkyboxFacePaths returns face files in libs.CUBE_FACE_* order
+func getSkyboxFacePaths(a_sDirectory, a_sFront, a_sBack, a_sLeft, a_sRight, a_sTop, a_sBottom string) [6]string {
+	var vPaths [6]string
+	vPaths[libs.CUBE_FACE_POSITIVE_X] = a_sDirectory + a_sRight
+	vPaths[libs.CUBE_FACE_NEGATIVE_X] = a_sDirectory + a_sLeft
+	vPaths[libs.CUBE_FACE_POSITIVE_Y] = a_sDirectory + a_sTop
+	vPaths[libs.CUBE_FACE_NEGATIVE_Y] = a_sDirectory + a_sBottom
+	vPaths[libs.CUBE_FACE_POSITIVE_Z] = a_sDirectory + a_sFront
+	vPaths[libs.CUBE_FACE_NEGATIVE_Z] = a_sDirectory + a_sBack
+	return vPaths
+}
+
+func (this *CSkybox) setFaceFiles(a_sDirectory, a_sFront, a_sBack, a_sLeft, a_sRight, a_sTop, a_sBottom string) {
+	this.sDirectory = a_sDirectory
 	this.sFront = a_sFront
 	this.sBack = a_sBack
 	this.sLeft = a_sLeft
 	this.sRight = a_sRight
 	this.sTop = a_sTop
 	this.sBottom = a_sBottom
+	this.sFile = ""
+}
+
+/*-----------------------------------------------
+
+  Name:	LoadSkyboxFromFile
+
+  Params:	sPath - cubemap DDS/KTX, equirectangular
+  		panorama (2:1, LDR or .hdr) or horizontal
+  		or vertical cross
+  		iFaceSize - face size for panoramas, 0
+  		picks quarter of panorama width
 
-	for i := 0; i < 6; i++ {
-		this.tTextures[i].SetFiltering(TEXTURE_FILTER_MAG_BILINEAR, TEXTURE_FILTER_MIN_BILINEAR)
-		this.tTextures[i].SetSamplerParameter(gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
-		this.tTextures[i].SetSamplerParameter(gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+  Result:	Converts file to cube faces and loads
+  		them into cubemap.
+
+  /*---------------------------------------------*/
+
+func (this *CSkybox) LoadSkyboxFromFile(sPath string, iFaceSize int) bool {
+	if libs.IsTextureContainerFile(sPath) {
+		texData, err := libs.LoadTextureFile(sPath)
+		if err != nil {
+			fmt.Println("Couldn't load skybox", sPath, ":", err)
+			return false
+		}
+		if texData.IsCubemap() {
+			if !this.tCubemap.CreateCubemapFromTextureData(texData, false) {
+				return false
+			}
+			this.finishLoading(sPath)
+			return true
+		}
 	}
 
+	img, err := loadImageFile(sPath)
+	if err != nil {
+		fmt.Println("Couldn't load skybox", sPath, ":", err)
+		return false
+	}
+	_, bHDR := img.(*libs.FloatImage)
+	floatImage := libs.ToFloatImage(img)
+
+	var vFloatFaces [6]*libs.FloatImage
+	if floatImage.Width == 2*floatImage.Height {
+		vFloatFaces = libs.CubeFacesFromEquirect(floatImage, iFaceSize)
+	} else if vFloatFaces, err = libs.CubeFacesFromCross(floatImage); err != nil {
+		fmt.Println("Couldn't load skybox", sPath, ":", err)
+		return false
+	}
+
+	// LDR faces go back to 8 bits, so they don't take float memory
+	var vFaces [6]image.Image
+	for i, face := range vFloatFaces {
+		if bHDR {
+			vFaces[i] = face
+		} else {
+			vFaces[i] = face.ToNRGBA()
+		}
+	}
+	if !this.tCubemap.CreateCubemap(vFaces, false) {
+		return false
+	}
+	this.finishLoading(sPath)
+	return true
+}
+
+func (this *CSkybox) finishLoading(sPath string) {
+	this.thCubemap = nil
+	this.sFile = sPath
+	this.sDirectory = ""
+	this.sFront, this.sBack, this.sLeft, this.sRight, this.sTop, this.sBottom = "", "", "", "", "", ""
+	this.createGeometry()
+}
+
+// createGeometry creates unit cube around origin, vertex positions are also directions into cubemap
+func (this *CSkybox) createGeometry() {
+	if this.thCubemap != nil {
+		// tCubemap isn't created on async path, handle applies sampler once its cubemap is uploaded
+		sdSkybox := DefaultSamplerDesc()
+		sdSkybox.MagFilter, sdSkybox.MinFilter = TEXTURE_FILTER_MAG_BILINEAR, TEXTURE_FILTER_MIN_BILINEAR
+		this.thCubemap.SetSamplerDesc(sdSkybox)
+	} else {
+		this.tCubemap.SetFiltering(TEXTURE_FILTER_MAG_BILINEAR, TEXTURE_FILTER_MIN_BILINEAR)
+	}
+	gl.Enable(gl.TEXTURE_CUBE_MAP_SEAMLESS)
+
+	if this.uiVAO != 0 {
+		return
+	}
 	gl.GenVertexArrays(1, &this.uiVAO)
 	gl.BindVertexArray(this.uiVAO)
 	this.vboRenderData = NewCVertexBufferObject()
 	this.vboRenderData.CreateVBO(0)
 	this.vboRenderData.BindVBO(gl.ARRAY_BUFFER)
 
-	var vSkyBoxVertices [24]mgl32.Vec3 = [24]mgl32.Vec3{
-		// Front face
-		mgl32.Vec3{200.0, 200.0, 200.0}, mgl32.Vec3{200.0, -200.0, 200.0}, mgl32.Vec3{-200.0, 200.0, 200.0}, mgl32.Vec3{-200.0, -200.0, 200.0},
-		// Back face
-		mgl32.Vec3{-200.0, 200.0, -200.0}, mgl32.Vec3{-200.0, -200.0, -200.0}, mgl32.Vec3{200.0, 200.0, -200.0}, mgl32.Vec3{200.0, -200.0, -200.0},
-		// Left face
-		mgl32.Vec3{-200.0, 200.0, 200.0}, mgl32.Vec3{-200.0, -200.0, 200.0}, mgl32.Vec3{-200.0, 200.0, -200.0}, mgl32.Vec3{-200.0, -200.0, -200.0},
-		// Right face
-		mgl32.Vec3{200.0, 200.0, -200.0}, mgl32.Vec3{200.0, -200.0, -200.0}, mgl32.Vec3{200.0, 200.0, 200.0}, mgl32.Vec3{200.0, -200.0, 200.0},
-		// Top face
-		mgl32.Vec3{-200.0, 200.0, -200.0}, mgl32.Vec3{200.0, 200.0, -200.0}, mgl32.Vec3{-200.0, 200.0, 200.0}, mgl32.Vec3{200.0, 200.0, 200.0},
-		// Bottom face
-		mgl32.Vec3{200.0, -200.0, -200.0}, mgl32.Vec3{-200.0, -200.0, -200.0}, mgl32.Vec3{200.0, -200.0, 200.0}, mgl32.Vec3{-200.0, -200.0, 200.0},
-	}
-
-	var vSkyBoxTexCoords [4]mgl32.Vec2 = [4]mgl32.Vec2{
-		mgl32.Vec2{0.0, 1.0}, mgl32.Vec2{0.0, 0.0}, mgl32.Vec2{1.0, 1.0}, mgl32.Vec2{1.0, 0.0},
+	var vCorners [8]mgl32.Vec3 = [8]mgl32.Vec3{
+		{-1, -1, -1}, {1, -1, -1}, {1, 1, -1}, {-1, 1, -1},
+		{-1, -1, 1}, {1, -1, 1}, {1, 1, 1}, {-1, 1, 1},
 	}
-
-	var vSkyBoxNormals [6]mgl32.Vec3 = [6]mgl32.Vec3{
-		mgl32.Vec3{0.0, 0.0, -1.0},
-		mgl32.Vec3{0.0, 0.0, 1.0},
-		mgl32.Vec3{1.0, 0.0, 0.0},
-		mgl32.Vec3{-1.0, 0.0, 0.0},
-		mgl32.Vec3{0.0, -1.0, 0.0},
-		mgl32.Vec3{0.0, 1.0, 0.0},
+	// Two triangles per face, facing inside the cube
+	var vIndices [36]int = [36]int{
+		0, 1, 2, 2, 3, 0, // Back
+		5, 4, 7, 7, 6, 5, // Front
+		4, 0, 3, 3, 7, 4, // Left
+		1, 5, 6, 6, 2, 1, // Right
+		3, 2, 6, 6, 7, 3, // Top
+		4, 5, 1, 1, 0, 4, // Bottom
 	}
-
-	for i := 0; i < 24; i++ {
-		this.vboRenderData.AddData(EncodeToBytes(vSkyBoxVertices[i]), int32(unsafe.Sizeof(mgl32.Vec3{})))
-		this.vboRenderData.AddData(EncodeToBytes(vSkyBoxTexCoords[i%4]), int32(unsafe.Sizeof(mgl32.Vec2{})))
-		this.vboRenderData.AddData(EncodeToBytes(vSkyBoxNormals[i/4]), int32(unsafe.Sizeof(mgl32.Vec3{})))
+	for _, iIndex := range vIndices {
+		this.vboRenderData.AddData(EncodeToBytes(vCorners[iIndex]), int32(unsafe.Sizeof(mgl32.Vec3{})))
 	}
 
 	this.vboRenderData.UploadDataToGPU(gl.STATIC_DRAW)
 
 	// Vertex positions
 	gl.EnableVertexAttribArray(0)
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, int32(2*unsafe.Sizeof(mgl32.Vec3{})+unsafe.Sizeof(mgl32.Vec2{})), nil)
-	// Texture coordinates
-	gl.EnableVertexAttribArray(1)
-	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, int32(2*unsafe.Sizeof(mgl32.Vec3{})+unsafe.Sizeof(mgl32.Vec2{})), unsafe.Pointer(unsafe.Sizeof(mgl32.Vec3{})))
-	// Normal vectors
-	gl.EnableVertexAttribArray(2)
-	gl.VertexAttribPointer(2, 3, gl.FLOAT, false, int32(2*unsafe.Sizeof(mgl32.Vec3{})+unsafe.Sizeof(mgl32.Vec2{})),
-		unsafe.Pointer(unsafe.Sizeof(mgl32.Vec3{})+unsafe.Sizeof(mgl32.Vec2{})))
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, int32(unsafe.Sizeof(mgl32.Vec3{})), nil)
 }
 
-func (this *CSkybox) RenderSkybox() {
+/*-----------------------------------------------
+
+  Name:	RenderSkybox
+
+  Params:	mProjection - projection matrix
+  		mView - camera view matrix, its translation
+  		is dropped
+  		vColor - color multiplying sky
+
+  Result:	Renders sky at infinite distance. Call it
+  		after opaque geometry, only pixels nothing
+  		was drawn to pass the depth test.
+
+  /*---------------------------------------------*/
+
+func (this *CSkybox) RenderSkybox(mProjection, mView mgl32.Mat4, vColor mgl32.Vec4) {
+	spSkybox.UseProgram()
+	spSkybox.SetUniformM4("mViewProjection", mProjection.Mul4(mView.Mat3().Mat4()))
+	spSkybox.SetUniformI32("gCubemap", 0)
+	spSkybox.SetUniformV4("vColor", vColor)
+	dlSun.SetUniformData(&spSkybox, "sunLight")
+
+	gl.DepthFunc(gl.LEQUAL)
 	gl.DepthMask(false)
+	this.getCubemap().BindTexture(0)
 	gl.BindVertexArray(this.uiVAO)
-	for i := 0; i < 6; i++ {
-		this.tTextures[i].BindTexture(0)
-		gl.DrawArrays(gl.TRIANGLE_STRIP, int32(i*4), 4)
-	}
+	gl.DrawArrays(gl.TRIANGLES, 0, 36)
 	gl.DepthMask(true)
+	gl.DepthFunc(gl.LESS)
+}
+
+// getCubemap returns cubemap being rendered, placeholder while LoadSkyboxAsync waits for faces
+func (this *CSkybox) getCubemap() *CTexture {
+	if this.thCubemap != nil {
+		return this.thCubemap.GetTexture()
+	}
+	return &this.tCubemap
 }
 
 func (this *CSkybox) DeleteSkybox() {
-	for i := 0; i < 6; i++ {
-		this.tTextures[i].DeleteTexture()
+	this.tCubemap.DeleteTexture()
+	if this.thCubemap != nil {
+		this.thCubemap.DeleteTexture()
+		this.thCubemap = nil
 	}
 	gl.DeleteVertexArrays(1, &this.uiVAO)
-	this.vboRenderData.DeleteVBO()
+	this.uiVAO = 0
+	if this.vboRenderData != nil {
+		this.vboRenderData.DeleteVBO()
+	}
 }
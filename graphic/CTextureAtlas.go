@@ -0,0 +1,149 @@
+package graphic
+
+import (
+	"antry/libs"
+	"fmt"
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"image"
+)
+
+/*-----------------------------------------------
+
+  Name:	CTextureAtlas
+
+  Result:	Texture holding many small images packed
+  		by libs.Atlas. Entries can be added at any
+  		time, Update uploads the changed part.
+
+  /*---------------------------------------------*/
+
+type CTextureAtlas struct {
+	atlas     *libs.Atlas
+	tTexture  CTexture
+	usage     ETextureUsage
+	mipFilter libs.MipFilter
+
+	iUploadedRevision int // Atlas revision in texture, -1 before first upload
+}
+
+func NewCTextureAtlas(iWidth, iHeight, iPadding int, usage ETextureUsage) *CTextureAtlas {
+	return &CTextureAtlas{atlas: libs.NewAtlas(iWidth, iHeight, iPadding), usage: usage, iUploadedRevision: -1}
+}
+
+// LoadTextureAtlas loads atlas pre-baked by libs.Atlas.SaveAtlas, it's uploaded by the first Update.
+func LoadTextureAtlas(sImagePath, sLayoutPath string, usage ETextureUsage) (*CTextureAtlas, error) {
+	atlas, err := libs.LoadAtlas(sImagePath, sLayoutPath)
+	if err != nil {
+		return nil, err
+	}
+	return &CTextureAtlas{atlas: atlas, usage: usage, iUploadedRevision: -1}, nil
+}
+
+// SetMipFilter chooses filter of generated mip levels, takes effect with the next upload.
+func (this *CTextureAtlas) SetMipFilter(filter libs.MipFilter) {
+	this.mipFilter = filter
+	this.iUploadedRevision = -1
+}
+
+// Add packs image into atlas, texture isn't changed until Update.
+func (this *CTextureAtlas) Add(sName string, img image.Image) (libs.AtlasEntry, error) {
+	return this.atlas.Add(sName, img)
+}
+
+// AddFile loads image file and packs it under its path.
+func (this *CTextureAtlas) AddFile(sPath string) (libs.AtlasEntry, error) {
+	img, err := loadImageFile(sPath)
+	if err != nil {
+		return libs.AtlasEntry{}, err
+	}
+	return this.atlas.Add(sPath, img)
+}
+
+/*-----------------------------------------------
+
+  Name:	Update
+
+  Params:	none
+
+  Result:	Uploads atlas with its mip levels if any
+  		entry was added since the last upload.
+  		Once texture exists, only the changed
+  		rectangle and mip regions it affects are
+  		uploaded. Texture stays at the same address.
+
+  /*---------------------------------------------*/
+
+func (this *CTextureAtlas) Update() bool {
+	if this.iUploadedRevision == this.atlas.GetRevision() {
+		return true
+	}
+	bLinear := this.usage == TEXTURE_USAGE_DATA
+	if this.tTexture.uiTexture != 0 && this.iUploadedRevision >= 0 {
+		rect := this.atlas.GetChangedRect(this.iUploadedRevision)
+		gl.BindTexture(gl.TEXTURE_2D, this.tTexture.uiTexture)
+		for _, region := range this.atlas.GenerateMipRegions(rect, this.mipFilter, bLinear) {
+			gl.TexSubImage2D(gl.TEXTURE_2D, int32(region.Level), int32(region.Rect.Min.X), int32(region.Rect.Min.Y),
+				int32(region.Rect.Dx()), int32(region.Rect.Dy()), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(region.Pix))
+		}
+		this.iUploadedRevision = this.atlas.GetRevision()
+		return true
+	}
+
+	texData := this.atlas.GetTextureData(this.mipFilter, bLinear)
+	if this.tTexture.uiTexture != 0 {
+		this.tTexture.DeleteTexture()
+	}
+	this.tTexture.SetUsage(this.usage)
+	if !this.tTexture.CreateFromTextureData(texData, false) {
+		fmt.Println("Couldn't upload texture atlas")
+		return false
+	}
+	// Entries are padded, but nothing lies beyond atlas edges
+	desc := DefaultSamplerDesc()
+	desc.SetWrap(gl.CLAMP_TO_EDGE)
+	desc.MagFilter = TEXTURE_FILTER_MAG_BILINEAR
+	desc.MinFilter = TEXTURE_FILTER_MIN_BILINEAR
+	if texData.MipLevels > 1 {
+		desc.MinFilter = TEXTURE_FILTER_MIN_TRILINEAR
+	}
+	this.tTexture.SetSamplerDesc(desc)
+	this.iUploadedRevision = this.atlas.GetRevision()
+	return true
+}
+
+// GetUV returns texture coordinates of entry's top left and bottom right corners.
+func (this *CTextureAtlas) GetUV(sName string) (float32, float32, float32, float32, bool) {
+	entry, bOK := this.atlas.Get(sName)
+	if !bOK {
+		return 0, 0, 0, 0, false
+	}
+	return entry.U0, entry.V0, entry.U1, entry.V1, true
+}
+
+func (this *CTextureAtlas) GetEntry(sName string) (libs.AtlasEntry, bool) {
+	return this.atlas.Get(sName)
+}
+
+func (this *CTextureAtlas) GetAtlas() *libs.Atlas {
+	return this.atlas
+}
+
+func (this *CTextureAtlas) GetTexture() *CTexture {
+	return &this.tTexture
+}
+
+// BindTexture uploads pending entries and binds atlas.
+func (this *CTextureAtlas) BindTexture(iTextureUnit uint32) {
+	this.Update()
+	this.tTexture.BindTexture(iTextureUnit)
+}
+
+// Save writes atlas image and layout, so it can be loaded pre-baked by LoadTextureAtlas.
+func (this *CTextureAtlas) Save(sImagePath, sLayoutPath string) error {
+	return this.atlas.SaveAtlas(sImagePath, sLayoutPath)
+}
+
+func (this *CTextureAtlas) DeleteAtlas() {
+	this.tTexture.DeleteTexture()
+	this.iUploadedRevision = -1
+}
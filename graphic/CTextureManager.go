@@ -0,0 +1,242 @@
+package graphic
+
+import (
+	"antry/libs"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// textureEntry is one loaded file together with the number of its users
+type textureEntry struct {
+	tTexture  CTexture
+	sPath     string // Canonical path
+	iRefCount int
+}
+
+// STextureMemoryInfo describes one texture in memory report
+type STextureMemoryInfo struct {
+	Path       string
+	Width      int32
+	Height     int32
+	Users      int
+	Bytes      int64
+	Compressed bool
+}
+
+/*-----------------------------------------------
+
+  Name:	CTextureManager
+
+  Result:	Shares textures loaded from files. Same
+  		file is loaded once, no matter how its
+  		path is written, and its GL texture is
+  		deleted when last user releases it.
+
+  /*---------------------------------------------*/
+
+type CTextureManager struct {
+	mEntries  map[string]*textureEntry
+	mTextures map[*CTexture]*textureEntry // Reverse lookup for ReleaseTexture
+}
+
+func NewCTextureManager() *CTextureManager {
+	this := CTextureManager{}
+	this.mEntries = make(map[string]*textureEntry)
+	this.mTextures = make(map[*CTexture]*textureEntry)
+	return &this
+}
+
+// Textures of models and other shared ones
+var tmTextures *CTextureManager = NewCTextureManager()
+
+func GetTextureManager() *CTextureManager {
+	return tmTextures
+}
+
+// CanonicalTexturePath turns asset path into clean VFS path, absolute OS paths get both separators turned into one and ./ and ../ removed.
+func CanonicalTexturePath(sPath string) string {
+	if !filepath.IsAbs(sPath) {
+		return libs.CleanAssetPath(sPath)
+	}
+	sPath = filepath.Clean(filepath.FromSlash(strings.ReplaceAll(sPath, "\\", "/")))
+	// Windows file names aren't case sensitive
+	if runtime.GOOS == "windows" {
+		sPath = strings.ToLower(sPath)
+	}
+	return sPath
+}
+
+/*-----------------------------------------------
+
+  Name:	AcquireTexture
+
+  Params:	sPath - texture file
+  		bGenerateMipMaps - used when the file is
+  		loaded for the first time
+
+  Result:	Returns shared texture of the file and
+  		counts caller as its user, nil if the file
+  		can't be loaded. Each successful call must
+  		be paired with ReleaseTexture.
+
+  /*---------------------------------------------*/
+
+func (this *CTextureManager) AcquireTexture(sPath string, bGenerateMipMaps bool) *CTexture {
+	return this.AcquireTextureEx(sPath, bGenerateMipMaps, TEXTURE_USAGE_COLOR)
+}
+
+// AcquireTextureEx works like AcquireTexture with given usage. File used both as colour and as data gives two textures.
+func (this *CTextureManager) AcquireTextureEx(sPath string, bGenerateMipMaps bool, usage ETextureUsage) *CTexture {
+	return this.acquire(sPath, usage, bGenerateMipMaps, func(texture *CTexture) bool {
+		return texture.LoadTexture2DEx(sPath, bGenerateMipMaps, usage)
+	})
+}
+
+// AcquireDecodedTexture works like AcquireTexture, but creates texture from data decoded by DecodeTextureFile if it isn't loaded yet.
+func (this *CTextureManager) AcquireDecodedTexture(sPath string, texData *libs.TextureData, bGenerateMipMaps bool) *CTexture {
+	return this.AcquireDecodedTextureEx(sPath, texData, bGenerateMipMaps, TEXTURE_USAGE_COLOR)
+}
+
+func (this *CTextureManager) AcquireDecodedTextureEx(sPath string, texData *libs.TextureData, bGenerateMipMaps bool, usage ETextureUsage) *CTexture {
+	return this.acquire(sPath, usage, bGenerateMipMaps, func(texture *CTexture) bool {
+		texture.SetUsage(usage)
+		if !texture.CreateFromTextureData(texData, bGenerateMipMaps) {
+			return false
+		}
+		texture.sPath = sPath
+		return true
+	})
+}
+
+// getTextureKey returns key of file's texture in manager, data textures are kept apart from colour ones
+func getTextureKey(sPath string, usage ETextureUsage) string {
+	var sKey string = CanonicalTexturePath(sPath)
+	if usage == TEXTURE_USAGE_DATA {
+		sKey += "#data"
+	}
+	return sKey
+}
+
+func (this *CTextureManager) acquire(sPath string, usage ETextureUsage, bGenerateMipMaps bool, fnCreate func(texture *CTexture) bool) *CTexture {
+	var sKey string = getTextureKey(sPath, usage)
+	if entry, bOK := this.mEntries[sKey]; bOK {
+		entry.iRefCount++
+		return &entry.tTexture
+	}
+	entry := &textureEntry{sPath: sKey, iRefCount: 1}
+	if !fnCreate(&entry.tTexture) {
+		fmt.Println("Texture manager couldn't load", sPath)
+		return nil
+	}
+	if bGenerateMipMaps {
+		entry.tTexture.SetFiltering(TEXTURE_FILTER_MAG_BILINEAR, TEXTURE_FILTER_MIN_TRILINEAR)
+	} else {
+		entry.tTexture.SetFiltering(TEXTURE_FILTER_MAG_BILINEAR, TEXTURE_FILTER_MIN_BILINEAR)
+	}
+	this.mEntries[sKey] = entry
+	this.mTextures[&entry.tTexture] = entry
+	return &entry.tTexture
+}
+
+// ReleaseTexture drops one user of texture from AcquireTexture, last one deletes it. Returns false for unknown textures.
+func (this *CTextureManager) ReleaseTexture(texture *CTexture) bool {
+	entry, bOK := this.mTextures[texture]
+	if !bOK {
+		return false
+	}
+	entry.iRefCount--
+	if entry.iRefCount <= 0 {
+		entry.tTexture.DeleteTexture()
+		delete(this.mTextures, texture)
+		delete(this.mEntries, entry.sPath)
+	}
+	return true
+}
+
+// GetRefCount returns number of users of file's textures of both usages, 0 if it's not loaded.
+func (this *CTextureManager) GetRefCount(sPath string) int {
+	var iRefCount int
+	for _, usage := range []ETextureUsage{TEXTURE_USAGE_COLOR, TEXTURE_USAGE_DATA} {
+		if entry, bOK := this.mEntries[getTextureKey(sPath, usage)]; bOK {
+			iRefCount += entry.iRefCount
+		}
+	}
+	return iRefCount
+}
+
+func (this *CTextureManager) GetNumTextures() int {
+	return len(this.mEntries)
+}
+
+// ReleaseAll deletes all textures, even those still having users.
+func (this *CTextureManager) ReleaseAll() {
+	for _, entry := range this.mEntries {
+		entry.tTexture.DeleteTexture()
+	}
+	this.mEntries = make(map[string]*textureEntry)
+	this.mTextures = make(map[*CTexture]*textureEntry)
+}
+
+// ReloadAll reloads every texture from its file, textures stay at the same addresses.
+func (this *CTextureManager) ReloadAll() {
+	for _, entry := range this.mEntries {
+		if !entry.tTexture.ReloadTexture() {
+			fmt.Println("Texture manager couldn't reload", entry.sPath)
+		}
+	}
+}
+
+/*-----------------------------------------------
+
+  Name:	GetMemoryReport
+
+  Params:	none
+
+  Result:	Returns estimated GPU memory of every
+  		managed texture, largest first.
+
+  /*---------------------------------------------*/
+
+func (this *CTextureManager) GetMemoryReport() []STextureMemoryInfo {
+	vReport := make([]STextureMemoryInfo, 0, len(this.mEntries))
+	for _, entry := range this.mEntries {
+		vReport = append(vReport, STextureMemoryInfo{
+			Path:       entry.sPath,
+			Width:      entry.tTexture.GetWidth(),
+			Height:     entry.tTexture.GetHeight(),
+			Users:      entry.iRefCount,
+			Bytes:      entry.tTexture.GetMemorySize(),
+			Compressed: entry.tTexture.IsCompressed(),
+		})
+	}
+	sort.Slice(vReport, func(i, j int) bool {
+		if vReport[i].Bytes != vReport[j].Bytes {
+			return vReport[i].Bytes > vReport[j].Bytes
+		}
+		return vReport[i].Path < vReport[j].Path
+	})
+	return vReport
+}
+
+func (this *CTextureManager) GetTotalMemory() int64 {
+	var iTotal int64
+	for _, entry := range this.mEntries {
+		iTotal += entry.tTexture.GetMemorySize()
+	}
+	return iTotal
+}
+
+// PrintMemoryReport prints memory report to standard output.
+func (this *CTextureManager) PrintMemoryReport() {
+	for _, info := range this.GetMemoryReport() {
+		sCompressed := ""
+		if info.Compressed {
+			sCompressed = ", compressed"
+		}
+		fmt.Printf("%8.2f MB  %dx%d, %d users%s  %s\n", float64(info.Bytes)/(1024*1024), info.Width, info.Height, info.Users, sCompressed, info.Path)
+	}
+	fmt.Printf("%8.2f MB  total in %d textures\n", float64(this.GetTotalMemory())/(1024*1024), this.GetNumTextures())
+}
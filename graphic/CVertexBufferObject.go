@@ -13,6 +13,7 @@ type CVertexBufferObject struct {
 	data         []byte
 
 	bDataUploaded bool
+	bKeepData     bool // Data stays in memory after upload, so the buffer can grow and be uploaded again
 }
 
 func NewCVertexBufferObject() *CVertexBufferObject {
@@ -60,10 +61,35 @@ func (this *CVertexBufferObject) BindVBO(a_iBufferType uint32) {
 	gl.BindBuffer(this.iBufferType, this.uiBuffer)
 }
 
+// BindVBOBase binds buffer to indexed binding point, e.g. gl.SHADER_STORAGE_BUFFER for compute shaders.
+func (this *CVertexBufferObject) BindVBOBase(a_iBufferType uint32, iIndex uint32) {
+	this.iBufferType = a_iBufferType
+	gl.BindBufferBase(this.iBufferType, iIndex, this.uiBuffer)
+}
+
 func (this *CVertexBufferObject) UploadDataToGPU(iDrawingHint uint32) {
-	gl.BufferData(this.iBufferType, len(this.data), unsafe.Pointer(&this.data[0]), iDrawingHint)
+	if len(this.data) == 0 {
+		gl.BufferData(this.iBufferType, 0, nil, iDrawingHint)
+	} else {
+		gl.BufferData(this.iBufferType, len(this.data), unsafe.Pointer(&this.data[0]), iDrawingHint)
+	}
 	this.bDataUploaded = true
-	this.data = nil
+	if !this.bKeepData {
+		this.data = nil
+	}
+}
+
+// UploadSubDataToGPU replaces part of buffer already on GPU, data kept in memory isn't changed.
+func (this *CVertexBufferObject) UploadSubDataToGPU(iOffset int, bData []byte) {
+	if !this.bDataUploaded || len(bData) == 0 {
+		return
+	}
+	gl.BufferSubData(this.iBufferType, iOffset, len(bData), unsafe.Pointer(&bData[0]))
+}
+
+// KeepDataAfterUpload keeps data in memory after UploadDataToGPU, AddData then appends to it.
+func (this *CVertexBufferObject) KeepDataAfterUpload(bKeep bool) {
+	this.bKeepData = bKeep
 }
 
 func (this *CVertexBufferObject) AddData(ptrData []byte, uiDataSize int32) {
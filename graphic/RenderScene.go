@@ -1,12 +1,14 @@
 package graphic
 
 import (
+	"antry/libs"
 	"fmt"
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/veandco/go-sdl2/sdl"
 	"math"
 	"os"
+	"time"
 )
 
 var vboSceneObjects CVertexBufferObject
@@ -23,6 +25,16 @@ var amModels [3]CAssimpModel
 
 var hmWorld CMultiLayeredHeightmap
 
+var alLoader *CAsyncLoader
+
+var sgScene *CSceneGraph
+
+// What frustum culling skipped in the last frame
+var csCulling SCullingStats
+
+// Time uploads of loaded assets may take each frame
+const UPLOAD_BUDGET_PER_FRAME = 4 * time.Millisecond
+
 /*-----------------------------------------------
 
 Name:    InitScene
@@ -43,8 +55,6 @@ func InitScene() {
 		return
 	}
 
-	LoadAllTextures()
-
 	gl.Enable(gl.DEPTH_TEST)
 	gl.ClearDepth(1.0)
 
@@ -55,21 +65,50 @@ func InitScene() {
 	cCamera = NewCFlyingCameraEx(mgl32.Vec3{0.0, 30.0, 100.0}, mgl32.Vec3{0.0, 30.0, 99.0}, mgl32.Vec3{0.0, 1.0, 0.0}, 25.0, 0.1)
 	cCamera.SetMovingKeys('W', 'S', 'A', 'D')
 
-	sbMainSkybox.LoadSkybox("data\\skyboxes\\elbrus\\", "elbrus_front.jpg", "elbrus_back.jpg",
-		"elbrus_right.jpg", "elbrus_left.jpg", "elbrus_top.jpg", "elbrus_top.jpg")
+	// Loose face files override the archive version of the skybox
+	if err := libs.GetAssetVFS().MountZip("data/skyboxes/elbrus/elbrus.zip", "data/skyboxes/elbrus", -1); err != nil {
+		fmt.Println("Couldn't mount skybox archive:", err)
+	}
 
 	dlSun = NewCDirectionalLightEx(mgl32.Vec3{1.0, 1.0, 1.0}, mgl32.Vec3{float32(math.Sqrt(2.0) / 2), float32(-math.Sqrt(2.0) / 2), 0}, 0.5)
 
-	amModels[0].LoadModelFromFile("data\\models\\Wolf\\Wolf.obj")
-	amModels[1].LoadModelFromFile("data\\models\\house\\house.3ds")
-	FinalizeVBO()
+	// Textures and models are decoded in background, placeholders are shown until they're uploaded
+	alLoader = NewCAsyncLoader(0)
+	LoadAllTextures(alLoader)
+	if err := sbMainSkybox.LoadSkyboxAsync(alLoader, "data/skyboxes/elbrus/", "elbrus_front.jpg", "elbrus_back.jpg",
+		"elbrus_right.jpg", "elbrus_left.jpg", "elbrus_top.jpg", "elbrus_top.jpg"); err != nil {
+		fmt.Println("Couldn't load skybox:", err)
+	}
+	for i, sPath := range []string{"data/models/Wolf/Wolf.obj", "data/models/house/house.3ds"} {
+		if _, err := alLoader.LoadModelAsync(sPath, &amModels[i]); err != nil {
+			fmt.Println("Couldn't load", sPath+":", err)
+		}
+	}
 
 	if !LoadTerrainShaderProgram() {
 		panic("LoadTerrainShaderProgram")
 	}
-	if !hmWorld.LoadHeightMapFromImage("data\\worlds\\consider_this_question.bmp") {
+	if !hmWorld.LoadHeightMapFromImage("data/worlds/consider_this_question.bmp") {
 		panic("LoadHeightMapFromImage")
 	}
+	hmWorld.SetRenderSize3(300.0, 35.0, 300.0)
+
+	// Models show up in their nodes once they're loaded
+	sgScene = NewCSceneGraph()
+	sgScene.CreateNode("sun", nil).AttachLight(dlSun)
+
+	snHouse := sgScene.CreateNode("house", nil)
+	snHouse.SetTranslation(mgl32.Vec3{320.0, 136.0, 0.0})
+	snHouse.SetScale(mgl32.Vec3{8.0, 8.0, 8.0}) // Casino :D
+	snHouse.AttachModel(&amModels[1])
+
+	// ... and also ONE wolf now only :P
+	snWolf := sgScene.CreateNode("wolf", nil)
+	snWolf.SetTranslation(mgl32.Vec3{-56.0, 61.6, 140.0})
+	snWolf.SetScale(mgl32.Vec3{2.8, 2.8, 2.8})
+	snWolf.AttachModel(&amModels[0])
+
+	sgScene.CreateNode("terrain", nil).AttachHeightmap(&hmWorld)
 
 }
 
@@ -93,6 +132,8 @@ func RenderScene(oglControl *COpenGLControl) {
 
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
+	alLoader.ProcessUploads(UPLOAD_BUDGET_PER_FRAME)
+
 	spMain.UseProgram()
 
 	spMain.SetUniformM4("matrices.projMatrix", *oglControl.GetProjectionMatrix())
@@ -103,6 +144,8 @@ func RenderScene(oglControl *COpenGLControl) {
 	spMain.SetUniformM4("matrices.modelMatrix", mgl32.Ident4())
 	spMain.SetUniformM4("matrices.normalMatrix", mgl32.Ident4())
 	spMain.SetUniformV4("vColor", mgl32.Vec4{1, 1, 1, 1})
+	spMain.SetUniformV3("vEyePosition", cCamera.vEye)
+	mtDefault.SetUniformData(&spMain, "material")
 
 	// This values will set the darkness of whole scene, that's why such name of variable :D
 	//var fAngleOfDarkness float32= 45.0f;
@@ -116,58 +159,33 @@ func RenderScene(oglControl *COpenGLControl) {
 	}
 	// Set the directional vector of light
 	dlSun.vDirection = mgl32.Vec3{float32(-math.Sin(float64(fAngleOfDarkness * 3.1415 / 180.0))), float32(-math.Cos(float64(fAngleOfDarkness * 3.1415 / 180.0))), 0.0}
-	dlSun.SetUniformData(&spMain, "sunLight")
-
-	spMain.SetUniformM4("matrices.modelMatrix", mgl32.Translate3D(cCamera.vEye.X(), cCamera.vEye.Y(), cCamera.vEye.Z()).Mul4(mgl32.Ident4()))
-	sbMainSkybox.RenderSkybox()
-
-	spMain.SetUniformM4("matrices.modelMatrix", mgl32.Ident4())
-
-	// Render a house
-
-	BindModelsVAO()
-
-	var mModel mgl32.Mat4 = mgl32.Translate3D(40.0, 17.0, 0).Mul4(mgl32.Ident4())
-	mModel = mgl32.Scale3D(8, 8, 8).Mul4(mModel) // Casino :D
-
-	spMain.SetModelAndNormalMatrix("matrices.modelMatrix", "matrices.normalMatrix", mModel)
-	amModels[1].RenderModel()
-
-	// ... and also ONE wolf now only :P
-
-	mModel = mgl32.Translate3D(-20.0, 22.0, 50).Mul4(mgl32.Ident4())
-	mModel = mgl32.Scale3D(2.8, 2.8, 2.8).Mul4(mModel)
-
-	spMain.SetModelAndNormalMatrix("matrices.modelMatrix", "matrices.normalMatrix", mModel)
-	amModels[0].RenderModel()
-
-	// Now we're going to render terrain
-
-	hmWorld.SetRenderSize3(300.0, 35.0, 300.0)
-	var spTerrain *CShaderProgram = GetShaderProgram()
-
-	spTerrain.UseProgram()
-
-	spTerrain.SetUniformM4("matrices.projMatrix", *oglControl.GetProjectionMatrix())
-	spTerrain.SetUniformM4("matrices.viewMatrix", cCamera.Look())
-
-	// We bind all 5 textures - 3 of them are textures for layers, 1 texture is a "path" texture, and last one is
-	// the places in heightmap where path should be and how intense should it be
-	for i := 0; i < 5; i++ {
-		var sSamplerName string
-		sSamplerName = fmt.Sprintf("gSampler[%d]", i)
-		tTextures[i].BindTexture(uint32(i))
-		spTerrain.SetUniformI32(sSamplerName, int32(i))
+	// Sun node turns the light, the first light in scene lights everything
+	var dlSceneSun *CDirectionalLight = dlSun
+	if vLights := sgScene.GetLights(); len(vLights) > 0 {
+		dlSceneSun = vLights[0].GetWorldLight()
 	}
+	dlSceneSun.SetUniformData(&spMain, "sunLight")
 
-	// ... set some uniforms
-	spTerrain.SetModelAndNormalMatrix("matrices.modelMatrix", "matrices.normalMatrix", mgl32.Ident4())
-	spTerrain.SetUniformV4("vColor", mgl32.Vec4{1, 1, 1, 1})
+	for i := range amModels {
+		amModels[i].UpdateAnimation(AppMain.sof(1))
+	}
 
-	dlSun.SetUniformData(spTerrain, "sunLight")
+	// Models and their meshes outside of view aren't drawn
+	var frustum libs.Frustum = libs.NewFrustum(*oglControl.GetProjectionMatrix(), cCamera.Look())
+	csCulling.Reset()
+	for _, node := range sgScene.GetRenderQueue() {
+		if model := node.GetModel(); model != nil && model.IsLoaded() {
+			spMain.UseProgram()
+			spMain.SetModelAndNormalMatrix("matrices.modelMatrix", "matrices.normalMatrix", node.GetWorldMatrix())
+			model.RenderModelCulled(node.GetWorldMatrix(), &frustum, &csCulling)
+		}
+		if heightmap := node.GetHeightmap(); heightmap != nil {
+			renderTerrain(oglControl, heightmap, node.GetWorldMatrix(), dlSceneSun)
+		}
+	}
 
-	// ... and finally render heightmap
-	hmWorld.RenderHeightmap()
+	// Sky goes last, so it's drawn only where nothing else is
+	sbMainSkybox.RenderSkybox(*oglControl.GetProjectionMatrix(), cCamera.Look(), mgl32.Vec4{1, 1, 1, 1})
 
 	cCamera.Update()
 
@@ -185,6 +203,15 @@ func RenderScene(oglControl *COpenGLControl) {
 
 	ftFont.PrintFormatted(20, int(h-30), 20, fmt.Sprintf("FPS: %d", oglControl.GetFPS()))
 	ftFont.PrintFormatted(20, int(h-80), 20, fmt.Sprintf("Heightmap size: %dx%d", hmWorld.GetNumHeightmapRows(), hmWorld.GetNumHeightmapCols()))
+	ftFont.PrintFormatted(20, int(h-170), 20, fmt.Sprintf("Meshes drawn: %d, culled: %d, models culled: %d/%d",
+		csCulling.MeshesDrawn, csCulling.MeshesCulled, csCulling.ModelsCulled, csCulling.ModelsTested))
+	if snPicked, _ := sgScene.Pick(cCamera.vEye, cCamera.vView.Sub(cCamera.vEye)); snPicked != nil {
+		ftFont.PrintFormatted(20, int(h-140), 20, fmt.Sprintf("Looking at: %s", snPicked.GetName()))
+	}
+	if !alLoader.IsIdle() {
+		iDone, iTotal := alLoader.GetProgress()
+		ftFont.PrintFormatted(20, int(h-110), 20, fmt.Sprintf("Loading: %d/%d (%.0f%%)", iDone, iTotal, alLoader.GetProgressFraction()*100))
+	}
 
 	gl.Enable(gl.DEPTH_TEST)
 
@@ -196,6 +223,55 @@ func RenderScene(oglControl *COpenGLControl) {
 	oglControl.SwapBuffers()
 }
 
+/*-----------------------------------------------
+
+  Name:    renderTerrain
+
+  Params:  oglControl - OpenGL control giving projection
+           heightmap - terrain to render
+           mModel - world matrix of its node
+           dlLight - sun lighting it
+
+  Result:  Binds terrain textures and renders it
+           with terrain program.
+
+  /*---------------------------------------------*/
+
+func renderTerrain(oglControl *COpenGLControl, heightmap *CMultiLayeredHeightmap, mModel mgl32.Mat4, dlLight *CDirectionalLight) {
+	var spTerrain *CShaderProgram = GetShaderProgram()
+
+	spTerrain.UseProgram()
+
+	spTerrain.SetUniformM4("matrices.projMatrix", *oglControl.GetProjectionMatrix())
+	spTerrain.SetUniformM4("matrices.viewMatrix", cCamera.Look())
+
+	if IsTerrainTextureArrayEnabled() {
+		// Layers and path texture are in one texture array, path map stays separate
+		thTerrainLayers.BindTexture(0)
+		spTerrain.SetUniformI32("gLayers", 0)
+		thTextures[4].BindTexture(1)
+		spTerrain.SetUniformI32("gPathMap", 1)
+	} else {
+		// We bind all 5 textures - 3 of them are textures for layers, 1 texture is a "path" texture, and last one is
+		// the places in heightmap where path should be and how intense should it be
+		for i := 0; i < 5; i++ {
+			var sSamplerName string
+			sSamplerName = fmt.Sprintf("gSampler[%d]", i)
+			thTextures[i].BindTexture(uint32(i))
+			spTerrain.SetUniformI32(sSamplerName, int32(i))
+		}
+	}
+
+	// ... set some uniforms
+	spTerrain.SetModelAndNormalMatrix("matrices.modelMatrix", "matrices.normalMatrix", mModel)
+	spTerrain.SetUniformV4("vColor", mgl32.Vec4{1, 1, 1, 1})
+
+	dlLight.SetUniformData(spTerrain, "sunLight")
+
+	// ... and finally render heightmap
+	heightmap.RenderHeightmap()
+}
+
 /*-----------------------------------------------
 
   Name:    ReleaseScene
@@ -207,14 +283,27 @@ func RenderScene(oglControl *COpenGLControl) {
   /*---------------------------------------------*/
 
 func ReleaseScene() {
+	alLoader.Shutdown()
 	for i := 0; i < NUMTEXTURES; i++ {
-		tTextures[i].DeleteTexture()
+		// Layers of texture array aren't loaded separately
+		if thTextures[i] != nil {
+			thTextures[i].DeleteTexture()
+		}
+	}
+	if IsTerrainTextureArrayEnabled() {
+		thTerrainLayers.DeleteTexture()
 	}
 	sbMainSkybox.DeleteSkybox()
+	for i := range amModels {
+		amModels[i].ReleaseModel()
+	}
+	tmTextures.ReleaseAll()
+	releasePlaceholderTextures()
 
 	spMain.DeleteProgram()
 	spOrtho2D.DeleteProgram()
 	spFont2D.DeleteProgram()
+	spSkybox.DeleteProgram()
 	for i := 0; i < NUMSHADERS; i++ {
 		shShaders[i].DeleteShader()
 	}
@@ -225,4 +314,5 @@ func ReleaseScene() {
 
 	hmWorld.ReleaseHeightmap()
 	ReleaseTerrainShaderProgram()
+	scSamplers.ReleaseAll()
 }
@@ -0,0 +1,398 @@
+package graphic
+
+import (
+	"antry/libs"
+	"encoding/binary"
+	"fmt"
+	"github.com/bloeys/assimp-go/asig"
+	"github.com/bloeys/gglm/gglm"
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+)
+
+// Position, texture coordinate, normal, tangent and bitangent of model's vertex
+const MODEL_VERTEX_SIZE = 14 * 4
+
+// Size of mBones array in main shader, skins with more joints are skinned on CPU
+const MAX_SKINNING_BONES = 64
+
+// Joints of model's vertex as four unsigned shorts and four float weights
+const MODEL_SKIN_VERTEX_SIZE = 2*libs.MAX_BONE_INFLUENCES + 4*libs.MAX_BONE_INFLUENCES
+
+// appendVertexSkin appends vertex's joints and weights in layout of model's skin VBO
+func appendVertexSkin(bData []byte, skin *libs.VertexSkin) []byte {
+	for _, iJoint := range skin.Joints {
+		bData = binary.LittleEndian.AppendUint16(bData, iJoint)
+	}
+	for _, fWeight := range skin.Weights {
+		bData = binary.LittleEndian.AppendUint32(bData, math.Float32bits(fWeight))
+	}
+	return bData
+}
+
+func decodeVertexSkin(bData []byte) libs.VertexSkin {
+	var skin libs.VertexSkin
+	for i := 0; i < libs.MAX_BONE_INFLUENCES; i++ {
+		skin.Joints[i] = binary.LittleEndian.Uint16(bData[2*i:])
+		skin.Weights[i] = math.Float32frombits(binary.LittleEndian.Uint32(bData[2*libs.MAX_BONE_INFLUENCES+4*i:]))
+	}
+	return skin
+}
+
+// gglmToMat4 copies assimp matrix, both are column-major
+func gglmToMat4(mMatrix *gglm.Mat4) mgl32.Mat4 {
+	var mResult mgl32.Mat4
+	for c := 0; c < 4; c++ {
+		for r := 0; r < 4; r++ {
+			mResult[c*4+r] = mMatrix.Data[c][r]
+		}
+	}
+	return mResult
+}
+
+// importAssimpSkeleton turns node hierarchy of assimp scene into skeleton, parents before children
+func importAssimpSkeleton(rootNode *asig.Node) (*libs.Skeleton, error) {
+	var vNodes []libs.SkeletonNode
+	var addNode func(node *asig.Node, iParent int)
+	addNode = func(node *asig.Node, iParent int) {
+		restPose := libs.IdentityPose()
+		if node.Transformation != nil {
+			restPose = libs.PoseFromMatrix(gglmToMat4(node.Transformation))
+		}
+		vNodes = append(vNodes, libs.SkeletonNode{Name: node.Name, Parent: iParent, RestPose: restPose})
+		iIndex := len(vNodes) - 1
+		for _, child := range node.Children {
+			addNode(child, iIndex)
+		}
+	}
+	if rootNode != nil {
+		addNode(rootNode, -1)
+	}
+	return libs.NewSkeleton(vNodes)
+}
+
+// importAssimpSkin returns skin made of mesh's bones and joints of each mesh vertex
+func importAssimpSkin(mesh *asig.Mesh, skeleton *libs.Skeleton) (libs.Skin, []libs.VertexSkin) {
+	var skin libs.Skin
+	vVertexSkins := make([]libs.VertexSkin, len(mesh.Vertices))
+	for _, bone := range mesh.Bones {
+		iNode := skeleton.FindNode(bone.Name)
+		if iNode < 0 {
+			fmt.Println("Bone", bone.Name, "has no node in hierarchy, it's ignored")
+			continue
+		}
+		iJoint := len(skin.Joints)
+		skin.Joints = append(skin.Joints, iNode)
+		skin.InverseBind = append(skin.InverseBind, gglmToMat4(&bone.OffsetMatrix))
+		for _, weight := range bone.Weights {
+			if int(weight.VertIndex) < len(vVertexSkins) {
+				vVertexSkins[weight.VertIndex].AddWeight(iJoint, weight.Weight)
+			}
+		}
+	}
+	for i := range vVertexSkins {
+		vVertexSkins[i].Normalize()
+	}
+	return skin, vVertexSkins
+}
+
+/*-----------------------------------------------
+
+  Name:	importAssimpAnimations
+
+  Params:	vAnimations - animations of assimp scene
+  		skeleton - nodes of the same scene
+
+  Result:	Turns node animations into clips, key times
+  		go from ticks to seconds. Channels of nodes
+  		missing in skeleton are skipped.
+
+  /*---------------------------------------------*/
+
+func importAssimpAnimations(vAnimations []*asig.Animation, skeleton *libs.Skeleton) []*libs.AnimationClip {
+	var vClips []*libs.AnimationClip
+	for i, animation := range vAnimations {
+		// Assimp leaves ticks per second at zero when file doesn't say, it assumes 25 then
+		fTicksPerSecond := animation.TicksPerSecond
+		if fTicksPerSecond <= 0 {
+			fTicksPerSecond = 25
+		}
+		var vChannels []libs.AnimationChannel
+		for _, nodeAnim := range animation.Channels {
+			iNode := skeleton.FindNode(nodeAnim.NodeName)
+			if iNode < 0 {
+				fmt.Println("Animated node", nodeAnim.NodeName, "has no node in hierarchy, it's ignored")
+				continue
+			}
+			translation := libs.AnimationChannel{Node: iNode, Path: libs.CHANNEL_TRANSLATION, Interpolation: libs.INTERPOLATION_LINEAR}
+			for _, key := range nodeAnim.PositionKeys {
+				translation.Times = append(translation.Times, float32(key.Time/fTicksPerSecond))
+				translation.Values = append(translation.Values, key.Value.Data[:]...)
+			}
+			rotation := libs.AnimationChannel{Node: iNode, Path: libs.CHANNEL_ROTATION, Interpolation: libs.INTERPOLATION_LINEAR}
+			for _, key := range nodeAnim.RotationKeys {
+				rotation.Times = append(rotation.Times, float32(key.Time/fTicksPerSecond))
+				rotation.Values = append(rotation.Values, key.Value.Data[:]...)
+			}
+			scale := libs.AnimationChannel{Node: iNode, Path: libs.CHANNEL_SCALE, Interpolation: libs.INTERPOLATION_LINEAR}
+			for _, key := range nodeAnim.ScalingKeys {
+				scale.Times = append(scale.Times, float32(key.Time/fTicksPerSecond))
+				scale.Values = append(scale.Values, key.Value.Data[:]...)
+			}
+			for _, channel := range []libs.AnimationChannel{translation, rotation, scale} {
+				if len(channel.Times) > 0 {
+					vChannels = append(vChannels, channel)
+				}
+			}
+		}
+		sName := animation.Name
+		if sName == "" {
+			sName = fmt.Sprintf("animation%d", i)
+		}
+		clip, err := libs.NewAnimationClip(sName, vChannels)
+		if err != nil {
+			fmt.Println("Animation", sName, "is ignored:", err)
+			continue
+		}
+		vClips = append(vClips, clip)
+	}
+	return vClips
+}
+
+/*-----------------------------------------------
+
+  Name:	setSkinning
+
+  Params:	data - imported model
+
+  Result:	Takes skeleton and clips of model, the
+  		first clip starts looping right away. Skins
+  		too big for the shader use CPU skinning.
+
+  /*---------------------------------------------*/
+
+func (this *CAssimpModel) setSkinning(data *CModelData) {
+	this.releaseSkinning()
+	this.iMeshSkins = append([]int(nil), data.iMeshSkins...)
+	for len(this.iMeshSkins) < len(data.iMeshSizes) {
+		this.iMeshSkins = append(this.iMeshSkins, -1)
+	}
+	if data.skeleton == nil || len(data.vSkins) == 0 {
+		return
+	}
+	this.skeleton = data.skeleton
+	this.vSkins = data.vSkins
+	this.vAnimations = data.vAnimations
+	this.bBindVertices = data.bVertexData
+	this.bSkinData = data.bSkinData
+	this.vJointMatrices = make([][]mgl32.Mat4, len(this.vSkins))
+	for _, skin := range this.vSkins {
+		if len(skin.Joints) > MAX_SKINNING_BONES {
+			this.bCPUSkinning = true
+		}
+	}
+	this.apPlayer = libs.NewAnimationPlayer(this.skeleton)
+	if len(this.vAnimations) > 0 {
+		this.apPlayer.Play(this.vAnimations[0], true)
+	}
+	this.updateJointMatrices()
+}
+
+func (this *CAssimpModel) releaseSkinning() {
+	this.skeleton = nil
+	this.vSkins = nil
+	this.vAnimations = nil
+	this.iMeshSkins = nil
+	this.apPlayer = nil
+	this.vJointMatrices = nil
+	this.bBindVertices = nil
+	this.bSkinData = nil
+	this.bSkinned = nil
+	this.bCPUSkinning = false
+}
+
+// IsAnimated tells whether model has skinned meshes.
+func (this *CAssimpModel) IsAnimated() bool {
+	return this.apPlayer != nil
+}
+
+func (this *CAssimpModel) GetSkeleton() *libs.Skeleton {
+	return this.skeleton
+}
+
+func (this *CAssimpModel) GetAnimations() []*libs.AnimationClip {
+	return this.vAnimations
+}
+
+// GetAnimationPlayer returns player of model's clips, nil for static models.
+func (this *CAssimpModel) GetAnimationPlayer() *libs.AnimationPlayer {
+	return this.apPlayer
+}
+
+// FindAnimation returns clip with name, nil if model has none.
+func (this *CAssimpModel) FindAnimation(sName string) *libs.AnimationClip {
+	for _, clip := range this.vAnimations {
+		if clip.Name == sName {
+			return clip
+		}
+	}
+	return nil
+}
+
+// PlayAnimation starts clip by name, returns false if model has no such clip.
+func (this *CAssimpModel) PlayAnimation(sName string, bLoop bool) bool {
+	clip := this.FindAnimation(sName)
+	if clip == nil || this.apPlayer == nil {
+		return false
+	}
+	this.apPlayer.Play(clip, bLoop)
+	return true
+}
+
+// CrossFadeAnimation blends clip by name over the playing one during fDuration seconds.
+func (this *CAssimpModel) CrossFadeAnimation(sName string, fDuration float32, bLoop bool) bool {
+	clip := this.FindAnimation(sName)
+	if clip == nil || this.apPlayer == nil {
+		return false
+	}
+	this.apPlayer.CrossFade(clip, fDuration, bLoop)
+	return true
+}
+
+// SetCPUSkinning switches between skinning in vertex shader and on CPU, skins too big for the shader stay on CPU.
+func (this *CAssimpModel) SetCPUSkinning(bCPU bool) {
+	if this.apPlayer == nil {
+		return
+	}
+	if !bCPU {
+		for _, skin := range this.vSkins {
+			if len(skin.Joints) > MAX_SKINNING_BONES {
+				return
+			}
+		}
+	}
+	if this.bCPUSkinning == bCPU {
+		return
+	}
+	this.bCPUSkinning = bCPU
+	if bCPU {
+		this.skinVerticesOnCPU()
+	} else if this.uiVAO != 0 {
+		// Shader skins bind pose again
+		this.vboVertices.BindVBO(gl.ARRAY_BUFFER)
+		this.vboVertices.UploadSubDataToGPU(0, this.bBindVertices)
+		this.bSkinned = nil
+	}
+}
+
+func (this *CAssimpModel) IsCPUSkinning() bool {
+	return this.bCPUSkinning
+}
+
+/*-----------------------------------------------
+
+  Name:	UpdateAnimation
+
+  Params:	fDelta - seconds since the last frame
+
+  Result:	Moves animation player and computes bone
+  		matrices. CPU skinning also moves vertices
+  		and uploads them.
+
+  /*---------------------------------------------*/
+
+func (this *CAssimpModel) UpdateAnimation(fDelta float32) {
+	if this.apPlayer == nil {
+		return
+	}
+	this.apPlayer.Update(fDelta)
+	this.updateJointMatrices()
+	if this.bCPUSkinning {
+		this.skinVerticesOnCPU()
+	}
+}
+
+func (this *CAssimpModel) updateJointMatrices() {
+	vGlobal := this.apPlayer.GetGlobalMatrices()
+	for i := range this.vSkins {
+		this.vJointMatrices[i] = this.vSkins[i].GetJointMatrices(vGlobal, this.vJointMatrices[i])
+	}
+}
+
+/*-----------------------------------------------
+
+  Name:	skinVerticesOnCPU
+
+  Params:	none
+
+  Result:	Moves bind pose vertices of skinned meshes
+  		by the current bone matrices and uploads
+  		them over model's vertex buffer.
+
+  /*---------------------------------------------*/
+
+func (this *CAssimpModel) skinVerticesOnCPU() {
+	if len(this.bBindVertices) == 0 {
+		return
+	}
+	if len(this.bSkinned) != len(this.bBindVertices) {
+		this.bSkinned = append([]byte(nil), this.bBindVertices...)
+	}
+	for iMesh, iSkin := range this.iMeshSkins {
+		if iSkin < 0 {
+			continue
+		}
+		vJointMatrices := this.vJointMatrices[iSkin]
+		iFirst := int(this.iMeshFirstVertices[iMesh])
+		for iVertex := iFirst; iVertex < iFirst+int(this.iMeshNumVertices[iMesh]); iVertex++ {
+			skin := decodeVertexSkin(this.bSkinData[iVertex*MODEL_SKIN_VERTEX_SIZE:])
+			mSkin, bWeighted := skin.GetSkinMatrix(vJointMatrices)
+			if !bWeighted {
+				continue
+			}
+			bBind := this.bBindVertices[iVertex*MODEL_VERTEX_SIZE:]
+			bSkinned := this.bSkinned[iVertex*MODEL_VERTEX_SIZE:]
+			// Position is moved, normal, tangent and bitangent are only turned
+			writeVec3(bSkinned[0:], mSkin.Mul4x1(readVec3(bBind[0:]).Vec4(1)).Vec3())
+			for _, iOffset := range []int{20, 32, 44} {
+				vDirection := mSkin.Mul4x1(readVec3(bBind[iOffset:]).Vec4(0)).Vec3()
+				if vDirection.Len() > 0 {
+					vDirection = vDirection.Normalize()
+				}
+				writeVec3(bSkinned[iOffset:], vDirection)
+			}
+		}
+	}
+	if this.uiVAO != 0 {
+		this.vboVertices.BindVBO(gl.ARRAY_BUFFER)
+		this.vboVertices.UploadSubDataToGPU(0, this.bSkinned)
+	}
+}
+
+func readVec3(bData []byte) mgl32.Vec3 {
+	var vResult mgl32.Vec3
+	for c := range vResult {
+		vResult[c] = math.Float32frombits(binary.LittleEndian.Uint32(bData[4*c:]))
+	}
+	return vResult
+}
+
+func writeVec3(bData []byte, vVector mgl32.Vec3) {
+	for c := range vVector {
+		binary.LittleEndian.PutUint32(bData[4*c:], math.Float32bits(vVector[c]))
+	}
+}
+
+// setSkinningUniforms gives shader bone matrices of mesh, or turns skinning off for it
+func (this *CAssimpModel) setSkinningUniforms(spProgram *CShaderProgram, iMesh int) {
+	if this.bCPUSkinning || iMesh >= len(this.iMeshSkins) || this.iMeshSkins[iMesh] < 0 {
+		spProgram.SetUniformI32("bSkinned", 0)
+		return
+	}
+	vJointMatrices := this.vJointMatrices[this.iMeshSkins[iMesh]]
+	spProgram.SetUniformI32("bSkinned", 1)
+	if len(vJointMatrices) > 0 {
+		spProgram.SetUniformM4N("mBones", &vJointMatrices[0], int32(len(vJointMatrices)))
+	}
+}
@@ -0,0 +1,148 @@
+package graphic
+
+import (
+	"antry/libs"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SCullingStats counts what frustum culling tested and skipped, reset it each frame
+type SCullingStats struct {
+	ModelsTested int
+	ModelsCulled int
+	MeshesTested int
+	MeshesCulled int
+	MeshesDrawn  int
+}
+
+func (this *SCullingStats) Reset() {
+	*this = SCullingStats{}
+}
+
+/*-----------------------------------------------
+
+  Name:	computeBounds
+
+  Params:	none
+
+  Result:	Computes box and sphere of each mesh from
+  		its vertex range and of the whole model
+  		from all vertices.
+
+  /*---------------------------------------------*/
+
+func (this *CModelData) computeBounds() {
+	this.vMeshBoxes = make([]libs.AABB, len(this.iMeshFirstVertices))
+	this.vMeshSpheres = make([]libs.Sphere, len(this.iMeshFirstVertices))
+	this.boxModel = libs.EmptyAABB()
+	var vAllPositions []mgl32.Vec3
+	for i, iFirst := range this.iMeshFirstVertices {
+		vPositions := make([]mgl32.Vec3, 0, this.iMeshNumVertices[i])
+		for iVertex := int(iFirst); iVertex < int(iFirst+this.iMeshNumVertices[i]); iVertex++ {
+			vPositions = append(vPositions, readVec3(this.bVertexData[iVertex*MODEL_VERTEX_SIZE:]))
+		}
+		this.vMeshBoxes[i] = libs.AABBFromPoints(vPositions)
+		this.vMeshSpheres[i] = libs.SphereFromPoints(vPositions)
+		this.boxModel = this.boxModel.Merge(this.vMeshBoxes[i])
+		vAllPositions = append(vAllPositions, vPositions...)
+	}
+	this.sphereModel = libs.SphereFromPoints(vAllPositions)
+}
+
+// GetBoundingBox returns box around model's vertices in bind pose, in model's space.
+func (this *CAssimpModel) GetBoundingBox() libs.AABB {
+	return this.boxModel
+}
+
+func (this *CAssimpModel) GetBoundingSphere() libs.Sphere {
+	return this.sphereModel
+}
+
+// GetMeshBoundingBox returns box of mesh in model's space, empty box for meshes out of range.
+func (this *CAssimpModel) GetMeshBoundingBox(iMesh int) libs.AABB {
+	if iMesh < 0 || iMesh >= len(this.vMeshBoxes) {
+		return libs.EmptyAABB()
+	}
+	return this.vMeshBoxes[iMesh]
+}
+
+func (this *CAssimpModel) GetMeshBoundingSphere(iMesh int) libs.Sphere {
+	if iMesh < 0 || iMesh >= len(this.vMeshSpheres) {
+		return libs.Sphere{Radius: -1}
+	}
+	return this.vMeshSpheres[iMesh]
+}
+
+// isMeshSkinned tells whether mesh moves with skeleton, then its bind pose bounds don't hold
+func (this *CAssimpModel) isMeshSkinned(iMesh int) bool {
+	return this.apPlayer != nil && iMesh < len(this.iMeshSkins) && this.iMeshSkins[iMesh] >= 0
+}
+
+// IsVisibleIn tells whether model at world matrix may be seen in frustum, animated models are always visible.
+func (this *CAssimpModel) IsVisibleIn(frustum *libs.Frustum, mWorld mgl32.Mat4) bool {
+	if !this.bLoaded {
+		return false
+	}
+	if this.IsAnimated() {
+		return true
+	}
+	if frustum.TestSphere(this.sphereModel.Transform(mWorld)) == libs.FRUSTUM_OUTSIDE {
+		return false
+	}
+	return frustum.TestAABB(this.boxModel.Transform(mWorld)) != libs.FRUSTUM_OUTSIDE
+}
+
+/*-----------------------------------------------
+
+  Name:	RenderModelCulled
+
+  Params:	mWorld - model matrix already given to
+  		program
+  		frustum - world space frustum of camera
+  		stats - counters to add to, may be nil
+
+  Result:	Renders model with main program, skipping
+  		it whole or meshes outside frustum. Sphere
+  		test rejects first, box test is tighter.
+  		Skinned meshes are always drawn.
+
+  /*---------------------------------------------*/
+
+func (this *CAssimpModel) RenderModelCulled(mWorld mgl32.Mat4, frustum *libs.Frustum, stats *SCullingStats) {
+	this.RenderModelCulledEx(&spMain, mWorld, frustum, stats)
+}
+
+func (this *CAssimpModel) RenderModelCulledEx(spProgram *CShaderProgram, mWorld mgl32.Mat4, frustum *libs.Frustum, stats *SCullingStats) {
+	if !this.bLoaded {
+		return
+	}
+	if stats == nil {
+		stats = &SCullingStats{}
+	}
+	stats.ModelsTested++
+	if !this.IsVisibleIn(frustum, mWorld) {
+		stats.ModelsCulled++
+		return
+	}
+	if len(this.bMeshVisible) != len(this.iMeshSizes) {
+		this.bMeshVisible = make([]bool, len(this.iMeshSizes))
+	}
+	for i := range this.iMeshSizes {
+		this.bMeshVisible[i] = true
+		if this.isMeshSkinned(i) || i >= len(this.vMeshBoxes) {
+			stats.MeshesDrawn++
+			continue
+		}
+		stats.MeshesTested++
+		test := frustum.TestSphere(this.vMeshSpheres[i].Transform(mWorld))
+		if test == libs.FRUSTUM_INTERSECTS {
+			test = frustum.TestAABB(this.vMeshBoxes[i].Transform(mWorld))
+		}
+		if test == libs.FRUSTUM_OUTSIDE {
+			this.bMeshVisible[i] = false
+			stats.MeshesCulled++
+			continue
+		}
+		stats.MeshesDrawn++
+	}
+	this.renderMeshes(spProgram, this.bMeshVisible)
+}
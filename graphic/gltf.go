@@ -0,0 +1,214 @@
+package graphic
+
+import (
+	"antry/libs"
+	"bytes"
+	"fmt"
+	"github.com/go-gl/mathgl/mgl32"
+	"image"
+)
+
+// SPBRMaterial is metallic-roughness material of model, texture paths are empty when material has no such texture
+type SPBRMaterial struct {
+	Name string
+
+	BaseColorFactor mgl32.Vec4
+	MetallicFactor  float32
+	RoughnessFactor float32
+	EmissiveFactor  mgl32.Vec3
+
+	BaseColorTexture         string // Also diffuse texture the model renders with
+	MetallicRoughnessTexture string
+	NormalTexture            string
+	NormalScale              float32
+	OcclusionTexture         string
+	OcclusionStrength        float32
+	EmissiveTexture          string
+
+	AlphaMode   string // OPAQUE, MASK or BLEND
+	AlphaCutoff float32
+	DoubleSided bool
+}
+
+// GetPBRMaterial returns metallic-roughness parameters of material, false for models that don't have them.
+func (this *CAssimpModel) GetPBRMaterial(iMaterial int) (SPBRMaterial, bool) {
+	if iMaterial < 0 || iMaterial >= len(this.vPBRMaterials) {
+		return SPBRMaterial{}, false
+	}
+	return this.vPBRMaterials[iMaterial], true
+}
+
+/*-----------------------------------------------
+
+  Name:	ImportGLTFModelData
+
+  Params:	sFilePath - .gltf or .glb model
+
+  Result:	Loads model with pure Go glTF loader into
+  		the same data assimp import gives. Images
+  		stored inside the model are decoded here.
+  		Skins and animations are kept for skinning.
+
+  /*---------------------------------------------*/
+
+func ImportGLTFModelData(sFilePath string) (*CModelData, error) {
+	model, err := libs.LoadGLTFFile(sFilePath)
+	if err != nil {
+		return nil, err
+	}
+	data := &CModelData{sPath: sFilePath}
+
+	// Last material is the default one for primitives without material
+	iDefaultMaterial := len(model.Materials)
+	var vMaterials []libs.GLTFMaterial = append(model.Materials, libs.DefaultGLTFMaterial())
+	vEmbedded := make(map[int]*libs.TextureData)
+	getImagePath := func(iImage int) string {
+		if iImage < 0 {
+			return ""
+		}
+		return model.Images[iImage].Path
+	}
+	for _, material := range vMaterials {
+		data.vPBRMaterials = append(data.vPBRMaterials, SPBRMaterial{
+			Name:                     material.Name,
+			BaseColorFactor:          material.BaseColorFactor,
+			MetallicFactor:           material.MetallicFactor,
+			RoughnessFactor:          material.RoughnessFactor,
+			EmissiveFactor:           material.EmissiveFactor,
+			BaseColorTexture:         getImagePath(material.BaseColorTexture),
+			MetallicRoughnessTexture: getImagePath(material.MetallicRoughnessTexture),
+			NormalTexture:            getImagePath(material.NormalTexture),
+			NormalScale:              material.NormalScale,
+			OcclusionTexture:         getImagePath(material.OcclusionTexture),
+			OcclusionStrength:        material.OcclusionStrength,
+			EmissiveTexture:          getImagePath(material.EmissiveTexture),
+			AlphaMode:                material.AlphaMode,
+			AlphaCutoff:              material.AlphaCutoff,
+			DoubleSided:              material.DoubleSided,
+		})
+		data.vMaterials = append(data.vMaterials, newGLTFMaterial(&material))
+		// glTF has no specular texture, metallic-roughness isn't used by the model shader
+		var vImages [NUM_TEXTURE_SLOTS]int = [NUM_TEXTURE_SLOTS]int{material.BaseColorTexture, material.NormalTexture, -1, material.EmissiveTexture}
+		var vPaths [NUM_TEXTURE_SLOTS]string
+		var vTextureData [NUM_TEXTURE_SLOTS]*libs.TextureData
+		for iSlot, iImage := range vImages {
+			vPaths[iSlot] = getImagePath(iImage)
+			if iImage < 0 || model.Images[iImage].Data == nil {
+				continue
+			}
+			if vEmbedded[iImage] == nil {
+				if vEmbedded[iImage], err = decodeEmbeddedImage(model.Images[iImage].Data); err != nil {
+					return nil, fmt.Errorf("%s: %v", model.Images[iImage].Path, err)
+				}
+			}
+			vTextureData[iSlot] = vEmbedded[iImage]
+		}
+		data.vTexturePaths = append(data.vTexturePaths, vPaths)
+		data.vTextureData = append(data.vTextureData, vTextureData)
+	}
+
+	// Animations move only skinned primitives, models without skins are static
+	if len(model.Skins) > 0 {
+		data.skeleton = model.Skeleton
+		data.vSkins = model.Skins
+		data.vAnimations = model.Animations
+	}
+
+	// Primitives keep their indices, those without normals get vertex for each corner with flat normal
+	var vboData CVertexBufferObject
+	for i := range model.Primitives {
+		primitive := &model.Primitives[i]
+		iMaterial := primitive.Material
+		if iMaterial < 0 {
+			iMaterial = iDefaultMaterial
+		}
+		data.iMaterialIndices = append(data.iMaterialIndices, uint(iMaterial))
+		iSkin := primitive.Skin
+		if primitive.Skinning == nil {
+			iSkin = -1
+		}
+		data.iMeshSkins = append(data.iMeshSkins, iSkin)
+		var iBaseVertex int32 = vboData.GetCurrentSize() / MODEL_VERTEX_SIZE
+		var iFirstIndex int32 = int32(len(data.vIndices))
+		data.iMeshFirstVertices = append(data.iMeshFirstVertices, iBaseVertex)
+		data.iMeshStartIndices = append(data.iMeshStartIndices, iFirstIndex)
+		addVertex := func(iIndex uint32, normal [3]float32, bSmooth bool) {
+			var uv [2]float32
+			if primitive.TexCoords != nil {
+				uv = primitive.TexCoords[iIndex]
+			}
+			// Bitangent is rebuilt from normal and tangent, flat shaded vertices have no tangent space
+			var tangent, bitangent mgl32.Vec3
+			if bSmooth && primitive.Tangents != nil {
+				vTangent := primitive.Tangents[iIndex]
+				tangent = mgl32.Vec3{vTangent[0], vTangent[1], vTangent[2]}
+				bitangent = mgl32.Vec3(normal).Cross(tangent).Mul(vTangent[3])
+			}
+			vboData.AddData(EncodeToBytes(primitive.Positions[iIndex]), 12)
+			vboData.AddData(EncodeToBytes(uv), 8)
+			vboData.AddData(EncodeToBytes(normal), 12)
+			vboData.AddData(EncodeToBytes(tangent), 12)
+			vboData.AddData(EncodeToBytes(bitangent), 12)
+			if data.skeleton != nil {
+				var vertexSkin libs.VertexSkin
+				if iSkin >= 0 {
+					vertexSkin = primitive.Skinning[iIndex]
+				}
+				data.bSkinData = appendVertexSkin(data.bSkinData, &vertexSkin)
+			}
+		}
+		if primitive.Normals != nil {
+			for iIndex := range primitive.Positions {
+				addVertex(uint32(iIndex), primitive.Normals[iIndex], true)
+			}
+			for _, iIndex := range primitive.Indices {
+				data.vIndices = append(data.vIndices, uint32(iBaseVertex)+iIndex)
+			}
+		} else {
+			for j := 0; j+2 < len(primitive.Indices); j += 3 {
+				vTriangle := primitive.Indices[j : j+3]
+				var vFaceNormal [3]float32
+				vA, vB, vC := mgl32.Vec3(primitive.Positions[vTriangle[0]]), mgl32.Vec3(primitive.Positions[vTriangle[1]]), mgl32.Vec3(primitive.Positions[vTriangle[2]])
+				if vNormal := vB.Sub(vA).Cross(vC.Sub(vA)); vNormal.Len() > 0 {
+					vFaceNormal = vNormal.Normalize()
+				}
+				for _, iIndex := range vTriangle {
+					data.vIndices = append(data.vIndices, uint32(vboData.GetCurrentSize()/MODEL_VERTEX_SIZE))
+					addVertex(iIndex, vFaceNormal, false)
+				}
+			}
+		}
+		data.iMeshNumVertices = append(data.iMeshNumVertices, vboData.GetCurrentSize()/MODEL_VERTEX_SIZE-iBaseVertex)
+		data.iMeshSizes = append(data.iMeshSizes, int32(len(data.vIndices))-iFirstIndex)
+	}
+	data.bVertexData = vboData.data
+	data.computeBounds()
+	return data, nil
+}
+
+// newGLTFMaterial approximates metallic-roughness material, metals reflect their base colour and other surfaces 4 %
+func newGLTFMaterial(material *libs.GLTFMaterial) CMaterial {
+	result := *NewCMaterial()
+	result.sName = material.Name
+	result.vDiffuseColor = mgl32.Vec4(material.BaseColorFactor).Vec3()
+	result.vSpecularColor = mgl32.Vec3{0.04, 0.04, 0.04}.Mul(1 - material.MetallicFactor).Add(result.vDiffuseColor.Mul(material.MetallicFactor))
+	result.vEmissiveColor = mgl32.Vec3(material.EmissiveFactor)
+	result.SetRoughness(material.RoughnessFactor)
+	result.bDoubleSided = material.DoubleSided
+	switch material.AlphaMode {
+	case "MASK":
+		result.SetAlphaTest(true, material.AlphaCutoff)
+	case "BLEND":
+		result.SetOpacity(material.BaseColorFactor[3])
+	}
+	return result
+}
+
+// decodeEmbeddedImage decodes image stored in glTF buffer or data URI to one RGBA8 level
+func decodeEmbeddedImage(bData []byte) (*libs.TextureData, error) {
+	img, _, err := image.Decode(bytes.NewReader(bData))
+	if err != nil {
+		return nil, err
+	}
+	return libs.CompressImage(img, libs.DDSEncodeOptions{Format: libs.TEXEL_FORMAT_RGBA8, NoMipmaps: true})
+}
@@ -4,13 +4,12 @@ import _ "image/jpeg"
 import (
 	"antry/libs"
 	_ "antry/libs"
+	"errors"
 	"fmt"
 	_ "github.com/ftrvxmtrx/tga"
 	"github.com/go-gl/gl/v4.1-core/gl"
+	xdraw "golang.org/x/image/draw"
 	"image"
-	"image/color"
-	"os"
-	"path/filepath"
 	"unsafe"
 )
 
@@ -27,6 +26,14 @@ const (
 	TEXTURE_FILTER_MIN_TRILINEAR                                // Bilinear criterion for minification on two closest mipmaps, then averaged
 )
 
+// ETextureUsage tells what texels hold, colour textures are sRGB encoded, data textures are linear
+type ETextureUsage int
+
+const (
+	TEXTURE_USAGE_COLOR ETextureUsage = iota // Albedo, skies, UI... decoded from sRGB when sampled
+	TEXTURE_USAGE_DATA                       // Normal maps, masks, heights... sampled as stored
+)
+
 const NUMTEXTURES = 5
 
 type CTexture struct {
@@ -35,7 +42,16 @@ type CTexture struct {
 	iBPP              int32  // Texture width, height, and bytes per pixel
 	uiTexture         uint32 // Texture name
 	uiSampler         uint32 // Sampler name
+	bSharedSampler    bool   // Sampler is from sampler cache, other textures may use it too
+	bSamplerDesc      bool   // sdSampler was set, default sampler state otherwise
+	sdSampler         SamplerDesc
+	tuUsage           ETextureUsage
+	bFloat            bool // Texels are half floats, e.g. from HDR image
 	bMipMapsGenerated bool
+	bCompressed       bool   // Texels are kept in GPU compressed format
+	iTarget           uint32 // GL_TEXTURE_2D_ARRAY for texture arrays, 0 means GL_TEXTURE_2D
+	iLayers           int32
+	vLayerPaths       []string // Files of texture array layers
 
 	tfMinification  ETextureFiltering
 	tfMagnification ETextureFiltering
@@ -52,163 +68,462 @@ func NewCTexture() *CTexture {
 func (this *CTexture) CreateEmptyTexture(a_iWidth, a_iHeight int32, format uint32) {
 	gl.GenTextures(1, &this.uiTexture)
 	gl.BindTexture(gl.TEXTURE_2D, this.uiTexture)
-	if format == gl.RGBA || format == gl.BGRA {
-		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, a_iWidth, a_iHeight, 0, format, gl.UNSIGNED_BYTE, nil)
-		// We must handle this because of internal format parameter
-	} else if format == gl.RGB || format == gl.BGR {
-		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGB, a_iWidth, a_iHeight, 0, format, gl.UNSIGNED_BYTE, nil)
-	} else {
-		gl.TexImage2D(gl.TEXTURE_2D, 0, int32(format), a_iWidth, a_iHeight, 0, format, gl.UNSIGNED_BYTE, nil)
-	}
-	gl.GenSamplers(1, &this.uiSampler)
+	// We must handle this because of internal format parameter
+	gl.TexImage2D(gl.TEXTURE_2D, 0, this.getInternalFormat(format), a_iWidth, a_iHeight, 0, format, gl.UNSIGNED_BYTE, nil)
+	this.bFloat = false
+	this.createSampler()
 }
 
 func (this *CTexture) CreateFromData(bData unsafe.Pointer, a_iWidth, a_iHeight, a_iBPP int32, format uint32, bGenerateMipMaps bool) {
 	// Generate an OpenGL texture ID for this texture
 	gl.GenTextures(1, &this.uiTexture)
 	gl.BindTexture(gl.TEXTURE_2D, this.uiTexture)
-	if format == gl.RGBA || format == gl.BGRA {
-		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, a_iWidth, a_iHeight, 0, format, gl.UNSIGNED_BYTE, bData)
-		// We must handle this because of internal format parameter
-	} else if format == gl.RGB || format == gl.BGR {
-		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGB, a_iWidth, a_iHeight, 0, format, gl.UNSIGNED_BYTE, bData)
-	} else {
-		gl.TexImage2D(gl.TEXTURE_2D, 0, int32(format), a_iWidth, a_iHeight, 0, format, gl.UNSIGNED_BYTE, bData)
-	}
+	// We must handle this because of internal format parameter
+	gl.TexImage2D(gl.TEXTURE_2D, 0, this.getInternalFormat(format), a_iWidth, a_iHeight, 0, format, gl.UNSIGNED_BYTE, bData)
 	if bGenerateMipMaps {
 		gl.GenerateMipmap(gl.TEXTURE_2D)
 	}
-	gl.GenSamplers(1, &this.uiSampler)
+	this.createSampler()
 
 	this.sPath = ""
 	this.bMipMapsGenerated = bGenerateMipMaps
 	this.iWidth = a_iWidth
 	this.iHeight = a_iHeight
 	this.iBPP = a_iBPP
+	this.bFloat = false
 }
 
-func (this *CTexture) LoadTexture2D(a_sPath string, bGenerateMipMaps bool) bool {
-	file, err := os.Open(a_sPath)
-	if err != nil {
-		fmt.Println("无法打开图片文件:", err)
-		panic(err)
-		return false
+// SetUsage sets what texels hold, it takes effect when the texture is created or reloaded next time.
+func (this *CTexture) SetUsage(usage ETextureUsage) {
+	this.tuUsage = usage
+}
+
+func (this *CTexture) GetUsage() ETextureUsage {
+	return this.tuUsage
+}
+
+func (this *CTexture) IsFloat() bool {
+	return this.bFloat
+}
+
+// isSRGB tells whether texture is stored in sRGB format, colour textures are only when framebuffer encodes to sRGB too
+func (this *CTexture) isSRGB() bool {
+	return this.tuUsage == TEXTURE_USAGE_COLOR && IsSRGBFramebuffer()
+}
+
+// isDataSRGB tells whether texture data is uploaded as sRGB. Colour space of container wins over usage,
+// but only when framebuffer encodes to sRGB, otherwise shaders work with stored values of every texture
+func (this *CTexture) isDataSRGB(texData *libs.TextureData) bool {
+	if texData.SRGBKnown && IsSRGBFramebuffer() {
+		return texData.SRGB
 	}
-	defer file.Close()
+	return this.isSRGB()
+}
 
-	img, fif, err := image.Decode(file)
-	if err != nil {
-		fmt.Println("无法打开图片文件:", err)
-		panic(err)
-		return false
+// getInternalFormat returns internal format for 8 bits per channel data of given pixel format
+func (this *CTexture) getInternalFormat(format uint32) int32 {
+	switch format {
+	case gl.RGBA, gl.BGRA:
+		if this.isSRGB() {
+			return gl.SRGB8_ALPHA8
+		}
+		return gl.RGBA8
+	case gl.RGB, gl.BGR:
+		if this.isSRGB() {
+			return gl.SRGB8
+		}
+		return gl.RGB8
+	}
+	return int32(format)
+}
+
+/*-----------------------------------------------
+
+  Name:	CreateFromFloatImage
+
+  Params:	img - linear float texels, e.g. from .hdr
+  		bGenerateMipMaps - whether to create mipmaps
+
+  Result:	Creates GL_RGB16F texture, or GL_RGBA16F if
+  		some texel isn't opaque.
+
+  /*---------------------------------------------*/
+
+func (this *CTexture) CreateFromFloatImage(img *libs.FloatImage, bGenerateMipMaps bool) {
+	var bAlpha bool = false
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] != 1 {
+			bAlpha = true
+			break
+		}
 	}
-	if fif == "" { // If still unknown, try to guess the file format from the file extension
-		fif = filepath.Base(this.sPath)
+	var iInternalFormat int32 = gl.RGB16F
+	this.iBPP = 48
+	if bAlpha {
+		iInternalFormat = gl.RGBA16F
+		this.iBPP = 64
+	}
+	gl.GenTextures(1, &this.uiTexture)
+	gl.BindTexture(gl.TEXTURE_2D, this.uiTexture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, iInternalFormat, int32(img.Width), int32(img.Height), 0, gl.RGBA, gl.FLOAT, gl.Ptr(img.Pix))
+	if bGenerateMipMaps {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
 	}
+	this.createSampler()
 
-	if fif == "" {
-		panic("unknown fif")
-		return false
+	this.sPath = ""
+	this.bMipMapsGenerated = bGenerateMipMaps
+	this.bCompressed = false
+	this.bFloat = true
+	this.iWidth = int32(img.Width)
+	this.iHeight = int32(img.Height)
+}
+
+// Whether DDS textures may be uploaded compressed, otherwise they are always decompressed on CPU
+var bCompressedTextureUpload bool = true
+
+func EnableCompressedTextureUpload(bEnabled bool) {
+	bCompressedTextureUpload = bEnabled
+}
+
+// sRGB variants of S3TC formats from GL_EXT_texture_sRGB, core profile headers don't have them
+const (
+	GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT = 0x8C4D
+	GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT = 0x8C4E
+	GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT = 0x8C4F
+)
+
+func getCompressedInternalFormat(format libs.TexelFormat, bSRGB bool) uint32 {
+	if bSRGB {
+		// Only colour formats have sRGB variants, BC4-BC6H are always linear
+		switch format {
+		case libs.TEXEL_FORMAT_BC1:
+			return GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT
+		case libs.TEXEL_FORMAT_BC2:
+			return GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT
+		case libs.TEXEL_FORMAT_BC3:
+			return GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT
+		case libs.TEXEL_FORMAT_BC7:
+			return gl.COMPRESSED_SRGB_ALPHA_BPTC_UNORM_ARB
+		}
+	}
+	switch format {
+	case libs.TEXEL_FORMAT_BC1:
+		return gl.COMPRESSED_RGBA_S3TC_DXT1_EXT
+	case libs.TEXEL_FORMAT_BC2:
+		return gl.COMPRESSED_RGBA_S3TC_DXT3_EXT
+	case libs.TEXEL_FORMAT_BC3:
+		return gl.COMPRESSED_RGBA_S3TC_DXT5_EXT
+	case libs.TEXEL_FORMAT_BC4:
+		return gl.COMPRESSED_RED_RGTC1
+	case libs.TEXEL_FORMAT_BC4_SIGNED:
+		return gl.COMPRESSED_SIGNED_RED_RGTC1
+	case libs.TEXEL_FORMAT_BC5:
+		return gl.COMPRESSED_RG_RGTC2
+	case libs.TEXEL_FORMAT_BC5_SIGNED:
+		return gl.COMPRESSED_SIGNED_RG_RGTC2
+	case libs.TEXEL_FORMAT_BC6H:
+		return gl.COMPRESSED_RGB_BPTC_UNSIGNED_FLOAT_ARB
+	case libs.TEXEL_FORMAT_BC6H_SIGNED:
+		return gl.COMPRESSED_RGB_BPTC_SIGNED_FLOAT_ARB
+	case libs.TEXEL_FORMAT_BC7:
+		return gl.COMPRESSED_RGBA_BPTC_UNORM_ARB
 	}
+	return 0
+}
 
-	// 获取图片宽度和高度
-	bounds := img.Bounds()
-	width, height := bounds.Max.X, bounds.Max.Y
+/*-----------------------------------------------
 
-	// If somehow one of these failed (they shouldn't), return failure
-	if width == 0 || height == 0 {
-		panic("unknown width")
-		return false
+  Name:	uploadTextureData
+
+  Params:	iTarget - TEXTURE_2D or TEXTURE_CUBE_MAP
+  		texData - texture from libs loader
+  		bGenerateMipMaps - generate mip levels if
+  		the data has only the top one
+
+  Result:	Uploads all mip levels of first layer into
+  		bound texture, cubemaps get all six faces,
+  		2D textures the first one. Compressed
+  		formats stay compressed if the driver can
+  		sample them, otherwise they are decoded.
+
+  /*---------------------------------------------*/
+
+func (this *CTexture) uploadTextureData(iTarget uint32, texData *libs.TextureData, bGenerateMipMaps bool) error {
+	var bCompressed bool = texData.Format.IsCompressed() && bCompressedTextureUpload && GetGLCapabilities().SupportsTexelFormat(texData.Format)
+	// Drivers don't have to generate mipmaps of compressed textures, so these are decoded instead
+	if bGenerateMipMaps && texData.MipLevels == 1 {
+		bCompressed = false
+	}
+	// Bottom-up blocks would be sampled upside down, decoding turns rows over
+	if texData.BottomUp {
+		bCompressed = false
 	}
-	// 确定位深度
-	var format uint32
-	var pixels []uint8
-	var bpp int32
-	// 根据不同的ColorModel获取对应的Pix
-	switch img.ColorModel() {
-	case color.RGBAModel:
-		rgba := img.(*image.RGBA)
-		pixels = rgba.Pix
-		format = gl.RGBA
-		bpp = 32
-	case color.NRGBAModel:
-		switch v := img.(type) {
-		case *image.NRGBA:
-			nrgba := v
-			pixels = nrgba.Pix
-		case *libs.DDSimg:
-			nrgba := v
-			pixels = *(*[]uint8)(unsafe.Pointer(&nrgba.Buf))
+	var iFaces int = 1
+	if iTarget == gl.TEXTURE_CUBE_MAP {
+		if !texData.IsCubemap() {
+			return errors.New("texture isn't a cubemap")
 		}
+		iFaces = 6
+	}
 
-		format = gl.RGBA
-		bpp = 32
-	case color.GrayModel:
-		gray := img.(*image.Gray)
-		pixels = gray.Pix
-		format = gl.RED
-		bpp = 8
-	case color.YCbCrModel:
-		yimg := img.(*image.YCbCr)
-		rgbaImg := image.NewRGBA(yimg.Bounds())
-		for y := 0; y < yimg.Bounds().Dy(); y++ {
-			for x := 0; x < yimg.Bounds().Dx(); x++ {
-				rgbaImg.Set(x, y, yimg.At(x, y))
+	var bSRGB bool = this.isDataSRGB(texData)
+	var iInternalFormat int32 = gl.RGBA8
+	if bSRGB {
+		iInternalFormat = gl.SRGB8_ALPHA8
+	}
+	for iFace := 0; iFace < iFaces; iFace++ {
+		var iImageTarget uint32 = iTarget
+		if iTarget == gl.TEXTURE_CUBE_MAP {
+			iImageTarget = gl.TEXTURE_CUBE_MAP_POSITIVE_X + uint32(iFace)
+		}
+		for iMip := 0; iMip < texData.MipLevels; iMip++ {
+			surface := texData.GetSurface(0, iFace, iMip)
+			if bCompressed {
+				bSlice := surface.GetSlice(texData.Format, 0)
+				gl.CompressedTexImage2D(iImageTarget, int32(iMip), getCompressedInternalFormat(texData.Format, bSRGB), int32(surface.Width), int32(surface.Height),
+					0, int32(len(bSlice)), gl.Ptr(bSlice))
+				continue
 			}
+			bPixels, err := texData.DecompressSurface(surface)
+			if err != nil {
+				return err
+			}
+			gl.TexImage2D(iImageTarget, int32(iMip), iInternalFormat, int32(surface.Width), int32(surface.Height), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(bPixels))
 		}
-		pixels = rgbaImg.Pix
-		format = gl.RGBA
-		bpp = 32
-	default:
-		fmt.Println("不支持的颜色模型")
-		panic("不支持的颜色模型")
 	}
+	// Files don't always carry complete mip chain, limit sampling to levels we have
+	gl.TexParameteri(iTarget, gl.TEXTURE_BASE_LEVEL, 0)
+	gl.TexParameteri(iTarget, gl.TEXTURE_MAX_LEVEL, int32(texData.MipLevels-1))
+	if bGenerateMipMaps && texData.MipLevels == 1 {
+		gl.TexParameteri(iTarget, gl.TEXTURE_MAX_LEVEL, 1000)
+		gl.GenerateMipmap(iTarget)
+	}
+
+	this.bCompressed = bCompressed
+	this.bFloat = false
+	this.bMipMapsGenerated = texData.MipLevels > 1 || bGenerateMipMaps
+	this.iWidth = int32(texData.Width)
+	this.iHeight = int32(texData.Height)
+	this.iBPP = 32
+	if bCompressed {
+		this.iBPP = int32(texData.Format.GetBlockSize() / 2) // 16 texels per block
+	}
+	return nil
+}
+
+/*-----------------------------------------------
+
+  Name:	CreateFromTextureData
+
+  Params:	texData - texture from libs loader
+  		bGenerateMipMaps - generate mip levels if
+  		the data has only the top one
 
-	this.CreateFromData(gl.Ptr(pixels), int32(width), int32(height), bpp, format, bGenerateMipMaps)
+  Result:	Creates 2D texture from all mip levels of
+  		first layer and face of the data.
+
+  /*---------------------------------------------*/
+
+func (this *CTexture) CreateFromTextureData(texData *libs.TextureData, bGenerateMipMaps bool) bool {
+	gl.GenTextures(1, &this.uiTexture)
+	gl.BindTexture(gl.TEXTURE_2D, this.uiTexture)
+	if err := this.uploadTextureData(gl.TEXTURE_2D, texData, bGenerateMipMaps); err != nil {
+		fmt.Println("Couldn't upload texture:", err)
+		gl.DeleteTextures(1, &this.uiTexture)
+		this.uiTexture = 0
+		return false
+	}
+	this.createSampler()
+	this.sPath = ""
+	return true
+}
 
+// LoadTextureContainer loads DDS, KTX or KTX2 file, keeps every mip level stored in it and uploads it compressed when possible.
+func (this *CTexture) LoadTextureContainer(a_sPath string, bGenerateMipMaps bool) bool {
+	texData, err := libs.LoadTextureFile(a_sPath)
+	if err != nil {
+		fmt.Println("Couldn't load texture", a_sPath+":", err)
+		return false
+	}
+	if !this.CreateFromTextureData(texData, bGenerateMipMaps) {
+		return false
+	}
 	this.sPath = a_sPath
+	return true
+}
 
-	return true // Success
+// LoadTexture2DEx loads texture with given usage, LoadTexture2D keeps usage set before (colour by default).
+func (this *CTexture) LoadTexture2DEx(a_sPath string, bGenerateMipMaps bool, usage ETextureUsage) bool {
+	this.tuUsage = usage
+	return this.LoadTexture2D(a_sPath, bGenerateMipMaps)
 }
 
+func (this *CTexture) LoadTexture2D(a_sPath string, bGenerateMipMaps bool) bool {
+	if libs.IsTextureContainerFile(a_sPath) {
+		return this.LoadTextureContainer(a_sPath, bGenerateMipMaps)
+	}
+	img, err := loadImageFile(a_sPath)
+	if err != nil {
+		fmt.Println("Couldn't load texture", a_sPath+":", err)
+		return false
+	}
+	// HDR images keep values above one in half floats
+	if floatImage, bOK := img.(*libs.FloatImage); bOK {
+		this.CreateFromFloatImage(floatImage, bGenerateMipMaps)
+		this.sPath = a_sPath
+		return true
+	}
+
+	gl.GenTextures(1, &this.uiTexture)
+	gl.BindTexture(gl.TEXTURE_2D, this.uiTexture)
+	if err := this.uploadImage(img, bGenerateMipMaps); err != nil {
+		fmt.Println("Couldn't load texture", a_sPath+":", err)
+		gl.DeleteTextures(1, &this.uiTexture)
+		this.uiTexture = 0
+		return false
+	}
+	this.createSampler()
+	this.sPath = a_sPath
+	return true
+}
+
+/*-----------------------------------------------
+
+  Name:	uploadImage
+
+  Params:	img - image of any colour model
+  		bGenerateMipMaps - whether to create mipmaps
+
+  Result:	Uploads image to bound 2D texture. Grey
+  		images get one channel read as grey, 16
+  		bit images keep precision in data textures.
+
+  /*---------------------------------------------*/
+
+func (this *CTexture) uploadImage(img image.Image, bGenerateMipMaps bool) error {
+	// There are no 16 bit sRGB formats, so colour textures get 8 bits
+	texels, err := libs.ConvertImage(img, this.tuUsage == TEXTURE_USAGE_DATA)
+	if err != nil {
+		return err
+	}
+	var format uint32 = gl.RGBA
+	var iInternalFormat int32 = this.getInternalFormat(gl.RGBA)
+	var iType uint32 = gl.UNSIGNED_BYTE
+	if texels.Channels == 1 {
+		format = gl.RED
+		iInternalFormat = gl.R8
+	}
+	if texels.Is16Bit() {
+		iType = gl.UNSIGNED_SHORT
+		iInternalFormat = gl.RGBA16
+		if texels.Channels == 1 {
+			iInternalFormat = gl.R16
+		}
+	}
+	// Rows are tightly packed, grey ones don't have to be aligned to four bytes
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, iInternalFormat, int32(texels.Width), int32(texels.Height), 0, format, iType, texels.GetPointer())
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	if texels.Channels == 1 {
+		vSwizzle := [4]int32{gl.RED, gl.RED, gl.RED, gl.ONE}
+		gl.TexParameteriv(gl.TEXTURE_2D, gl.TEXTURE_SWIZZLE_RGBA, &vSwizzle[0])
+	}
+	if bGenerateMipMaps {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+	}
+
+	this.bCompressed = false
+	this.bFloat = false
+	this.bMipMapsGenerated = bGenerateMipMaps
+	this.iWidth = int32(texels.Width)
+	this.iHeight = int32(texels.Height)
+	this.iBPP = int32(texels.GetBytesPerTexel() * 8)
+	return nil
+}
+
+// SetSamplerParameter sets raw sampler parameter. Shared sampler is first copied, so other textures aren't affected.
 func (this *CTexture) SetSamplerParameter(parameter uint32, value int32) {
+	if this.bSharedSampler {
+		var uiOwnSampler uint32
+		gl.GenSamplers(1, &uiOwnSampler)
+		applySamplerDesc(uiOwnSampler, this.sdSampler.normalize())
+		this.releaseSampler()
+		this.uiSampler = uiOwnSampler
+	}
 	gl.SamplerParameteri(this.uiSampler, parameter, value)
 }
 
+// createSampler gives texture its own sampler with default state, unless it already has one
+func (this *CTexture) createSampler() {
+	if this.uiSampler == 0 {
+		gl.GenSamplers(1, &this.uiSampler)
+		this.bSharedSampler = false
+	}
+}
+
+func (this *CTexture) releaseSampler() {
+	if this.uiSampler == 0 {
+		return
+	}
+	if this.bSharedSampler {
+		scSamplers.ReleaseSampler(this.uiSampler)
+	} else {
+		gl.DeleteSamplers(1, &this.uiSampler)
+	}
+	this.uiSampler = 0
+	this.bSharedSampler = false
+}
+
+/*-----------------------------------------------
+
+  Name:	SetSamplerDesc
+
+  Params:	desc - complete sampler state
+
+  Result:	Switches texture to shared sampler from
+  		sampler cache, textures with equal
+  		descriptions use the same GL sampler.
+
+  /*---------------------------------------------*/
+
+func (this *CTexture) SetSamplerDesc(desc SamplerDesc) {
+	uiSampler := scSamplers.AcquireSampler(desc)
+	this.releaseSampler()
+	this.uiSampler = uiSampler
+	this.bSharedSampler = true
+	this.sdSampler = desc
+	this.bSamplerDesc = true
+	this.tfMagnification = desc.MagFilter
+	this.tfMinification = desc.MinFilter
+}
+
+// GetSamplerDesc returns sampler state set by SetSamplerDesc or SetFiltering, DefaultSamplerDesc if there was none.
+func (this *CTexture) GetSamplerDesc() SamplerDesc {
+	if !this.bSamplerDesc {
+		return DefaultSamplerDesc()
+	}
+	return this.sdSampler
+}
+
+// SetFiltering changes only filters of texture's sampler state.
 func (this *CTexture) SetFiltering(a_tfMagnification, a_tfMinification ETextureFiltering) {
-	gl.BindSampler(0, this.uiSampler)
-
-	// Set magnification filter
-	if a_tfMagnification == TEXTURE_FILTER_MAG_NEAREST {
-		gl.SamplerParameteri(this.uiSampler, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
-	} else if a_tfMagnification == TEXTURE_FILTER_MAG_BILINEAR {
-		gl.SamplerParameteri(this.uiSampler, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	}
-	// Set minification filter
-	if a_tfMinification == TEXTURE_FILTER_MIN_NEAREST {
-		gl.SamplerParameteri(this.uiSampler, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
-	} else if a_tfMinification == TEXTURE_FILTER_MIN_BILINEAR {
-		gl.SamplerParameteri(this.uiSampler, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	} else if a_tfMinification == TEXTURE_FILTER_MIN_NEAREST_MIPMAP {
-		gl.SamplerParameteri(this.uiSampler, gl.TEXTURE_MIN_FILTER, gl.NEAREST_MIPMAP_NEAREST)
-	} else if a_tfMinification == TEXTURE_FILTER_MIN_BILINEAR_MIPMAP {
-		gl.SamplerParameteri(this.uiSampler, gl.TEXTURE_MIN_FILTER, gl.LINEAR_MIPMAP_NEAREST)
-	} else if a_tfMinification == TEXTURE_FILTER_MIN_TRILINEAR {
-		gl.SamplerParameteri(this.uiSampler, gl.TEXTURE_MIN_FILTER, gl.LINEAR_MIPMAP_LINEAR)
-	}
-	this.tfMinification = a_tfMinification
-	this.tfMagnification = a_tfMagnification
+	sdSampler := this.GetSamplerDesc()
+	sdSampler.MagFilter = a_tfMagnification
+	sdSampler.MinFilter = a_tfMinification
+	this.SetSamplerDesc(sdSampler)
 }
 
 func (this *CTexture) BindTexture(iTextureUnit uint32) {
 	gl.ActiveTexture(gl.TEXTURE0 + iTextureUnit)
-	gl.BindTexture(gl.TEXTURE_2D, this.uiTexture)
+	gl.BindTexture(this.GetTarget(), this.uiTexture)
 	gl.BindSampler(iTextureUnit, this.uiSampler)
 }
 
 func (this *CTexture) DeleteTexture() {
-	gl.DeleteSamplers(1, &this.uiSampler)
+	this.releaseSampler()
 	gl.DeleteTextures(1, &this.uiTexture)
+	this.uiTexture = 0
 }
 
 func (this *CTexture) GetMinificationFilter() ETextureFiltering {
@@ -231,6 +546,10 @@ func (this *CTexture) GetBPP() int32 {
 	return this.iBPP
 }
 
+func (this *CTexture) IsCompressed() bool {
+	return this.bCompressed
+}
+
 func (this *CTexture) GetTextureID() uint32 {
 	return this.uiTexture
 }
@@ -239,82 +558,382 @@ func (this *CTexture) GetPath() string {
 	return this.sPath
 }
 
+/*-----------------------------------------------
+
+  Name:	GetMemorySize
+
+  Params:	none
+
+  Result:	Returns estimated GPU memory of texture
+  		in bytes, with all layers, faces and mip
+  		levels.
+
+  /*---------------------------------------------*/
+
+func (this *CTexture) GetMemorySize() int64 {
+	if this.uiTexture == 0 {
+		return 0
+	}
+	var iSize int64 = int64(this.iWidth) * int64(this.iHeight) * int64(this.iBPP) / 8
+	if this.GetTarget() == gl.TEXTURE_CUBE_MAP {
+		iSize *= 6
+	} else if this.iLayers > 0 {
+		iSize *= int64(this.iLayers)
+	}
+	// Full mip chain adds one third
+	if this.bMipMapsGenerated {
+		iSize += iSize / 3
+	}
+	return iSize
+}
+
+func (this *CTexture) GetTarget() uint32 {
+	if this.iTarget == 0 {
+		return gl.TEXTURE_2D
+	}
+	return this.iTarget
+}
+
+func (this *CTexture) GetNumLayers() int32 {
+	if this.iLayers == 0 {
+		return 1
+	}
+	return this.iLayers
+}
+
 func (this *CTexture) ReloadTexture() bool {
-	file, err := os.Open(this.sPath)
+	if this.iTarget == gl.TEXTURE_2D_ARRAY {
+		return this.reloadTextureArray()
+	}
+	if libs.IsTextureContainerFile(this.sPath) {
+		// Format or mip count may have changed, so the texture object is recreated, sampler stays
+		texData, err := libs.LoadTextureFile(this.sPath)
+		if err != nil {
+			fmt.Println("Couldn't load texture", this.sPath+":", err)
+			return false
+		}
+		gl.DeleteTextures(1, &this.uiTexture)
+		gl.GenTextures(1, &this.uiTexture)
+		gl.BindTexture(gl.TEXTURE_2D, this.uiTexture)
+		if err := this.uploadTextureData(gl.TEXTURE_2D, texData, this.bMipMapsGenerated); err != nil {
+			fmt.Println("Couldn't upload texture:", err)
+			return false
+		}
+		return true
+	}
+	img, err := loadImageFile(this.sPath)
 	if err != nil {
-		fmt.Println("无法打开图片文件:", err)
-		panic(err)
+		fmt.Println("Couldn't load texture", this.sPath+":", err)
+		return false
+	}
+	if floatImage, bOK := img.(*libs.FloatImage); bOK {
+		// Size or alpha may have changed, so the texture object is recreated, sampler and path stay
+		sPath := this.sPath
+		gl.DeleteTextures(1, &this.uiTexture)
+		this.CreateFromFloatImage(floatImage, this.bMipMapsGenerated)
+		this.sPath = sPath
+		return true
+	}
+
+	// Size or colour model may have changed, so the texture object is recreated, sampler stays
+	gl.DeleteTextures(1, &this.uiTexture)
+	gl.GenTextures(1, &this.uiTexture)
+	gl.BindTexture(gl.TEXTURE_2D, this.uiTexture)
+	if err := this.uploadImage(img, this.bMipMapsGenerated); err != nil {
+		fmt.Println("Couldn't reload texture", this.sPath+":", err)
 		return false
 	}
+	return true
+}
+
+/*-----------------------------------------------
+
+  Name:	loadImageFile
+
+  Params:	sPath - image or texture container file
+
+  Result:	Returns top level of the file as image,
+  		containers are decoded to RGBA.
+
+  /*---------------------------------------------*/
+
+func loadImageFile(sPath string) (image.Image, error) {
+	if libs.IsTextureContainerFile(sPath) {
+		texData, err := libs.LoadTextureFile(sPath)
+		if err != nil {
+			return nil, err
+		}
+		bPixels, err := texData.DecompressSurface(texData.GetSurface(0, 0, 0))
+		if err != nil {
+			return nil, err
+		}
+		return &image.NRGBA{Pix: bPixels[:texData.Width*texData.Height*4], Stride: texData.Width * 4, Rect: image.Rect(0, 0, texData.Width, texData.Height)}, nil
+	}
+	file, err := libs.OpenAsset(sPath)
+	if err != nil {
+		return nil, err
+	}
 	defer file.Close()
+	img, _, err := image.Decode(file)
+	return img, err
+}
+
+/*-----------------------------------------------
 
-	img, fif, err := image.Decode(file)
+  Name:	DecodeTextureFile
+
+  Params:	sPath - image or texture container file
+
+  Result:	Reads and decodes texture without OpenGL
+  		calls, so it can run on any goroutine.
+  		Images become one RGBA8 level, containers
+  		keep all their surfaces.
+
+  /*---------------------------------------------*/
+
+func DecodeTextureFile(sPath string) (*libs.TextureData, error) {
+	if libs.IsTextureContainerFile(sPath) {
+		return libs.LoadTextureFile(sPath)
+	}
+	img, err := loadImageFile(sPath)
 	if err != nil {
-		fmt.Println("无法打开图片文件:", err)
-		panic(err)
+		return nil, err
+	}
+	return libs.CompressImage(img, libs.DDSEncodeOptions{Format: libs.TEXEL_FORMAT_RGBA8, NoMipmaps: true})
+}
+
+// resizeImage returns img as NRGBA of given size, scaling it with Catmull-Rom filter if it's different.
+func resizeImage(img image.Image, iWidth, iHeight int) *image.NRGBA {
+	bounds := img.Bounds()
+	if bounds.Dx() == iWidth && bounds.Dy() == iHeight {
+		return libs.ToNRGBA(img)
+	}
+	nrgba := image.NewNRGBA(image.Rect(0, 0, iWidth, iHeight))
+	xdraw.CatmullRom.Scale(nrgba, nrgba.Bounds(), img, bounds, xdraw.Src, nil)
+	return nrgba
+}
+
+/*-----------------------------------------------
+
+  Name:	CreateTextureArray
+
+  Params:	vImages - one image per layer
+  		bGenerateMipMaps - whether to create mipmaps
+
+  Result:	Creates GL_TEXTURE_2D_ARRAY in size of the
+  		first image, other images are resized to
+  		it if they differ.
+
+  /*---------------------------------------------*/
+
+func (this *CTexture) CreateTextureArray(vImages []image.Image, bGenerateMipMaps bool) bool {
+	if len(vImages) == 0 {
+		fmt.Println("Texture array needs at least one image")
+		return false
+	}
+	if iMaxLayers := GetGLCapabilities().GetMaxArrayTextureLayers(); len(vImages) > iMaxLayers {
+		fmt.Printf("Texture array has %d layers, driver allows only %d\n", len(vImages), iMaxLayers)
+		return false
+	}
+	bounds := vImages[0].Bounds()
+	if bounds.Empty() {
+		fmt.Println("First image of texture array is empty")
 		return false
 	}
-	if fif == "" { // If still unknown, try to guess the file format from the file extension
-		fif = filepath.Base(this.sPath)
+	iWidth, iHeight := bounds.Dx(), bounds.Dy()
+
+	gl.GenTextures(1, &this.uiTexture)
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, this.uiTexture)
+	gl.TexImage3D(gl.TEXTURE_2D_ARRAY, 0, this.getInternalFormat(gl.RGBA), int32(iWidth), int32(iHeight), int32(len(vImages)), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	for i, img := range vImages {
+		nrgba := resizeImage(img, iWidth, iHeight)
+		gl.TexSubImage3D(gl.TEXTURE_2D_ARRAY, 0, 0, 0, int32(i), int32(iWidth), int32(iHeight), 1, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(nrgba.Pix))
+	}
+	if bGenerateMipMaps {
+		gl.GenerateMipmap(gl.TEXTURE_2D_ARRAY)
 	}
+	this.createSampler()
+
+	this.iTarget = gl.TEXTURE_2D_ARRAY
+	this.iLayers = int32(len(vImages))
+	this.sPath = ""
+	this.vLayerPaths = nil
+	this.bCompressed = false
+	this.bFloat = false
+	this.bMipMapsGenerated = bGenerateMipMaps
+	this.iWidth = int32(iWidth)
+	this.iHeight = int32(iHeight)
+	this.iBPP = 32
+	return true
+}
 
-	if fif == "" {
-		panic("fif == \"\"")
+// LoadTextureArray creates texture array with one layer per file, see CreateTextureArray.
+func (this *CTexture) LoadTextureArray(vPaths []string, bGenerateMipMaps bool) bool {
+	vImages := make([]image.Image, len(vPaths))
+	for i, sPath := range vPaths {
+		img, err := loadImageFile(sPath)
+		if err != nil {
+			fmt.Println("Couldn't load texture array layer", sPath+":", err)
+			return false
+		}
+		vImages[i] = img
+	}
+	if !this.CreateTextureArray(vImages, bGenerateMipMaps) {
 		return false
 	}
+	this.vLayerPaths = append([]string(nil), vPaths...)
+	return true
+}
 
-	// 获取图片宽度和高度
-	bounds := img.Bounds()
-	width, height := bounds.Max.X, bounds.Max.Y
+func (this *CTexture) GetLayerPaths() []string {
+	return this.vLayerPaths
+}
 
-	// If somehow one of these failed (they shouldn't), return failure
-	if width == 0 || height == 0 {
-		panic("width == 0")
+// reloadTextureArray loads layers from their files again, sampler stays.
+func (this *CTexture) reloadTextureArray() bool {
+	if len(this.vLayerPaths) == 0 {
 		return false
 	}
-	// 确定位深度
-	var format uint32
-	var pixels []uint8
-	// 根据不同的ColorModel获取对应的Pix
-	switch img.ColorModel() {
-	case color.RGBAModel:
-		rgba := img.(*image.RGBA)
-		pixels = rgba.Pix
-		format = gl.RGBA
-	case color.NRGBAModel:
-		nrgba := img.(*image.NRGBA)
-		pixels = nrgba.Pix
-		format = gl.RGBA
-	case color.GrayModel:
-		gray := img.(*image.Gray)
-		pixels = gray.Pix
-		format = gl.RED
-	default:
-		fmt.Println("不支持的颜色模型")
-		panic("不支持的颜色模型")
+	gl.DeleteTextures(1, &this.uiTexture)
+	return this.LoadTextureArray(this.vLayerPaths, this.bMipMapsGenerated)
+}
+
+/*-----------------------------------------------
+
+  Name:	CreateCubemap
+
+  Params:	vFaces - faces in libs.CUBE_FACE_* order,
+  		faces not in size of the first one are
+  		resized
+  		bGenerateMipMaps - whether to create mipmaps
+
+  Result:	Creates GL_TEXTURE_CUBE_MAP. If the first
+  		face is *libs.FloatImage, all faces are
+  		stored as half floats, so HDR values stay.
+
+  /*---------------------------------------------*/
+
+func (this *CTexture) CreateCubemap(vFaces [6]image.Image, bGenerateMipMaps bool) bool {
+	for _, face := range vFaces {
+		if face == nil || face.Bounds().Empty() {
+			fmt.Println("Cubemap needs six non-empty faces")
+			return false
+		}
 	}
+	// Faces must be square, the first one's width decides
+	var iSize int = vFaces[0].Bounds().Dx()
+	_, bFloat := vFaces[0].(*libs.FloatImage)
 
-	gl.BindTexture(gl.TEXTURE_2D, this.uiTexture)
-	gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, this.iWidth, this.iHeight, format, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
-	if this.bMipMapsGenerated {
-		gl.GenerateMipmap(gl.TEXTURE_2D)
+	gl.GenTextures(1, &this.uiTexture)
+	gl.BindTexture(gl.TEXTURE_CUBE_MAP, this.uiTexture)
+	for i, face := range vFaces {
+		var iTarget uint32 = gl.TEXTURE_CUBE_MAP_POSITIVE_X + uint32(i)
+		if bFloat {
+			floatFace := libs.ToFloatImage(face)
+			if floatFace.Width != iSize || floatFace.Height != iSize {
+				floatFace = libs.ToFloatImage(resizeImage(face, iSize, iSize))
+				fmt.Println("HDR cubemap face", i, "had to be resized, values above one were lost")
+			}
+			gl.TexImage2D(iTarget, 0, gl.RGB16F, int32(iSize), int32(iSize), 0, gl.RGBA, gl.FLOAT, gl.Ptr(floatFace.Pix))
+			continue
+		}
+		nrgba := resizeImage(face, iSize, iSize)
+		gl.TexImage2D(iTarget, 0, this.getInternalFormat(gl.RGBA), int32(iSize), int32(iSize), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(nrgba.Pix))
+	}
+	if bGenerateMipMaps {
+		gl.GenerateMipmap(gl.TEXTURE_CUBE_MAP)
 	}
+	this.finishCubemap(int32(iSize), bGenerateMipMaps)
+	this.bCompressed = false
+	this.bFloat = bFloat
+	this.iBPP = 32
+	if bFloat {
+		this.iBPP = 48
+	}
+	return true
+}
 
-	return true // Success
+// CreateCubemapFromTextureData uploads every face and mip level of cubemap from DDS or KTX file.
+func (this *CTexture) CreateCubemapFromTextureData(texData *libs.TextureData, bGenerateMipMaps bool) bool {
+	gl.GenTextures(1, &this.uiTexture)
+	gl.BindTexture(gl.TEXTURE_CUBE_MAP, this.uiTexture)
+	if err := this.uploadTextureData(gl.TEXTURE_CUBE_MAP, texData, bGenerateMipMaps); err != nil {
+		fmt.Println("Couldn't upload cubemap:", err)
+		gl.DeleteTextures(1, &this.uiTexture)
+		this.uiTexture = 0
+		return false
+	}
+	this.finishCubemap(int32(texData.Width), this.bMipMapsGenerated)
+	return true
+}
+
+func (this *CTexture) finishCubemap(iSize int32, bMipMaps bool) {
+	// Edges of faces must not wrap, otherwise seams show up
+	sdSampler := this.GetSamplerDesc()
+	sdSampler.SetWrap(gl.CLAMP_TO_EDGE)
+	this.SetSamplerDesc(sdSampler)
+
+	this.iTarget = gl.TEXTURE_CUBE_MAP
+	this.iLayers = 0
+	this.sPath = ""
+	this.vLayerPaths = nil
+	this.bMipMapsGenerated = bMipMaps
+	this.iWidth = iSize
+	this.iHeight = iSize
 }
 
-var tTextures [NUMTEXTURES]CTexture
+var thTextures [NUMTEXTURES]*CTextureHandle
+var thTerrainLayers *CTextureHandle // Terrain layers and path texture when terrain uses texture array
+
+/*-----------------------------------------------
+
+  Name:	LoadAllTextures
+
+  Params:	alLoader - loader decoding the textures
 
-func LoadAllTextures() {
-	// Load textures
+  Result:	Requests terrain textures, placeholders
+  		are bound until they're uploaded.
 
+  /*---------------------------------------------*/
+
+func LoadAllTextures(alLoader *CAsyncLoader) {
 	var sTextureNames = []string{"fungus.dds", "sand_grass_02.jpg", "rock_2_4w.jpg", "sand.jpg", "path.png"}
+	var iFirst int
+	if IsTerrainTextureArrayEnabled() {
+		// First four textures are terrain layers and path texture, in the order terrain expects them
+		vLayerPaths := make([]string, DEFAULT_TERRAIN_PATH_LAYER+1)
+		for i := range vLayerPaths {
+			vLayerPaths[i] = "data/textures/" + sTextureNames[i]
+		}
+		thLayers, err := alLoader.LoadTextureArrayAsync(vLayerPaths, true)
+		if err != nil {
+			panic(err)
+		}
+		thLayers.SetSamplerDesc(getTerrainSamplerDesc())
+		thTerrainLayers = thLayers
+		// Terrain samples layers only from the array, path map is the one texture it still needs on its own
+		iFirst = len(vLayerPaths)
+	}
 
-	for i := 0; i < NUMTEXTURES; i++ {
-		flag := tTextures[i].LoadTexture2D("data\\textures\\"+sTextureNames[i], true)
-		if !flag {
-			panic("LoadTexture2D failed")
+	for i := iFirst; i < NUMTEXTURES; i++ {
+		// Last texture is path mask, not a colour
+		var usage ETextureUsage = TEXTURE_USAGE_COLOR
+		if i == NUMTEXTURES-1 {
+			usage = TEXTURE_USAGE_DATA
 		}
-		tTextures[i].SetFiltering(TEXTURE_FILTER_MAG_BILINEAR, TEXTURE_FILTER_MIN_BILINEAR_MIPMAP)
+		thTexture, err := alLoader.LoadTextureAsyncEx("data/textures/"+sTextureNames[i], true, usage)
+		if err != nil {
+			panic(err)
+		}
+		thTexture.SetSamplerDesc(getTerrainSamplerDesc())
+		thTextures[i] = thTexture
 	}
 }
+
+// getTerrainSamplerDesc returns sampler state of terrain textures, all of them share one sampler
+func getTerrainSamplerDesc() SamplerDesc {
+	sdTerrain := DefaultSamplerDesc()
+	sdTerrain.MinFilter = TEXTURE_FILTER_MIN_TRILINEAR
+	sdTerrain.Anisotropy = 8 // Terrain is mostly seen at grazing angles
+	return sdTerrain
+}
@@ -0,0 +1,618 @@
+package libs
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+)
+
+// Bones affecting one vertex at most
+const MAX_BONE_INFLUENCES = 4
+
+type EInterpolation int
+
+const (
+	INTERPOLATION_STEP EInterpolation = iota
+	INTERPOLATION_LINEAR
+	INTERPOLATION_CUBIC_SPLINE // Every key has in-tangent, value and out-tangent
+)
+
+type EChannelPath int
+
+const (
+	CHANNEL_TRANSLATION EChannelPath = iota
+	CHANNEL_ROTATION
+	CHANNEL_SCALE
+)
+
+// JointPose is transform of skeleton node relative to its parent
+type JointPose struct {
+	Translation mgl32.Vec3
+	Rotation    mgl32.Quat
+	Scale       mgl32.Vec3
+}
+
+func IdentityPose() JointPose {
+	return JointPose{Rotation: mgl32.QuatIdent(), Scale: mgl32.Vec3{1, 1, 1}}
+}
+
+// PoseFromMatrix splits matrix into translation, rotation and scale, shear is lost.
+func PoseFromMatrix(mMatrix mgl32.Mat4) JointPose {
+	pose := JointPose{Translation: mMatrix.Col(3).Vec3()}
+	vAxes := [3]mgl32.Vec3{mMatrix.Col(0).Vec3(), mMatrix.Col(1).Vec3(), mMatrix.Col(2).Vec3()}
+	for i := range vAxes {
+		pose.Scale[i] = vAxes[i].Len()
+	}
+	// Mirroring is kept in scale, rotation must be proper
+	if mMatrix.Mat3().Det() < 0 {
+		pose.Scale[0] = -pose.Scale[0]
+	}
+	mRotation := mgl32.Ident4()
+	for i := range vAxes {
+		if pose.Scale[i] != 0 {
+			mRotation.SetCol(i, vAxes[i].Mul(1/pose.Scale[i]).Vec4(0))
+		}
+	}
+	pose.Rotation = mgl32.Mat4ToQuat(mRotation).Normalize()
+	return pose
+}
+
+// Mat4 returns pose as matrix, scale is applied first and translation last.
+func (this JointPose) Mat4() mgl32.Mat4 {
+	return mgl32.Translate3D(this.Translation[0], this.Translation[1], this.Translation[2]).
+		Mul4(this.Rotation.Mat4()).Mul4(mgl32.Scale3D(this.Scale[0], this.Scale[1], this.Scale[2]))
+}
+
+// BlendPoses interpolates between two poses, fAmount 0 gives the first one.
+func BlendPoses(poseA, poseB JointPose, fAmount float32) JointPose {
+	return JointPose{
+		Translation: poseA.Translation.Add(poseB.Translation.Sub(poseA.Translation).Mul(fAmount)),
+		Rotation:    mgl32.QuatSlerp(poseA.Rotation, poseB.Rotation, fAmount),
+		Scale:       poseA.Scale.Add(poseB.Scale.Sub(poseA.Scale).Mul(fAmount)),
+	}
+}
+
+// SkeletonNode is bone or any other node of model's hierarchy
+type SkeletonNode struct {
+	Name     string
+	Parent   int // -1 for roots, always lower than node's own index
+	RestPose JointPose
+}
+
+/*-----------------------------------------------
+
+  Name:	Skeleton
+
+  Result:	Node hierarchy of model. Parents come
+  		before their children, so global transforms
+  		are computed in one pass.
+
+  /*---------------------------------------------*/
+
+type Skeleton struct {
+	Nodes []SkeletonNode
+}
+
+func NewSkeleton(vNodes []SkeletonNode) (*Skeleton, error) {
+	for i, node := range vNodes {
+		if node.Parent < -1 || node.Parent >= i {
+			return nil, fmt.Errorf("node %d (%s) has parent %d, parents must come before children", i, node.Name, node.Parent)
+		}
+	}
+	return &Skeleton{Nodes: vNodes}, nil
+}
+
+func (this *Skeleton) GetNumNodes() int {
+	return len(this.Nodes)
+}
+
+// FindNode returns index of node with name, -1 if there's none.
+func (this *Skeleton) FindNode(sName string) int {
+	for i := range this.Nodes {
+		if this.Nodes[i].Name == sName {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetRestPose returns new copy of rest pose of all nodes.
+func (this *Skeleton) GetRestPose() []JointPose {
+	vPose := make([]JointPose, len(this.Nodes))
+	for i := range this.Nodes {
+		vPose[i] = this.Nodes[i].RestPose
+	}
+	return vPose
+}
+
+// GetGlobalMatrices turns local poses into model space transforms, vResult is reused if it's big enough.
+func (this *Skeleton) GetGlobalMatrices(vPose []JointPose, vResult []mgl32.Mat4) []mgl32.Mat4 {
+	if cap(vResult) < len(this.Nodes) {
+		vResult = make([]mgl32.Mat4, len(this.Nodes))
+	}
+	vResult = vResult[:len(this.Nodes)]
+	for i := range this.Nodes {
+		mLocal := vPose[i].Mat4()
+		if iParent := this.Nodes[i].Parent; iParent >= 0 {
+			vResult[i] = vResult[iParent].Mul4(mLocal)
+		} else {
+			vResult[i] = mLocal
+		}
+	}
+	return vResult
+}
+
+// Skin binds mesh vertices to skeleton nodes, vertex joint indices point into Joints
+type Skin struct {
+	Name        string
+	Joints      []int        // Skeleton node of each joint
+	InverseBind []mgl32.Mat4 // Moves vertex from mesh space to joint's space in bind pose
+}
+
+// GetJointMatrices returns skinning matrix of every joint for global transforms of skeleton nodes.
+func (this *Skin) GetJointMatrices(vGlobal []mgl32.Mat4, vResult []mgl32.Mat4) []mgl32.Mat4 {
+	if cap(vResult) < len(this.Joints) {
+		vResult = make([]mgl32.Mat4, len(this.Joints))
+	}
+	vResult = vResult[:len(this.Joints)]
+	for i, iNode := range this.Joints {
+		vResult[i] = vGlobal[iNode].Mul4(this.InverseBind[i])
+	}
+	return vResult
+}
+
+// VertexSkin holds joints affecting vertex, unused slots have zero weight
+type VertexSkin struct {
+	Joints  [MAX_BONE_INFLUENCES]uint16
+	Weights [MAX_BONE_INFLUENCES]float32
+}
+
+// AddWeight adds joint influence, when all slots are taken the weakest one is replaced if it's weaker.
+func (this *VertexSkin) AddWeight(iJoint int, fWeight float32) {
+	var iWeakest int = 0
+	for i := 1; i < MAX_BONE_INFLUENCES; i++ {
+		if this.Weights[i] < this.Weights[iWeakest] {
+			iWeakest = i
+		}
+	}
+	if fWeight > this.Weights[iWeakest] {
+		this.Joints[iWeakest] = uint16(iJoint)
+		this.Weights[iWeakest] = fWeight
+	}
+}
+
+// Normalize makes weights sum up to one, vertices without weights are left alone.
+func (this *VertexSkin) Normalize() {
+	var fSum float32
+	for _, fWeight := range this.Weights {
+		fSum += fWeight
+	}
+	if fSum <= 0 {
+		return
+	}
+	for i := range this.Weights {
+		this.Weights[i] /= fSum
+	}
+}
+
+// GetSkinMatrix blends joint matrices by weights, false for vertices no joint affects.
+func (this *VertexSkin) GetSkinMatrix(vJointMatrices []mgl32.Mat4) (mgl32.Mat4, bool) {
+	var mSkin mgl32.Mat4
+	var bWeighted bool = false
+	for i, fWeight := range this.Weights {
+		if fWeight <= 0 || int(this.Joints[i]) >= len(vJointMatrices) {
+			continue
+		}
+		mSkin = mSkin.Add(vJointMatrices[this.Joints[i]].Mul(fWeight))
+		bWeighted = true
+	}
+	return mSkin, bWeighted
+}
+
+/*-----------------------------------------------
+
+  Name:	SkinVertex
+
+  Params:	vPosition, vNormal - vertex in bind pose
+  		skin - joints affecting vertex
+  		vJointMatrices - from Skin.GetJointMatrices
+
+  Result:	Returns vertex moved by skeleton, the same
+  		thing skinning vertex shader does.
+
+  /*---------------------------------------------*/
+
+func SkinVertex(vPosition, vNormal mgl32.Vec3, skin *VertexSkin, vJointMatrices []mgl32.Mat4) (mgl32.Vec3, mgl32.Vec3) {
+	mSkin, bWeighted := skin.GetSkinMatrix(vJointMatrices)
+	if !bWeighted {
+		return vPosition, vNormal
+	}
+	vSkinnedPosition := mSkin.Mul4x1(vPosition.Vec4(1)).Vec3()
+	vSkinnedNormal := mSkin.Mul4x1(vNormal.Vec4(0)).Vec3()
+	if vSkinnedNormal.Len() > 0 {
+		vSkinnedNormal = vSkinnedNormal.Normalize()
+	}
+	return vSkinnedPosition, vSkinnedNormal
+}
+
+// AnimationChannel animates one part of one node's pose
+type AnimationChannel struct {
+	Node          int // Skeleton node
+	Path          EChannelPath
+	Interpolation EInterpolation
+	Times         []float32 // Key times in seconds, ascending
+	Values        []float32 // 3 components per value, 4 for rotations, cubic spline has 3 values per key
+}
+
+func (this *AnimationChannel) getNumComponents() int {
+	if this.Path == CHANNEL_ROTATION {
+		return 4
+	}
+	return 3
+}
+
+// Validate checks that channel has as many values as keys need.
+func (this *AnimationChannel) Validate() error {
+	if len(this.Times) == 0 {
+		return errors.New("channel has no keys")
+	}
+	iValuesPerKey := this.getNumComponents()
+	if this.Interpolation == INTERPOLATION_CUBIC_SPLINE {
+		iValuesPerKey *= 3
+	}
+	if len(this.Values) != len(this.Times)*iValuesPerKey {
+		return fmt.Errorf("channel has %d values for %d keys", len(this.Values), len(this.Times))
+	}
+	for i := 1; i < len(this.Times); i++ {
+		if this.Times[i] < this.Times[i-1] {
+			return errors.New("key times aren't ascending")
+		}
+	}
+	return nil
+}
+
+// getKeyValue returns value of key, for cubic spline iPart 0 is in-tangent, 1 value and 2 out-tangent
+func (this *AnimationChannel) getKeyValue(iKey, iPart int, vResult []float32) {
+	iComponents := this.getNumComponents()
+	iOffset := iKey * iComponents
+	if this.Interpolation == INTERPOLATION_CUBIC_SPLINE {
+		iOffset = (iKey*3 + iPart) * iComponents
+	}
+	copy(vResult, this.Values[iOffset:iOffset+iComponents])
+}
+
+/*-----------------------------------------------
+
+  Name:	Sample
+
+  Params:	fTime - time in seconds
+  		vResult - 3 or 4 components to fill
+
+  Result:	Interpolates value of channel at time,
+  		time out of keys' range gets the first or
+  		the last key.
+
+  /*---------------------------------------------*/
+
+func (this *AnimationChannel) Sample(fTime float32, vResult []float32) {
+	iKeys := len(this.Times)
+	if fTime <= this.Times[0] || iKeys == 1 {
+		this.getKeyValue(0, 1, vResult)
+		return
+	}
+	if fTime >= this.Times[iKeys-1] {
+		this.getKeyValue(iKeys-1, 1, vResult)
+		return
+	}
+	// Last key that isn't after the time
+	iLow, iHigh := 0, iKeys-1
+	for iHigh-iLow > 1 {
+		iMiddle := (iLow + iHigh) / 2
+		if this.Times[iMiddle] <= fTime {
+			iLow = iMiddle
+		} else {
+			iHigh = iMiddle
+		}
+	}
+	fDelta := this.Times[iHigh] - this.Times[iLow]
+	if this.Interpolation == INTERPOLATION_STEP || fDelta <= 0 {
+		this.getKeyValue(iLow, 1, vResult)
+		return
+	}
+	s := (fTime - this.Times[iLow]) / fDelta
+	iComponents := this.getNumComponents()
+	var vA, vB [4]float32
+	switch this.Interpolation {
+	case INTERPOLATION_LINEAR:
+		this.getKeyValue(iLow, 1, vA[:])
+		this.getKeyValue(iHigh, 1, vB[:])
+		if this.Path == CHANNEL_ROTATION {
+			q := mgl32.QuatSlerp(mgl32.Quat{W: vA[3], V: mgl32.Vec3{vA[0], vA[1], vA[2]}},
+				mgl32.Quat{W: vB[3], V: mgl32.Vec3{vB[0], vB[1], vB[2]}}, s)
+			vResult[0], vResult[1], vResult[2], vResult[3] = q.V[0], q.V[1], q.V[2], q.W
+			return
+		}
+		for c := 0; c < iComponents; c++ {
+			vResult[c] = vA[c] + (vB[c]-vA[c])*s
+		}
+	case INTERPOLATION_CUBIC_SPLINE:
+		// Hermite spline, tangents are scaled by key distance
+		var vOutTangent, vInTangent [4]float32
+		this.getKeyValue(iLow, 1, vA[:])
+		this.getKeyValue(iLow, 2, vOutTangent[:])
+		this.getKeyValue(iHigh, 0, vInTangent[:])
+		this.getKeyValue(iHigh, 1, vB[:])
+		s2, s3 := s*s, s*s*s
+		for c := 0; c < iComponents; c++ {
+			vResult[c] = (2*s3-3*s2+1)*vA[c] + (s3-2*s2+s)*fDelta*vOutTangent[c] +
+				(-2*s3+3*s2)*vB[c] + (s3-s2)*fDelta*vInTangent[c]
+		}
+		if this.Path == CHANNEL_ROTATION {
+			fLength := float32(math.Sqrt(float64(vResult[0]*vResult[0] + vResult[1]*vResult[1] +
+				vResult[2]*vResult[2] + vResult[3]*vResult[3])))
+			if fLength > 0 {
+				for c := 0; c < 4; c++ {
+					vResult[c] /= fLength
+				}
+			}
+		}
+	}
+}
+
+// AnimationClip is named animation of skeleton
+type AnimationClip struct {
+	Name     string
+	Duration float32 // Time of the last key of all channels
+	Channels []AnimationChannel
+}
+
+// NewAnimationClip validates channels and computes duration of clip.
+func NewAnimationClip(sName string, vChannels []AnimationChannel) (*AnimationClip, error) {
+	this := &AnimationClip{Name: sName, Channels: vChannels}
+	for i := range vChannels {
+		if err := vChannels[i].Validate(); err != nil {
+			return nil, fmt.Errorf("animation %s channel %d: %v", sName, i, err)
+		}
+		if fLast := vChannels[i].Times[len(vChannels[i].Times)-1]; fLast > this.Duration {
+			this.Duration = fLast
+		}
+	}
+	return this, nil
+}
+
+// SamplePose overwrites animated parts of pose at time, parts clip doesn't animate keep their values.
+func (this *AnimationClip) SamplePose(fTime float32, vPose []JointPose) {
+	var vValue [4]float32
+	for i := range this.Channels {
+		channel := &this.Channels[i]
+		if channel.Node < 0 || channel.Node >= len(vPose) {
+			continue
+		}
+		channel.Sample(fTime, vValue[:])
+		pose := &vPose[channel.Node]
+		switch channel.Path {
+		case CHANNEL_TRANSLATION:
+			pose.Translation = mgl32.Vec3{vValue[0], vValue[1], vValue[2]}
+		case CHANNEL_ROTATION:
+			pose.Rotation = mgl32.Quat{W: vValue[3], V: mgl32.Vec3{vValue[0], vValue[1], vValue[2]}}.Normalize()
+		case CHANNEL_SCALE:
+			pose.Scale = mgl32.Vec3{vValue[0], vValue[1], vValue[2]}
+		}
+	}
+}
+
+// animationTrack is clip being played with its own time
+type animationTrack struct {
+	clip  *AnimationClip
+	fTime float32
+	bLoop bool
+}
+
+// advance moves time of track, returns false when clip that doesn't loop has ended
+func (this *animationTrack) advance(fDelta float32) bool {
+	if this.clip == nil {
+		return false
+	}
+	fDuration := this.clip.Duration
+	this.fTime += fDelta
+	if fDuration <= 0 {
+		this.fTime = 0
+		return this.bLoop
+	}
+	if this.bLoop {
+		this.fTime = float32(math.Mod(float64(this.fTime), float64(fDuration)))
+		if this.fTime < 0 {
+			this.fTime += fDuration
+		}
+		return true
+	}
+	if this.fTime >= fDuration {
+		this.fTime = fDuration
+		return false
+	}
+	if this.fTime <= 0 {
+		this.fTime = 0
+		return fDelta >= 0
+	}
+	return true
+}
+
+/*-----------------------------------------------
+
+  Name:	AnimationPlayer
+
+  Result:	Plays clips on skeleton. Clip can loop,
+  		speed up, slow down or go backwards, and
+  		new clip can fade in over the playing one.
+
+  /*---------------------------------------------*/
+
+type AnimationPlayer struct {
+	skeleton *Skeleton
+	current  animationTrack
+	previous animationTrack // Clip fading out, nil clip when there's no cross-fade
+
+	fFadeDuration float32
+	fFadeTime     float32
+	fSpeed        float32
+	bPlaying      bool
+
+	vPose         []JointPose
+	vPreviousPose []JointPose
+	vGlobal       []mgl32.Mat4
+}
+
+func NewAnimationPlayer(skeleton *Skeleton) *AnimationPlayer {
+	this := &AnimationPlayer{skeleton: skeleton, fSpeed: 1}
+	this.vPose = skeleton.GetRestPose()
+	this.vPreviousPose = make([]JointPose, len(this.vPose))
+	this.vGlobal = skeleton.GetGlobalMatrices(this.vPose, nil)
+	return this
+}
+
+// Play starts clip from its beginning right away, any cross-fade is dropped.
+func (this *AnimationPlayer) Play(clip *AnimationClip, bLoop bool) {
+	this.current = animationTrack{clip: clip, bLoop: bLoop}
+	if this.fSpeed < 0 && clip != nil {
+		this.current.fTime = clip.Duration
+	}
+	this.previous = animationTrack{}
+	this.bPlaying = clip != nil
+	this.Update(0)
+}
+
+/*-----------------------------------------------
+
+  Name:	CrossFade
+
+  Params:	clip - clip to play
+  		fDuration - seconds of blending
+  		bLoop - whether new clip loops
+
+  Result:	Starts clip and blends it over the one
+  		playing now, which keeps running until it's
+  		faded out.
+
+  /*---------------------------------------------*/
+
+func (this *AnimationPlayer) CrossFade(clip *AnimationClip, fDuration float32, bLoop bool) {
+	if this.current.clip == nil || fDuration <= 0 {
+		this.Play(clip, bLoop)
+		return
+	}
+	this.previous = this.current
+	this.fFadeDuration = fDuration
+	this.fFadeTime = 0
+	this.current = animationTrack{clip: clip, bLoop: bLoop}
+	if this.fSpeed < 0 && clip != nil {
+		this.current.fTime = clip.Duration
+	}
+	this.bPlaying = clip != nil
+	this.Update(0)
+}
+
+// Stop freezes the current pose, Resume continues from it.
+func (this *AnimationPlayer) Stop() {
+	this.bPlaying = false
+}
+
+func (this *AnimationPlayer) Resume() {
+	this.bPlaying = this.current.clip != nil
+}
+
+func (this *AnimationPlayer) IsPlaying() bool {
+	return this.bPlaying
+}
+
+func (this *AnimationPlayer) IsCrossFading() bool {
+	return this.previous.clip != nil
+}
+
+// SetSpeed sets playback rate, 1 is normal speed and negative values play backwards.
+func (this *AnimationPlayer) SetSpeed(fSpeed float32) {
+	this.fSpeed = fSpeed
+}
+
+func (this *AnimationPlayer) GetSpeed() float32 {
+	return this.fSpeed
+}
+
+func (this *AnimationPlayer) GetClip() *AnimationClip {
+	return this.current.clip
+}
+
+func (this *AnimationPlayer) GetTime() float32 {
+	return this.current.fTime
+}
+
+// SetTime jumps to time of current clip, pose is updated right away.
+func (this *AnimationPlayer) SetTime(fTime float32) {
+	this.current.fTime = fTime
+	this.current.advance(0)
+	this.samplePose()
+}
+
+/*-----------------------------------------------
+
+  Name:	Update
+
+  Params:	fDelta - seconds since the last update
+
+  Result:	Moves playback by delta scaled by speed and
+  		samples pose of skeleton. Clip that doesn't
+  		loop stops at its end.
+
+  /*---------------------------------------------*/
+
+func (this *AnimationPlayer) Update(fDelta float32) {
+	if this.bPlaying {
+		fStep := fDelta * this.fSpeed
+		if !this.current.advance(fStep) {
+			this.bPlaying = false
+		}
+		if this.previous.clip != nil {
+			this.previous.advance(fStep)
+			this.fFadeTime += fDelta
+			if this.fFadeTime >= this.fFadeDuration {
+				this.previous = animationTrack{}
+			}
+		}
+	}
+	this.samplePose()
+}
+
+// samplePose computes pose and global matrices from tracks
+func (this *AnimationPlayer) samplePose() {
+	for i := range this.skeleton.Nodes {
+		this.vPose[i] = this.skeleton.Nodes[i].RestPose
+	}
+	if this.current.clip != nil {
+		this.current.clip.SamplePose(this.current.fTime, this.vPose)
+	}
+	if this.previous.clip != nil {
+		for i := range this.skeleton.Nodes {
+			this.vPreviousPose[i] = this.skeleton.Nodes[i].RestPose
+		}
+		this.previous.clip.SamplePose(this.previous.fTime, this.vPreviousPose)
+		fAmount := this.fFadeTime / this.fFadeDuration
+		for i := range this.vPose {
+			this.vPose[i] = BlendPoses(this.vPreviousPose[i], this.vPose[i], fAmount)
+		}
+	}
+	this.vGlobal = this.skeleton.GetGlobalMatrices(this.vPose, this.vGlobal)
+}
+
+// GetPose returns local pose of every skeleton node, it's valid until the next update.
+func (this *AnimationPlayer) GetPose() []JointPose {
+	return this.vPose
+}
+
+// GetGlobalMatrices returns model space transform of every skeleton node, it's valid until the next update.
+func (this *AnimationPlayer) GetGlobalMatrices() []mgl32.Mat4 {
+	return this.vGlobal
+}
+
+func (this *AnimationPlayer) GetSkeleton() *Skeleton {
+	return this.skeleton
+}
@@ -0,0 +1,239 @@
+package libs
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"testing"
+)
+
+const fAnimationEpsilon = 1e-5
+
+func isNearValues(vA, vB []float32, fTolerance float32) bool {
+	if len(vA) != len(vB) {
+		return false
+	}
+	for i := range vA {
+		if math.Abs(float64(vA[i]-vB[i])) > float64(fTolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestVertexSkinWeights(t *testing.T) {
+	var skin VertexSkin
+	for i, fWeight := range []float32{0.1, 0.4, 0.3, 0.2} {
+		skin.AddWeight(i, fWeight)
+	}
+	// Fifth influence replaces the weakest one only if it's stronger
+	skin.AddWeight(7, 0.25)
+	skin.AddWeight(8, 0.05)
+	if skin.Joints != [4]uint16{7, 1, 2, 3} || skin.Weights != [4]float32{0.25, 0.4, 0.3, 0.2} {
+		t.Fatalf("skin is %v", skin)
+	}
+	skin.Normalize()
+	if !isNearValues(skin.Weights[:], []float32{0.25 / 1.15, 0.4 / 1.15, 0.3 / 1.15, 0.2 / 1.15}, fAnimationEpsilon) {
+		t.Fatalf("normalized weights are %v", skin.Weights)
+	}
+
+	var empty VertexSkin
+	empty.Normalize()
+	if empty.Weights != [4]float32{} {
+		t.Fatalf("weights of vertex without joints are %v", empty.Weights)
+	}
+}
+
+func TestSkinVertex(t *testing.T) {
+	vJointMatrices := []mgl32.Mat4{mgl32.Translate3D(1, 0, 0), mgl32.HomogRotate3DZ(math.Pi / 2)}
+	var skin VertexSkin
+	skin.AddWeight(0, 3)
+	skin.AddWeight(1, 1)
+	skin.Normalize()
+
+	// 0.75 of translation plus 0.25 of rotation, worked out by hand
+	mExpected := mgl32.Mat4{0.75, 0.25, 0, 0, -0.25, 0.75, 0, 0, 0, 0, 1, 0, 0.75, 0, 0, 1}
+	mSkin, bWeighted := skin.GetSkinMatrix(vJointMatrices)
+	if !bWeighted || !isNearValues(mSkin[:], mExpected[:], fAnimationEpsilon) {
+		t.Fatalf("skin matrix is %v, want %v", mSkin, mExpected)
+	}
+	vPosition, vNormal := SkinVertex(mgl32.Vec3{1, 0, 0}, mgl32.Vec3{1, 0, 0}, &skin, vJointMatrices)
+	vExpectedNormal := mgl32.Vec3{0.75, 0.25, 0}.Normalize()
+	if !isNearVec3(vPosition, mgl32.Vec3{1.5, 0.25, 0}, fAnimationEpsilon) || !isNearVec3(vNormal, vExpectedNormal, fAnimationEpsilon) {
+		t.Fatalf("skinned vertex is %v, normal %v", vPosition, vNormal)
+	}
+
+	// Vertices without weights and joints past the matrices are left alone
+	var unweighted VertexSkin
+	unweighted.Joints[0], unweighted.Weights[0] = 5, 1
+	if _, bWeighted := unweighted.GetSkinMatrix(vJointMatrices); bWeighted {
+		t.Fatal("joint past matrices is used")
+	}
+	if vPosition, vNormal := SkinVertex(mgl32.Vec3{1, 2, 3}, mgl32.Vec3{0, 0, 1}, &VertexSkin{}, vJointMatrices); vPosition != (mgl32.Vec3{1, 2, 3}) || vNormal != (mgl32.Vec3{0, 0, 1}) {
+		t.Fatalf("vertex without weights moved to %v, %v", vPosition, vNormal)
+	}
+}
+
+func TestSkinJointMatrices(t *testing.T) {
+	restRoot, restChild := IdentityPose(), IdentityPose()
+	restRoot.Translation = mgl32.Vec3{0, 1, 0}
+	restChild.Translation = mgl32.Vec3{0, 1, 0}
+	skeleton, err := NewSkeleton([]SkeletonNode{{Name: "root", Parent: -1, RestPose: restRoot}, {Name: "child", Parent: 0, RestPose: restChild}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vPose := skeleton.GetRestPose()
+	vGlobal := skeleton.GetGlobalMatrices(vPose, nil)
+	skin := Skin{Joints: []int{0, 1}, InverseBind: []mgl32.Mat4{vGlobal[0].Inv(), vGlobal[1].Inv()}}
+	var vertexSkin VertexSkin
+	vertexSkin.AddWeight(1, 1)
+
+	// Rest pose is bind pose, so nothing moves
+	vPosition, _ := SkinVertex(mgl32.Vec3{1, 2, 0}, mgl32.Vec3{1, 0, 0}, &vertexSkin, skin.GetJointMatrices(vGlobal, nil))
+	if !isNearVec3(vPosition, mgl32.Vec3{1, 2, 0}, fAnimationEpsilon) {
+		t.Fatalf("vertex in rest pose moved to %v", vPosition)
+	}
+	// Child turned by 90 degrees around Z swings vertex around its joint at 0,2,0
+	vPose[1].Rotation = mgl32.QuatRotate(math.Pi/2, mgl32.Vec3{0, 0, 1})
+	vGlobal = skeleton.GetGlobalMatrices(vPose, vGlobal)
+	vPosition, vNormal := SkinVertex(mgl32.Vec3{1, 2, 0}, mgl32.Vec3{1, 0, 0}, &vertexSkin, skin.GetJointMatrices(vGlobal, nil))
+	if !isNearVec3(vPosition, mgl32.Vec3{0, 3, 0}, fAnimationEpsilon) || !isNearVec3(vNormal, mgl32.Vec3{0, 1, 0}, fAnimationEpsilon) {
+		t.Fatalf("vertex of turned joint is %v, normal %v", vPosition, vNormal)
+	}
+}
+
+func TestAnimationChannelSample(t *testing.T) {
+	fSin, fCos := float32(math.Sin(math.Pi/8)), float32(math.Cos(math.Pi/8))
+	fQuarterSin, fQuarterCos := float32(math.Sin(math.Pi/16)), float32(math.Cos(math.Pi/16))
+	fRight := float32(math.Sqrt(0.5))
+	vTranslations := []float32{0, 0, 0, 10, 20, 30, 40, 50, 60}
+	vCases := []struct {
+		sName   string
+		channel AnimationChannel
+		fTime   float32
+		vValue  []float32
+	}{
+		{"step before keys", AnimationChannel{Interpolation: INTERPOLATION_STEP, Times: []float32{0, 1, 2}, Values: vTranslations}, -1, []float32{0, 0, 0}},
+		{"step between keys", AnimationChannel{Interpolation: INTERPOLATION_STEP, Times: []float32{0, 1, 2}, Values: vTranslations}, 1.99, []float32{10, 20, 30}},
+		{"step at key", AnimationChannel{Interpolation: INTERPOLATION_STEP, Times: []float32{0, 1, 2}, Values: vTranslations}, 1, []float32{10, 20, 30}},
+		{"step after keys", AnimationChannel{Interpolation: INTERPOLATION_STEP, Times: []float32{0, 1, 2}, Values: vTranslations}, 5, []float32{40, 50, 60}},
+		{"linear between keys", AnimationChannel{Interpolation: INTERPOLATION_LINEAR, Times: []float32{0, 1, 2}, Values: vTranslations}, 0.5, []float32{5, 10, 15}},
+		{"linear at key", AnimationChannel{Interpolation: INTERPOLATION_LINEAR, Times: []float32{0, 1, 2}, Values: vTranslations}, 1, []float32{10, 20, 30}},
+		{"linear second span", AnimationChannel{Interpolation: INTERPOLATION_LINEAR, Times: []float32{0, 1, 2}, Values: vTranslations}, 1.5, []float32{25, 35, 45}},
+		{"linear single key", AnimationChannel{Interpolation: INTERPOLATION_LINEAR, Times: []float32{3}, Values: []float32{1, 2, 3}}, 10, []float32{1, 2, 3}},
+		// Rotation from none to 90 degrees around Z, halfway is 45 and quarter way 22.5 degrees
+		{"slerp halfway", AnimationChannel{Path: CHANNEL_ROTATION, Interpolation: INTERPOLATION_LINEAR, Times: []float32{0, 2},
+			Values: []float32{0, 0, 0, 1, 0, 0, fRight, fRight}}, 1, []float32{0, 0, fSin, fCos}},
+		{"slerp quarter", AnimationChannel{Path: CHANNEL_ROTATION, Interpolation: INTERPOLATION_LINEAR, Times: []float32{0, 2},
+			Values: []float32{0, 0, 0, 1, 0, 0, fRight, fRight}}, 0.5, []float32{0, 0, fQuarterSin, fQuarterCos}},
+		{"slerp at key", AnimationChannel{Path: CHANNEL_ROTATION, Interpolation: INTERPOLATION_LINEAR, Times: []float32{0, 2},
+			Values: []float32{0, 0, 0, 1, 0, 0, fRight, fRight}}, 2, []float32{0, 0, fRight, fRight}},
+		// Keys hold in-tangent, value and out-tangent. Halfway Hermite weights are 1/2, 1/8, 1/2 and -1/8, tangents scale by 2 seconds
+		{"cubic spline halfway", AnimationChannel{Interpolation: INTERPOLATION_CUBIC_SPLINE, Times: []float32{0, 2},
+			Values: []float32{9, 9, 9, 0, 1, 0, 1, 0, 0, 0, -2, 0, 2, 1, 0, 9, 9, 9}}, 1, []float32{1.25, 1.5, 0}},
+		{"cubic spline at key", AnimationChannel{Interpolation: INTERPOLATION_CUBIC_SPLINE, Times: []float32{0, 2},
+			Values: []float32{9, 9, 9, 0, 1, 0, 1, 0, 0, 0, -2, 0, 2, 1, 0, 9, 9, 9}}, 2, []float32{2, 1, 0}},
+	}
+	for _, testCase := range vCases {
+		if err := testCase.channel.Validate(); err != nil {
+			t.Fatalf("%s: %v", testCase.sName, err)
+		}
+		vValue := make([]float32, len(testCase.vValue))
+		testCase.channel.Sample(testCase.fTime, vValue)
+		if !isNearValues(vValue, testCase.vValue, fAnimationEpsilon) {
+			t.Errorf("%s: sampled %v, want %v", testCase.sName, vValue, testCase.vValue)
+		}
+	}
+
+	vInvalid := []AnimationChannel{
+		{Interpolation: INTERPOLATION_LINEAR},
+		{Interpolation: INTERPOLATION_LINEAR, Times: []float32{0, 1}, Values: []float32{0, 0, 0}},
+		{Interpolation: INTERPOLATION_CUBIC_SPLINE, Times: []float32{0, 1}, Values: vTranslations[:6]},
+		{Interpolation: INTERPOLATION_LINEAR, Times: []float32{1, 0}, Values: vTranslations[:6]},
+	}
+	for i, channel := range vInvalid {
+		if channel.Validate() == nil {
+			t.Errorf("invalid channel %d passed", i)
+		}
+	}
+}
+
+// newSlideClip returns clip moving node 0 along X from fFrom to fTo over fDuration seconds
+func newSlideClip(t *testing.T, sName string, fFrom, fTo, fDuration float32) *AnimationClip {
+	clip, err := NewAnimationClip(sName, []AnimationChannel{{Node: 0, Path: CHANNEL_TRANSLATION, Interpolation: INTERPOLATION_LINEAR,
+		Times: []float32{0, fDuration}, Values: []float32{fFrom, 0, 0, fTo, 0, 0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clip
+}
+
+func TestAnimationPlayer(t *testing.T) {
+	skeleton, err := NewSkeleton([]SkeletonNode{{Name: "root", Parent: -1, RestPose: IdentityPose()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slide := newSlideClip(t, "slide", 0, 2, 2)
+	if slide.Duration != 2 {
+		t.Fatalf("clip lasts %v", slide.Duration)
+	}
+	player := NewAnimationPlayer(skeleton)
+	vSteps := []struct {
+		sName  string
+		fSpeed float32
+		fDelta float32
+		fTime  float32
+	}{
+		{"forward", 1, 0.5, 0.5},
+		{"loop wraps", 1, 2, 0.5},
+		{"double speed", 2, 0.25, 1},
+		{"backwards wraps", -1, 1.5, 1.5},
+		{"half speed", 0.5, 0.8, 1.9},
+	}
+	player.Play(slide, true)
+	for _, step := range vSteps {
+		player.SetSpeed(step.fSpeed)
+		player.Update(step.fDelta)
+		if fX := player.GetPose()[0].Translation[0]; math.Abs(float64(player.GetTime()-step.fTime)) > fAnimationEpsilon || math.Abs(float64(fX-step.fTime)) > fAnimationEpsilon {
+			t.Fatalf("%s: time is %v, node at %v, want %v", step.sName, player.GetTime(), fX, step.fTime)
+		}
+	}
+	if vGlobal := player.GetGlobalMatrices(); !isNearVec3(vGlobal[0].Col(3).Vec3(), mgl32.Vec3{1.9, 0, 0}, fAnimationEpsilon) {
+		t.Fatalf("global matrix is %v", vGlobal[0])
+	}
+
+	// Clip that doesn't loop stops at its end
+	player.SetSpeed(1)
+	player.Play(slide, false)
+	player.Update(3)
+	if player.IsPlaying() || player.GetTime() != 2 || player.GetPose()[0].Translation[0] != 2 {
+		t.Fatalf("clip that doesn't loop is at %v, playing %v", player.GetTime(), player.IsPlaying())
+	}
+
+	// Fading clip keeps running, weight of new clip grows with fade time
+	hold := newSlideClip(t, "hold", 10, 10, 1)
+	player.Play(slide, true)
+	player.Update(1)
+	player.CrossFade(hold, 0.5, true)
+	vFade := []struct {
+		fDelta     float32
+		fX         float32
+		bCrossFade bool
+	}{
+		{0, 1, true},
+		{0.25, 1.25 + (10-1.25)*0.5, true},
+		{0.125, 1.375 + (10-1.375)*0.75, true},
+		{0.125, 10, false},
+	}
+	if player.GetPose()[0].Translation[0] != 1 {
+		t.Fatalf("cross-fade started at %v", player.GetPose()[0].Translation[0])
+	}
+	for i, step := range vFade {
+		player.Update(step.fDelta)
+		if fX := player.GetPose()[0].Translation[0]; math.Abs(float64(fX-step.fX)) > fAnimationEpsilon || player.IsCrossFading() != step.bCrossFade {
+			t.Fatalf("fade step %d: node at %v, cross-fading %v, want %v, %v", i, fX, player.IsCrossFading(), step.fX, step.bCrossFade)
+		}
+	}
+	if player.GetClip() != hold {
+		t.Fatalf("playing %s after cross-fade", player.GetClip().Name)
+	}
+}
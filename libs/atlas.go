@@ -0,0 +1,476 @@
+package libs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+)
+
+// AtlasEntry is one packed image, X, Y, Width and Height are in texels without padding
+type AtlasEntry struct {
+	Name   string
+	X      int
+	Y      int
+	Width  int
+	Height int
+	U0, V0 float32 // Texture coordinates of top left corner, V grows down like image rows
+	U1, V1 float32 // Texture coordinates of bottom right corner
+}
+
+// AtlasLayout is serialisable description of packed atlas, image is stored separately
+type AtlasLayout struct {
+	Width   int
+	Height  int
+	Padding int
+	Entries []AtlasEntry
+}
+
+// skylineNode is horizontal segment of skyline, everything below Y is used
+type skylineNode struct {
+	x, y, iWidth int
+}
+
+/*-----------------------------------------------
+
+  Name:	Atlas
+
+  Result:	Packs images into one with skyline
+  		bottom-left packer. Every image gets
+  		padding filled with its edge texels, so
+  		filtering and mipmaps don't bleed
+  		neighbours in.
+
+  /*---------------------------------------------*/
+
+type Atlas struct {
+	iWidth   int
+	iHeight  int
+	iPadding int
+
+	vSkyline []skylineNode
+	image    *image.NRGBA
+	vEntries []AtlasEntry
+	mEntries map[string]int // Index to vEntries by name
+
+	iRevision    int // Grows with every change of image
+	iBaseEntries int // Entries restored from layout, they belong to revision 0
+}
+
+// MipRegion is rectangle of one mip level with its texels, rows are tightly packed RGBA8
+type MipRegion struct {
+	Level int
+	Rect  image.Rectangle // In texels of the level
+	Pix   []byte
+}
+
+func NewAtlas(iWidth, iHeight, iPadding int) *Atlas {
+	this := Atlas{iWidth: iWidth, iHeight: iHeight, iPadding: MAX(0, iPadding)}
+	this.vSkyline = []skylineNode{{0, 0, iWidth}}
+	this.image = image.NewNRGBA(image.Rect(0, 0, iWidth, iHeight))
+	this.mEntries = make(map[string]int)
+	return &this
+}
+
+// findPosition returns top left corner where rectangle touches the lowest skyline, or false if it doesn't fit
+func (this *Atlas) findPosition(iWidth, iHeight int) (int, int, int, bool) {
+	var iBestIndex, iBestX, iBestY int = -1, 0, this.iHeight
+	var iBestWaste int
+	for i, node := range this.vSkyline {
+		if node.x+iWidth > this.iWidth {
+			break
+		}
+		// Rectangle rests on the highest node it spans
+		var y int = 0
+		var iWaste int = 0
+		iRemaining := iWidth
+		for j := i; iRemaining > 0; j++ {
+			y = MAX(y, this.vSkyline[j].y)
+			iRemaining -= this.vSkyline[j].iWidth
+		}
+		if y+iHeight > this.iHeight {
+			continue
+		}
+		iRemaining = iWidth
+		for j := i; iRemaining > 0; j++ {
+			iSpan := MIN(iRemaining, this.vSkyline[j].iWidth)
+			iWaste += (y - this.vSkyline[j].y) * iSpan
+			iRemaining -= iSpan
+		}
+		if y < iBestY || (y == iBestY && iWaste < iBestWaste) {
+			iBestIndex, iBestX, iBestY, iBestWaste = i, node.x, y, iWaste
+		}
+	}
+	return iBestIndex, iBestX, iBestY, iBestIndex >= 0
+}
+
+// placeRectangle raises skyline under placed rectangle
+func (this *Atlas) placeRectangle(iIndex, x, y, iWidth, iHeight int) {
+	newNode := skylineNode{x, y + iHeight, iWidth}
+	vSkyline := append([]skylineNode{}, this.vSkyline[:iIndex]...)
+	vSkyline = append(vSkyline, newNode)
+	iEnd := x + iWidth
+	for _, node := range this.vSkyline[iIndex:] {
+		if node.x+node.iWidth <= iEnd {
+			continue // Covered completely
+		}
+		if node.x < iEnd {
+			node.iWidth -= iEnd - node.x
+			node.x = iEnd
+		}
+		vSkyline = append(vSkyline, node)
+	}
+	// Neighbours at the same height become one node
+	this.vSkyline = vSkyline[:1]
+	for _, node := range vSkyline[1:] {
+		last := &this.vSkyline[len(this.vSkyline)-1]
+		if last.y == node.y {
+			last.iWidth += node.iWidth
+		} else {
+			this.vSkyline = append(this.vSkyline, node)
+		}
+	}
+}
+
+/*-----------------------------------------------
+
+  Name:	Add
+
+  Params:	sName - unique name of entry
+  		img - image to pack
+
+  Result:	Packs image and returns its entry, error
+  		if the name is taken or atlas is full.
+
+  /*---------------------------------------------*/
+
+func (this *Atlas) Add(sName string, img image.Image) (AtlasEntry, error) {
+	if _, bOK := this.mEntries[sName]; bOK {
+		return AtlasEntry{}, fmt.Errorf("atlas already has entry %q", sName)
+	}
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return AtlasEntry{}, fmt.Errorf("image of atlas entry %q is empty", sName)
+	}
+	iPaddedWidth, iPaddedHeight := bounds.Dx()+2*this.iPadding, bounds.Dy()+2*this.iPadding
+	iIndex, x, y, bOK := this.findPosition(iPaddedWidth, iPaddedHeight)
+	if !bOK {
+		return AtlasEntry{}, fmt.Errorf("atlas %dx%d has no room for %q of %dx%d", this.iWidth, this.iHeight, sName, bounds.Dx(), bounds.Dy())
+	}
+	this.placeRectangle(iIndex, x, y, iPaddedWidth, iPaddedHeight)
+
+	entry := AtlasEntry{Name: sName, X: x + this.iPadding, Y: y + this.iPadding, Width: bounds.Dx(), Height: bounds.Dy()}
+	this.setUV(&entry)
+	draw.Draw(this.image, image.Rect(entry.X, entry.Y, entry.X+entry.Width, entry.Y+entry.Height), img, bounds.Min, draw.Src)
+	this.extrudeEdges(entry)
+
+	this.mEntries[sName] = len(this.vEntries)
+	this.vEntries = append(this.vEntries, entry)
+	this.iRevision++
+	return entry, nil
+}
+
+func (this *Atlas) setUV(entry *AtlasEntry) {
+	entry.U0 = float32(entry.X) / float32(this.iWidth)
+	entry.V0 = float32(entry.Y) / float32(this.iHeight)
+	entry.U1 = float32(entry.X+entry.Width) / float32(this.iWidth)
+	entry.V1 = float32(entry.Y+entry.Height) / float32(this.iHeight)
+}
+
+// extrudeEdges copies edge texels of entry into its padding
+func (this *Atlas) extrudeEdges(entry AtlasEntry) {
+	p := this.iPadding
+	for y := entry.Y - p; y < entry.Y+entry.Height+p; y++ {
+		iSrcY := MIN(MAX(y, entry.Y), entry.Y+entry.Height-1)
+		for x := entry.X - p; x < entry.X+entry.Width+p; x++ {
+			iSrcX := MIN(MAX(x, entry.X), entry.X+entry.Width-1)
+			if iSrcX == x && iSrcY == y {
+				continue
+			}
+			iDst, iSrc := this.image.PixOffset(x, y), this.image.PixOffset(iSrcX, iSrcY)
+			copy(this.image.Pix[iDst:iDst+4], this.image.Pix[iSrc:iSrc+4])
+		}
+	}
+}
+
+func (this *Atlas) Get(sName string) (AtlasEntry, bool) {
+	iIndex, bOK := this.mEntries[sName]
+	if !bOK {
+		return AtlasEntry{}, false
+	}
+	return this.vEntries[iIndex], true
+}
+
+// GetEntries returns entries in order they were added.
+func (this *Atlas) GetEntries() []AtlasEntry {
+	return append([]AtlasEntry(nil), this.vEntries...)
+}
+
+func (this *Atlas) GetImage() *image.NRGBA {
+	return this.image
+}
+
+// GetRevision changes whenever an entry is added, so uploaded copies can tell they are stale.
+func (this *Atlas) GetRevision() int {
+	return this.iRevision
+}
+
+/*-----------------------------------------------
+
+  Name:	GetChangedRect
+
+  Params:	iRevision - revision of uploaded copy
+
+  Result:	Returns rectangle around padded entries
+  		added since the revision, empty if none.
+  		Whole atlas is returned for revisions it
+  		never had.
+
+  /*---------------------------------------------*/
+
+func (this *Atlas) GetChangedRect(iRevision int) image.Rectangle {
+	if iRevision < 0 || iRevision > this.iRevision {
+		return this.image.Rect
+	}
+	var rect image.Rectangle
+	for _, entry := range this.vEntries[this.iBaseEntries+iRevision:] {
+		p := this.iPadding
+		rect = rect.Union(image.Rect(entry.X-p, entry.Y-p, entry.X+entry.Width+p, entry.Y+entry.Height+p))
+	}
+	return rect
+}
+
+// GetUsedArea returns part of atlas covered by entries including their padding, in 0..1.
+func (this *Atlas) GetUsedArea() float32 {
+	var iUsed int
+	for _, entry := range this.vEntries {
+		iUsed += (entry.Width + 2*this.iPadding) * (entry.Height + 2*this.iPadding)
+	}
+	return float32(iUsed) / float32(this.iWidth*this.iHeight)
+}
+
+/*-----------------------------------------------
+
+  Name:	GetSafeMipLevels
+
+  Params:	none
+
+  Result:	Returns number of mip levels (with the
+  		top one) in which no entry reaches into
+  		its neighbour. Each level halves padding.
+
+  /*---------------------------------------------*/
+
+func (this *Atlas) GetSafeMipLevels() int {
+	var iLevels int = 1
+	for iGap := this.iPadding; iGap >= 1 && iLevels < 16; iGap /= 2 {
+		iLevels++
+	}
+	// Level can't be smaller than 1x1
+	iMaxLevels := 1
+	for iSize := MAX(this.iWidth, this.iHeight); iSize > 1; iSize /= 2 {
+		iMaxLevels++
+	}
+	return MIN(iLevels, iMaxLevels)
+}
+
+// GenerateMipmaps returns texels of safe mip levels, the first one is atlas image itself.
+func (this *Atlas) GenerateMipmaps(filter MipFilter, bLinear bool) [][]byte {
+	vLevels := GenerateMipmaps(this.image, filter, bLinear)
+	return vLevels[:MIN(len(vLevels), this.GetSafeMipLevels())]
+}
+
+/*-----------------------------------------------
+
+  Name:	GenerateMipRegions
+
+  Params:	rect - changed rectangle of top level
+  		filter, bLinear - same as in
+  		GenerateMipmaps
+
+  Result:	Returns region of every safe mip level
+  		covering all texels rect affects, equal to
+  		the same part of GenerateMipmaps. Levels are
+  		made from a crop aligned to the smallest
+  		level. Kaiser filter spreads change less
+  		than 7 texels of the smallest level, so its
+  		crop gets margin of 16 of them and half is
+  		returned. Atlases whose size isn't multiple
+  		of the alignment get whole levels.
+
+  /*---------------------------------------------*/
+
+func (this *Atlas) GenerateMipRegions(rect image.Rectangle, filter MipFilter, bLinear bool) []MipRegion {
+	rect = rect.Intersect(this.image.Rect)
+	if rect.Empty() {
+		return nil
+	}
+	iLevels := this.GetSafeMipLevels()
+	iAlign := 1 << (iLevels - 1)
+	var iMargin int
+	if filter == MIP_FILTER_KAISER {
+		iMargin = 16 * iAlign
+	}
+	crop := this.image.Rect
+	if this.iWidth%iAlign == 0 && this.iHeight%iAlign == 0 {
+		crop = image.Rect((rect.Min.X-iMargin)/iAlign*iAlign, (rect.Min.Y-iMargin)/iAlign*iAlign,
+			(rect.Max.X+iMargin+iAlign-1)/iAlign*iAlign, (rect.Max.Y+iMargin+iAlign-1)/iAlign*iAlign).Intersect(this.image.Rect)
+	}
+	// Sides of crop inside the atlas are filtered with wrong neighbours, only half of margin is kept there
+	inner := crop
+	if crop.Min.X > 0 {
+		inner.Min.X += iMargin / 2
+	}
+	if crop.Min.Y > 0 {
+		inner.Min.Y += iMargin / 2
+	}
+	if crop.Max.X < this.iWidth {
+		inner.Max.X -= iMargin / 2
+	}
+	if crop.Max.Y < this.iHeight {
+		inner.Max.Y -= iMargin / 2
+	}
+
+	vLevels := GenerateMipmaps(this.image.SubImage(crop), filter, bLinear)
+	vRegions := make([]MipRegion, 0, iLevels)
+	for iMip := 0; iMip < iLevels && iMip < len(vLevels); iMip++ {
+		iCropWidth := GetMipSize(crop.Dx(), iMip)
+		region := MipRegion{Level: iMip, Rect: image.Rect(inner.Min.X>>iMip, inner.Min.Y>>iMip, inner.Max.X>>iMip, inner.Max.Y>>iMip)}
+		vOffset := region.Rect.Min.Sub(image.Pt(crop.Min.X>>iMip, crop.Min.Y>>iMip))
+		for y := 0; y < region.Rect.Dy(); y++ {
+			iStart := ((vOffset.Y+y)*iCropWidth + vOffset.X) * 4
+			region.Pix = append(region.Pix, vLevels[iMip][iStart:iStart+region.Rect.Dx()*4]...)
+		}
+		vRegions = append(vRegions, region)
+	}
+	return vRegions
+}
+
+// GetTextureData returns atlas with its safe mip levels as RGBA8 texture data.
+func (this *Atlas) GetTextureData(filter MipFilter, bLinear bool) *TextureData {
+	vLevels := this.GenerateMipmaps(filter, bLinear)
+	texData := &TextureData{Format: TEXEL_FORMAT_RGBA8, SRGB: !bLinear, Width: this.iWidth, Height: this.iHeight,
+		Depth: 1, Faces: 1, Layers: 1, MipLevels: len(vLevels)}
+	for iMip, pixels := range vLevels {
+		texData.Surfaces = append(texData.Surfaces, TextureSurface{Width: GetMipSize(this.iWidth, iMip), Height: GetMipSize(this.iHeight, iMip), Depth: 1, Data: pixels})
+	}
+	return texData
+}
+
+func (this *Atlas) GetLayout() AtlasLayout {
+	return AtlasLayout{Width: this.iWidth, Height: this.iHeight, Padding: this.iPadding, Entries: this.GetEntries()}
+}
+
+/*-----------------------------------------------
+
+  Name:	NewAtlasFromLayout
+
+  Params:	layout - layout of pre-baked atlas
+  		img - its image
+
+  Result:	Restores atlas, more entries can be added
+  		above the packed ones.
+
+  /*---------------------------------------------*/
+
+func NewAtlasFromLayout(layout AtlasLayout, img image.Image) (*Atlas, error) {
+	if layout.Width <= 0 || layout.Height <= 0 {
+		return nil, errors.New("atlas layout has no size")
+	}
+	if img.Bounds().Dx() != layout.Width || img.Bounds().Dy() != layout.Height {
+		return nil, fmt.Errorf("atlas image is %dx%d, layout says %dx%d", img.Bounds().Dx(), img.Bounds().Dy(), layout.Width, layout.Height)
+	}
+	this := NewAtlas(layout.Width, layout.Height, layout.Padding)
+	draw.Draw(this.image, this.image.Bounds(), img, img.Bounds().Min, draw.Src)
+	// Skyline goes along the lowest free row of each column
+	vColumns := make([]int, layout.Width)
+	for _, entry := range layout.Entries {
+		if _, bOK := this.mEntries[entry.Name]; bOK {
+			return nil, fmt.Errorf("atlas layout has entry %q twice", entry.Name)
+		}
+		if entry.X-this.iPadding < 0 || entry.Y-this.iPadding < 0 || entry.X+entry.Width+this.iPadding > layout.Width ||
+			entry.Y+entry.Height+this.iPadding > layout.Height {
+			return nil, fmt.Errorf("atlas entry %q is outside of atlas", entry.Name)
+		}
+		this.setUV(&entry)
+		for x := entry.X - this.iPadding; x < entry.X+entry.Width+this.iPadding; x++ {
+			vColumns[x] = MAX(vColumns[x], entry.Y+entry.Height+this.iPadding)
+		}
+		this.mEntries[entry.Name] = len(this.vEntries)
+		this.vEntries = append(this.vEntries, entry)
+	}
+	this.iBaseEntries = len(this.vEntries)
+	this.vSkyline = this.vSkyline[:0]
+	for x, y := range vColumns {
+		if x > 0 && this.vSkyline[len(this.vSkyline)-1].y == y {
+			this.vSkyline[len(this.vSkyline)-1].iWidth++
+		} else {
+			this.vSkyline = append(this.vSkyline, skylineNode{x, y, 1})
+		}
+	}
+	return this, nil
+}
+
+func (this *AtlasLayout) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(this)
+}
+
+func ReadAtlasLayout(r io.Reader) (AtlasLayout, error) {
+	var layout AtlasLayout
+	err := json.NewDecoder(r).Decode(&layout)
+	return layout, err
+}
+
+// SaveAtlas writes atlas image as PNG and its layout as JSON.
+func (this *Atlas) SaveAtlas(sImagePath, sLayoutPath string) error {
+	imageFile, err := os.Create(sImagePath)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(imageFile, this.image); err != nil {
+		imageFile.Close()
+		return err
+	}
+	if err := imageFile.Close(); err != nil {
+		return err
+	}
+	layoutFile, err := os.Create(sLayoutPath)
+	if err != nil {
+		return err
+	}
+	layout := this.GetLayout()
+	if err := layout.Write(layoutFile); err != nil {
+		layoutFile.Close()
+		return err
+	}
+	return layoutFile.Close()
+}
+
+// LoadAtlas reads atlas saved by SaveAtlas.
+func LoadAtlas(sImagePath, sLayoutPath string) (*Atlas, error) {
+	layoutFile, err := OpenAsset(sLayoutPath)
+	if err != nil {
+		return nil, err
+	}
+	defer layoutFile.Close()
+	layout, err := ReadAtlasLayout(layoutFile)
+	if err != nil {
+		return nil, fmt.Errorf("atlas layout %s: %v", sLayoutPath, err)
+	}
+	imageFile, err := OpenAsset(sImagePath)
+	if err != nil {
+		return nil, err
+	}
+	defer imageFile.Close()
+	img, _, err := image.Decode(imageFile)
+	if err != nil {
+		return nil, fmt.Errorf("atlas image %s: %v", sImagePath, err)
+	}
+	return NewAtlasFromLayout(layout, img)
+}
@@ -0,0 +1,211 @@
+package libs
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// newSolidImage returns image of one colour with different top left texel, so extruded edges can be checked
+func newSolidImage(iWidth, iHeight int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, iWidth, iHeight))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	img.SetNRGBA(0, 0, color.NRGBA{c.B, c.R, c.G, 255})
+	return img
+}
+
+func getPaddedRect(entry AtlasEntry, iPadding int) image.Rectangle {
+	return image.Rect(entry.X-iPadding, entry.Y-iPadding, entry.X+entry.Width+iPadding, entry.Y+entry.Height+iPadding)
+}
+
+// checkAtlas verifies entries lie inside atlas with their padding, don't overlap and have edge texels in padding
+func checkAtlas(t *testing.T, atlas *Atlas) {
+	t.Helper()
+	vEntries := atlas.GetEntries()
+	for i, entry := range vEntries {
+		rect := getPaddedRect(entry, atlas.iPadding)
+		if !rect.In(atlas.image.Rect) {
+			t.Fatalf("%s %v is outside of atlas", entry.Name, rect)
+		}
+		for _, other := range vEntries[:i] {
+			if rect.Overlaps(getPaddedRect(other, atlas.iPadding)) {
+				t.Fatalf("%s %v overlaps %s %v", entry.Name, rect, other.Name, getPaddedRect(other, atlas.iPadding))
+			}
+		}
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				iEdgeX := MIN(MAX(x, entry.X), entry.X+entry.Width-1)
+				iEdgeY := MIN(MAX(y, entry.Y), entry.Y+entry.Height-1)
+				if atlas.image.NRGBAAt(x, y) != atlas.image.NRGBAAt(iEdgeX, iEdgeY) {
+					t.Fatalf("%s texel %d,%d isn't its edge texel %d,%d", entry.Name, x, y, iEdgeX, iEdgeY)
+				}
+			}
+		}
+		if entry.U0 != float32(entry.X)/float32(atlas.iWidth) || entry.V1 != float32(entry.Y+entry.Height)/float32(atlas.iHeight) {
+			t.Fatalf("%s has UV %v,%v - %v,%v", entry.Name, entry.U0, entry.V0, entry.U1, entry.V1)
+		}
+	}
+}
+
+func TestAtlasPacking(t *testing.T) {
+	atlas := NewAtlas(64, 64, 2)
+	vSizes := []image.Point{{20, 10}, {8, 8}, {30, 4}, {12, 20}, {5, 5}, {16, 16}, {3, 9}, {10, 3}}
+	for i, size := range vSizes {
+		c := color.NRGBA{uint8(30 * i), uint8(255 - 30*i), uint8(17 * i), 255}
+		entry, err := atlas.Add(string(rune('a'+i)), newSolidImage(size.X, size.Y, c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.Width != size.X || entry.Height != size.Y || atlas.image.NRGBAAt(entry.X+1, entry.Y) != c {
+			t.Fatalf("%s is %dx%d at %d,%d", entry.Name, entry.Width, entry.Height, entry.X, entry.Y)
+		}
+	}
+	checkAtlas(t, atlas)
+	if atlas.GetRevision() != len(vSizes) {
+		t.Fatalf("revision is %d after %d entries", atlas.GetRevision(), len(vSizes))
+	}
+	if _, err := atlas.Add("a", newSolidImage(1, 1, color.NRGBA{})); err == nil {
+		t.Fatal("entry was added twice")
+	}
+	if _, err := atlas.Add("empty", image.NewNRGBA(image.Rect(0, 0, 0, 4))); err == nil {
+		t.Fatal("empty image was added")
+	}
+}
+
+func TestAtlasFull(t *testing.T) {
+	atlas := NewAtlas(32, 32, 1)
+	// Padded 16x16 entries fill the atlas exactly
+	for i := 0; i < 4; i++ {
+		if _, err := atlas.Add(string(rune('a'+i)), newSolidImage(14, 14, color.NRGBA{uint8(i), 0, 0, 255})); err != nil {
+			t.Fatalf("entry %d: %v", i, err)
+		}
+	}
+	checkAtlas(t, atlas)
+	if atlas.GetUsedArea() != 1 {
+		t.Fatalf("used area is %v", atlas.GetUsedArea())
+	}
+	iRevision := atlas.GetRevision()
+	if _, err := atlas.Add("extra", newSolidImage(1, 1, color.NRGBA{})); err == nil {
+		t.Fatal("entry was added to full atlas")
+	}
+	// Entry bigger than atlas with its padding
+	if _, err := NewAtlas(32, 32, 1).Add("big", newSolidImage(31, 4, color.NRGBA{})); err == nil {
+		t.Fatal("entry wider than atlas was added")
+	}
+	if atlas.GetRevision() != iRevision || len(atlas.GetEntries()) != 4 {
+		t.Fatal("failed Add changed atlas")
+	}
+}
+
+func TestAtlasLayoutRoundTrip(t *testing.T) {
+	atlas := NewAtlas(64, 32, 1)
+	for i, size := range []image.Point{{10, 6}, {4, 12}, {20, 3}} {
+		if _, err := atlas.Add(string(rune('a'+i)), newSolidImage(size.X, size.Y, color.NRGBA{uint8(80 * i), 40, 200, 255})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	layout := atlas.GetLayout()
+	var buf bytes.Buffer
+	if err := layout.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	readLayout, err := ReadAtlasLayout(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := NewAtlasFromLayout(readLayout, atlas.GetImage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	vEntries, vRestored := atlas.GetEntries(), restored.GetEntries()
+	if len(vRestored) != len(vEntries) || restored.iPadding != 1 || !bytes.Equal(restored.image.Pix, atlas.image.Pix) {
+		t.Fatalf("restored %d entries with padding %d", len(vRestored), restored.iPadding)
+	}
+	for i := range vEntries {
+		if entry, bOK := restored.Get(vEntries[i].Name); !bOK || entry != vEntries[i] || vRestored[i] != vEntries[i] {
+			t.Fatalf("entry %v was restored as %v", vEntries[i], entry)
+		}
+	}
+	// Restored skyline keeps new entries off the packed ones
+	for i := 0; i < 6; i++ {
+		if _, err := restored.Add(string(rune('x'+i)), newSolidImage(7, 5, color.NRGBA{0, uint8(40 * i), 0, 255})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkAtlas(t, restored)
+	// Restored entries belong to revision 0, only the new ones changed since
+	var changed image.Rectangle
+	for _, entry := range restored.GetEntries()[len(vEntries):] {
+		changed = changed.Union(getPaddedRect(entry, 1))
+	}
+	if rect := restored.GetChangedRect(0); !rect.Eq(changed) {
+		t.Fatalf("changed rect of restored atlas is %v, want %v", rect, changed)
+	}
+
+	vBadLayouts := []AtlasLayout{
+		{Width: 0, Height: 32},
+		{Width: 32, Height: 32},
+		{Width: 64, Height: 32, Padding: 1, Entries: []AtlasEntry{{Name: "a", X: 0, Y: 1, Width: 4, Height: 4}}},
+		{Width: 64, Height: 32, Padding: 1, Entries: []AtlasEntry{{Name: "a", X: 1, Y: 1, Width: 4, Height: 4}, {Name: "a", X: 8, Y: 1, Width: 4, Height: 4}}},
+	}
+	for _, badLayout := range vBadLayouts {
+		if _, err := NewAtlasFromLayout(badLayout, atlas.GetImage()); err == nil {
+			t.Errorf("layout %v was restored", badLayout)
+		}
+	}
+}
+
+// Partial mips must be the same as the part of whole regenerated levels
+func TestAtlasMipRegions(t *testing.T) {
+	for _, filter := range []MipFilter{MIP_FILTER_BOX, MIP_FILTER_KAISER} {
+		// Size of 510 isn't multiple of the smallest safe level, it gets whole levels
+		for _, iSize := range []int{512, 510} {
+			atlas := NewAtlas(iSize, iSize, 4)
+			for i := 0; i < 12; i++ {
+				img := newVerticalGradientImage(9+i*3, 7+i*2)
+				img.Pix[4*i] = 255
+				if _, err := atlas.Add(string(rune('a'+i)), img); err != nil {
+					t.Fatal(err)
+				}
+			}
+			iRevision := atlas.GetRevision()
+			if _, err := atlas.Add("new", newSolidImage(11, 13, color.NRGBA{250, 10, 10, 255})); err != nil {
+				t.Fatal(err)
+			}
+			rect := atlas.GetChangedRect(iRevision)
+			if entry, _ := atlas.Get("new"); !rect.Eq(getPaddedRect(entry, 4)) {
+				t.Fatalf("changed rect is %v, entry %v", rect, entry)
+			}
+			if !atlas.GetChangedRect(atlas.GetRevision()).Empty() || !atlas.GetChangedRect(-1).Eq(atlas.image.Rect) {
+				t.Fatal("changed rects of current and unknown revision are wrong")
+			}
+
+			vLevels := atlas.GenerateMipmaps(filter, false)
+			vRegions := atlas.GenerateMipRegions(rect, filter, false)
+			if len(vRegions) != len(vLevels) {
+				t.Fatalf("got %d regions for %d levels", len(vRegions), len(vLevels))
+			}
+			if bWhole := vRegions[0].Rect.Eq(atlas.image.Rect); bWhole != (iSize%8 != 0) {
+				t.Fatalf("filter %d size %d: top level region is %v", filter, iSize, vRegions[0].Rect)
+			}
+			for _, region := range vRegions {
+				iLevelWidth := GetMipSize(iSize, region.Level)
+				vChanged := image.Rect(rect.Min.X>>region.Level, rect.Min.Y>>region.Level, (rect.Max.X+(1<<region.Level)-1)>>region.Level,
+					(rect.Max.Y+(1<<region.Level)-1)>>region.Level)
+				if !vChanged.In(region.Rect) || !region.Rect.In(image.Rect(0, 0, iLevelWidth, GetMipSize(iSize, region.Level))) {
+					t.Fatalf("filter %d size %d: level %d region %v doesn't cover change %v", filter, iSize, region.Level, region.Rect, vChanged)
+				}
+				for y := region.Rect.Min.Y; y < region.Rect.Max.Y; y++ {
+					iRow := (y - region.Rect.Min.Y) * region.Rect.Dx() * 4
+					iLevelRow := (y*iLevelWidth + region.Rect.Min.X) * 4
+					if !bytes.Equal(region.Pix[iRow:iRow+region.Rect.Dx()*4], vLevels[region.Level][iLevelRow:iLevelRow+region.Rect.Dx()*4]) {
+						t.Fatalf("filter %d size %d: level %d row %d differs from whole level", filter, iSize, region.Level, y)
+					}
+				}
+			}
+		}
+	}
+}
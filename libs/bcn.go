@@ -0,0 +1,764 @@
+package libs
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Block decoders for BC1-BC7 (DXT1-5, ATI1/ATI2, BPTC). Every decoder takes one
+// compressed 4x4 block and writes its 16 texels as RGBA, row by row.
+
+type ddsBlock [16][4]DDSByte
+
+type ddsBlockDecoder func(block []byte, px *ddsBlock)
+
+// Interpolation weights of BC6H/BC7 for 2, 3 and 4 bit indices
+var bcWeights2 = []uint32{0, 21, 43, 64}
+var bcWeights3 = []uint32{0, 9, 18, 27, 37, 46, 55, 64}
+var bcWeights4 = []uint32{0, 4, 9, 13, 17, 21, 26, 30, 34, 38, 43, 47, 51, 55, 60, 64}
+
+func bcGetWeights(iBits uint) []uint32 {
+	switch iBits {
+	case 2:
+		return bcWeights2
+	case 3:
+		return bcWeights3
+	}
+	return bcWeights4
+}
+
+// Two subset partitions, bit i tells the subset of texel i; BC6H uses first 32 of them
+var bcPartitions2 = [64]uint16{
+	0xCCCC, 0x8888, 0xEEEE, 0xECC8, 0xC880, 0xFEEC, 0xFEC8, 0xEC80, 0xC800, 0xFFEC, 0xFE80, 0xE800, 0xFFE8, 0xFF00, 0xFFF0, 0xF000,
+	0xF710, 0x008E, 0x7100, 0x08CE, 0x008C, 0x7310, 0x3100, 0x8CCE, 0x088C, 0x3110, 0x6666, 0x366C, 0x17E8, 0x0FF0, 0x718E, 0x399C,
+	0xAAAA, 0xF0F0, 0x5A5A, 0x33CC, 0x3C3C, 0x55AA, 0x9696, 0xA55A, 0x73CE, 0x13C8, 0x324C, 0x3BDC, 0x6996, 0xC33C, 0x9966, 0x0660,
+	0x0272, 0x04E4, 0x4E40, 0x2720, 0xC936, 0x936C, 0x39C6, 0x639C, 0x9336, 0x9CC6, 0x817E, 0xE718, 0xCCF0, 0x0FCC, 0x7744, 0xEE22,
+}
+
+// Three subset partitions, two bits per texel
+var bcPartitions3 = [64]uint32{
+	0xAA685050, 0x6A5A5040, 0x5A5A4200, 0x5450A0A8, 0xA5A50000, 0xA0A05050, 0x5555A0A0, 0x5A5A5050,
+	0xAA550000, 0xAA555500, 0xAAAA5500, 0x90909090, 0x94949494, 0xA4A4A4A4, 0xA9A59450, 0x2A0A4250,
+	0xA5945040, 0x0A425054, 0xA5A5A500, 0x55A0A0A0, 0xA8A85454, 0x6A6A4040, 0xA4A45000, 0x1A1A0500,
+	0x0050A4A4, 0xAAA59090, 0x14696914, 0x69691400, 0xA08585A0, 0xAA821414, 0x50A4A450, 0x6A5A0200,
+	0xA9A58000, 0x5090A0A8, 0xA8A09050, 0x24242424, 0x00AA5500, 0x24924924, 0x24499224, 0x50A50A50,
+	0x500AA550, 0xAAAA4444, 0x66660000, 0xA5A0A5A0, 0x50A050A0, 0x69286928, 0x44AAAA44, 0x66666600,
+	0xAA444444, 0x54A854A8, 0x95809580, 0x96969600, 0xA85454A8, 0x80959580, 0xAA141414, 0x96960000,
+	0xAAAA1414, 0xA05050A0, 0xA0A5A5A0, 0x96000000, 0x40804080, 0xA9A8A9A8, 0xAAAAAA44, 0x2A4A5254,
+}
+
+// Anchor texels, their indices are stored with one bit less. Texel 0 is always the anchor of subset 0
+var bcAnchors2 = [64]uint8{
+	15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15,
+	15, 2, 8, 2, 2, 8, 8, 15, 2, 8, 2, 2, 8, 8, 2, 2,
+	15, 15, 6, 8, 2, 8, 15, 15, 2, 8, 2, 2, 2, 15, 15, 6,
+	6, 2, 6, 8, 15, 15, 2, 2, 15, 15, 15, 15, 15, 2, 2, 15,
+}
+
+var bcAnchors3Second = [64]uint8{
+	3, 3, 15, 15, 8, 3, 15, 15, 8, 8, 6, 6, 6, 5, 3, 3,
+	3, 3, 8, 15, 3, 3, 6, 10, 5, 8, 8, 6, 8, 5, 15, 15,
+	8, 15, 3, 5, 6, 10, 8, 15, 15, 3, 15, 5, 15, 15, 15, 15,
+	3, 15, 5, 5, 5, 8, 5, 10, 5, 10, 8, 13, 15, 12, 3, 3,
+}
+
+var bcAnchors3Third = [64]uint8{
+	15, 8, 8, 3, 15, 15, 3, 8, 15, 15, 15, 15, 15, 15, 15, 8,
+	15, 8, 15, 3, 15, 8, 15, 8, 3, 15, 6, 10, 15, 15, 10, 8,
+	15, 3, 15, 10, 10, 8, 9, 10, 6, 15, 8, 15, 3, 6, 6, 8,
+	15, 3, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 3, 15, 15, 8,
+}
+
+func bcGetSubset(iSubsets, iPartition uint32, iTexel int) uint32 {
+	switch iSubsets {
+	case 2:
+		return uint32(bcPartitions2[iPartition]>>iTexel) & 1
+	case 3:
+		return (bcPartitions3[iPartition] >> (2 * iTexel)) & 3
+	}
+	return 0
+}
+
+func bcIsAnchor(iSubsets, iPartition uint32, iTexel int) bool {
+	switch iSubsets {
+	case 2:
+		return iTexel == 0 || iTexel == int(bcAnchors2[iPartition])
+	case 3:
+		return iTexel == 0 || iTexel == int(bcAnchors3Second[iPartition]) || iTexel == int(bcAnchors3Third[iPartition])
+	}
+	return iTexel == 0
+}
+
+// Reads little endian bit fields from a 128 bit block, starting with the lowest bit of first byte
+type bcBitReader struct {
+	block []byte
+	iPos  uint
+}
+
+func (this *bcBitReader) Read(iBits uint) uint32 {
+	var iResult uint32
+	for i := uint(0); i < iBits; i++ {
+		iBit := (this.block[this.iPos>>3] >> (this.iPos & 7)) & 1
+		iResult |= uint32(iBit) << i
+		this.iPos++
+	}
+	return iResult
+}
+
+func bcInterpolate(iFirst, iSecond, iWeight uint32) uint32 {
+	return ((64-iWeight)*iFirst + iWeight*iSecond + 32) >> 6
+}
+
+func bcUnpack565(iColor uint16) (int, int, int) {
+	r := int(iColor>>11) & 31
+	g := int(iColor>>5) & 63
+	b := int(iColor) & 31
+	return r<<3 | r>>2, g<<2 | g>>4, b<<3 | b>>2
+}
+
+/*-----------------------------------------------
+
+  Name:	ddsDecodeColorBlock
+
+  Params:	block - 8 bytes of BC1 color data
+  		bOpaque - BC2/BC3 always use four colors,
+  		BC1 switches to three colors and
+  		transparent black if color0 <= color1
+
+  Result:	Writes RGB (and BC1 alpha) of all texels.
+
+  /*---------------------------------------------*/
+
+func ddsDecodeColorBlock(block []byte, bOpaque bool, px *ddsBlock) {
+	iColor0 := binary.LittleEndian.Uint16(block[0:])
+	iColor1 := binary.LittleEndian.Uint16(block[2:])
+	iCodes := binary.LittleEndian.Uint32(block[4:])
+
+	var vPalette [4][4]int
+	r0, g0, b0 := bcUnpack565(iColor0)
+	r1, g1, b1 := bcUnpack565(iColor1)
+	vPalette[0] = [4]int{r0, g0, b0, 255}
+	vPalette[1] = [4]int{r1, g1, b1, 255}
+	if bOpaque || iColor0 > iColor1 {
+		vPalette[2] = [4]int{(2*r0 + r1) / 3, (2*g0 + g1) / 3, (2*b0 + b1) / 3, 255}
+		vPalette[3] = [4]int{(r0 + 2*r1) / 3, (g0 + 2*g1) / 3, (b0 + 2*b1) / 3, 255}
+	} else {
+		vPalette[2] = [4]int{(r0 + r1) / 2, (g0 + g1) / 2, (b0 + b1) / 2, 255}
+		vPalette[3] = [4]int{0, 0, 0, 0}
+	}
+
+	for i := 0; i < 16; i++ {
+		vColor := vPalette[(iCodes>>(2*i))&3]
+		px[i][0] = DDSByte(vColor[0])
+		px[i][1] = DDSByte(vColor[1])
+		px[i][2] = DDSByte(vColor[2])
+		if !bOpaque {
+			px[i][3] = DDSByte(vColor[3])
+		}
+	}
+}
+
+/*-----------------------------------------------
+
+  Name:	ddsDecodeChannelBlock
+
+  Params:	block - 8 bytes of BC3 alpha / BC4 data
+  		bSigned - endpoints are signed (BC4_SNORM)
+
+  Result:	Returns 16 channel values, signed ones
+  		are in range -127..127.
+
+  /*---------------------------------------------*/
+
+func ddsDecodeChannelBlock(block []byte, bSigned bool) [16]int {
+	var v0, v1 int
+	var iMin, iMax int = 0, 255
+	if bSigned {
+		v0, v1 = int(int8(block[0])), int(int8(block[1]))
+		iMin, iMax = -127, 127
+		// -128 is treated as -127
+		v0, v1 = MAX(v0, -127), MAX(v1, -127)
+	} else {
+		v0, v1 = int(block[0]), int(block[1])
+	}
+
+	var vPalette [8]int
+	vPalette[0], vPalette[1] = v0, v1
+	if v0 > v1 {
+		for i := 2; i < 8; i++ {
+			vPalette[i] = ((8-i)*v0 + (i-1)*v1) / 7
+		}
+	} else {
+		for i := 2; i < 6; i++ {
+			vPalette[i] = ((6-i)*v0 + (i-1)*v1) / 5
+		}
+		vPalette[6], vPalette[7] = iMin, iMax
+	}
+
+	var iCodes uint64
+	for i := 0; i < 6; i++ {
+		iCodes |= uint64(block[2+i]) << (8 * i)
+	}
+	var vResult [16]int
+	for i := 0; i < 16; i++ {
+		vResult[i] = vPalette[(iCodes>>(3*i))&7]
+	}
+	return vResult
+}
+
+// ddsSignedToByte maps -127..127 to 0..255, so signed normal maps can be viewed as ordinary textures.
+func ddsSignedToByte(iValue int) DDSByte {
+	return DDSByte(((iValue+127)*255 + 127) / 254)
+}
+
+func ddsDecodeChannel(block []byte, bSigned bool, px *ddsBlock, iChannel int) {
+	vValues := ddsDecodeChannelBlock(block, bSigned)
+	for i := 0; i < 16; i++ {
+		if bSigned {
+			px[i][iChannel] = ddsSignedToByte(vValues[i])
+		} else {
+			px[i][iChannel] = DDSByte(vValues[i])
+		}
+	}
+}
+
+// BC1 (DXT1), 8 bytes per block
+func ddsDecodeBlockBC1(block []byte, px *ddsBlock) {
+	ddsDecodeColorBlock(block, false, px)
+}
+
+// BC2 (DXT2, DXT3), explicit 4 bit alpha followed by color
+func ddsDecodeBlockBC2(block []byte, px *ddsBlock) {
+	ddsDecodeColorBlock(block[8:], true, px)
+	iAlpha := binary.LittleEndian.Uint64(block)
+	for i := 0; i < 16; i++ {
+		px[i][3] = DDSByte((iAlpha>>(4*i))&15) * 17
+	}
+}
+
+// BC3 (DXT4, DXT5), interpolated alpha followed by color
+func ddsDecodeBlockBC3(block []byte, px *ddsBlock) {
+	ddsDecodeColorBlock(block[8:], true, px)
+	ddsDecodeChannel(block, false, px, 3)
+}
+
+// BC4 (ATI1) holds single channel, it's expanded to gray so height and mask maps look as expected
+func ddsDecodeBlockBC4(block []byte, px *ddsBlock) {
+	ddsDecodeBlockBC4Channel(block, false, px)
+}
+
+func ddsDecodeBlockBC4S(block []byte, px *ddsBlock) {
+	ddsDecodeBlockBC4Channel(block, true, px)
+}
+
+func ddsDecodeBlockBC4Channel(block []byte, bSigned bool, px *ddsBlock) {
+	ddsDecodeChannel(block, bSigned, px, 0)
+	for i := 0; i < 16; i++ {
+		px[i][1], px[i][2], px[i][3] = px[i][0], px[i][0], 255
+	}
+}
+
+// BC5 (ATI2) holds red and green, usually X and Y of a normal map
+func ddsDecodeBlockBC5(block []byte, px *ddsBlock) {
+	ddsDecodeBlockBC5Channels(block, false, false, px)
+}
+
+func ddsDecodeBlockBC5S(block []byte, px *ddsBlock) {
+	ddsDecodeBlockBC5Channels(block, true, false, px)
+}
+
+// A2XY is BC5 with channels stored in swapped order
+func ddsDecodeBlockA2XY(block []byte, px *ddsBlock) {
+	ddsDecodeBlockBC5Channels(block, false, true, px)
+}
+
+func ddsDecodeBlockBC5Channels(block []byte, bSigned, bSwapped bool, px *ddsBlock) {
+	var iFirst, iSecond int = 0, 1
+	if bSwapped {
+		iFirst, iSecond = 1, 0
+	}
+	ddsDecodeChannel(block[:8], bSigned, px, iFirst)
+	ddsDecodeChannel(block[8:], bSigned, px, iSecond)
+	for i := 0; i < 16; i++ {
+		px[i][2], px[i][3] = 0, 255
+	}
+}
+
+type bc7Mode struct {
+	iSubsets        uint32
+	iPartitionBits  uint
+	iRotationBits   uint
+	iSelectionBits  uint // Index selection, mode 4 can swap which index set drives color and alpha
+	iColorBits      uint
+	iAlphaBits      uint
+	iEndpointPBits  uint // One P-bit per endpoint
+	iSharedPBits    uint // One P-bit per subset
+	iIndexBits      uint
+	iSecondaryIndex uint
+}
+
+var bc7Modes = [8]bc7Mode{
+	{3, 4, 0, 0, 4, 0, 1, 0, 3, 0},
+	{2, 6, 0, 0, 6, 0, 0, 1, 3, 0},
+	{3, 6, 0, 0, 5, 0, 0, 0, 2, 0},
+	{2, 6, 0, 0, 7, 0, 1, 0, 2, 0},
+	{1, 0, 2, 1, 5, 6, 0, 0, 2, 3},
+	{1, 0, 2, 0, 7, 8, 0, 0, 2, 2},
+	{1, 0, 0, 0, 7, 7, 1, 0, 4, 0},
+	{2, 6, 0, 0, 5, 5, 1, 0, 2, 0},
+}
+
+// bcExpand replicates high bits of iValue into the low ones, iBits is 5 to 8.
+func bcExpand(iValue uint32, iBits uint) uint32 {
+	return (iValue << (8 - iBits)) | (iValue >> (2*iBits - 8))
+}
+
+/*-----------------------------------------------
+
+  Name:	ddsDecodeBlockBC7
+
+  Params:	block - 16 bytes of BPTC data
+
+  Result:	Decodes block in any of 8 BC7 modes,
+  		reserved mode decodes to transparent
+  		black as the spec requires.
+
+  /*---------------------------------------------*/
+
+func ddsDecodeBlockBC7(block []byte, px *ddsBlock) {
+	var iMode uint
+	for iMode < 8 && block[0]&(1<<iMode) == 0 {
+		iMode++
+	}
+	if iMode == 8 {
+		*px = ddsBlock{}
+		return
+	}
+	mode := bc7Modes[iMode]
+	brBits := bcBitReader{block: block, iPos: iMode + 1}
+
+	iPartition := brBits.Read(mode.iPartitionBits)
+	iRotation := brBits.Read(mode.iRotationBits)
+	iSelection := brBits.Read(mode.iSelectionBits)
+
+	// Endpoints are stored channel by channel: R of all endpoints, then G...
+	var iEndpoints int = int(mode.iSubsets) * 2
+	var vEndpoints [6][4]uint32
+	for c := 0; c < 3; c++ {
+		for e := 0; e < iEndpoints; e++ {
+			vEndpoints[e][c] = brBits.Read(mode.iColorBits)
+		}
+	}
+	for e := 0; e < iEndpoints && mode.iAlphaBits > 0; e++ {
+		vEndpoints[e][3] = brBits.Read(mode.iAlphaBits)
+	}
+
+	iColorBits, iAlphaBits := mode.iColorBits, mode.iAlphaBits
+	if mode.iEndpointPBits > 0 || mode.iSharedPBits > 0 {
+		var iPBit uint32
+		for e := 0; e < iEndpoints; e++ {
+			// Shared P-bit is read once per subset and used by both its endpoints
+			if mode.iEndpointPBits > 0 || e%2 == 0 {
+				iPBit = brBits.Read(1)
+			}
+			for c := 0; c < 4; c++ {
+				vEndpoints[e][c] = vEndpoints[e][c]<<1 | iPBit
+			}
+		}
+		iColorBits++
+		if iAlphaBits > 0 {
+			iAlphaBits++
+		}
+	}
+	for e := 0; e < iEndpoints; e++ {
+		for c := 0; c < 3; c++ {
+			vEndpoints[e][c] = bcExpand(vEndpoints[e][c], iColorBits)
+		}
+		if iAlphaBits > 0 {
+			vEndpoints[e][3] = bcExpand(vEndpoints[e][3], iAlphaBits)
+		} else {
+			vEndpoints[e][3] = 255
+		}
+	}
+
+	var vIndices, vSecondary [16]uint32
+	for i := 0; i < 16; i++ {
+		iBits := mode.iIndexBits
+		if bcIsAnchor(mode.iSubsets, iPartition, i) {
+			iBits--
+		}
+		vIndices[i] = brBits.Read(iBits)
+	}
+	for i := 0; i < 16 && mode.iSecondaryIndex > 0; i++ {
+		iBits := mode.iSecondaryIndex
+		if i == 0 {
+			iBits--
+		}
+		vSecondary[i] = brBits.Read(iBits)
+	}
+
+	vColorWeights, vAlphaWeights := bcGetWeights(mode.iIndexBits), bcGetWeights(mode.iIndexBits)
+	if mode.iSecondaryIndex > 0 {
+		vAlphaWeights = bcGetWeights(mode.iSecondaryIndex)
+		if iSelection == 1 {
+			vColorWeights, vAlphaWeights = vAlphaWeights, vColorWeights
+		}
+	}
+
+	for i := 0; i < 16; i++ {
+		iSubset := bcGetSubset(mode.iSubsets, iPartition, i)
+		vFirst, vSecond := vEndpoints[2*iSubset], vEndpoints[2*iSubset+1]
+		iColorIndex, iAlphaIndex := vIndices[i], vIndices[i]
+		if mode.iSecondaryIndex > 0 {
+			iAlphaIndex = vSecondary[i]
+			if iSelection == 1 {
+				iColorIndex, iAlphaIndex = iAlphaIndex, iColorIndex
+			}
+		}
+		var vTexel [4]uint32
+		for c := 0; c < 3; c++ {
+			vTexel[c] = bcInterpolate(vFirst[c], vSecond[c], vColorWeights[iColorIndex])
+		}
+		vTexel[3] = bcInterpolate(vFirst[3], vSecond[3], vAlphaWeights[iAlphaIndex])
+		if iRotation > 0 {
+			vTexel[3], vTexel[iRotation-1] = vTexel[iRotation-1], vTexel[3]
+		}
+		for c := 0; c < 4; c++ {
+			px[i][c] = DDSByte(vTexel[c])
+		}
+	}
+}
+
+type bc6hMode struct {
+	iValue        uint32 // Mode bits as read from the block, 2 or 5 of them
+	iModeBits     uint
+	bTransformed  bool // Endpoints other than the first are deltas from it
+	iEndpointBits uint
+	vDeltaBits    [3]uint
+	iRegions      uint32
+	sLayout       string
+	vFields       []bc6hField
+}
+
+// One bit of the header: destination endpoint (w, x, y, z or partition), channel and bit position
+type bc6hField struct {
+	iEndpoint int // 0-3 for w, x, y, z; 4 for partition index
+	iChannel  int
+	iBit      uint
+}
+
+// Header layouts follow the BC6H specification, e.g. "gy4" is bit 4 of green channel of endpoint y
+// and "rw15-10" reads bits 15 down to 10. Partition bits are "d".
+var bc6hModes = []bc6hMode{
+	{iValue: 0, iModeBits: 2, bTransformed: true, iEndpointBits: 10, vDeltaBits: [3]uint{5, 5, 5}, iRegions: 2,
+		sLayout: "gy4 by4 bz4 rw0-9 gw0-9 bw0-9 rx0-4 gz4 gy0-3 gx0-4 bz0 gz0-3 bx0-4 bz1 by0-3 ry0-4 bz2 rz0-4 bz3 d0-4"},
+	{iValue: 1, iModeBits: 2, bTransformed: true, iEndpointBits: 7, vDeltaBits: [3]uint{6, 6, 6}, iRegions: 2,
+		sLayout: "gy5 gz4 gz5 rw0-6 bz0 bz1 by4 gw0-6 by5 bz2 gy4 bw0-6 bz3 bz5 bz4 rx0-5 gy0-3 gx0-5 gz0-3 bx0-5 by0-3 ry0-5 rz0-5 d0-4"},
+	{iValue: 2, iModeBits: 5, bTransformed: true, iEndpointBits: 11, vDeltaBits: [3]uint{5, 4, 4}, iRegions: 2,
+		sLayout: "rw0-9 gw0-9 bw0-9 rx0-4 rw10 gy0-3 gx0-3 gw10 bz0 gz0-3 bx0-3 bw10 bz1 by0-3 ry0-4 bz2 rz0-4 bz3 d0-4"},
+	{iValue: 6, iModeBits: 5, bTransformed: true, iEndpointBits: 11, vDeltaBits: [3]uint{4, 5, 4}, iRegions: 2,
+		sLayout: "rw0-9 gw0-9 bw0-9 rx0-3 rw10 gz4 gy0-3 gx0-4 gw10 gz0-3 bx0-3 bw10 bz1 by0-3 ry0-3 bz0 bz2 rz0-3 gy4 bz3 d0-4"},
+	{iValue: 10, iModeBits: 5, bTransformed: true, iEndpointBits: 11, vDeltaBits: [3]uint{4, 4, 5}, iRegions: 2,
+		sLayout: "rw0-9 gw0-9 bw0-9 rx0-3 rw10 by4 gy0-3 gx0-3 gw10 bz0 gz0-3 bx0-4 bw10 by0-3 ry0-3 bz1 bz2 rz0-3 bz4 bz3 d0-4"},
+	{iValue: 14, iModeBits: 5, bTransformed: true, iEndpointBits: 9, vDeltaBits: [3]uint{5, 5, 5}, iRegions: 2,
+		sLayout: "rw0-8 by4 gw0-8 gy4 bw0-8 bz4 rx0-4 gz4 gy0-3 gx0-4 bz0 gz0-3 bx0-4 bz1 by0-3 ry0-4 bz2 rz0-4 bz3 d0-4"},
+	{iValue: 18, iModeBits: 5, bTransformed: true, iEndpointBits: 8, vDeltaBits: [3]uint{6, 5, 5}, iRegions: 2,
+		sLayout: "rw0-7 gz4 by4 gw0-7 bz2 gy4 bw0-7 bz3 bz4 rx0-5 gy0-3 gx0-4 bz0 gz0-3 bx0-4 bz1 by0-3 ry0-5 rz0-5 d0-4"},
+	{iValue: 22, iModeBits: 5, bTransformed: true, iEndpointBits: 8, vDeltaBits: [3]uint{5, 6, 5}, iRegions: 2,
+		sLayout: "rw0-7 bz0 by4 gw0-7 gy5 gy4 bw0-7 gz5 bz4 rx0-4 gz4 gy0-3 gx0-5 gz0-3 bx0-4 bz1 by0-3 ry0-4 bz2 rz0-4 bz3 d0-4"},
+	{iValue: 26, iModeBits: 5, bTransformed: true, iEndpointBits: 8, vDeltaBits: [3]uint{5, 5, 6}, iRegions: 2,
+		sLayout: "rw0-7 bz1 by4 gw0-7 by5 gy4 bw0-7 bz5 bz4 rx0-4 gz4 gy0-3 gx0-4 bz0 gz0-3 bx0-5 by0-3 ry0-4 bz2 rz0-4 bz3 d0-4"},
+	{iValue: 30, iModeBits: 5, bTransformed: false, iEndpointBits: 6, vDeltaBits: [3]uint{6, 6, 6}, iRegions: 2,
+		sLayout: "rw0-5 gz4 bz0 bz1 by4 gw0-5 gy5 by5 bz2 gy4 bw0-5 gz5 bz3 bz5 bz4 rx0-5 gy0-3 gx0-5 gz0-3 bx0-5 by0-3 ry0-5 rz0-5 d0-4"},
+	{iValue: 3, iModeBits: 5, bTransformed: false, iEndpointBits: 10, vDeltaBits: [3]uint{10, 10, 10}, iRegions: 1,
+		sLayout: "rw0-9 gw0-9 bw0-9 rx0-9 gx0-9 bx0-9"},
+	{iValue: 7, iModeBits: 5, bTransformed: true, iEndpointBits: 11, vDeltaBits: [3]uint{9, 9, 9}, iRegions: 1,
+		sLayout: "rw0-9 gw0-9 bw0-9 rx0-8 rw10 gx0-8 gw10 bx0-8 bw10"},
+	{iValue: 11, iModeBits: 5, bTransformed: true, iEndpointBits: 12, vDeltaBits: [3]uint{8, 8, 8}, iRegions: 1,
+		sLayout: "rw0-9 gw0-9 bw0-9 rx0-7 rw11-10 gx0-7 gw11-10 bx0-7 bw11-10"},
+	{iValue: 15, iModeBits: 5, bTransformed: true, iEndpointBits: 16, vDeltaBits: [3]uint{4, 4, 4}, iRegions: 1,
+		sLayout: "rw0-9 gw0-9 bw0-9 rx0-3 rw15-10 gx0-3 gw15-10 bx0-3 bw15-10"},
+}
+
+func init() {
+	for i := range bc6hModes {
+		bc6hModes[i].vFields = bc6hParseLayout(bc6hModes[i].sLayout)
+	}
+}
+
+func bc6hParseLayout(sLayout string) []bc6hField {
+	var vResult []bc6hField
+	for _, sToken := range strings.Fields(sLayout) {
+		var field bc6hField
+		sRange := sToken[1:]
+		if sToken[0] == 'd' {
+			field.iEndpoint = 4
+		} else {
+			field.iChannel = strings.IndexByte("rgb", sToken[0])
+			field.iEndpoint = strings.IndexByte("wxyz", sToken[1])
+			sRange = sToken[2:]
+		}
+		vBounds := strings.Split(sRange, "-")
+		iFrom, _ := strconv.Atoi(vBounds[0])
+		iTo := iFrom
+		if len(vBounds) > 1 {
+			iTo, _ = strconv.Atoi(vBounds[1])
+		}
+		for iBit := iFrom; ; {
+			field.iBit = uint(iBit)
+			vResult = append(vResult, field)
+			if iBit == iTo {
+				break
+			}
+			if iTo > iFrom {
+				iBit++
+			} else {
+				iBit--
+			}
+		}
+	}
+	return vResult
+}
+
+func bcSignExtend(iValue int32, iBits uint) int32 {
+	var iShift uint = 32 - iBits
+	return (iValue << iShift) >> iShift
+}
+
+func bc6hUnquantize(iValue int32, iBits uint, bSigned bool) int32 {
+	if !bSigned {
+		switch {
+		case iBits >= 15 || iValue == 0:
+			return iValue
+		case iValue == (1<<iBits)-1:
+			return 0xFFFF
+		}
+		return ((iValue << 16) + 0x8000) >> iBits
+	}
+	if iBits >= 16 {
+		return iValue
+	}
+	var bNegative bool = iValue < 0
+	if bNegative {
+		iValue = -iValue
+	}
+	var iResult int32
+	switch {
+	case iValue == 0:
+		iResult = 0
+	case iValue >= (1<<(iBits-1))-1:
+		iResult = 0x7FFF
+	default:
+		iResult = ((iValue << 15) + 0x4000) >> (iBits - 1)
+	}
+	if bNegative {
+		return -iResult
+	}
+	return iResult
+}
+
+// bc6hFinishUnquantize scales interpolated value to half float bits.
+func bc6hFinishUnquantize(iValue int32, bSigned bool) uint16 {
+	if !bSigned {
+		return uint16((iValue * 31) >> 6)
+	}
+	if iValue < 0 {
+		return uint16((((-iValue) * 31) >> 5)) | 0x8000
+	}
+	return uint16((iValue * 31) >> 5)
+}
+
+func bcHalfToFloat(iHalf uint16) float32 {
+	iSign := uint32(iHalf>>15) << 31
+	iExponent := uint32(iHalf>>10) & 0x1F
+	iMantissa := uint32(iHalf) & 0x3FF
+	switch {
+	case iExponent == 0 && iMantissa == 0:
+		return math.Float32frombits(iSign)
+	case iExponent == 0:
+		// Denormal, value is mantissa * 2^-24
+		fValue := float32(iMantissa) / (1 << 24)
+		if iSign != 0 {
+			return -fValue
+		}
+		return fValue
+	case iExponent == 31:
+		return math.Float32frombits(iSign | 0x7F800000 | iMantissa<<13)
+	}
+	return math.Float32frombits(iSign | (iExponent+112)<<23 | iMantissa<<13)
+}
+
+/*-----------------------------------------------
+
+  Name:	ddsDecodeBlockBC6HFloat
+
+  Params:	block - 16 bytes of BC6H data
+  		bSigned - BC6H_SF16 instead of BC6H_UF16
+
+  Result:	Returns linear RGB of all texels,
+  		reserved modes decode to zero.
+
+  /*---------------------------------------------*/
+
+func ddsDecodeBlockBC6HFloat(block []byte, bSigned bool) [16][3]float32 {
+	var vResult [16][3]float32
+	brBits := bcBitReader{block: block}
+	iValue := brBits.Read(2)
+	if iValue > 1 {
+		iValue |= brBits.Read(3) << 2
+	}
+	var mode *bc6hMode
+	for i := range bc6hModes {
+		if bc6hModes[i].iValue == iValue {
+			mode = &bc6hModes[i]
+			break
+		}
+	}
+	if mode == nil {
+		return vResult
+	}
+
+	var vEndpoints [4][3]int32
+	var iPartition uint32
+	for _, field := range mode.vFields {
+		iBit := brBits.Read(1)
+		if field.iEndpoint == 4 {
+			iPartition |= iBit << field.iBit
+		} else {
+			vEndpoints[field.iEndpoint][field.iChannel] |= int32(iBit << field.iBit)
+		}
+	}
+
+	var iEndpoints int = int(mode.iRegions) * 2
+	for c := 0; c < 3; c++ {
+		if bSigned {
+			vEndpoints[0][c] = bcSignExtend(vEndpoints[0][c], mode.iEndpointBits)
+		}
+		for e := 1; e < iEndpoints; e++ {
+			if mode.bTransformed {
+				iDelta := bcSignExtend(vEndpoints[e][c], mode.vDeltaBits[c])
+				vEndpoints[e][c] = (vEndpoints[0][c] + iDelta) & (1<<mode.iEndpointBits - 1)
+			}
+			if bSigned {
+				vEndpoints[e][c] = bcSignExtend(vEndpoints[e][c], mode.iEndpointBits)
+			}
+		}
+		for e := 0; e < iEndpoints; e++ {
+			vEndpoints[e][c] = bc6hUnquantize(vEndpoints[e][c], mode.iEndpointBits, bSigned)
+		}
+	}
+
+	var iIndexBits uint = 3
+	if mode.iRegions == 1 {
+		iIndexBits = 4
+	}
+	vWeights := bcGetWeights(iIndexBits)
+	for i := 0; i < 16; i++ {
+		iBits := iIndexBits
+		if bcIsAnchor(mode.iRegions, iPartition, i) {
+			iBits--
+		}
+		iWeight := int32(vWeights[brBits.Read(iBits)])
+		iSubset := bcGetSubset(mode.iRegions, iPartition, i)
+		vFirst, vSecond := vEndpoints[2*iSubset], vEndpoints[2*iSubset+1]
+		for c := 0; c < 3; c++ {
+			iInterpolated := ((64-iWeight)*vFirst[c] + iWeight*vSecond[c] + 32) >> 6
+			vResult[i][c] = bcHalfToFloat(bc6hFinishUnquantize(iInterpolated, bSigned))
+		}
+	}
+	return vResult
+}
+
+// BC6H is clamped to 0..1 for 8 bit output, HDR content needs ddsDecodeBlockBC6HFloat.
+func ddsDecodeBlockBC6H(block []byte, px *ddsBlock) {
+	ddsDecodeBlockBC6HClamped(block, false, px)
+}
+
+func ddsDecodeBlockBC6HS(block []byte, px *ddsBlock) {
+	ddsDecodeBlockBC6HClamped(block, true, px)
+}
+
+func ddsDecodeBlockBC6HClamped(block []byte, bSigned bool, px *ddsBlock) {
+	vTexels := ddsDecodeBlockBC6HFloat(block, bSigned)
+	for i := 0; i < 16; i++ {
+		for c := 0; c < 3; c++ {
+			fValue := math.Max(0, math.Min(1, float64(vTexels[i][c])))
+			px[i][c] = DDSByte(fValue*255 + 0.5)
+		}
+		px[i][3] = 255
+	}
+}
+
+func bcGetBlockDecoder(format TexelFormat) ddsBlockDecoder {
+	switch format {
+	case TEXEL_FORMAT_BC1:
+		return ddsDecodeBlockBC1
+	case TEXEL_FORMAT_BC2:
+		return ddsDecodeBlockBC2
+	case TEXEL_FORMAT_BC3:
+		return ddsDecodeBlockBC3
+	case TEXEL_FORMAT_BC4:
+		return ddsDecodeBlockBC4
+	case TEXEL_FORMAT_BC4_SIGNED:
+		return ddsDecodeBlockBC4S
+	case TEXEL_FORMAT_BC5:
+		return ddsDecodeBlockBC5
+	case TEXEL_FORMAT_BC5_SIGNED:
+		return ddsDecodeBlockBC5S
+	case TEXEL_FORMAT_BC6H:
+		return ddsDecodeBlockBC6H
+	case TEXEL_FORMAT_BC6H_SIGNED:
+		return ddsDecodeBlockBC6HS
+	case TEXEL_FORMAT_BC7:
+		return ddsDecodeBlockBC7
+	}
+	return nil
+}
+
+/*-----------------------------------------------
+
+  Name:	bcDecompress
+
+  Params:	data - compressed slices
+  		iWidth, iHeight, iDepth - size in texels
+  		iBlockSize - bytes per 4x4 block
+  		fnDecode - decoder of a single block
+
+  Result:	Returns RGBA8 texels of all slices.
+
+  /*---------------------------------------------*/
+
+func bcDecompress(data []byte, iWidth, iHeight, iDepth, iBlockSize int, fnDecode ddsBlockDecoder) ([]byte, error) {
+	// Partial blocks at right and bottom edge are still stored whole
+	blocksX := (iWidth + 3) / 4
+	blocksY := (iHeight + 3) / 4
+	if len(data) < blocksX*blocksY*iBlockSize*iDepth {
+		return nil, errors.New("compressed texture data is truncated")
+	}
+
+	bPixels := make([]byte, iWidth*iHeight*iDepth*4)
+	var px ddsBlock
+	for z := 0; z < iDepth; z++ {
+		for y := 0; y < blocksY; y++ {
+			for x := 0; x < blocksX; x++ {
+				blockOffset := ((z*blocksY+y)*blocksX + x) * iBlockSize
+				fnDecode(data[blockOffset:blockOffset+iBlockSize], &px)
+
+				for b := 0; b < 16; b++ {
+					pxX, pxY := x*4+b%4, y*4+b/4
+					if pxX >= iWidth || pxY >= iHeight {
+						continue
+					}
+					pxIndex := (z*iHeight*iWidth + pxY*iWidth + pxX) * 4
+					for c := 0; c < 4; c++ {
+						bPixels[pxIndex+c] = byte(px[b][c])
+					}
+				}
+			}
+		}
+	}
+	return bPixels, nil
+}
@@ -0,0 +1,481 @@
+package libs
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Block encoders for BC1 and BC3. They take 16 RGBA texels row by row, like decoders give them.
+
+type bcEndpoints struct {
+	iColor0, iColor1 uint16
+	iIndices         uint32
+	iError           int
+}
+
+// Best 5 and 6 bit endpoint pairs to reproduce single 8 bit value by 2/3 interpolation, filled in init
+var bcSingleColor5, bcSingleColor6 [256][2]uint8
+
+func init() {
+	bcFillSingleColorTable(&bcSingleColor5, 5)
+	bcFillSingleColorTable(&bcSingleColor6, 6)
+}
+
+func bcFillSingleColorTable(vTable *[256][2]uint8, iBits uint) {
+	iMax := 1<<iBits - 1
+	for v := 0; v < 256; v++ {
+		iBestError := math.MaxInt32
+		for e0 := 0; e0 <= iMax; e0++ {
+			for e1 := 0; e1 <= iMax; e1++ {
+				iValue := (2*bcExpandBits(e0, iBits) + bcExpandBits(e1, iBits)) / 3
+				iError := iValue - v
+				if iError < 0 {
+					iError = -iError
+				}
+				if iError < iBestError {
+					iBestError = iError
+					vTable[v] = [2]uint8{uint8(e0), uint8(e1)}
+				}
+			}
+		}
+	}
+}
+
+func bcExpandBits(iValue int, iBits uint) int {
+	if iBits == 5 {
+		return iValue<<3 | iValue>>2
+	}
+	return iValue<<2 | iValue>>4
+}
+
+func bcPack565(vColor [3]float64) uint16 {
+	r := uint16(math.Max(0, math.Min(31, math.Floor(vColor[0]*31/255+0.5))))
+	g := uint16(math.Max(0, math.Min(63, math.Floor(vColor[1]*63/255+0.5))))
+	b := uint16(math.Max(0, math.Min(31, math.Floor(vColor[2]*31/255+0.5))))
+	return r<<11 | g<<5 | b
+}
+
+// bcGetPalette builds colors exactly like ddsDecodeColorBlock does, index 3 of three color mode is transparent.
+func bcGetPalette(iColor0, iColor1 uint16, bFourColors bool) [4][3]int {
+	var vPalette [4][3]int
+	r0, g0, b0 := bcUnpack565(iColor0)
+	r1, g1, b1 := bcUnpack565(iColor1)
+	vPalette[0] = [3]int{r0, g0, b0}
+	vPalette[1] = [3]int{r1, g1, b1}
+	if bFourColors {
+		vPalette[2] = [3]int{(2*r0 + r1) / 3, (2*g0 + g1) / 3, (2*b0 + b1) / 3}
+		vPalette[3] = [3]int{(r0 + 2*r1) / 3, (g0 + 2*g1) / 3, (b0 + 2*b1) / 3}
+	} else {
+		vPalette[2] = [3]int{(r0 + r1) / 2, (g0 + g1) / 2, (b0 + b1) / 2}
+	}
+	return vPalette
+}
+
+/*-----------------------------------------------
+
+  Name:	bcFitIndices
+
+  Params:	px - texels of the block
+  		vTransparent - texels that must use
+  		transparent index (three color mode only)
+
+  Result:	Chooses nearest palette entry for every
+  		texel and returns total squared error.
+
+  /*---------------------------------------------*/
+
+func bcFitIndices(px *ddsBlock, vTransparent *[16]bool, iColor0, iColor1 uint16, bFourColors bool) bcEndpoints {
+	vPalette := bcGetPalette(iColor0, iColor1, bFourColors)
+	iColors := 3
+	if bFourColors {
+		iColors = 4
+	}
+	result := bcEndpoints{iColor0: iColor0, iColor1: iColor1}
+	for i := 0; i < 16; i++ {
+		if vTransparent[i] {
+			result.iIndices |= 3 << (2 * i)
+			continue
+		}
+		iBest, iBestError := 0, math.MaxInt32
+		for p := 0; p < iColors; p++ {
+			dr := vPalette[p][0] - int(px[i][0])
+			dg := vPalette[p][1] - int(px[i][1])
+			db := vPalette[p][2] - int(px[i][2])
+			if iError := dr*dr + dg*dg + db*db; iError < iBestError {
+				iBest, iBestError = p, iError
+			}
+		}
+		result.iIndices |= uint32(iBest) << (2 * i)
+		result.iError += iBestError
+	}
+	return result
+}
+
+// bcGetWeight tells how much of first endpoint palette entry has
+func bcGetWeight(iIndex uint32, bFourColors bool) float64 {
+	if bFourColors {
+		return [4]float64{1, 0, 2.0 / 3, 1.0 / 3}[iIndex]
+	}
+	return [4]float64{1, 0, 0.5, 0}[iIndex]
+}
+
+/*-----------------------------------------------
+
+  Name:	bcRefineEndpoints
+
+  Params:	fit - current endpoints and indices
+
+  Result:	Solves least squares for endpoints that
+  		best reproduce texels with given indices,
+  		false if the system is degenerate.
+
+  /*---------------------------------------------*/
+
+func bcRefineEndpoints(px *ddsBlock, vTransparent *[16]bool, fit bcEndpoints, bFourColors bool) ([3]float64, [3]float64, bool) {
+	var fA, fB, fC float64
+	var vX0, vX1 [3]float64
+	for i := 0; i < 16; i++ {
+		if vTransparent[i] {
+			continue
+		}
+		w := bcGetWeight((fit.iIndices>>(2*i))&3, bFourColors)
+		fA += w * w
+		fB += w * (1 - w)
+		fC += (1 - w) * (1 - w)
+		for c := 0; c < 3; c++ {
+			vX0[c] += w * float64(px[i][c])
+			vX1[c] += (1 - w) * float64(px[i][c])
+		}
+	}
+	fDet := fA*fC - fB*fB
+	if math.Abs(fDet) < 1e-6 {
+		return vX0, vX1, false
+	}
+	var vEndpoint0, vEndpoint1 [3]float64
+	for c := 0; c < 3; c++ {
+		vEndpoint0[c] = (fC*vX0[c] - fB*vX1[c]) / fDet
+		vEndpoint1[c] = (fA*vX1[c] - fB*vX0[c]) / fDet
+	}
+	return vEndpoint0, vEndpoint1, true
+}
+
+// bcGetPrincipalAxis returns line through the texels along which they vary most.
+func bcGetPrincipalAxis(px *ddsBlock, vTransparent *[16]bool) ([3]float64, [3]float64) {
+	var vMean [3]float64
+	var iCount int
+	for i := 0; i < 16; i++ {
+		if vTransparent[i] {
+			continue
+		}
+		for c := 0; c < 3; c++ {
+			vMean[c] += float64(px[i][c])
+		}
+		iCount++
+	}
+	for c := 0; c < 3; c++ {
+		vMean[c] /= float64(iCount)
+	}
+
+	var mCovariance [3][3]float64
+	for i := 0; i < 16; i++ {
+		if vTransparent[i] {
+			continue
+		}
+		for a := 0; a < 3; a++ {
+			for b := 0; b < 3; b++ {
+				mCovariance[a][b] += (float64(px[i][a]) - vMean[a]) * (float64(px[i][b]) - vMean[b])
+			}
+		}
+	}
+
+	// Power iteration converges to eigenvector with the largest eigenvalue. It starts from covariance row of the
+	// channel varying most, fixed start like gray axis would be orthogonal to e.g. red-green gradients and never move.
+	vAxis := [3]float64{1 / math.Sqrt(3), 1 / math.Sqrt(3), 1 / math.Sqrt(3)}
+	iWidest := 0
+	for c := 1; c < 3; c++ {
+		if mCovariance[c][c] > mCovariance[iWidest][iWidest] {
+			iWidest = c
+		}
+	}
+	if fLength := math.Sqrt(mCovariance[iWidest][0]*mCovariance[iWidest][0] + mCovariance[iWidest][1]*mCovariance[iWidest][1] +
+		mCovariance[iWidest][2]*mCovariance[iWidest][2]); fLength > 1e-9 {
+		for a := 0; a < 3; a++ {
+			vAxis[a] = mCovariance[iWidest][a] / fLength
+		}
+	}
+	for iIteration := 0; iIteration < 8; iIteration++ {
+		var vNext [3]float64
+		for a := 0; a < 3; a++ {
+			vNext[a] = mCovariance[a][0]*vAxis[0] + mCovariance[a][1]*vAxis[1] + mCovariance[a][2]*vAxis[2]
+		}
+		fLength := math.Sqrt(vNext[0]*vNext[0] + vNext[1]*vNext[1] + vNext[2]*vNext[2])
+		if fLength < 1e-9 {
+			break
+		}
+		for a := 0; a < 3; a++ {
+			vAxis[a] = vNext[a] / fLength
+		}
+	}
+	return vMean, vAxis
+}
+
+func bcEncodeColorEndpoints(px *ddsBlock, vTransparent *[16]bool, bFourColors bool) bcEndpoints {
+	vMean, vAxis := bcGetPrincipalAxis(px, vTransparent)
+	fMin, fMax := math.MaxFloat64, -math.MaxFloat64
+	for i := 0; i < 16; i++ {
+		if vTransparent[i] {
+			continue
+		}
+		fProjection := (float64(px[i][0])-vMean[0])*vAxis[0] + (float64(px[i][1])-vMean[1])*vAxis[1] + (float64(px[i][2])-vMean[2])*vAxis[2]
+		fMin, fMax = math.Min(fMin, fProjection), math.Max(fMax, fProjection)
+	}
+	var vEndpoint0, vEndpoint1 [3]float64
+	for c := 0; c < 3; c++ {
+		vEndpoint0[c] = vMean[c] + vAxis[c]*fMax
+		vEndpoint1[c] = vMean[c] + vAxis[c]*fMin
+	}
+
+	best := bcFitIndices(px, vTransparent, bcPack565(vEndpoint0), bcPack565(vEndpoint1), bFourColors)
+	for iIteration := 0; iIteration < 3; iIteration++ {
+		vEndpoint0, vEndpoint1, bSolved := bcRefineEndpoints(px, vTransparent, best, bFourColors)
+		if !bSolved {
+			break
+		}
+		fit := bcFitIndices(px, vTransparent, bcPack565(vEndpoint0), bcPack565(vEndpoint1), bFourColors)
+		if fit.iError >= best.iError {
+			break
+		}
+		best = fit
+	}
+	return best
+}
+
+// bcEncodeSingleColor uses tables to hit solid color more exactly than rounding to 565 could.
+func bcEncodeSingleColor(vColor [4]DDSByte) bcEndpoints {
+	r, g, b := bcSingleColor5[vColor[0]], bcSingleColor6[vColor[1]], bcSingleColor5[vColor[2]]
+	iColor0 := uint16(r[0])<<11 | uint16(g[0])<<5 | uint16(b[0])
+	iColor1 := uint16(r[1])<<11 | uint16(g[1])<<5 | uint16(b[1])
+	return bcEndpoints{iColor0: iColor0, iColor1: iColor1, iIndices: 0xAAAAAAAA} // Index 2 everywhere
+}
+
+// bcOrderEndpoints swaps endpoints so their order selects wanted mode, remapping indices.
+func bcOrderEndpoints(fit bcEndpoints, bFourColors bool) bcEndpoints {
+	var bSwap bool
+	if bFourColors {
+		if fit.iColor0 == fit.iColor1 {
+			// Every entry is the same color, but three color mode would make index 3 transparent
+			fit.iIndices = 0
+			return fit
+		}
+		bSwap = fit.iColor0 < fit.iColor1
+	} else {
+		bSwap = fit.iColor0 > fit.iColor1
+	}
+	if !bSwap {
+		return fit
+	}
+	fit.iColor0, fit.iColor1 = fit.iColor1, fit.iColor0
+	var iIndices uint32
+	for i := 0; i < 16; i++ {
+		iIndex := (fit.iIndices >> (2 * i)) & 3
+		switch {
+		case iIndex < 2:
+			iIndex ^= 1
+		case bFourColors:
+			iIndex ^= 1 // 2 and 3 are 2/3 and 1/3 of first endpoint
+		}
+		iIndices |= iIndex << (2 * i)
+	}
+	fit.iIndices = iIndices
+	return fit
+}
+
+/*-----------------------------------------------
+
+  Name:	bcEncodeColorBlock
+
+  Params:	px - texels of the block
+  		bAllowAlpha - BC1 may use three color mode
+  		to store texels with alpha below 128 as
+  		transparent black
+  		block - 8 output bytes
+
+  Result:	Encodes RGB of the block.
+
+  /*---------------------------------------------*/
+
+func bcEncodeColorBlock(px *ddsBlock, bAllowAlpha bool, block []byte) {
+	var vTransparent [16]bool
+	var bHasTransparent, bSolid bool = false, true
+	var iOpaque int
+	for i := 0; i < 16; i++ {
+		vTransparent[i] = bAllowAlpha && px[i][3] < 128
+		if vTransparent[i] {
+			bHasTransparent = true
+			continue
+		}
+		if iOpaque > 0 && (px[i][0] != px[0][0] || px[i][1] != px[0][1] || px[i][2] != px[0][2]) {
+			bSolid = false
+		}
+		iOpaque++
+	}
+
+	var fit bcEndpoints
+	bFourColors := !bHasTransparent
+	switch {
+	case iOpaque == 0:
+		fit = bcEndpoints{iIndices: 0xFFFFFFFF}
+	case bSolid && bFourColors:
+		// Solid block with transparent texels goes through the generic path, it can't use index 2 in three color mode the same way
+		var vColor [4]DDSByte
+		for i := 0; i < 16; i++ {
+			if !vTransparent[i] {
+				vColor = px[i]
+				break
+			}
+		}
+		fit = bcEncodeSingleColor(vColor)
+	default:
+		fit = bcEncodeColorEndpoints(px, &vTransparent, bFourColors)
+	}
+	fit = bcOrderEndpoints(fit, bFourColors)
+
+	binary.LittleEndian.PutUint16(block[0:], fit.iColor0)
+	binary.LittleEndian.PutUint16(block[2:], fit.iColor1)
+	binary.LittleEndian.PutUint32(block[4:], fit.iIndices)
+}
+
+func bcGetAlphaPalette(a0, a1 int) [8]int {
+	var vPalette [8]int
+	vPalette[0], vPalette[1] = a0, a1
+	if a0 > a1 {
+		for i := 2; i < 8; i++ {
+			vPalette[i] = ((8-i)*a0 + (i-1)*a1) / 7
+		}
+	} else {
+		for i := 2; i < 6; i++ {
+			vPalette[i] = ((6-i)*a0 + (i-1)*a1) / 5
+		}
+		vPalette[6], vPalette[7] = 0, 255
+	}
+	return vPalette
+}
+
+func bcFitAlpha(vValues *[16]int, a0, a1 int) (uint64, int) {
+	vPalette := bcGetAlphaPalette(a0, a1)
+	var iIndices uint64
+	var iTotalError int
+	for i := 0; i < 16; i++ {
+		iBest, iBestError := 0, math.MaxInt32
+		for p := 0; p < 8; p++ {
+			iError := vPalette[p] - vValues[i]
+			if iError < 0 {
+				iError = -iError
+			}
+			if iError < iBestError {
+				iBest, iBestError = p, iError
+			}
+		}
+		iIndices |= uint64(iBest) << (3 * i)
+		iTotalError += iBestError * iBestError
+	}
+	return iIndices, iTotalError
+}
+
+/*-----------------------------------------------
+
+  Name:	bcEncodeChannelBlock
+
+  Params:	vValues - 16 values 0..255
+  		block - 8 output bytes
+
+  Result:	Encodes BC3 alpha / BC4 block, trying both
+  		eight value mode and six value mode with
+  		exact 0 and 255.
+
+  /*---------------------------------------------*/
+
+func bcEncodeChannelBlock(vValues *[16]int, block []byte) {
+	iMin, iMax := 255, 0
+	iInnerMin, iInnerMax := 255, 0
+	for _, v := range vValues {
+		iMin, iMax = MIN(iMin, v), MAX(iMax, v)
+		if v != 0 && v != 255 {
+			iInnerMin, iInnerMax = MIN(iInnerMin, v), MAX(iInnerMax, v)
+		}
+	}
+
+	a0, a1 := iMax, iMin
+	iIndices, iError := bcFitAlpha(vValues, a0, a1)
+	if iMin == iMax {
+		iIndices, iError = 0, 0
+	}
+	if iError > 0 && (iMin == 0 || iMax == 255) {
+		if iInnerMin > iInnerMax {
+			iInnerMin, iInnerMax = 0, 0
+		}
+		iSixIndices, iSixError := bcFitAlpha(vValues, iInnerMin, iInnerMax)
+		if iSixError < iError {
+			a0, a1, iIndices = iInnerMin, iInnerMax, iSixIndices
+		}
+	}
+
+	block[0], block[1] = byte(a0), byte(a1)
+	for i := 0; i < 6; i++ {
+		block[2+i] = byte(iIndices >> (8 * i))
+	}
+}
+
+// BC1 keeps texels with alpha below 128 as transparent black
+func bcEncodeBlockBC1(px *ddsBlock, block []byte) {
+	bcEncodeColorBlock(px, true, block)
+}
+
+// BC3 stores alpha block first, then color block without transparency
+func bcEncodeBlockBC3(px *ddsBlock, block []byte) {
+	var vAlpha [16]int
+	for i := 0; i < 16; i++ {
+		vAlpha[i] = int(px[i][3])
+	}
+	bcEncodeChannelBlock(&vAlpha, block[:8])
+	bcEncodeColorBlock(px, false, block[8:16])
+}
+
+/*-----------------------------------------------
+
+  Name:	bcCompress
+
+  Params:	pixels - RGBA8 texels of a 2D image
+  		iWidth, iHeight - its size
+  		format - TEXEL_FORMAT_BC1 or _BC3
+
+  Result:	Returns compressed blocks, partial edge
+  		blocks repeat the last row and column.
+
+  /*---------------------------------------------*/
+
+func bcCompress(pixels []byte, iWidth, iHeight int, format TexelFormat) []byte {
+	fnEncode := bcEncodeBlockBC1
+	if format == TEXEL_FORMAT_BC3 {
+		fnEncode = bcEncodeBlockBC3
+	}
+	iBlockSize := format.GetBlockSize()
+	blocksX, blocksY := (iWidth+3)/4, (iHeight+3)/4
+	result := make([]byte, blocksX*blocksY*iBlockSize)
+
+	var px ddsBlock
+	for y := 0; y < blocksY; y++ {
+		for x := 0; x < blocksX; x++ {
+			for b := 0; b < 16; b++ {
+				pxX, pxY := MIN(x*4+b%4, iWidth-1), MIN(y*4+b/4, iHeight-1)
+				pxIndex := (pxY*iWidth + pxX) * 4
+				for c := 0; c < 4; c++ {
+					px[b][c] = DDSByte(pixels[pxIndex+c])
+				}
+			}
+			blockOffset := (y*blocksX + x) * iBlockSize
+			fnEncode(&px, result[blockOffset:blockOffset+iBlockSize])
+		}
+	}
+	return result
+}
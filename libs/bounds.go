@@ -0,0 +1,301 @@
+package libs
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+)
+
+// AABB is axis-aligned box, empty box has Min above Max
+type AABB struct {
+	Min mgl32.Vec3
+	Max mgl32.Vec3
+}
+
+// EmptyAABB returns box that any point extends to itself.
+func EmptyAABB() AABB {
+	fInf := float32(math.Inf(1))
+	return AABB{Min: mgl32.Vec3{fInf, fInf, fInf}, Max: mgl32.Vec3{-fInf, -fInf, -fInf}}
+}
+
+// AABBFromPoints returns smallest box around points, empty box if there are none.
+func AABBFromPoints(vPoints []mgl32.Vec3) AABB {
+	box := EmptyAABB()
+	for _, vPoint := range vPoints {
+		box = box.Extend(vPoint)
+	}
+	return box
+}
+
+func (this AABB) IsEmpty() bool {
+	return this.Min[0] > this.Max[0] || this.Min[1] > this.Max[1] || this.Min[2] > this.Max[2]
+}
+
+// Extend returns box grown to contain point.
+func (this AABB) Extend(vPoint mgl32.Vec3) AABB {
+	for c := 0; c < 3; c++ {
+		if vPoint[c] < this.Min[c] {
+			this.Min[c] = vPoint[c]
+		}
+		if vPoint[c] > this.Max[c] {
+			this.Max[c] = vPoint[c]
+		}
+	}
+	return this
+}
+
+// Merge returns box around both boxes, empty boxes are ignored.
+func (this AABB) Merge(other AABB) AABB {
+	if other.IsEmpty() {
+		return this
+	}
+	return this.Extend(other.Min).Extend(other.Max)
+}
+
+func (this AABB) GetCenter() mgl32.Vec3 {
+	return this.Min.Add(this.Max).Mul(0.5)
+}
+
+// GetHalfSize returns distance from center to faces along each axis.
+func (this AABB) GetHalfSize() mgl32.Vec3 {
+	return this.Max.Sub(this.Min).Mul(0.5)
+}
+
+func (this AABB) ContainsPoint(vPoint mgl32.Vec3) bool {
+	for c := 0; c < 3; c++ {
+		if vPoint[c] < this.Min[c] || vPoint[c] > this.Max[c] {
+			return false
+		}
+	}
+	return true
+}
+
+/*-----------------------------------------------
+
+  Name:	Transform
+
+  Params:	mMatrix - affine transformation
+
+  Result:	Returns axis-aligned box around the box
+  		transformed by matrix. Each axis of matrix
+  		adds its smaller and bigger end separately.
+
+  /*---------------------------------------------*/
+
+func (this AABB) Transform(mMatrix mgl32.Mat4) AABB {
+	if this.IsEmpty() {
+		return this
+	}
+	vTranslation := mMatrix.Col(3).Vec3()
+	result := AABB{Min: vTranslation, Max: vTranslation}
+	for iCol := 0; iCol < 3; iCol++ {
+		for iRow := 0; iRow < 3; iRow++ {
+			fA := mMatrix.At(iRow, iCol) * this.Min[iCol]
+			fB := mMatrix.At(iRow, iCol) * this.Max[iCol]
+			if fA > fB {
+				fA, fB = fB, fA
+			}
+			result.Min[iRow] += fA
+			result.Max[iRow] += fB
+		}
+	}
+	return result
+}
+
+// IntersectRay returns smallest non-negative parameter of ray point inside box, slab method.
+func (this AABB) IntersectRay(vOrigin, vDirection mgl32.Vec3) (float32, bool) {
+	fNear, fFar := float32(0), float32(math.Inf(1))
+	for c := 0; c < 3; c++ {
+		if vDirection[c] == 0 {
+			if vOrigin[c] < this.Min[c] || vOrigin[c] > this.Max[c] {
+				return 0, false
+			}
+			continue
+		}
+		fT1 := (this.Min[c] - vOrigin[c]) / vDirection[c]
+		fT2 := (this.Max[c] - vOrigin[c]) / vDirection[c]
+		if fT1 > fT2 {
+			fT1, fT2 = fT2, fT1
+		}
+		if fT1 > fNear {
+			fNear = fT1
+		}
+		if fT2 < fFar {
+			fFar = fT2
+		}
+		if fNear > fFar {
+			return 0, false
+		}
+	}
+	return fNear, true
+}
+
+// Sphere is bounding sphere, negative radius means it bounds nothing
+type Sphere struct {
+	Center mgl32.Vec3
+	Radius float32
+}
+
+// SphereFromPoints returns sphere centered in box of points, reaching the farthest one.
+func SphereFromPoints(vPoints []mgl32.Vec3) Sphere {
+	if len(vPoints) == 0 {
+		return Sphere{Radius: -1}
+	}
+	vCenter := AABBFromPoints(vPoints).GetCenter()
+	var fRadiusSq float32
+	for _, vPoint := range vPoints {
+		if fDistSq := vPoint.Sub(vCenter).LenSqr(); fDistSq > fRadiusSq {
+			fRadiusSq = fDistSq
+		}
+	}
+	return Sphere{Center: vCenter, Radius: float32(math.Sqrt(float64(fRadiusSq)))}
+}
+
+func (this Sphere) IsEmpty() bool {
+	return this.Radius < 0
+}
+
+// Transform returns sphere moved by matrix, radius grows by its largest axis scale.
+func (this Sphere) Transform(mMatrix mgl32.Mat4) Sphere {
+	if this.IsEmpty() {
+		return this
+	}
+	var fMaxScale float32
+	for iCol := 0; iCol < 3; iCol++ {
+		if fScale := mMatrix.Col(iCol).Vec3().Len(); fScale > fMaxScale {
+			fMaxScale = fScale
+		}
+	}
+	return Sphere{Center: mMatrix.Mul4x1(this.Center.Vec4(1)).Vec3(), Radius: this.Radius * fMaxScale}
+}
+
+// Plane is set of points where Normal dot point + Distance is zero, positive side is in front
+type Plane struct {
+	Normal   mgl32.Vec3
+	Distance float32
+}
+
+// GetSignedDistance returns distance of point from plane, negative behind it. Normal must be unit.
+func (this Plane) GetSignedDistance(vPoint mgl32.Vec3) float32 {
+	return this.Normal.Dot(vPoint) + this.Distance
+}
+
+// EFrustumTest is result of testing volume against frustum
+type EFrustumTest int
+
+const (
+	FRUSTUM_OUTSIDE EFrustumTest = iota
+	FRUSTUM_INTERSECTS
+	FRUSTUM_INSIDE
+)
+
+const (
+	FRUSTUM_PLANE_LEFT = iota
+	FRUSTUM_PLANE_RIGHT
+	FRUSTUM_PLANE_BOTTOM
+	FRUSTUM_PLANE_TOP
+	FRUSTUM_PLANE_NEAR
+	FRUSTUM_PLANE_FAR
+	NUM_FRUSTUM_PLANES
+)
+
+// Frustum is volume camera sees, planes face inwards
+type Frustum struct {
+	Planes [NUM_FRUSTUM_PLANES]Plane
+}
+
+/*-----------------------------------------------
+
+  Name:	NewFrustum
+
+  Params:	mProjection - projection matrix
+  		mView - view matrix
+
+  Result:	Returns world space frustum of camera.
+  		Planes are rows of clip matrix added to or
+  		subtracted from its fourth row.
+
+  /*---------------------------------------------*/
+
+func NewFrustum(mProjection, mView mgl32.Mat4) Frustum {
+	mClip := mProjection.Mul4(mView)
+	vRow3 := mClip.Row(3)
+	var frustum Frustum
+	for i := 0; i < NUM_FRUSTUM_PLANES; i++ {
+		vRow := mClip.Row(i / 2)
+		var vPlane mgl32.Vec4
+		if i%2 == 0 {
+			vPlane = vRow3.Add(vRow)
+		} else {
+			vPlane = vRow3.Sub(vRow)
+		}
+		fLength := vPlane.Vec3().Len()
+		if fLength > 0 {
+			vPlane = vPlane.Mul(1 / fLength)
+		}
+		frustum.Planes[i] = Plane{Normal: vPlane.Vec3(), Distance: vPlane.W()}
+	}
+	return frustum
+}
+
+func (this *Frustum) ContainsPoint(vPoint mgl32.Vec3) bool {
+	for _, plane := range this.Planes {
+		if plane.GetSignedDistance(vPoint) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestSphere tells whether sphere is outside, crossing or inside frustum. Empty spheres are outside.
+func (this *Frustum) TestSphere(sphere Sphere) EFrustumTest {
+	if sphere.IsEmpty() {
+		return FRUSTUM_OUTSIDE
+	}
+	result := FRUSTUM_INSIDE
+	for _, plane := range this.Planes {
+		fDistance := plane.GetSignedDistance(sphere.Center)
+		if fDistance < -sphere.Radius {
+			return FRUSTUM_OUTSIDE
+		}
+		if fDistance < sphere.Radius {
+			result = FRUSTUM_INTERSECTS
+		}
+	}
+	return result
+}
+
+/*-----------------------------------------------
+
+  Name:	TestAABB
+
+  Params:	box - world space box
+
+  Result:	Tells whether box is outside, crossing or
+  		inside frustum. Corner farthest along each
+  		plane normal decides if box is outside it.
+  		Boxes near frustum corners may be reported
+  		crossing though they're outside.
+
+  /*---------------------------------------------*/
+
+func (this *Frustum) TestAABB(box AABB) EFrustumTest {
+	if box.IsEmpty() {
+		return FRUSTUM_OUTSIDE
+	}
+	result := FRUSTUM_INSIDE
+	for _, plane := range this.Planes {
+		vPositive, vNegative := box.Min, box.Max
+		for c := 0; c < 3; c++ {
+			if plane.Normal[c] >= 0 {
+				vPositive[c], vNegative[c] = box.Max[c], box.Min[c]
+			}
+		}
+		if plane.GetSignedDistance(vPositive) < 0 {
+			return FRUSTUM_OUTSIDE
+		}
+		if plane.GetSignedDistance(vNegative) < 0 {
+			result = FRUSTUM_INTERSECTS
+		}
+	}
+	return result
+}
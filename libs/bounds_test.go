@@ -0,0 +1,149 @@
+package libs
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"testing"
+)
+
+const fBoundsEpsilon = 1e-4
+
+// isNearVec3 compares with absolute tolerance, mgl32 uses relative one, which never matches zero
+func isNearVec3(vA, vB mgl32.Vec3, fTolerance float32) bool {
+	for c := 0; c < 3; c++ {
+		if math.Abs(float64(vA[c]-vB[c])) > float64(fTolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAABBFromPoints(t *testing.T) {
+	box := AABBFromPoints([]mgl32.Vec3{{1, -2, 3}, {-4, 5, 0}, {2, 0, -6}})
+	if box.IsEmpty() || box.Min != (mgl32.Vec3{-4, -2, -6}) || box.Max != (mgl32.Vec3{2, 5, 3}) {
+		t.Fatalf("got %v", box)
+	}
+	if box.GetCenter() != (mgl32.Vec3{-1, 1.5, -1.5}) || box.GetHalfSize() != (mgl32.Vec3{3, 3.5, 4.5}) {
+		t.Fatalf("center %v, half size %v", box.GetCenter(), box.GetHalfSize())
+	}
+
+	empty := AABBFromPoints(nil)
+	if !empty.IsEmpty() {
+		t.Fatalf("box of no points is %v", empty)
+	}
+	if box.Merge(empty) != box || !empty.Transform(mgl32.Translate3D(1, 2, 3)).IsEmpty() {
+		t.Fatal("empty box changed merge or transform result")
+	}
+	// Single point gives box of zero size, which isn't empty
+	if point := AABBFromPoints([]mgl32.Vec3{{1, 2, 3}}); point.IsEmpty() || !point.ContainsPoint(mgl32.Vec3{1, 2, 3}) {
+		t.Fatalf("box of one point is %v", point)
+	}
+}
+
+func TestSphereFromPoints(t *testing.T) {
+	sphere := SphereFromPoints([]mgl32.Vec3{{-1, 0, 0}, {3, 0, 0}, {1, 2, 0}})
+	if sphere.Center != (mgl32.Vec3{1, 1, 0}) || math.Abs(float64(sphere.Radius)-math.Sqrt(5)) > fBoundsEpsilon {
+		t.Fatalf("got %v", sphere)
+	}
+	empty := SphereFromPoints(nil)
+	if !empty.IsEmpty() || !empty.Transform(mgl32.Scale3D(2, 2, 2)).IsEmpty() {
+		t.Fatalf("sphere of no points is %v", empty)
+	}
+}
+
+func TestBoundsTransform(t *testing.T) {
+	box := AABB{Min: mgl32.Vec3{-1, -2, -3}, Max: mgl32.Vec3{1, 2, 3}}
+	sphere := Sphere{Center: mgl32.Vec3{1, 0, 0}, Radius: 2}
+	vCases := []struct {
+		sName   string
+		mMatrix mgl32.Mat4
+		box     AABB
+		sphere  Sphere
+	}{
+		{"scale", mgl32.Translate3D(10, 0, 0).Mul4(mgl32.Scale3D(2, 3, 4)),
+			AABB{Min: mgl32.Vec3{8, -6, -12}, Max: mgl32.Vec3{12, 6, 12}}, Sphere{Center: mgl32.Vec3{12, 0, 0}, Radius: 8}},
+		// Quarter turn around Y moves X to -Z and Z to X
+		{"rotation Y", mgl32.HomogRotate3DY(math.Pi / 2),
+			AABB{Min: mgl32.Vec3{-3, -2, -1}, Max: mgl32.Vec3{3, 2, 1}}, Sphere{Center: mgl32.Vec3{0, 0, -1}, Radius: 2}},
+		// Box turned by 45 degrees is bounded by its diagonal
+		{"rotation Z", mgl32.HomogRotate3DZ(math.Pi / 4),
+			AABB{Min: mgl32.Vec3{-1.5 * math.Sqrt2, -1.5 * math.Sqrt2, -3}, Max: mgl32.Vec3{1.5 * math.Sqrt2, 1.5 * math.Sqrt2, 3}},
+			Sphere{Center: mgl32.Vec3{0.5 * math.Sqrt2, 0.5 * math.Sqrt2, 0}, Radius: 2}},
+	}
+	for _, testCase := range vCases {
+		result := box.Transform(testCase.mMatrix)
+		if !isNearVec3(result.Min, testCase.box.Min, fBoundsEpsilon) || !isNearVec3(result.Max, testCase.box.Max, fBoundsEpsilon) {
+			t.Errorf("%s: box is %v, want %v", testCase.sName, result, testCase.box)
+		}
+		resultSphere := sphere.Transform(testCase.mMatrix)
+		if !isNearVec3(resultSphere.Center, testCase.sphere.Center, fBoundsEpsilon) ||
+			math.Abs(float64(resultSphere.Radius-testCase.sphere.Radius)) > fBoundsEpsilon {
+			t.Errorf("%s: sphere is %v, want %v", testCase.sName, resultSphere, testCase.sphere)
+		}
+	}
+}
+
+// newTestFrustum returns frustum of camera at 10,5,0 looking along -Z with 90 degree field of view, near 1 and far 100
+func newTestFrustum() Frustum {
+	mProjection := mgl32.Perspective(mgl32.DegToRad(90), 1, 1, 100)
+	mView := mgl32.LookAtV(mgl32.Vec3{10, 5, 0}, mgl32.Vec3{10, 5, -1}, mgl32.Vec3{0, 1, 0})
+	return NewFrustum(mProjection, mView)
+}
+
+func TestFrustumPlanes(t *testing.T) {
+	frustum := newTestFrustum()
+	vEye := mgl32.Vec3{10, 5, 0}
+	fSide := float32(math.Sqrt2 / 2)
+	vNormals := [NUM_FRUSTUM_PLANES]mgl32.Vec3{
+		FRUSTUM_PLANE_LEFT:   {fSide, 0, -fSide},
+		FRUSTUM_PLANE_RIGHT:  {-fSide, 0, -fSide},
+		FRUSTUM_PLANE_BOTTOM: {0, fSide, -fSide},
+		FRUSTUM_PLANE_TOP:    {0, -fSide, -fSide},
+		FRUSTUM_PLANE_NEAR:   {0, 0, -1},
+		FRUSTUM_PLANE_FAR:    {0, 0, 1},
+	}
+	// Side planes go through the eye, near and far ones through points 1 and 100 in front of it
+	vPointsOnPlanes := [NUM_FRUSTUM_PLANES]mgl32.Vec3{vEye, vEye, vEye, vEye, {10, 5, -1}, {10, 5, -100}}
+	for i, plane := range frustum.Planes {
+		fDistance := -vNormals[i].Dot(vPointsOnPlanes[i])
+		if !isNearVec3(plane.Normal, vNormals[i], fBoundsEpsilon) || math.Abs(float64(plane.Distance-fDistance)) > 1e-3 {
+			t.Errorf("plane %d is %v, want normal %v and distance %v", i, plane, vNormals[i], fDistance)
+		}
+	}
+	if !frustum.ContainsPoint(mgl32.Vec3{10, 5, -50}) || frustum.ContainsPoint(mgl32.Vec3{10, 5, 1}) || frustum.ContainsPoint(mgl32.Vec3{-50, 5, -10}) {
+		t.Error("points are on wrong side of planes")
+	}
+}
+
+func TestFrustumTests(t *testing.T) {
+	frustum := newTestFrustum()
+	vCases := []struct {
+		sName   string
+		vCenter mgl32.Vec3
+		fSize   float32 // Sphere radius and box half size
+		result  EFrustumTest
+	}{
+		{"inside", mgl32.Vec3{10, 5, -50}, 1, FRUSTUM_INSIDE},
+		{"behind camera", mgl32.Vec3{10, 5, 10}, 1, FRUSTUM_OUTSIDE},
+		{"beyond far plane", mgl32.Vec3{10, 5, -150}, 10, FRUSTUM_OUTSIDE},
+		{"left of view", mgl32.Vec3{-50, 5, -20}, 2, FRUSTUM_OUTSIDE},
+		{"crossing near plane", mgl32.Vec3{10, 5, -1}, 0.5, FRUSTUM_INTERSECTS},
+		{"crossing far plane", mgl32.Vec3{10, 5, -100}, 5, FRUSTUM_INTERSECTS},
+		{"crossing right plane", mgl32.Vec3{30, 5, -20}, 3, FRUSTUM_INTERSECTS},
+		{"around camera", mgl32.Vec3{10, 5, 0}, 1000, FRUSTUM_INTERSECTS},
+	}
+	for _, testCase := range vCases {
+		sphere := Sphere{Center: testCase.vCenter, Radius: testCase.fSize}
+		if result := frustum.TestSphere(sphere); result != testCase.result {
+			t.Errorf("%s: sphere test gives %d, want %d", testCase.sName, result, testCase.result)
+		}
+		vHalfSize := mgl32.Vec3{testCase.fSize, testCase.fSize, testCase.fSize}
+		box := AABB{Min: testCase.vCenter.Sub(vHalfSize), Max: testCase.vCenter.Add(vHalfSize)}
+		if result := frustum.TestAABB(box); result != testCase.result {
+			t.Errorf("%s: box test gives %d, want %d", testCase.sName, result, testCase.result)
+		}
+	}
+	if frustum.TestSphere(SphereFromPoints(nil)) != FRUSTUM_OUTSIDE || frustum.TestAABB(EmptyAABB()) != FRUSTUM_OUTSIDE {
+		t.Error("empty volumes aren't outside")
+	}
+}
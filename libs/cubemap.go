@@ -0,0 +1,140 @@
+package libs
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// Cube faces in OpenGL order, TextureData of cubemaps keeps them so too
+const (
+	CUBE_FACE_POSITIVE_X = iota
+	CUBE_FACE_NEGATIVE_X
+	CUBE_FACE_POSITIVE_Y
+	CUBE_FACE_NEGATIVE_Y
+	CUBE_FACE_POSITIVE_Z
+	CUBE_FACE_NEGATIVE_Z
+)
+
+/*-----------------------------------------------
+
+  Name:	GetCubeFaceDirection
+
+  Params:	iFace - one of CUBE_FACE_*
+  		s, t - position on the face in -1..1, t
+  		goes down from first row
+
+  Result:	Returns direction OpenGL samples the face
+  		texel with, not normalized.
+
+  /*---------------------------------------------*/
+
+func GetCubeFaceDirection(iFace int, s, t float64) (float64, float64, float64) {
+	switch iFace {
+	case CUBE_FACE_POSITIVE_X:
+		return 1, -t, -s
+	case CUBE_FACE_NEGATIVE_X:
+		return -1, -t, s
+	case CUBE_FACE_POSITIVE_Y:
+		return s, 1, t
+	case CUBE_FACE_NEGATIVE_Y:
+		return s, -1, -t
+	case CUBE_FACE_POSITIVE_Z:
+		return s, -t, 1
+	}
+	return -s, -t, -1
+}
+
+/*-----------------------------------------------
+
+  Name:	CubeFacesFromEquirect
+
+  Params:	img - equirectangular panorama, its middle
+  		looks along -Z
+  		iFaceSize - size of created faces, 0 picks
+  		quarter of panorama width
+
+  Result:	Returns six faces in CUBE_FACE_* order,
+  		each texel is sampled from panorama in
+  		direction of its center.
+
+  /*---------------------------------------------*/
+
+func CubeFacesFromEquirect(img *FloatImage, iFaceSize int) [6]*FloatImage {
+	if iFaceSize <= 0 {
+		iFaceSize = MAX(1, img.Width/4)
+	}
+	var vFaces [6]*FloatImage
+	for iFace := range vFaces {
+		face := NewFloatImage(iFaceSize, iFaceSize)
+		for y := 0; y < iFaceSize; y++ {
+			t := 2*(float64(y)+0.5)/float64(iFaceSize) - 1
+			for x := 0; x < iFaceSize; x++ {
+				s := 2*(float64(x)+0.5)/float64(iFaceSize) - 1
+				dx, dy, dz := GetCubeFaceDirection(iFace, s, t)
+				fLength := math.Sqrt(dx*dx + dy*dy + dz*dz)
+				u := 0.5 + math.Atan2(dx, -dz)/(2*math.Pi)
+				v := math.Acos(dy/fLength) / math.Pi
+				face.SetTexel(x, y, img.Sample(u, v, true))
+			}
+		}
+		vFaces[iFace] = face
+	}
+	return vFaces
+}
+
+// Cells of faces in cross layouts, as column and row
+var vHorizontalCrossCells = [6]image.Point{
+	CUBE_FACE_POSITIVE_X: {2, 1}, CUBE_FACE_NEGATIVE_X: {0, 1},
+	CUBE_FACE_POSITIVE_Y: {1, 0}, CUBE_FACE_NEGATIVE_Y: {1, 2},
+	CUBE_FACE_POSITIVE_Z: {1, 1}, CUBE_FACE_NEGATIVE_Z: {3, 1},
+}
+
+var vVerticalCrossCells = [6]image.Point{
+	CUBE_FACE_POSITIVE_X: {2, 1}, CUBE_FACE_NEGATIVE_X: {0, 1},
+	CUBE_FACE_POSITIVE_Y: {1, 0}, CUBE_FACE_NEGATIVE_Y: {1, 2},
+	CUBE_FACE_POSITIVE_Z: {1, 1}, CUBE_FACE_NEGATIVE_Z: {1, 3},
+}
+
+/*-----------------------------------------------
+
+  Name:	CubeFacesFromCross
+
+  Params:	img - horizontal (4x3 cells) or vertical
+  		(3x4 cells) cross with +Z in the middle
+
+  Result:	Returns six faces in CUBE_FACE_* order.
+  		-Z of vertical cross is stored upside down
+  		and gets rotated back.
+
+  /*---------------------------------------------*/
+
+func CubeFacesFromCross(img *FloatImage) ([6]*FloatImage, error) {
+	var vFaces [6]*FloatImage
+	var vCells *[6]image.Point
+	var iFaceSize int
+	switch {
+	case img.Width*3 == img.Height*4:
+		vCells, iFaceSize = &vHorizontalCrossCells, img.Width/4
+	case img.Width*4 == img.Height*3:
+		vCells, iFaceSize = &vVerticalCrossCells, img.Width/3
+	default:
+		return vFaces, fmt.Errorf("%dx%d image isn't a cross layout, it must be 4:3 or 3:4", img.Width, img.Height)
+	}
+	if iFaceSize == 0 {
+		return vFaces, fmt.Errorf("cross layout of %dx%d is too small", img.Width, img.Height)
+	}
+	for iFace, cell := range vCells {
+		vFaces[iFace] = img.SubImage(image.Rect(cell.X*iFaceSize, cell.Y*iFaceSize, (cell.X+1)*iFaceSize, (cell.Y+1)*iFaceSize))
+	}
+	if vCells == &vVerticalCrossCells {
+		face := vFaces[CUBE_FACE_NEGATIVE_Z]
+		// Rotating by 180 degrees just reverses texel order
+		for i, j := 0, face.Width*face.Height-1; i < j; i, j = i+1, j-1 {
+			vTexel := face.GetTexel(i%face.Width, i/face.Width)
+			face.SetTexel(i%face.Width, i/face.Width, face.GetTexel(j%face.Width, j/face.Width))
+			face.SetTexel(j%face.Width, j/face.Width, vTexel)
+		}
+	}
+	return vFaces, nil
+}
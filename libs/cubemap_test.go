@@ -0,0 +1,127 @@
+package libs
+
+import (
+	"math"
+	"testing"
+)
+
+// newDirectionPanorama returns equirectangular image whose texels hold direction they look in, mapped to 0..1
+func newDirectionPanorama(iWidth, iHeight int) *FloatImage {
+	img := NewFloatImage(iWidth, iHeight)
+	for y := 0; y < iHeight; y++ {
+		fTheta := (float64(y) + 0.5) / float64(iHeight) * math.Pi
+		for x := 0; x < iWidth; x++ {
+			// Middle of panorama looks along -Z
+			fPhi := ((float64(x)+0.5)/float64(iWidth) - 0.5) * 2 * math.Pi
+			dx, dy, dz := math.Sin(fTheta)*math.Sin(fPhi), math.Cos(fTheta), -math.Sin(fTheta)*math.Cos(fPhi)
+			img.SetTexel(x, y, [4]float32{float32(dx*0.5 + 0.5), float32(dy*0.5 + 0.5), float32(dz*0.5 + 0.5), 1})
+		}
+	}
+	return img
+}
+
+func TestCubeFaceDirection(t *testing.T) {
+	vCases := []struct {
+		iFace      int
+		s, t       float64
+		vDirection [3]float64
+	}{
+		{CUBE_FACE_POSITIVE_X, 0, 0, [3]float64{1, 0, 0}},
+		{CUBE_FACE_NEGATIVE_X, 0, 0, [3]float64{-1, 0, 0}},
+		{CUBE_FACE_POSITIVE_Y, 0, 0, [3]float64{0, 1, 0}},
+		{CUBE_FACE_NEGATIVE_Y, 0, 0, [3]float64{0, -1, 0}},
+		{CUBE_FACE_POSITIVE_Z, 0, 0, [3]float64{0, 0, 1}},
+		{CUBE_FACE_NEGATIVE_Z, 0, 0, [3]float64{0, 0, -1}},
+		// Top left corners, as OpenGL picks texel 0,0 of each face
+		{CUBE_FACE_POSITIVE_X, -1, -1, [3]float64{1, 1, 1}},
+		{CUBE_FACE_NEGATIVE_X, -1, -1, [3]float64{-1, 1, -1}},
+		{CUBE_FACE_POSITIVE_Y, -1, -1, [3]float64{-1, 1, -1}},
+		{CUBE_FACE_NEGATIVE_Y, -1, -1, [3]float64{-1, -1, 1}},
+		{CUBE_FACE_POSITIVE_Z, -1, -1, [3]float64{-1, 1, 1}},
+		{CUBE_FACE_NEGATIVE_Z, -1, -1, [3]float64{1, 1, -1}},
+	}
+	for _, testCase := range vCases {
+		dx, dy, dz := GetCubeFaceDirection(testCase.iFace, testCase.s, testCase.t)
+		if [3]float64{dx, dy, dz} != testCase.vDirection {
+			t.Errorf("face %d at %v,%v looks at %v,%v,%v, want %v", testCase.iFace, testCase.s, testCase.t, dx, dy, dz, testCase.vDirection)
+		}
+	}
+}
+
+func TestCubeFacesFromEquirect(t *testing.T) {
+	panorama := newDirectionPanorama(256, 128)
+	vFaces := CubeFacesFromEquirect(panorama, 0)
+	for iFace, face := range vFaces {
+		if face.Width != 64 || face.Height != 64 {
+			t.Fatalf("face %d is %dx%d, want quarter of panorama width", iFace, face.Width, face.Height)
+		}
+		for y := 0; y < face.Height; y++ {
+			for x := 0; x < face.Width; x++ {
+				s := 2*(float64(x)+0.5)/float64(face.Width) - 1
+				v := 2*(float64(y)+0.5)/float64(face.Height) - 1
+				dx, dy, dz := GetCubeFaceDirection(iFace, s, v)
+				fLength := math.Sqrt(dx*dx + dy*dy + dz*dz)
+				vTexel := face.GetTexel(x, y)
+				for c, fExpected := range []float64{dx, dy, dz} {
+					// Bilinear filtering bends the direction a bit, most near poles
+					if fValue := float64(vTexel[c])*2 - 1; math.Abs(fValue-fExpected/fLength) > 0.03 {
+						t.Fatalf("face %d texel %d,%d channel %d is %.3f, want %.3f", iFace, x, y, c, fValue, fExpected/fLength)
+					}
+				}
+			}
+		}
+	}
+	if vFaces = CubeFacesFromEquirect(panorama, 8); vFaces[CUBE_FACE_NEGATIVE_Y].Width != 8 {
+		t.Fatalf("face is %d wide, want 8", vFaces[CUBE_FACE_NEGATIVE_Y].Width)
+	}
+}
+
+// newCrossImage returns cross layout of 2x2 faces, texels hold cell column and row, texel position and one
+func newCrossImage(iCellsX, iCellsY int) *FloatImage {
+	img := NewFloatImage(iCellsX*2, iCellsY*2)
+	for y := 0; y < img.Height; y++ {
+		for x := 0; x < img.Width; x++ {
+			img.SetTexel(x, y, [4]float32{float32(x / 2), float32(y / 2), float32(y%2*2 + x%2), 1})
+		}
+	}
+	return img
+}
+
+func TestCubeFacesFromCross(t *testing.T) {
+	vCases := []struct {
+		sName  string
+		img    *FloatImage
+		vCells [6][2]float32
+	}{
+		{"horizontal", newCrossImage(4, 3), [6][2]float32{{2, 1}, {0, 1}, {1, 0}, {1, 2}, {1, 1}, {3, 1}}},
+		{"vertical", newCrossImage(3, 4), [6][2]float32{{2, 1}, {0, 1}, {1, 0}, {1, 2}, {1, 1}, {1, 3}}},
+	}
+	for _, testCase := range vCases {
+		vFaces, err := CubeFacesFromCross(testCase.img)
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.sName, err)
+		}
+		for iFace, face := range vFaces {
+			if face.Width != 2 || face.Height != 2 {
+				t.Fatalf("%s: face %d is %dx%d", testCase.sName, iFace, face.Width, face.Height)
+			}
+			for i := 0; i < 4; i++ {
+				vTexel := face.GetTexel(i%2, i/2)
+				iPosition := i
+				// -Z of vertical cross is upside down
+				if testCase.sName == "vertical" && iFace == CUBE_FACE_NEGATIVE_Z {
+					iPosition = 3 - i
+				}
+				if vTexel != [4]float32{testCase.vCells[iFace][0], testCase.vCells[iFace][1], float32(iPosition), 1} {
+					t.Fatalf("%s: face %d texel %d is %v", testCase.sName, iFace, i, vTexel)
+				}
+			}
+		}
+	}
+
+	for _, img := range []*FloatImage{NewFloatImage(10, 6), NewFloatImage(0, 0), NewFloatImage(8, 8)} {
+		if _, err := CubeFacesFromCross(img); err == nil {
+			t.Errorf("%dx%d image was split to faces", img.Width, img.Height)
+		}
+	}
+}
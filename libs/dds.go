@@ -4,20 +4,24 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"image"
 	"image/color"
 	"io"
 	"math"
 	"os"
+	"unsafe"
 )
 
 func init() {
 	image.RegisterFormat("dds", "DDS ", Decode, DecodeConfig)
 }
 
+// DDSimg is top mip level of first face or layer, decoded to RGBA
 type DDSimg struct {
-	h   DDSHeader
-	Buf []DDSByte
+	h    DDSHeader
+	Buf  []DDSByte
+	Data *TextureData // Every surface in stored format, graphic uploads it directly if it can
 
 	rBit, gBit, bBit, aBit uint
 
@@ -25,7 +29,17 @@ type DDSimg struct {
 }
 
 func DecodeConfig(r io.Reader) (image.Config, error) {
-	return image.Config{}, nil
+	var header struct {
+		Magic  uint32
+		Header DDSHeader
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return image.Config{}, err
+	}
+	if header.Magic != DDSMagic {
+		return image.Config{}, errors.New("not a DDS file")
+	}
+	return image.Config{ColorModel: color.NRGBAModel, Width: int(header.Header.Width), Height: int(header.Header.Height)}, nil
 }
 
 func (i *DDSimg) ColorModel() color.Model {
@@ -36,16 +50,14 @@ func (i *DDSimg) Bounds() image.Rectangle {
 	return image.Rect(0, 0, int(i.h.Width), int(i.h.Height))
 }
 
+// At returns texel of first slice, Buf is always RGBA whatever the stored format is
 func (i *DDSimg) At(x, y int) color.Color {
 	imgWidth := MAX(1, int(i.h.Width))
-	imgHeight := MAX(1, int(i.h.Height))
-	imgDepth := MAX(1, int(i.h.Depth))
-
-	//blocksX := MAX(1, imgWidth/4)
-	//blocksY := MAX(1, imgHeight/4)
-	bytesPerPixel := i.h.PixelFormat.RGBBitCount / 8
+	if !(image.Point{x, y}.In(i.Bounds())) {
+		return color.NRGBA{}
+	}
 
-	pxIndex := (imgDepth*imgWidth*imgHeight + y*imgWidth + x) * int(bytesPerPixel)
+	pxIndex := (y*imgWidth + x) * 4
 	r := uint8(i.Buf[pxIndex+0])
 	g := uint8(i.Buf[pxIndex+1])
 	b := uint8(i.Buf[pxIndex+2])
@@ -53,21 +65,18 @@ func (i *DDSimg) At(x, y int) color.Color {
 	return color.NRGBA{r, g, b, a}
 }
 
+func (i *DDSimg) GetTextureData() *TextureData {
+	return i.Data
+}
+
 func Decode(r io.Reader) (image.Image, error) {
 	im, err := ddsLoadFromFileFromReader(r)
 	if err != nil {
 		return &DDSimg{}, err
 	}
 	return &DDSimg{h: im.Header,
-		Buf: im.Pixels,
-
-		//pitch: int(pitch),
-		//stride: int(stride),
-		//
-		//rBit: lowestSetBit(h.pixelFormat.rBitMask),
-		//gBit: lowestSetBit(h.pixelFormat.gBitMask),
-		//bBit: lowestSetBit(h.pixelFormat.bBitMask),
-		//aBit: lowestSetBit(h.pixelFormat.aBitMask),
+		Buf:  im.Pixels,
+		Data: im.Data,
 	}, nil
 }
 
@@ -159,7 +168,8 @@ type DDSHeaderDXT10 struct {
 type DDSImage struct {
 	Header      DDSHeader
 	HeaderDXT10 DDSHeaderDXT10
-	Pixels      []DDSByte // Always RGBA; currently no support for mipmaps
+	Pixels      []DDSByte    // Top mip level of first face or layer (all its slices), always RGBA
+	Data        *TextureData // Every surface of the texture
 }
 
 // Constants
@@ -168,14 +178,33 @@ const (
 )
 
 func FOURCC(str string) DDSUint {
-	return DDSUint((str[3] << 24) | (str[2] << 16) | (str[1] << 8) | str[0])
+	return DDSUint(uint32(str[3])<<24 | uint32(str[2])<<16 | uint32(str[1])<<8 | uint32(str[0]))
 }
+
+// ddsFourCCString prints FourCC for error messages, legacy D3DFMT numbers are printed as numbers.
+func ddsFourCCString(iFourCC DDSUint) string {
+	var bFourCC [4]byte
+	binary.LittleEndian.PutUint32(bFourCC[:], uint32(iFourCC))
+	for _, b := range bFourCC {
+		if b < ' ' || b > '~' {
+			return fmt.Sprintf("D3DFMT %d", iFourCC)
+		}
+	}
+	return fmt.Sprintf("%q", string(bFourCC[:]))
+}
+
 func MAX(x, y int) int {
 	if x > y {
 		return x
 	}
 	return y
 }
+func MIN(x, y int) int {
+	if x < y {
+		return x
+	}
+	return y
+}
 func IMAGE_PITCH(width, blockSize int) int {
 	return MAX(1, ((width+3)/4)) * blockSize
 }
@@ -189,35 +218,31 @@ func ddsCalculateLeftShift(rightShift *DDSUint, bitCount DDSUint) DDSUint {
 	return 8 - bitCount
 }
 
-// Function to parse uncompressed DDS image
-func ddsParseUncompressed(image *DDSImage, data []byte, dataLength int) {
-	imgWidth := MAX(1, int(image.Header.Width))
-	imgHeight := MAX(1, int(image.Header.Height))
-	imgDepth := MAX(1, int(image.Header.Depth))
-
+// Function to convert uncompressed DDS surface to RGBA, pixel format can differ from the header one for DX10 files
+func ddsConvertUncompressed(pf *DDSPixelFormat, data []byte, imgWidth, imgHeight, imgDepth int) ([]byte, error) {
 	rRightShift, gRightShift, bRightShift, aRightShift := DDSUint(math.MaxUint32), DDSUint(math.MaxUint32), DDSUint(math.MaxUint32), DDSUint(math.MaxUint32)
 	rLeftShift, gLeftShift, bLeftShift, aLeftShift := DDSUint(0), DDSUint(0), DDSUint(0), DDSUint(0)
 
 	for i := 0; i < 32; i++ {
-		if (image.Header.PixelFormat.RBitMask>>i)&1 != 0 {
+		if (pf.RBitMask>>i)&1 != 0 {
 			if rRightShift == math.MaxUint32 {
 				rRightShift = DDSUint(i)
 			}
 			rLeftShift++
 		}
-		if (image.Header.PixelFormat.GBitMask>>i)&1 != 0 {
+		if (pf.GBitMask>>i)&1 != 0 {
 			if gRightShift == math.MaxUint32 {
 				gRightShift = DDSUint(i)
 			}
 			gLeftShift++
 		}
-		if (image.Header.PixelFormat.BBitMask>>i)&1 != 0 {
+		if (pf.BBitMask>>i)&1 != 0 {
 			if bRightShift == math.MaxUint32 {
 				bRightShift = DDSUint(i)
 			}
 			bLeftShift++
 		}
-		if (image.Header.PixelFormat.ABitMask>>i)&1 != 0 {
+		if (pf.ABitMask>>i)&1 != 0 {
 			if aRightShift == math.MaxUint32 {
 				aRightShift = DDSUint(i)
 			}
@@ -245,287 +270,329 @@ func ddsParseUncompressed(image *DDSImage, data []byte, dataLength int) {
 	bLeftShift = ddsCalculateLeftShift(&bRightShift, bLeftShift)
 	aLeftShift = ddsCalculateLeftShift(&aRightShift, aLeftShift)
 
-	bytesPerPixel := image.Header.PixelFormat.RGBBitCount / 8
-
-	data = data[128:] // skip the header
+	bytesPerPixel := int(pf.RGBBitCount / 8)
+	if len(data) < imgWidth*imgHeight*imgDepth*bytesPerPixel {
+		return nil, errors.New("DDS pixel data is truncated")
+	}
+	bLuminance := (pf.Flags & DDPF_LUMINANCE) != 0
+	pixels := make([]byte, imgWidth*imgHeight*imgDepth*4)
 
 	// Read the actual data
-	for z := 0; z < imgDepth; z++ {
-		for x := 0; x < imgWidth; x++ {
-			for y := 0; y < imgHeight; y++ {
-				pxIndex := (z*imgWidth*imgHeight + y*imgWidth + x) * int(bytesPerPixel)
-				dataIndex := (z*imgWidth*imgHeight + (imgHeight-y-1)*imgWidth + x) * 4
-
-				// Get the data into uint
-				var px DDSUint
-				switch bytesPerPixel {
-				case 1:
-					px = DDSUint(data[pxIndex])
-				case 2:
-					px = DDSUint(binary.LittleEndian.Uint16(data[pxIndex:]))
-				case 4:
-					px = DDSUint(binary.LittleEndian.Uint32(data[pxIndex:]))
-				}
+	for pxIndex := 0; pxIndex < imgWidth*imgHeight*imgDepth; pxIndex++ {
+		dataIndex := pxIndex * 4
 
-				// Decode
-				image.Pixels[dataIndex+0] = DDSByte(((px & image.Header.PixelFormat.RBitMask) >> rRightShift) << rLeftShift)
-				image.Pixels[dataIndex+1] = DDSByte(((px & image.Header.PixelFormat.GBitMask) >> gRightShift) << gLeftShift)
-				image.Pixels[dataIndex+2] = DDSByte(((px & image.Header.PixelFormat.BBitMask) >> bRightShift) << bLeftShift)
-				if image.Header.PixelFormat.ABitMask == 0 {
-					image.Pixels[dataIndex+3] = 0xFF
-				} else {
-					image.Pixels[dataIndex+3] = DDSByte(((px & image.Header.PixelFormat.ABitMask) >> aRightShift) << aLeftShift)
-				}
-			}
+		// Get the data into uint
+		var px DDSUint
+		for i := 0; i < bytesPerPixel; i++ {
+			px |= DDSUint(data[pxIndex*bytesPerPixel+i]) << (8 * i)
+		}
+
+		// Decode
+		pixels[dataIndex+0] = byte(((px & pf.RBitMask) >> rRightShift) << rLeftShift)
+		pixels[dataIndex+1] = byte(((px & pf.GBitMask) >> gRightShift) << gLeftShift)
+		pixels[dataIndex+2] = byte(((px & pf.BBitMask) >> bRightShift) << bLeftShift)
+		if bLuminance {
+			pixels[dataIndex+1] = pixels[dataIndex+0]
+			pixels[dataIndex+2] = pixels[dataIndex+0]
+		}
+		if pf.ABitMask == 0 {
+			pixels[dataIndex+3] = 0xFF
+		} else {
+			pixels[dataIndex+3] = byte(((px & pf.ABitMask) >> aRightShift) << aLeftShift)
 		}
 	}
+	return pixels, nil
 }
 
-// Function to parse DXT1 compressed DDS image
-func ddsParseDXT1(image *DDSImage, data []byte, dataLength int) {
-	imgWidth := MAX(1, int(image.Header.Width))
-	imgHeight := MAX(1, int(image.Header.Height))
-	imgDepth := MAX(1, int(image.Header.Depth))
-
-	blocksX := MAX(1, imgWidth/4)
-	blocksY := MAX(1, imgHeight/4)
-
-	for z := 0; z < imgDepth; z++ {
-		for x := 0; x < blocksX; x++ {
-			for y := 0; y < blocksY; y++ {
-				var color0, color1 uint16
-				var codes uint32
-
-				// Read the block data
-				blockOffset := (y*blocksX + x) * 8
-				color0 = binary.LittleEndian.Uint16(data[blockOffset:])
-				color1 = binary.LittleEndian.Uint16(data[blockOffset+2:])
-				codes = binary.LittleEndian.Uint32(data[blockOffset+4:])
-
-				// Unpack the color data
-				r0 := DDSByte((color0 & 0b1111100000000000) >> 8)
-				g0 := DDSByte((color0 & 0b0000011111100000) >> 3)
-				b0 := DDSByte((color0 & 0b0000000000011111) << 3)
-				r1 := DDSByte((color1 & 0b1111100000000000) >> 8)
-				g1 := DDSByte((color1 & 0b0000011111100000) >> 3)
-				b1 := DDSByte((color1 & 0b0000000000011111) << 3)
-
-				// Process the data
-				for b := 0; b < 16; b++ {
-					pxIndex := ((z * 4) * imgHeight * imgWidth) + (imgHeight-((y*4)+b/4)-1)*imgWidth + (x*4+b%4)*4
-
-					code := (codes >> (2 * b)) & 3
-					image.Pixels[pxIndex+3] = 0xFF
-					switch code {
-					case 0:
-						// color0
-						image.Pixels[pxIndex+0] = r0
-						image.Pixels[pxIndex+1] = g0
-						image.Pixels[pxIndex+2] = b0
-					case 1:
-						// color1
-						image.Pixels[pxIndex+0] = r1
-						image.Pixels[pxIndex+1] = g1
-						image.Pixels[pxIndex+2] = b1
-					case 2:
-						if color0 > color1 {
-							// (2*color0 + color1) / 3
-							image.Pixels[pxIndex+0] = (2*r0 + r1) / 3
-							image.Pixels[pxIndex+1] = (2*g0 + g1) / 3
-							image.Pixels[pxIndex+2] = (2*b0 + b1) / 3
-						} else {
-							// (color0 + color1) / 2
-							image.Pixels[pxIndex+0] = (r0 + r1) / 2
-							image.Pixels[pxIndex+1] = (g0 + g1) / 2
-							image.Pixels[pxIndex+2] = (b0 + b1) / 2
-						}
-					case 3:
-						if color0 > color1 {
-							// (color0 + 2*color1) / 3
-							image.Pixels[pxIndex+0] = (r0 + 2*r1) / 3
-							image.Pixels[pxIndex+1] = (g0 + 2*g1) / 3
-							image.Pixels[pxIndex+2] = (b0 + 2*b1) / 3
-						} else {
-							// black
-							image.Pixels[pxIndex+0] = 0x00
-							image.Pixels[pxIndex+1] = 0x00
-							image.Pixels[pxIndex+2] = 0x00
-						}
-					}
-				}
-			}
+// DXGI_FORMAT values of DX10 header that we can decode
+const (
+	DXGI_FORMAT_R10G10B10A2_TYPELESS = 23
+	DXGI_FORMAT_R10G10B10A2_UNORM    = 24
+	DXGI_FORMAT_R8G8B8A8_TYPELESS    = 27
+	DXGI_FORMAT_R8G8B8A8_UNORM       = 28
+	DXGI_FORMAT_R8G8B8A8_UNORM_SRGB  = 29
+	DXGI_FORMAT_R8G8_TYPELESS        = 48
+	DXGI_FORMAT_R8G8_UNORM           = 49
+	DXGI_FORMAT_R8_TYPELESS          = 60
+	DXGI_FORMAT_R8_UNORM             = 61
+	DXGI_FORMAT_A8_UNORM             = 65
+	DXGI_FORMAT_BC1_TYPELESS         = 70
+	DXGI_FORMAT_BC1_UNORM            = 71
+	DXGI_FORMAT_BC1_UNORM_SRGB       = 72
+	DXGI_FORMAT_BC2_TYPELESS         = 73
+	DXGI_FORMAT_BC2_UNORM            = 74
+	DXGI_FORMAT_BC2_UNORM_SRGB       = 75
+	DXGI_FORMAT_BC3_TYPELESS         = 76
+	DXGI_FORMAT_BC3_UNORM            = 77
+	DXGI_FORMAT_BC3_UNORM_SRGB       = 78
+	DXGI_FORMAT_BC4_TYPELESS         = 79
+	DXGI_FORMAT_BC4_UNORM            = 80
+	DXGI_FORMAT_BC4_SNORM            = 81
+	DXGI_FORMAT_BC5_TYPELESS         = 82
+	DXGI_FORMAT_BC5_UNORM            = 83
+	DXGI_FORMAT_BC5_SNORM            = 84
+	DXGI_FORMAT_B5G6R5_UNORM         = 85
+	DXGI_FORMAT_B5G5R5A1_UNORM       = 86
+	DXGI_FORMAT_B8G8R8A8_UNORM       = 87
+	DXGI_FORMAT_B8G8R8X8_UNORM       = 88
+	DXGI_FORMAT_B8G8R8A8_TYPELESS    = 90
+	DXGI_FORMAT_B8G8R8A8_UNORM_SRGB  = 91
+	DXGI_FORMAT_B8G8R8X8_TYPELESS    = 92
+	DXGI_FORMAT_B8G8R8X8_UNORM_SRGB  = 93
+	DXGI_FORMAT_BC6H_TYPELESS        = 94
+	DXGI_FORMAT_BC6H_UF16            = 95
+	DXGI_FORMAT_BC6H_SF16            = 96
+	DXGI_FORMAT_BC7_TYPELESS         = 97
+	DXGI_FORMAT_BC7_UNORM            = 98
+	DXGI_FORMAT_BC7_UNORM_SRGB       = 99
+	DXGI_FORMAT_B4G4R4A4_UNORM       = 115
+)
+
+// DX10 header values
+const (
+	DDS_DIMENSION_TEXTURE3D       = 4
+	DDS_RESOURCE_MISC_TEXTURECUBE = 0x4
+)
+
+// Uncompressed DXGI formats are described by masks, so they go through ddsConvertUncompressed
+func ddsGetDXGIPixelFormat(iFormat DDSUint) (DDSPixelFormat, bool) {
+	switch iFormat {
+	case DXGI_FORMAT_R10G10B10A2_TYPELESS, DXGI_FORMAT_R10G10B10A2_UNORM:
+		return DDSPixelFormat{Flags: DDPF_RGB | DDPF_ALPHAPIXELS, RGBBitCount: 32, RBitMask: 0x3FF, GBitMask: 0xFFC00, BBitMask: 0x3FF00000, ABitMask: 0xC0000000}, true
+	case DXGI_FORMAT_R8G8B8A8_TYPELESS, DXGI_FORMAT_R8G8B8A8_UNORM, DXGI_FORMAT_R8G8B8A8_UNORM_SRGB:
+		return DDSPixelFormat{Flags: DDPF_RGB | DDPF_ALPHAPIXELS, RGBBitCount: 32, RBitMask: 0xFF, GBitMask: 0xFF00, BBitMask: 0xFF0000, ABitMask: 0xFF000000}, true
+	case DXGI_FORMAT_R8G8_TYPELESS, DXGI_FORMAT_R8G8_UNORM:
+		return DDSPixelFormat{Flags: DDPF_RGB, RGBBitCount: 16, RBitMask: 0xFF, GBitMask: 0xFF00}, true
+	case DXGI_FORMAT_R8_TYPELESS, DXGI_FORMAT_R8_UNORM:
+		return DDSPixelFormat{Flags: DDPF_RGB, RGBBitCount: 8, RBitMask: 0xFF}, true
+	case DXGI_FORMAT_A8_UNORM:
+		return DDSPixelFormat{Flags: DDPF_ALPHA, RGBBitCount: 8, ABitMask: 0xFF}, true
+	case DXGI_FORMAT_B5G6R5_UNORM:
+		return DDSPixelFormat{Flags: DDPF_RGB, RGBBitCount: 16, RBitMask: 0xF800, GBitMask: 0x7E0, BBitMask: 0x1F}, true
+	case DXGI_FORMAT_B5G5R5A1_UNORM:
+		return DDSPixelFormat{Flags: DDPF_RGB | DDPF_ALPHAPIXELS, RGBBitCount: 16, RBitMask: 0x7C00, GBitMask: 0x3E0, BBitMask: 0x1F, ABitMask: 0x8000}, true
+	case DXGI_FORMAT_B4G4R4A4_UNORM:
+		return DDSPixelFormat{Flags: DDPF_RGB | DDPF_ALPHAPIXELS, RGBBitCount: 16, RBitMask: 0xF00, GBitMask: 0xF0, BBitMask: 0xF, ABitMask: 0xF000}, true
+	case DXGI_FORMAT_B8G8R8A8_UNORM, DXGI_FORMAT_B8G8R8A8_TYPELESS, DXGI_FORMAT_B8G8R8A8_UNORM_SRGB:
+		return DDSPixelFormat{Flags: DDPF_RGB | DDPF_ALPHAPIXELS, RGBBitCount: 32, RBitMask: 0xFF0000, GBitMask: 0xFF00, BBitMask: 0xFF, ABitMask: 0xFF000000}, true
+	case DXGI_FORMAT_B8G8R8X8_UNORM, DXGI_FORMAT_B8G8R8X8_TYPELESS, DXGI_FORMAT_B8G8R8X8_UNORM_SRGB:
+		return DDSPixelFormat{Flags: DDPF_RGB, RGBBitCount: 32, RBitMask: 0xFF0000, GBitMask: 0xFF00, BBitMask: 0xFF}, true
+	}
+	return DDSPixelFormat{}, false
+}
+
+// ddsGetDXGITexelFormat maps compressed DXGI format to TexelFormat, false if it isn't one.
+func ddsGetDXGITexelFormat(iFormat DDSUint) (TexelFormat, bool) {
+	switch iFormat {
+	case DXGI_FORMAT_BC1_TYPELESS, DXGI_FORMAT_BC1_UNORM, DXGI_FORMAT_BC1_UNORM_SRGB:
+		return TEXEL_FORMAT_BC1, true
+	case DXGI_FORMAT_BC2_TYPELESS, DXGI_FORMAT_BC2_UNORM, DXGI_FORMAT_BC2_UNORM_SRGB:
+		return TEXEL_FORMAT_BC2, true
+	case DXGI_FORMAT_BC3_TYPELESS, DXGI_FORMAT_BC3_UNORM, DXGI_FORMAT_BC3_UNORM_SRGB:
+		return TEXEL_FORMAT_BC3, true
+	case DXGI_FORMAT_BC4_TYPELESS, DXGI_FORMAT_BC4_UNORM:
+		return TEXEL_FORMAT_BC4, true
+	case DXGI_FORMAT_BC4_SNORM:
+		return TEXEL_FORMAT_BC4_SIGNED, true
+	case DXGI_FORMAT_BC5_TYPELESS, DXGI_FORMAT_BC5_UNORM:
+		return TEXEL_FORMAT_BC5, true
+	case DXGI_FORMAT_BC5_SNORM:
+		return TEXEL_FORMAT_BC5_SIGNED, true
+	case DXGI_FORMAT_BC6H_TYPELESS, DXGI_FORMAT_BC6H_UF16:
+		return TEXEL_FORMAT_BC6H, true
+	case DXGI_FORMAT_BC6H_SF16:
+		return TEXEL_FORMAT_BC6H_SIGNED, true
+	case DXGI_FORMAT_BC7_TYPELESS, DXGI_FORMAT_BC7_UNORM, DXGI_FORMAT_BC7_UNORM_SRGB:
+		return TEXEL_FORMAT_BC7, true
+	}
+	return TEXEL_FORMAT_RGBA8, false
+}
+
+func ddsIsDXGISRGB(iFormat DDSUint) bool {
+	switch iFormat {
+	case DXGI_FORMAT_R8G8B8A8_UNORM_SRGB, DXGI_FORMAT_BC1_UNORM_SRGB, DXGI_FORMAT_BC2_UNORM_SRGB, DXGI_FORMAT_BC3_UNORM_SRGB,
+		DXGI_FORMAT_B8G8R8A8_UNORM_SRGB, DXGI_FORMAT_B8G8R8X8_UNORM_SRGB, DXGI_FORMAT_BC7_UNORM_SRGB:
+		return true
+	}
+	return false
+}
+
+// Typeless formats leave colour space to whoever samples them
+func ddsIsDXGITypeless(iFormat DDSUint) bool {
+	switch iFormat {
+	case DXGI_FORMAT_R10G10B10A2_TYPELESS, DXGI_FORMAT_R8G8B8A8_TYPELESS, DXGI_FORMAT_R8G8_TYPELESS, DXGI_FORMAT_R8_TYPELESS,
+		DXGI_FORMAT_BC1_TYPELESS, DXGI_FORMAT_BC2_TYPELESS, DXGI_FORMAT_BC3_TYPELESS, DXGI_FORMAT_BC4_TYPELESS,
+		DXGI_FORMAT_BC5_TYPELESS, DXGI_FORMAT_B8G8R8A8_TYPELESS, DXGI_FORMAT_B8G8R8X8_TYPELESS, DXGI_FORMAT_BC6H_TYPELESS,
+		DXGI_FORMAT_BC7_TYPELESS:
+		return true
+	}
+	return false
+}
+
+// ddsSurfaceConverter turns stored surface into RGBA8, used for formats GPUs can't sample directly
+type ddsSurfaceConverter func(data []byte, iWidth, iHeight, iDepth int) ([]byte, error)
+
+/*-----------------------------------------------
+
+  Name:	ddsGetFormat
+
+  Params:	image - image with parsed headers
+
+  Result:	Returns format surfaces are kept in.
+  		Formats we can't keep as they are come
+  		with converter to RGBA8 and size of
+  		their stored 2D image.
+
+  /*---------------------------------------------*/
+
+func ddsGetFormat(image *DDSImage) (TexelFormat, ddsSurfaceConverter, func(int, int) int, error) {
+	pf := &image.Header.PixelFormat
+	var fourCC DDSUint
+	if (pf.Flags & DDPF_FOURCC) != 0 {
+		fourCC = pf.FourCC
+	}
+	switch fourCC {
+	case FOURCC("DXT1"):
+		return TEXEL_FORMAT_BC1, nil, nil, nil
+	case FOURCC("DXT2"), FOURCC("DXT3"):
+		return TEXEL_FORMAT_BC2, nil, nil, nil
+	case FOURCC("DXT4"), FOURCC("DXT5"):
+		return TEXEL_FORMAT_BC3, nil, nil, nil
+	case FOURCC("ATI1"), FOURCC("BC4U"):
+		return TEXEL_FORMAT_BC4, nil, nil, nil
+	case FOURCC("BC4S"):
+		return TEXEL_FORMAT_BC4_SIGNED, nil, nil, nil
+	case FOURCC("ATI2"), FOURCC("BC5U"):
+		return TEXEL_FORMAT_BC5, nil, nil, nil
+	case FOURCC("BC5S"):
+		return TEXEL_FORMAT_BC5_SIGNED, nil, nil, nil
+	case FOURCC("A2XY"):
+		// Swapped channels can't be sampled as BC5, so it's always decompressed
+		fnConvert := func(data []byte, iWidth, iHeight, iDepth int) ([]byte, error) {
+			return bcDecompress(data, iWidth, iHeight, iDepth, 16, ddsDecodeBlockA2XY)
+		}
+		return TEXEL_FORMAT_RGBA8, fnConvert, TEXEL_FORMAT_BC5.GetDataSize, nil
+	case FOURCC("DX10"):
+		if format, bFound := ddsGetDXGITexelFormat(image.HeaderDXT10.DXGIFormat); bFound {
+			return format, nil, nil, nil
 		}
+		if pfDXGI, bFound := ddsGetDXGIPixelFormat(image.HeaderDXT10.DXGIFormat); bFound {
+			pf = &pfDXGI
+			break
+		}
+		return 0, nil, nil, fmt.Errorf("unsupported DXGI format %d in DDS file", image.HeaderDXT10.DXGIFormat)
+	case 0:
+		if (pf.Flags & (DDPF_RGB | DDPF_LUMINANCE | DDPF_ALPHA)) == 0 {
+			return 0, nil, nil, errors.New("unsupported DDS pixel format")
+		}
+	default:
+		return 0, nil, nil, fmt.Errorf("unsupported DDS format %s", ddsFourCCString(fourCC))
+	}
 
-		// Skip this slice
-		data = data[blocksX*blocksY*16:]
-	}
-}
-
-// Function to parse DXT3 compressed DDS image
-func ddsParseDXT3(image *DDSImage, data []byte, dataLength int) {
-	imgWidth := MAX(1, int(image.Header.Width))
-	imgHeight := MAX(1, int(image.Header.Height))
-	imgDepth := MAX(1, int(image.Header.Depth))
-
-	blocksX := MAX(1, imgWidth/4)
-	blocksY := MAX(1, imgHeight/4)
-
-	for z := 0; z < imgDepth; z++ {
-		for x := 0; x < blocksX; x++ {
-			for y := 0; y < blocksY; y++ {
-				var color0, color1 uint16
-				var codes uint32
-				var alphaData uint64
-
-				// Read the block data
-				blockOffset := (y*blocksX + x) * 16
-				alphaData = binary.LittleEndian.Uint64(data[blockOffset:])
-				color0 = binary.LittleEndian.Uint16(data[blockOffset+8:])
-				color1 = binary.LittleEndian.Uint16(data[blockOffset+10:])
-				codes = binary.LittleEndian.Uint32(data[blockOffset+12:])
-
-				// Unpack the color data
-				r0 := DDSByte((color0 & 0b1111100000000000) >> 8)
-				g0 := DDSByte((color0 & 0b0000011111100000) >> 3)
-				b0 := DDSByte((color0 & 0b0000000000011111) << 3)
-				r1 := DDSByte((color1 & 0b1111100000000000) >> 8)
-				g1 := DDSByte((color1 & 0b0000011111100000) >> 3)
-				b1 := DDSByte((color1 & 0b0000000000011111) << 3)
-
-				// Process the data
-				for b := 0; b < 16; b++ {
-					pxIndex := ((z * 4) * imgHeight * imgWidth) + (imgHeight-((y*4)+b/4)-1)*imgWidth + (x*4+b%4)*4
-					code := (codes >> (2 * b)) & 0b0011
-
-					alpha := DDSByte((alphaData >> (4 * b)) & 0b1111)
-					image.Pixels[pxIndex+3] = alpha
-
-					switch code {
-					case 0:
-						// color0
-						image.Pixels[pxIndex+0] = r0
-						image.Pixels[pxIndex+1] = g0
-						image.Pixels[pxIndex+2] = b0
-					case 1:
-						// color1
-						image.Pixels[pxIndex+0] = r1
-						image.Pixels[pxIndex+1] = g1
-						image.Pixels[pxIndex+2] = b1
-					case 2:
-						// (2*color0 + color1) / 3
-						image.Pixels[pxIndex+0] = (2*r0 + r1) / 3
-						image.Pixels[pxIndex+1] = (2*g0 + g1) / 3
-						image.Pixels[pxIndex+2] = (2*b0 + b1) / 3
-					case 3:
-						// (color0 + 2*color1) / 3
-						image.Pixels[pxIndex+0] = (r0 + 2*r1) / 3
-						image.Pixels[pxIndex+1] = (g0 + 2*g1) / 3
-						image.Pixels[pxIndex+2] = (b0 + 2*b1) / 3
-					}
-				}
+	bytesPerPixel := int(pf.RGBBitCount / 8)
+	if bytesPerPixel < 1 || bytesPerPixel > 4 {
+		return 0, nil, nil, fmt.Errorf("unsupported DDS pixel size of %d bits", pf.RGBBitCount)
+	}
+	fnConvert := func(data []byte, iWidth, iHeight, iDepth int) ([]byte, error) {
+		return ddsConvertUncompressed(pf, data, iWidth, iHeight, iDepth)
+	}
+	fnSize := func(iWidth, iHeight int) int {
+		return iWidth * iHeight * bytesPerPixel
+	}
+	return TEXEL_FORMAT_RGBA8, fnConvert, fnSize, nil
+}
+
+/*-----------------------------------------------
+
+  Name:	ddsReadSurfaces
+
+  Params:	image - image with parsed headers
+  		data - everything after the headers
+
+  Result:	Fills image.Data with all mip levels of
+  		all layers and faces, converting formats
+  		that can't stay compressed.
+
+  /*---------------------------------------------*/
+
+func ddsReadSurfaces(image *DDSImage, data []byte) error {
+	format, fnConvert, fnSize, err := ddsGetFormat(image)
+	if err != nil {
+		return err
+	}
+
+	texData := &TextureData{Format: format, Width: MAX(1, int(image.Header.Width)), Height: MAX(1, int(image.Header.Height)),
+		Depth: 1, Faces: 1, Layers: 1, MipLevels: 1}
+	if (image.Header.Flags&DDSD_MIPMAPCOUNT) != 0 && image.Header.MipmapCount > 0 {
+		texData.MipLevels = int(image.Header.MipmapCount)
+	}
+	bDX10 := (image.Header.PixelFormat.Flags&DDPF_FOURCC) != 0 && image.Header.PixelFormat.FourCC == FOURCC("DX10")
+	if bDX10 {
+		texData.SRGB = ddsIsDXGISRGB(image.HeaderDXT10.DXGIFormat)
+		texData.SRGBKnown = !ddsIsDXGITypeless(image.HeaderDXT10.DXGIFormat)
+		texData.Layers = MAX(1, int(image.HeaderDXT10.ArraySize))
+		if (image.HeaderDXT10.MiscFlag & DDS_RESOURCE_MISC_TEXTURECUBE) != 0 {
+			texData.Faces = 6
+		}
+		if image.HeaderDXT10.ResourceDimension == DDS_DIMENSION_TEXTURE3D {
+			texData.Depth = MAX(1, int(image.Header.Depth))
+		}
+	} else {
+		if (image.Header.Caps2 & DDSCAPS2_CUBEMAP) != 0 {
+			// Legacy cubemaps may leave faces out, we need all of them
+			const iAllFaces = DDSCAPS2_CUBEMAP_POSITIVEX | DDSCAPS2_CUBEMAP_NEGATIVEX | DDSCAPS2_CUBEMAP_POSITIVEY |
+				DDSCAPS2_CUBEMAP_NEGATIVEY | DDSCAPS2_CUBEMAP_POSITIVEZ | DDSCAPS2_CUBEMAP_NEGATIVEZ
+			if (image.Header.Caps2 & iAllFaces) != iAllFaces {
+				return errors.New("DDS cubemaps without all six faces are not supported")
 			}
+			texData.Faces = 6
+		}
+		if (image.Header.Caps2&DDSCAPS2_VOLUME) != 0 || (image.Header.Flags&DDSD_DEPTH) != 0 {
+			texData.Depth = MAX(1, int(image.Header.Depth))
+		}
+	}
+	if err := texData.validate(); err != nil {
+		return err
+	}
+	// Header values come straight from file, huge ones would make us allocate surfaces data can't have
+	if texData.MipLevels > 32 || texData.Width > 1<<16 || texData.Height > 1<<16 || texData.Depth > 1<<16 || texData.Layers > 1<<16 {
+		return errors.New("DDS texture is too large")
+	}
+
+	if fnConvert == nil {
+		if err := texData.ReadTextureSurfaces(data); err != nil {
+			return err
 		}
+		image.Data = texData
+		return nil
+	}
 
-		// Skip this slice
-		data = data[blocksX*blocksY*16:]
-	}
-}
-
-// Function to parse DXT5 compressed DDS image
-func ddsParseDXT5(image *DDSImage, data []byte, dataLength int) {
-	imgWidth := MAX(1, int(image.Header.Width))
-	imgHeight := MAX(1, int(image.Header.Height))
-	imgDepth := MAX(1, int(image.Header.Depth))
-
-	blocksX := MAX(1, imgWidth/4)
-	blocksY := MAX(1, imgHeight/4)
-
-	for z := 0; z < imgDepth; z++ {
-		for x := 0; x < blocksX; x++ {
-			for y := 0; y < blocksY; y++ {
-				var color0, color1 uint16
-				var codes uint32
-				var alphaCodes uint64
-				var alpha0, alpha1 DDSByte
-
-				// Read the block data
-				blockOffset := (y*blocksX + x) * 16
-				alpha0 = DDSByte(data[blockOffset])
-				alpha1 = DDSByte(data[blockOffset+1])
-				alphaCodes = binary.LittleEndian.Uint64(data[blockOffset+2:])
-				color0 = binary.LittleEndian.Uint16(data[blockOffset+8:])
-				color1 = binary.LittleEndian.Uint16(data[blockOffset+10:])
-				codes = binary.LittleEndian.Uint32(data[blockOffset+12:])
-
-				// Unpack the color data
-				r0 := DDSByte((color0 & 0b1111100000000000) >> 8)
-				g0 := DDSByte((color0 & 0b0000011111100000) >> 3)
-				b0 := DDSByte((color0 & 0b0000000000011111) << 3)
-				r1 := DDSByte((color1 & 0b1111100000000000) >> 8)
-				g1 := DDSByte((color1 & 0b0000011111100000) >> 3)
-				b1 := DDSByte((color1 & 0b0000000000011111) << 3)
-
-				// Process the data
-				for b := 0; b < 16; b++ {
-					pxIndex := (z*imgHeight*imgWidth + (imgHeight-((y*4)+b/4)-1)*imgWidth + x*4 + b%4) * 4
-					code := (codes >> (2 * b)) & 0b0011
-					alphaCode := (alphaCodes >> (3 * b)) & 0b0111
-
-					// Color
-					switch code {
-					case 0:
-						image.Pixels[pxIndex+0] = r0
-						image.Pixels[pxIndex+1] = g0
-						image.Pixels[pxIndex+2] = b0
-					case 1:
-						image.Pixels[pxIndex+0] = r1
-						image.Pixels[pxIndex+1] = g1
-						image.Pixels[pxIndex+2] = b1
-					case 2:
-						image.Pixels[pxIndex+0] = (2*r0 + r1) / 3
-						image.Pixels[pxIndex+1] = (2*g0 + g1) / 3
-						image.Pixels[pxIndex+2] = (2*b0 + b1) / 3
-					case 3:
-						image.Pixels[pxIndex+0] = (r0 + 2*r1) / 3
-						image.Pixels[pxIndex+1] = (g0 + 2*g1) / 3
-						image.Pixels[pxIndex+2] = (b0 + 2*b1) / 3
-					}
-
-					// Alpha
-					alpha := DDSByte(0xFF)
-					switch {
-					case alphaCode == 0:
-						alpha = alpha0
-					case alphaCode == 1:
-						alpha = alpha1
-					default:
-						if alpha0 > alpha1 {
-							alpha = DDSByte(((8-int(alphaCode))*int(alpha0) + (int(alphaCode)-1)*int(alpha1)) / 7)
-						} else {
-							switch alphaCode {
-							case 6:
-								alpha = 0
-							case 7:
-								alpha = 255
-							default:
-								alpha = DDSByte(((6-int(alphaCode))*int(alpha0) + (int(alphaCode)-1)*int(alpha1)) / 5)
-							}
-						}
-					}
-					image.Pixels[pxIndex+3] = alpha
+	// Stored layout differs from RGBA8 one, so surfaces are cut with the stored size and converted one by one
+	for iLayer := 0; iLayer < texData.Layers; iLayer++ {
+		for iFace := 0; iFace < texData.Faces; iFace++ {
+			for iMip := 0; iMip < texData.MipLevels; iMip++ {
+				surface := TextureSurface{Width: GetMipSize(texData.Width, iMip), Height: GetMipSize(texData.Height, iMip), Depth: GetMipSize(texData.Depth, iMip)}
+				iSize := fnSize(surface.Width, surface.Height) * surface.Depth
+				if len(data) < iSize {
+					return fmt.Errorf("DDS data is truncated at layer %d, face %d, mip level %d", iLayer, iFace, iMip)
+				}
+				surface.Data, err = fnConvert(data[:iSize], surface.Width, surface.Height, surface.Depth)
+				if err != nil {
+					return err
 				}
+				data = data[iSize:]
+				texData.Surfaces = append(texData.Surfaces, surface)
 			}
 		}
-
-		// Skip this slice
-		data = data[blocksX*blocksY*16:]
 	}
+	image.Data = texData
+	return nil
 }
 
 // Constants
 const DDSMagic = 0x20534444 // 'DDS '
-// Load DDS image from memory
-func ddsLoadFromMemory(data []byte) (*DDSImage, error) {
+// Parse DDS headers and surfaces from memory, without decoding anything
+func ddsParse(data []byte) (*DDSImage, error) {
 	dataReader := bytes.NewReader(data)
 
 	var magic uint32
@@ -566,53 +633,30 @@ func ddsLoadFromMemory(data []byte) (*DDSImage, error) {
 		}
 	}
 
-	// Allocate pixel data
-	imgWidth := MAX(1, int(ret.Header.Width))
-	imgHeight := MAX(1, int(ret.Header.Height))
-	imgDepth := MAX(1, int(ret.Header.Depth))
-	ret.Pixels = make([]DDSByte, imgWidth*imgHeight*imgDepth*4)
+	if err := ddsReadSurfaces(ret, data[len(data)-dataReader.Len():]); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
 
-	// Parse/decompress the pixel data
-	dataLoc, err := io.ReadAll(dataReader)
+// Load DDS image from memory, decoding top mip level to Pixels
+func ddsLoadFromMemory(data []byte) (*DDSImage, error) {
+	ret, err := ddsParse(data)
 	if err != nil {
 		return nil, err
 	}
-	dataLength := len(dataLoc)
-	if (ret.Header.PixelFormat.Flags & DDPF_FOURCC) != 0 {
-		switch ret.Header.PixelFormat.FourCC {
-		case FOURCC("DXT1"):
-			ddsParseDXT1(ret, dataLoc, dataLength)
-		case FOURCC("DXT2"):
-			ddsParseDXT3(ret, dataLoc, dataLength)
-		case FOURCC("DXT3"):
-			ddsParseDXT3(ret, dataLoc, dataLength)
-		case FOURCC("DXT4"):
-			ddsParseDXT5(ret, dataLoc, dataLength)
-		case FOURCC("DXT5"):
-			ddsParseDXT5(ret, dataLoc, dataLength)
-		case FOURCC("DX10"):
-			// ddsParseDXT10
-		case FOURCC("ATI1"):
-			// ddsParseATI1
-		case FOURCC("ATI2"):
-			// ddsParseATI2
-		case FOURCC("A2XY"):
-			// ddsParseA2XY
-		default:
-			ddsParseUncompressed(ret, dataLoc, dataLength)
-		}
+
+	pixels, err := ret.Data.DecompressSurface(&ret.Data.Surfaces[0])
+	if err != nil {
+		return nil, err
 	}
+	ret.Pixels = *(*[]DDSByte)(unsafe.Pointer(&pixels))
 
 	return ret, nil
 }
 
 func ddsLoadFromFileFromReader(file io.Reader) (*DDSImage, error) {
 	// Read the whole file
-	//ddsData := make([]byte, fileSize)
-	//_, err := file.Read(ddsData)
-	//if err != nil && err != io.EOF {
-	//	return nil, err
-	//}
 	ddsData, err := io.ReadAll(file)
 	if err != nil {
 		return nil, err
@@ -626,6 +670,39 @@ func ddsLoadFromFileFromReader(file io.Reader) (*DDSImage, error) {
 	return ddsImage, nil
 }
 
+/*-----------------------------------------------
+
+  Name:	LoadDDS
+
+  Params:	r - DDS file contents
+
+  Result:	Returns every surface of the texture in
+  		its stored format, nothing is decoded
+  		unless the format has to be.
+
+  /*---------------------------------------------*/
+
+func LoadDDS(r io.Reader) (*TextureData, error) {
+	ddsData, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	ddsImage, err := ddsParse(ddsData)
+	if err != nil {
+		return nil, err
+	}
+	return ddsImage.Data, nil
+}
+
+func LoadDDSFile(sPath string) (*TextureData, error) {
+	file, err := OpenAsset(sPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadDDS(file)
+}
+
 // Load DDS image from file
 func ddsLoadFromFile(filename string) (*DDSImage, error) {
 	// Open the file
@@ -650,4 +727,5 @@ func ddsLoadFromFile(filename string) (*DDSImage, error) {
 // Free DDS image
 func ddsImageFree(image *DDSImage) {
 	image.Pixels = nil
+	image.Data = nil
 }
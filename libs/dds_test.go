@@ -0,0 +1,172 @@
+package libs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadReferencePNG reads reference image of testdata, made by gen_dds.py
+func loadReferencePNG(t *testing.T, sName string) *image.NRGBA {
+	t.Helper()
+	file, err := os.Open(filepath.Join("testdata", sName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, bOK := img.(*image.NRGBA)
+	if !bOK {
+		t.Fatalf("%s isn't RGBA PNG", sName)
+	}
+	return result
+}
+
+func TestDDSGolden(t *testing.T) {
+	vCases := []struct {
+		sName   string
+		format  TexelFormat
+		iLayers int
+	}{
+		{"bc4", TEXEL_FORMAT_BC4, 1},
+		{"bc5", TEXEL_FORMAT_BC5, 1},
+		{"bc6h", TEXEL_FORMAT_BC6H, 1},
+		{"bc7", TEXEL_FORMAT_BC7, 1},
+		{"bc6h_regions", TEXEL_FORMAT_BC6H, 1},
+		{"bc7_subsets", TEXEL_FORMAT_BC7, 1},
+		{"dx10_rgba8_array", TEXEL_FORMAT_RGBA8, 2},
+	}
+	for _, testCase := range vCases {
+		t.Run(testCase.sName, func(t *testing.T) {
+			bData, err := os.ReadFile(filepath.Join("testdata", testCase.sName+".dds"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			texData, err := LoadDDS(bytes.NewReader(bData))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if texData.Format != testCase.format || texData.Layers != testCase.iLayers {
+				t.Fatalf("got %v with %d layers, want %v with %d", texData.Format, texData.Layers, testCase.format, testCase.iLayers)
+			}
+			imgReference := loadReferencePNG(t, testCase.sName+".png")
+			if imgReference.Rect.Dx() != texData.Width || imgReference.Rect.Dy() != texData.Height*texData.Layers {
+				t.Fatalf("texture is %dx%d, reference %v", texData.Width, texData.Height, imgReference.Rect)
+			}
+			// Layers are stacked vertically in reference
+			var bDecoded []byte
+			for iLayer := 0; iLayer < texData.Layers; iLayer++ {
+				bPixels, err := texData.DecompressSurface(texData.GetSurface(iLayer, 0, 0))
+				if err != nil {
+					t.Fatal(err)
+				}
+				bDecoded = append(bDecoded, bPixels...)
+			}
+			if len(bDecoded) != len(imgReference.Pix) {
+				t.Fatalf("decoded %d bytes, reference has %d", len(bDecoded), len(imgReference.Pix))
+			}
+			for i := range bDecoded {
+				if bDecoded[i] != imgReference.Pix[i] {
+					iTexel := i / 4
+					t.Fatalf("texel %d,%d channel %d is %d, reference %d", iTexel%texData.Width, iTexel/texData.Width,
+						i%4, bDecoded[i], imgReference.Pix[i])
+				}
+			}
+		})
+	}
+}
+
+// TestDDSImageDecode checks that image.Decode gives top mip as image
+func TestDDSImageDecode(t *testing.T) {
+	bData, err := os.ReadFile(filepath.Join("testdata", "bc7.dds"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, sFormat, err := image.Decode(bytes.NewReader(bData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	imgReference := loadReferencePNG(t, "bc7.png")
+	if sFormat != "dds" || img.Bounds() != imgReference.Rect {
+		t.Fatalf("got %s image of %v", sFormat, img.Bounds())
+	}
+	for y := 0; y < imgReference.Rect.Dy(); y++ {
+		for x := 0; x < imgReference.Rect.Dx(); x++ {
+			if img.At(x, y) != imgReference.NRGBAAt(x, y) {
+				t.Fatalf("texel %d,%d is %v, reference %v", x, y, img.At(x, y), imgReference.NRGBAAt(x, y))
+			}
+		}
+	}
+}
+
+// patchDDSHeader returns copy of fixture with 32-bit header fields at offsets changed
+func patchDDSHeader(t *testing.T, sName string, mFields map[int]uint32) []byte {
+	t.Helper()
+	bData, err := os.ReadFile(filepath.Join("testdata", sName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for iOffset, iValue := range mFields {
+		binary.LittleEndian.PutUint32(bData[iOffset:], iValue)
+	}
+	return bData
+}
+
+func TestDDSRejectsBadHeaders(t *testing.T) {
+	// Offsets count the magic, DX10 header follows the 124 bytes of the main one
+	const iFlags, iMipmapCount, iFourCC, iDXGIFormat, iArraySize = 8, 28, 84, 128, 140
+	const iMipFlags = DDSD_CAPS | DDSD_HEIGHT | DDSD_WIDTH | DDSD_PIXELFORMAT | DDSD_MIPMAPCOUNT
+	vCases := []struct {
+		sName    string
+		sFile    string
+		mFields  map[int]uint32
+		sMessage string
+	}{
+		{"unsupported FourCC", "bc4.dds", map[int]uint32{iFourCC: uint32(FOURCC("ETC1"))}, "unsupported DDS format"},
+		{"unsupported DXGI format", "bc7.dds", map[int]uint32{iDXGIFormat: 2}, "unsupported DXGI format"},
+		{"huge array", "dx10_rgba8_array.dds", map[int]uint32{iArraySize: 1 << 30}, "too large"},
+		{"huge mip count", "bc4.dds", map[int]uint32{iFlags: iMipFlags, iMipmapCount: 1 << 31}, "too large"},
+		{"missing mips", "bc4.dds", map[int]uint32{iFlags: iMipFlags, iMipmapCount: 4}, "truncated"},
+		{"missing layer", "dx10_rgba8_array.dds", map[int]uint32{iArraySize: 3}, "truncated"},
+	}
+	for _, testCase := range vCases {
+		t.Run(testCase.sName, func(t *testing.T) {
+			_, err := LoadDDS(bytes.NewReader(patchDDSHeader(t, testCase.sFile, testCase.mFields)))
+			if err == nil || !strings.Contains(err.Error(), testCase.sMessage) {
+				t.Fatalf("got error %v, want one containing %q", err, testCase.sMessage)
+			}
+		})
+	}
+}
+
+// Legacy FourCC headers don't tell colour space, DX10 ones do unless format is typeless
+func TestDDSColorSpace(t *testing.T) {
+	vCases := []struct {
+		sFile      string
+		mFields    map[int]uint32
+		bSRGB      bool
+		bSRGBKnown bool
+	}{
+		{"bc4.dds", nil, false, false},
+		{"bc7.dds", nil, false, true},
+		{"bc7.dds", map[int]uint32{128: DXGI_FORMAT_BC7_UNORM_SRGB}, true, true},
+		{"bc7.dds", map[int]uint32{128: DXGI_FORMAT_BC7_TYPELESS}, false, false},
+	}
+	for _, testCase := range vCases {
+		texData, err := LoadDDS(bytes.NewReader(patchDDSHeader(t, testCase.sFile, testCase.mFields)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if texData.SRGB != testCase.bSRGB || texData.SRGBKnown != testCase.bSRGBKnown {
+			t.Errorf("%s %v: SRGB %v, known %v", testCase.sFile, testCase.mFields, texData.SRGB, texData.SRGBKnown)
+		}
+	}
+}
@@ -0,0 +1,487 @@
+package libs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// MipFilter chooses how lower mip levels are resampled
+type MipFilter int
+
+const (
+	MIP_FILTER_BOX    MipFilter = iota // Average of covered texels, fast and soft
+	MIP_FILTER_KAISER                  // Kaiser windowed sinc, keeps more detail
+)
+
+// DDSEncodeOptions control EncodeDDS, zero value gives uncompressed RGBA8 with box filtered mips of sRGB image
+type DDSEncodeOptions struct {
+	Format    TexelFormat // TEXEL_FORMAT_BC1, TEXEL_FORMAT_BC3 or TEXEL_FORMAT_RGBA8
+	MipFilter MipFilter
+	NoMipmaps bool // Write only the top level
+	Linear    bool // Image holds linear data (normal maps, masks), so it's filtered without gamma conversion
+	DX10      bool // Write DX10 header, which records whether data is sRGB, but legacy readers can't load it
+}
+
+// Texel data of one mip level while filtering, RGBA in linear space with premultiplied alpha
+type mipLevel struct {
+	iWidth, iHeight int
+	vTexels         []float32
+}
+
+var vSRGBToLinear [256]float32
+
+func init() {
+	for i := range vSRGBToLinear {
+		vSRGBToLinear[i] = float32(SRGBToLinear(float64(i) / 255))
+	}
+}
+
+func SRGBToLinear(fValue float64) float64 {
+	if fValue <= 0.04045 {
+		return fValue / 12.92
+	}
+	return math.Pow((fValue+0.055)/1.055, 2.4)
+}
+
+func LinearToSRGB(fValue float64) float64 {
+	if fValue <= 0.0031308 {
+		return fValue * 12.92
+	}
+	return 1.055*math.Pow(fValue, 1/2.4) - 0.055
+}
+
+func newMipLevel(img *image.NRGBA, bLinear bool) *mipLevel {
+	iWidth, iHeight := img.Bounds().Dx(), img.Bounds().Dy()
+	level := &mipLevel{iWidth: iWidth, iHeight: iHeight, vTexels: make([]float32, iWidth*iHeight*4)}
+	for y := 0; y < iHeight; y++ {
+		for x := 0; x < iWidth; x++ {
+			px := img.Pix[y*img.Stride+x*4:]
+			fAlpha := float32(px[3]) / 255
+			for c := 0; c < 3; c++ {
+				fValue := float32(px[c]) / 255
+				if !bLinear {
+					fValue = vSRGBToLinear[px[c]]
+				}
+				level.vTexels[(y*iWidth+x)*4+c] = fValue * fAlpha
+			}
+			level.vTexels[(y*iWidth+x)*4+3] = fAlpha
+		}
+	}
+	return level
+}
+
+// toPixels converts level back to RGBA8, undoing premultiplication and linearization.
+func (this *mipLevel) toPixels(bLinear bool) []byte {
+	pixels := make([]byte, this.iWidth*this.iHeight*4)
+	for i := 0; i < this.iWidth*this.iHeight; i++ {
+		fAlpha := math.Max(0, math.Min(1, float64(this.vTexels[i*4+3])))
+		for c := 0; c < 3; c++ {
+			var fValue float64
+			if fAlpha > 0 {
+				fValue = math.Max(0, math.Min(1, float64(this.vTexels[i*4+c])/fAlpha))
+			}
+			if !bLinear {
+				fValue = LinearToSRGB(fValue)
+			}
+			pixels[i*4+c] = byte(fValue*255 + 0.5)
+		}
+		pixels[i*4+3] = byte(fAlpha*255 + 0.5)
+	}
+	return pixels
+}
+
+func besselI0(x float64) float64 {
+	// Power series converges fast for arguments Kaiser window uses
+	fSum, fTerm := 1.0, 1.0
+	for k := 1; k < 30; k++ {
+		fTerm *= (x / (2 * float64(k))) * (x / (2 * float64(k)))
+		fSum += fTerm
+	}
+	return fSum
+}
+
+// Kaiser window of width 3 and alpha 4 over sinc, in units of destination texels
+const (
+	KAISER_WIDTH = 3.0
+	KAISER_ALPHA = 4.0
+)
+
+func mipFilterWeight(filter MipFilter, x float64) float64 {
+	if filter == MIP_FILTER_BOX {
+		if x >= -0.5 && x < 0.5 {
+			return 1
+		}
+		return 0
+	}
+	if math.Abs(x) >= KAISER_WIDTH {
+		return 0
+	}
+	fSinc := 1.0
+	if x != 0 {
+		fSinc = math.Sin(math.Pi*x) / (math.Pi * x)
+	}
+	fWindow := x / KAISER_WIDTH
+	return fSinc * besselI0(KAISER_ALPHA*math.Sqrt(1-fWindow*fWindow)) / besselI0(KAISER_ALPHA)
+}
+
+/*-----------------------------------------------
+
+  Name:	resampleAxis
+
+  Params:	src - level to resample
+  		iSize - new size along the axis
+  		bHorizontal - resample width or height
+
+  Result:	Returns level resampled along one axis,
+  		texels outside the image repeat the edge.
+
+  /*---------------------------------------------*/
+
+func (this *mipLevel) resampleAxis(iSize int, bHorizontal bool, filter MipFilter) *mipLevel {
+	iSrcSize, iOther := this.iHeight, this.iWidth
+	result := &mipLevel{iWidth: this.iWidth, iHeight: iSize}
+	if bHorizontal {
+		iSrcSize, iOther = this.iWidth, this.iHeight
+		result.iWidth, result.iHeight = iSize, this.iHeight
+	}
+	if iSrcSize == iSize {
+		return this
+	}
+	result.vTexels = make([]float32, result.iWidth*result.iHeight*4)
+	fScale := float64(iSrcSize) / float64(iSize)
+	fSupport := 0.5
+	if filter == MIP_FILTER_KAISER {
+		fSupport = KAISER_WIDTH
+	}
+
+	vWeights := make([]float64, 0, int(2*fSupport*fScale)+2)
+	for i := 0; i < iSize; i++ {
+		// Texel centers in source coordinates
+		fCenter := (float64(i) + 0.5) * fScale
+		iFirst := int(math.Floor(fCenter - fSupport*fScale))
+		iLast := int(math.Ceil(fCenter + fSupport*fScale))
+		vWeights = vWeights[:0]
+		var fTotal float64
+		for s := iFirst; s <= iLast; s++ {
+			w := mipFilterWeight(filter, (float64(s)+0.5-fCenter)/fScale)
+			vWeights = append(vWeights, w)
+			fTotal += w
+		}
+		if fTotal == 0 {
+			continue
+		}
+		for o := 0; o < iOther; o++ {
+			var vSum [4]float64
+			for k, w := range vWeights {
+				if w == 0 {
+					continue
+				}
+				s := MIN(MAX(iFirst+k, 0), iSrcSize-1)
+				iIndex := (s*this.iWidth + o) * 4
+				if bHorizontal {
+					iIndex = (o*this.iWidth + s) * 4
+				}
+				for c := 0; c < 4; c++ {
+					vSum[c] += w * float64(this.vTexels[iIndex+c])
+				}
+			}
+			iIndex := (i*result.iWidth + o) * 4
+			if bHorizontal {
+				iIndex = (o*result.iWidth + i) * 4
+			}
+			for c := 0; c < 4; c++ {
+				// Negative lobes of Kaiser can overshoot
+				result.vTexels[iIndex+c] = float32(math.Max(0, vSum[c]/fTotal))
+			}
+		}
+	}
+	return result
+}
+
+/*-----------------------------------------------
+
+  Name:	GenerateMipmaps
+
+  Params:	img - top level
+  		filter - MIP_FILTER_BOX or MIP_FILTER_KAISER
+  		bLinear - img holds linear data, otherwise
+  		it's treated as sRGB and filtered in
+  		linear space
+
+  Result:	Returns RGBA8 texels of every level down
+  		to 1x1, the first one is img itself.
+
+  /*---------------------------------------------*/
+
+func GenerateMipmaps(img image.Image, filter MipFilter, bLinear bool) [][]byte {
+	nrgba := ToNRGBA(img)
+	level := newMipLevel(nrgba, bLinear)
+	vLevels := [][]byte{nrgba.Pix}
+	for level.iWidth > 1 || level.iHeight > 1 {
+		level = level.resampleAxis(MAX(1, level.iWidth/2), true, filter).resampleAxis(MAX(1, level.iHeight/2), false, filter)
+		vLevels = append(vLevels, level.toPixels(bLinear))
+	}
+	return vLevels
+}
+
+// ToNRGBA returns img as tightly packed *image.NRGBA starting at 0,0, converting if needed.
+func ToNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, bOK := img.(*image.NRGBA); bOK && nrgba.Rect.Min == (image.Point{}) && nrgba.Stride == nrgba.Rect.Dx()*4 {
+		return nrgba
+	}
+	bounds := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+	return nrgba
+}
+
+/*-----------------------------------------------
+
+  Name:	CompressImage
+
+  Params:	img - any image
+  		options - format and mip generation
+
+  Result:	Returns 2D texture with mip chain in
+  		requested format, ready for WriteDDS.
+
+  /*---------------------------------------------*/
+
+func CompressImage(img image.Image, options DDSEncodeOptions) (*TextureData, error) {
+	switch options.Format {
+	case TEXEL_FORMAT_BC1, TEXEL_FORMAT_BC3, TEXEL_FORMAT_RGBA8:
+	default:
+		return nil, fmt.Errorf("DDS encoder doesn't support %v", options.Format)
+	}
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return nil, errors.New("image is empty")
+	}
+
+	var vLevels [][]byte
+	if options.NoMipmaps {
+		vLevels = [][]byte{ToNRGBA(img).Pix}
+	} else {
+		vLevels = GenerateMipmaps(img, options.MipFilter, options.Linear)
+	}
+
+	texData := &TextureData{Format: options.Format, SRGB: !options.Linear, Width: bounds.Dx(), Height: bounds.Dy(),
+		Depth: 1, Faces: 1, Layers: 1, MipLevels: len(vLevels)}
+	for iMip, pixels := range vLevels {
+		surface := TextureSurface{Width: GetMipSize(texData.Width, iMip), Height: GetMipSize(texData.Height, iMip), Depth: 1, Data: pixels}
+		if options.Format.IsCompressed() {
+			surface.Data = bcCompress(pixels, surface.Width, surface.Height, options.Format)
+		}
+		texData.Surfaces = append(texData.Surfaces, surface)
+	}
+	return texData, nil
+}
+
+// ddsGetDXGIFormat is inverse of ddsGetDXGITexelFormat for formats WriteDDS can write.
+func ddsGetDXGIFormat(format TexelFormat, bSRGB bool) DDSUint {
+	switch format {
+	case TEXEL_FORMAT_BC1:
+		if bSRGB {
+			return DXGI_FORMAT_BC1_UNORM_SRGB
+		}
+		return DXGI_FORMAT_BC1_UNORM
+	case TEXEL_FORMAT_BC2:
+		if bSRGB {
+			return DXGI_FORMAT_BC2_UNORM_SRGB
+		}
+		return DXGI_FORMAT_BC2_UNORM
+	case TEXEL_FORMAT_BC3:
+		if bSRGB {
+			return DXGI_FORMAT_BC3_UNORM_SRGB
+		}
+		return DXGI_FORMAT_BC3_UNORM
+	case TEXEL_FORMAT_BC4:
+		return DXGI_FORMAT_BC4_UNORM
+	case TEXEL_FORMAT_BC4_SIGNED:
+		return DXGI_FORMAT_BC4_SNORM
+	case TEXEL_FORMAT_BC5:
+		return DXGI_FORMAT_BC5_UNORM
+	case TEXEL_FORMAT_BC5_SIGNED:
+		return DXGI_FORMAT_BC5_SNORM
+	case TEXEL_FORMAT_BC6H:
+		return DXGI_FORMAT_BC6H_UF16
+	case TEXEL_FORMAT_BC6H_SIGNED:
+		return DXGI_FORMAT_BC6H_SF16
+	case TEXEL_FORMAT_BC7:
+		if bSRGB {
+			return DXGI_FORMAT_BC7_UNORM_SRGB
+		}
+		return DXGI_FORMAT_BC7_UNORM
+	}
+	if bSRGB {
+		return DXGI_FORMAT_R8G8B8A8_UNORM_SRGB
+	}
+	return DXGI_FORMAT_R8G8B8A8_UNORM
+}
+
+/*-----------------------------------------------
+
+  Name:	WriteDDS
+
+  Params:	w - destination
+  		texData - texture to store
+
+  Result:	Writes DDS file with legacy header, so
+  		old tools can read it. DX10 header is used
+  		only for arrays and formats without FourCC.
+
+  /*---------------------------------------------*/
+
+func WriteDDS(w io.Writer, texData *TextureData) error {
+	return WriteDDSEx(w, texData, false)
+}
+
+// WriteDDSEx writes DDS file like WriteDDS, bDX10 forces DX10 header, which also stores whether texels are sRGB.
+func WriteDDSEx(w io.Writer, texData *TextureData, bDX10 bool) error {
+	if err := texData.validate(); err != nil {
+		return err
+	}
+	// DDS rows go from top to bottom, blocks of compressed data can't be turned over without decoding them
+	if texData.BottomUp {
+		if texData.Format.IsCompressed() {
+			return errors.New("bottom-up compressed texture can't be written to DDS")
+		}
+		var err error
+		if texData, err = texData.Decompress(); err != nil {
+			return err
+		}
+	}
+	header := DDSHeader{Size: 124, Flags: DDSD_CAPS | DDSD_HEIGHT | DDSD_WIDTH | DDSD_PIXELFORMAT,
+		Width: DDSUint(texData.Width), Height: DDSUint(texData.Height), Caps: DDSCAPS_TEXTURE}
+	header.PixelFormat.Size = 32
+	if texData.MipLevels > 1 {
+		header.Flags |= DDSD_MIPMAPCOUNT
+		header.MipmapCount = DDSUint(texData.MipLevels)
+		header.Caps |= DDSCAPS_COMPLEX | DDSCAPS_MIPMAP
+	}
+	if texData.IsCubemap() {
+		header.Caps |= DDSCAPS_COMPLEX
+		header.Caps2 |= DDSCAPS2_CUBEMAP | DDSCAPS2_CUBEMAP_POSITIVEX | DDSCAPS2_CUBEMAP_NEGATIVEX | DDSCAPS2_CUBEMAP_POSITIVEY |
+			DDSCAPS2_CUBEMAP_NEGATIVEY | DDSCAPS2_CUBEMAP_POSITIVEZ | DDSCAPS2_CUBEMAP_NEGATIVEZ
+	}
+	if texData.IsVolume() {
+		header.Flags |= DDSD_DEPTH
+		header.Depth = DDSUint(texData.Depth)
+		header.Caps |= DDSCAPS_COMPLEX
+		header.Caps2 |= DDSCAPS2_VOLUME
+	}
+	if texData.Format.IsCompressed() {
+		header.Flags |= DDSD_LINEARSIZE
+		header.PitchOrLinearSize = DDSUint(texData.Format.GetDataSize(texData.Width, texData.Height))
+	} else {
+		header.Flags |= DDSD_PITCH
+		header.PitchOrLinearSize = DDSUint(texData.Width * 4)
+	}
+
+	bDX10 = bDX10 || texData.Layers > 1
+	switch {
+	case bDX10:
+		header.PixelFormat.Flags = DDPF_FOURCC
+		header.PixelFormat.FourCC = FOURCC("DX10")
+	case texData.Format == TEXEL_FORMAT_BC1:
+		header.PixelFormat.Flags, header.PixelFormat.FourCC = DDPF_FOURCC, FOURCC("DXT1")
+	case texData.Format == TEXEL_FORMAT_BC2:
+		header.PixelFormat.Flags, header.PixelFormat.FourCC = DDPF_FOURCC, FOURCC("DXT3")
+	case texData.Format == TEXEL_FORMAT_BC3:
+		header.PixelFormat.Flags, header.PixelFormat.FourCC = DDPF_FOURCC, FOURCC("DXT5")
+	case texData.Format == TEXEL_FORMAT_BC4:
+		header.PixelFormat.Flags, header.PixelFormat.FourCC = DDPF_FOURCC, FOURCC("ATI1")
+	case texData.Format == TEXEL_FORMAT_BC5:
+		header.PixelFormat.Flags, header.PixelFormat.FourCC = DDPF_FOURCC, FOURCC("ATI2")
+	case texData.Format == TEXEL_FORMAT_RGBA8:
+		header.PixelFormat = DDSPixelFormat{Size: 32, Flags: DDPF_RGB | DDPF_ALPHAPIXELS, RGBBitCount: 32,
+			RBitMask: 0xFF, GBitMask: 0xFF00, BBitMask: 0xFF0000, ABitMask: 0xFF000000}
+	default:
+		// Signed and BPTC formats have no FourCC
+		bDX10 = true
+		header.PixelFormat.Flags = DDPF_FOURCC
+		header.PixelFormat.FourCC = FOURCC("DX10")
+	}
+
+	bw := bufio.NewWriter(w)
+	binary.Write(bw, binary.LittleEndian, uint32(DDSMagic))
+	binary.Write(bw, binary.LittleEndian, &header)
+	if bDX10 {
+		headerDXT10 := DDSHeaderDXT10{DXGIFormat: ddsGetDXGIFormat(texData.Format, texData.SRGB), ResourceDimension: 3,
+			ArraySize: DDSUint(texData.Layers)}
+		if texData.IsCubemap() {
+			headerDXT10.MiscFlag = DDS_RESOURCE_MISC_TEXTURECUBE
+		}
+		if texData.IsVolume() {
+			headerDXT10.ResourceDimension = DDS_DIMENSION_TEXTURE3D
+		}
+		binary.Write(bw, binary.LittleEndian, &headerDXT10)
+	}
+	for i := range texData.Surfaces {
+		if _, err := bw.Write(texData.Surfaces[i].Data); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// EncodeDDS compresses img with its mip chain and writes it as DDS file.
+func EncodeDDS(w io.Writer, img image.Image, options DDSEncodeOptions) error {
+	texData, err := CompressImage(img, options)
+	if err != nil {
+		return err
+	}
+	return WriteDDSEx(w, texData, options.DX10)
+}
+
+/*-----------------------------------------------
+
+  Name:	ConvertToDDS
+
+  Params:	sSource - image file Go can decode
+  		sDestination - DDS file to write
+  		options - format and mip generation
+
+  Result:	Converts image file to DDS, meant for
+  		pre-compressing textures of the assets.
+
+  /*---------------------------------------------*/
+
+func ConvertToDDS(sSource, sDestination string, options DDSEncodeOptions) error {
+	file, err := os.Open(sSource)
+	if err != nil {
+		return err
+	}
+	img, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %v", sSource, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sDestination), 0755); err != nil {
+		return err
+	}
+	// Write to temporary file first, so that a failed conversion doesn't leave broken texture behind
+	sTmpPath := sDestination + ".tmp"
+	out, err := os.Create(sTmpPath)
+	if err != nil {
+		return err
+	}
+	err = EncodeDDS(out, img, options)
+	if errClose := out.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		os.Remove(sTmpPath)
+		return err
+	}
+	return os.Rename(sTmpPath, sDestination)
+}
@@ -0,0 +1,156 @@
+package libs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// newGradientImage returns image with colour changing along x, which BC1 palette line can follow, alpha changes along y if bAlpha
+func newGradientImage(iWidth, iHeight int, bAlpha bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, iWidth, iHeight))
+	for y := 0; y < iHeight; y++ {
+		for x := 0; x < iWidth; x++ {
+			var iAlpha uint8 = 255
+			if bAlpha {
+				iAlpha = uint8(255 * y / (iHeight - 1))
+			}
+			img.SetNRGBA(x, y, color.NRGBA{uint8(255 * x / (iWidth - 1)), uint8(255 - 255*x/(iWidth-1)), 128, iAlpha})
+		}
+	}
+	return img
+}
+
+// newBoxMipmaps averages 2x2 texels in linear space with premultiplied alpha down to 1x1, as box filter of sRGB image should
+func newBoxMipmaps(img *image.NRGBA) [][]byte {
+	iWidth, iHeight := img.Rect.Dx(), img.Rect.Dy()
+	vTexels := make([]float64, len(img.Pix))
+	for i := 0; i < len(img.Pix); i += 4 {
+		fAlpha := float64(img.Pix[i+3]) / 255
+		for c := 0; c < 3; c++ {
+			vTexels[i+c] = SRGBToLinear(float64(img.Pix[i+c])/255) * fAlpha
+		}
+		vTexels[i+3] = fAlpha
+	}
+	vLevels := [][]byte{img.Pix}
+	for iWidth > 1 || iHeight > 1 {
+		iNewWidth, iNewHeight := MAX(1, iWidth/2), MAX(1, iHeight/2)
+		vNewTexels := make([]float64, iNewWidth*iNewHeight*4)
+		pixels := make([]byte, len(vNewTexels))
+		for y := 0; y < iNewHeight; y++ {
+			for x := 0; x < iNewWidth; x++ {
+				iIndex := (y*iNewWidth + x) * 4
+				var iCount int
+				for sy := y * iHeight / iNewHeight; sy < (y+1)*iHeight/iNewHeight; sy++ {
+					for sx := x * iWidth / iNewWidth; sx < (x+1)*iWidth/iNewWidth; sx++ {
+						for c := 0; c < 4; c++ {
+							vNewTexels[iIndex+c] += vTexels[(sy*iWidth+sx)*4+c]
+						}
+						iCount++
+					}
+				}
+				for c := 0; c < 4; c++ {
+					vNewTexels[iIndex+c] /= float64(iCount)
+				}
+				fAlpha := vNewTexels[iIndex+3]
+				for c := 0; c < 3; c++ {
+					var fValue float64
+					if fAlpha > 0 {
+						fValue = vNewTexels[iIndex+c] / fAlpha
+					}
+					pixels[iIndex+c] = byte(LinearToSRGB(fValue)*255 + 0.5)
+				}
+				pixels[iIndex+3] = byte(fAlpha*255 + 0.5)
+			}
+		}
+		vLevels = append(vLevels, pixels)
+		iWidth, iHeight, vTexels = iNewWidth, iNewHeight, vNewTexels
+	}
+	return vLevels
+}
+
+func TestDDSEncodeRoundTrip(t *testing.T) {
+	vCases := []struct {
+		sName      string
+		options    DDSEncodeOptions
+		bAlpha     bool
+		iFourCC    DDSUint
+		iTolerance int
+	}{
+		{"DXT1", DDSEncodeOptions{Format: TEXEL_FORMAT_BC1}, false, FOURCC("DXT1"), 8},
+		// Smaller mips squeeze the whole alpha ramp into one block, its 8 alpha levels are about 31 apart
+		{"DXT5", DDSEncodeOptions{Format: TEXEL_FORMAT_BC3}, true, FOURCC("DXT5"), 16},
+		{"RGBA8", DDSEncodeOptions{Format: TEXEL_FORMAT_RGBA8, NoMipmaps: true}, true, 0, 0},
+		{"DX10 BC1", DDSEncodeOptions{Format: TEXEL_FORMAT_BC1, DX10: true}, false, FOURCC("DX10"), 8},
+		// Zero value is RGBA8 with mips, filtering in float may round last bit differently
+		{"zero value", DDSEncodeOptions{}, true, 0, 1},
+	}
+	for _, testCase := range vCases {
+		t.Run(testCase.sName, func(t *testing.T) {
+			img := newGradientImage(16, 8, testCase.bAlpha)
+			var buf bytes.Buffer
+			if err := EncodeDDS(&buf, img, testCase.options); err != nil {
+				t.Fatal(err)
+			}
+			// FourCC sits after magic and 80 bytes of header
+			var header DDSHeader
+			binary.Read(bytes.NewReader(buf.Bytes()[4:]), binary.LittleEndian, &header)
+			if header.PixelFormat.FourCC != testCase.iFourCC {
+				t.Fatalf("FourCC is %s, want %s", ddsFourCCString(header.PixelFormat.FourCC), ddsFourCCString(testCase.iFourCC))
+			}
+
+			texData, err := LoadDDS(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			iMipLevels := 5 // 16x8 down to 1x1
+			if testCase.options.NoMipmaps {
+				iMipLevels = 1
+			}
+			if texData.Format != testCase.options.Format || texData.Width != 16 || texData.Height != 8 || texData.MipLevels != iMipLevels {
+				t.Fatalf("got %v %dx%d with %d mips", texData.Format, texData.Width, texData.Height, texData.MipLevels)
+			}
+			if texData.SRGBKnown != testCase.options.DX10 || (testCase.options.DX10 && !texData.SRGB) {
+				t.Fatalf("SRGB %v, known %v", texData.SRGB, texData.SRGBKnown)
+			}
+			vReference := newBoxMipmaps(img)
+			for iMip := 0; iMip < texData.MipLevels; iMip++ {
+				bPixels, err := texData.DecompressSurface(&texData.Surfaces[iMip])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(bPixels) != len(vReference[iMip]) {
+					t.Fatalf("mip %d has %d bytes, want %d", iMip, len(bPixels), len(vReference[iMip]))
+				}
+				for i := range bPixels {
+					if iDiff := int(bPixels[i]) - int(vReference[iMip][i]); iDiff < -testCase.iTolerance || iDiff > testCase.iTolerance {
+						t.Fatalf("mip %d texel %d channel %d is %d, reference %d", iMip, i/4, i%4, bPixels[i], vReference[iMip][i])
+					}
+				}
+			}
+		})
+	}
+}
+
+// Arrays can't be stored in legacy header, so WriteDDS has to switch to DX10 one
+func TestWriteDDSArray(t *testing.T) {
+	texData, err := CompressImage(newGradientImage(4, 4, false), DDSEncodeOptions{Format: TEXEL_FORMAT_BC1, NoMipmaps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	texData.Layers = 2
+	texData.Surfaces = append(texData.Surfaces, texData.Surfaces[0])
+	var buf bytes.Buffer
+	if err := WriteDDS(&buf, texData); err != nil {
+		t.Fatal(err)
+	}
+	result, err := LoadDDS(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Layers != 2 || !bytes.Equal(result.GetSurface(1, 0, 0).Data, texData.Surfaces[1].Data) {
+		t.Fatalf("got %d layers", result.Layers)
+	}
+}
@@ -0,0 +1,1178 @@
+package libs
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	gltfMagic     = 0x46546C67 // "glTF"
+	gltfChunkJSON = 0x4E4F534A
+	gltfChunkBIN  = 0x004E4942
+
+	gltfModeTriangles     = 4
+	gltfModeTriangleStrip = 5
+	gltfModeTriangleFan   = 6
+
+	gltfComponentByte          = 5120
+	gltfComponentUnsignedByte  = 5121
+	gltfComponentShort         = 5122
+	gltfComponentUnsignedShort = 5123
+	gltfComponentUnsignedInt   = 5125
+	gltfComponentFloat         = 5126
+
+	// Accessors without buffer view hold zeros, nothing in the file bounds their size
+	gltfMaxZeroElements = 1 << 24
+)
+
+// JSON part of glTF file, only what the loader reads
+type gltfDocument struct {
+	Asset struct {
+		Version string `json:"version"`
+	} `json:"asset"`
+	ExtensionsRequired []string `json:"extensionsRequired"`
+	Scene              *int     `json:"scene"`
+	Scenes             []struct {
+		Nodes []int `json:"nodes"`
+	} `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes"`
+	Meshes      []gltfMesh       `json:"meshes"`
+	Accessors   []gltfAccessor   `json:"accessors"`
+	BufferViews []gltfBufferView `json:"bufferViews"`
+	Buffers     []gltfBuffer     `json:"buffers"`
+	Materials   []gltfMaterial   `json:"materials"`
+	Textures    []gltfTexture    `json:"textures"`
+	Images      []gltfImage      `json:"images"`
+	Skins       []gltfSkin       `json:"skins"`
+	Animations  []gltfAnimation  `json:"animations"`
+}
+
+type gltfNode struct {
+	Name        string       `json:"name"`
+	Children    []int        `json:"children"`
+	Mesh        *int         `json:"mesh"`
+	Skin        *int         `json:"skin"`
+	Matrix      *[16]float32 `json:"matrix"`
+	Translation *[3]float32  `json:"translation"`
+	Rotation    *[4]float32  `json:"rotation"`
+	Scale       *[3]float32  `json:"scale"`
+}
+
+type gltfMesh struct {
+	Name       string          `json:"name"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices"`
+	Material   *int           `json:"material"`
+	Mode       *int           `json:"mode"`
+}
+
+type gltfSkin struct {
+	Name                string `json:"name"`
+	InverseBindMatrices *int   `json:"inverseBindMatrices"`
+	Joints              []int  `json:"joints"`
+}
+
+type gltfAnimation struct {
+	Name     string `json:"name"`
+	Channels []struct {
+		Sampler int `json:"sampler"`
+		Target  struct {
+			Node *int   `json:"node"`
+			Path string `json:"path"`
+		} `json:"target"`
+	} `json:"channels"`
+	Samplers []struct {
+		Input         int    `json:"input"`
+		Output        int    `json:"output"`
+		Interpolation string `json:"interpolation"`
+	} `json:"samplers"`
+}
+
+type gltfAccessor struct {
+	BufferView    *int        `json:"bufferView"`
+	ByteOffset    int         `json:"byteOffset"`
+	ComponentType int         `json:"componentType"`
+	Normalized    bool        `json:"normalized"`
+	Count         int         `json:"count"`
+	Type          string      `json:"type"`
+	Sparse        *gltfSparse `json:"sparse"`
+}
+
+// gltfSparse replaces some elements of accessor, values are tightly packed
+type gltfSparse struct {
+	Count   int `json:"count"`
+	Indices struct {
+		BufferView    int `json:"bufferView"`
+		ByteOffset    int `json:"byteOffset"`
+		ComponentType int `json:"componentType"`
+	} `json:"indices"`
+	Values struct {
+		BufferView int `json:"bufferView"`
+		ByteOffset int `json:"byteOffset"`
+	} `json:"values"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride"`
+}
+
+type gltfBuffer struct {
+	URI        string `json:"uri"`
+	ByteLength int    `json:"byteLength"`
+}
+
+type gltfTextureInfo struct {
+	Index    int      `json:"index"`
+	TexCoord int      `json:"texCoord"`
+	Scale    *float32 `json:"scale"`    // Normal textures only
+	Strength *float32 `json:"strength"` // Occlusion textures only
+}
+
+type gltfMaterial struct {
+	Name                 string `json:"name"`
+	PbrMetallicRoughness *struct {
+		BaseColorFactor          *[4]float32      `json:"baseColorFactor"`
+		BaseColorTexture         *gltfTextureInfo `json:"baseColorTexture"`
+		MetallicFactor           *float32         `json:"metallicFactor"`
+		RoughnessFactor          *float32         `json:"roughnessFactor"`
+		MetallicRoughnessTexture *gltfTextureInfo `json:"metallicRoughnessTexture"`
+	} `json:"pbrMetallicRoughness"`
+	NormalTexture    *gltfTextureInfo `json:"normalTexture"`
+	OcclusionTexture *gltfTextureInfo `json:"occlusionTexture"`
+	EmissiveTexture  *gltfTextureInfo `json:"emissiveTexture"`
+	EmissiveFactor   *[3]float32      `json:"emissiveFactor"`
+	AlphaMode        string           `json:"alphaMode"`
+	AlphaCutoff      *float32         `json:"alphaCutoff"`
+	DoubleSided      bool             `json:"doubleSided"`
+}
+
+type gltfTexture struct {
+	Source *int `json:"source"`
+}
+
+type gltfImage struct {
+	Name       string `json:"name"`
+	URI        string `json:"uri"`
+	MimeType   string `json:"mimeType"`
+	BufferView *int   `json:"bufferView"`
+}
+
+// GLTFImage is image used by materials, either a file or encoded image stored in the model
+type GLTFImage struct {
+	Path     string // Asset path of image file, for embedded images model path with #image and index
+	Data     []byte // Encoded embedded image, nil for image files
+	MimeType string
+}
+
+// GLTFMaterial holds metallic-roughness parameters, textures are indices to GLTFModel.Images, -1 if missing
+type GLTFMaterial struct {
+	Name string
+
+	BaseColorFactor [4]float32
+	MetallicFactor  float32
+	RoughnessFactor float32
+	EmissiveFactor  [3]float32
+
+	NormalScale       float32
+	OcclusionStrength float32
+
+	BaseColorTexture         int
+	MetallicRoughnessTexture int // Roughness in green, metalness in blue channel
+	NormalTexture            int
+	OcclusionTexture         int // Occlusion in red channel
+	EmissiveTexture          int
+
+	AlphaMode   string // OPAQUE, MASK or BLEND
+	AlphaCutoff float32
+	DoubleSided bool
+}
+
+// DefaultGLTFMaterial returns material of primitives without one, as glTF specification defines it.
+func DefaultGLTFMaterial() GLTFMaterial {
+	return GLTFMaterial{BaseColorFactor: [4]float32{1, 1, 1, 1}, MetallicFactor: 1, RoughnessFactor: 1,
+		NormalScale: 1, OcclusionStrength: 1, BaseColorTexture: -1, MetallicRoughnessTexture: -1,
+		NormalTexture: -1, OcclusionTexture: -1, EmissiveTexture: -1, AlphaMode: "OPAQUE", AlphaCutoff: 0.5}
+}
+
+// GLTFPrimitive is one mesh part of one node, already placed in model space
+type GLTFPrimitive struct {
+	Name      string       // Name of node, or mesh if node has none
+	Positions [][3]float32 // Transformed by node's world matrix, skinned primitives stay in bind pose
+	Normals   [][3]float32 // Transformed by node's world matrix, nil if mesh has none
+	TexCoords [][2]float32 // First texture coordinate set, nil if mesh has none
+	Tangents  [][4]float32 // W is sign of bitangent, computed when file has none, nil without normals or texture coordinates
+	Indices   []uint32     // Triangle list, counter-clockwise
+	Material  int          // Index to GLTFModel.Materials, -1 for default material
+	Skin      int          // Index to GLTFModel.Skins, -1 for primitives that aren't skinned
+	Skinning  []VertexSkin // Joints and weights of each vertex, nil if primitive isn't skinned
+}
+
+// GLTFModel is glTF scene flattened to primitives
+type GLTFModel struct {
+	Primitives []GLTFPrimitive
+	Materials  []GLTFMaterial
+	Images     []GLTFImage
+	Skeleton   *Skeleton // Every node of the file, animations and skins refer to them
+	Skins      []Skin
+	Animations []*AnimationClip
+}
+
+// gltfLoader keeps state while one file is loaded
+type gltfLoader struct {
+	sPath       string
+	doc         gltfDocument
+	vBuffers    [][]byte
+	model       *GLTFModel
+	vNodeToBone []int // Skeleton node of each document node
+}
+
+// IsGLTFFile tells by extension whether file is glTF model.
+func IsGLTFFile(sPath string) bool {
+	switch strings.ToLower(filepath.Ext(sPath)) {
+	case ".gltf", ".glb":
+		return true
+	}
+	return false
+}
+
+/*-----------------------------------------------
+
+  Name:	LoadGLTFFile
+
+  Params:	sPath - .gltf or .glb asset, buffers and
+  		images are read relative to it
+
+  Result:	Loads default scene of the model with
+  		node transforms applied to its meshes.
+
+  /*---------------------------------------------*/
+
+func LoadGLTFFile(sPath string) (*GLTFModel, error) {
+	bData, err := ReadAsset(sPath)
+	if err != nil {
+		return nil, err
+	}
+	model, err := ParseGLTF(bData, sPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", sPath, err)
+	}
+	return model, nil
+}
+
+// ParseGLTF loads model from .gltf or .glb data, sPath is used to find external buffers and images.
+func ParseGLTF(bData []byte, sPath string) (*GLTFModel, error) {
+	this := &gltfLoader{sPath: sPath, model: &GLTFModel{}}
+	var bBinChunk []byte
+	if len(bData) >= 12 && binary.LittleEndian.Uint32(bData) == gltfMagic {
+		bJSON, bBin, err := splitGLB(bData)
+		if err != nil {
+			return nil, err
+		}
+		bData, bBinChunk = bJSON, bBin
+	}
+	if err := json.Unmarshal(bData, &this.doc); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(this.doc.Asset.Version, "2.") {
+		return nil, fmt.Errorf("glTF version %q isn't supported", this.doc.Asset.Version)
+	}
+	if len(this.doc.ExtensionsRequired) > 0 {
+		return nil, fmt.Errorf("required extensions %v aren't supported", this.doc.ExtensionsRequired)
+	}
+	if err := this.loadBuffers(bBinChunk); err != nil {
+		return nil, err
+	}
+	if err := this.loadImages(); err != nil {
+		return nil, err
+	}
+	this.loadMaterials()
+	if err := this.loadSkeleton(); err != nil {
+		return nil, err
+	}
+	if err := this.loadSkins(); err != nil {
+		return nil, err
+	}
+	if err := this.loadAnimations(); err != nil {
+		return nil, err
+	}
+	if err := this.loadScene(); err != nil {
+		return nil, err
+	}
+	return this.model, nil
+}
+
+// splitGLB returns JSON and binary chunk of binary glTF
+func splitGLB(bData []byte) ([]byte, []byte, error) {
+	if iVersion := binary.LittleEndian.Uint32(bData[4:]); iVersion != 2 {
+		return nil, nil, fmt.Errorf("GLB version %d isn't supported", iVersion)
+	}
+	iLength := MIN(int(binary.LittleEndian.Uint32(bData[8:])), len(bData))
+	var bJSON, bBin []byte
+	for iOffset := 12; iOffset+8 <= iLength; {
+		iChunkLength := int(binary.LittleEndian.Uint32(bData[iOffset:]))
+		iChunkType := binary.LittleEndian.Uint32(bData[iOffset+4:])
+		iOffset += 8
+		if iChunkLength < 0 || iOffset+iChunkLength > iLength {
+			return nil, nil, errors.New("GLB chunk goes past end of file")
+		}
+		switch iChunkType {
+		case gltfChunkJSON:
+			bJSON = bData[iOffset : iOffset+iChunkLength]
+		case gltfChunkBIN:
+			if bBin == nil {
+				bBin = bData[iOffset : iOffset+iChunkLength]
+			}
+		}
+		iOffset += iChunkLength
+	}
+	if bJSON == nil {
+		return nil, nil, errors.New("GLB has no JSON chunk")
+	}
+	return bJSON, bBin, nil
+}
+
+// resolveURI returns path of file referenced from the model, relative to model's directory
+func (this *gltfLoader) resolveURI(sURI string) string {
+	if sUnescaped, err := url.PathUnescape(sURI); err == nil {
+		sURI = sUnescaped
+	}
+	if filepath.IsAbs(this.sPath) {
+		return filepath.Join(filepath.Dir(this.sPath), filepath.FromSlash(sURI))
+	}
+	return CleanAssetPath(path.Join(path.Dir(CleanAssetPath(this.sPath)), sURI))
+}
+
+// readURI returns content of data URI or referenced file
+func (this *gltfLoader) readURI(sURI string) ([]byte, error) {
+	if strings.HasPrefix(sURI, "data:") {
+		iComma := strings.IndexByte(sURI, ',')
+		if iComma < 0 || !strings.HasSuffix(sURI[:iComma], ";base64") {
+			return nil, errors.New("only base64 data URIs are supported")
+		}
+		return base64.StdEncoding.DecodeString(sURI[iComma+1:])
+	}
+	return ReadAsset(this.resolveURI(sURI))
+}
+
+func (this *gltfLoader) loadBuffers(bBinChunk []byte) error {
+	this.vBuffers = make([][]byte, len(this.doc.Buffers))
+	for i, buffer := range this.doc.Buffers {
+		var bData []byte = bBinChunk
+		if buffer.URI != "" {
+			var err error
+			if bData, err = this.readURI(buffer.URI); err != nil {
+				return fmt.Errorf("buffer %d: %v", i, err)
+			}
+		} else if bData == nil {
+			return fmt.Errorf("buffer %d has no data", i)
+		}
+		if len(bData) < buffer.ByteLength {
+			return fmt.Errorf("buffer %d has %d bytes, %d expected", i, len(bData), buffer.ByteLength)
+		}
+		this.vBuffers[i] = bData[:buffer.ByteLength]
+	}
+	return nil
+}
+
+// getBufferView returns bytes of buffer view
+func (this *gltfLoader) getBufferView(iView int) ([]byte, *gltfBufferView, error) {
+	if iView < 0 || iView >= len(this.doc.BufferViews) {
+		return nil, nil, fmt.Errorf("buffer view %d doesn't exist", iView)
+	}
+	view := &this.doc.BufferViews[iView]
+	if view.Buffer < 0 || view.Buffer >= len(this.vBuffers) {
+		return nil, nil, fmt.Errorf("buffer %d doesn't exist", view.Buffer)
+	}
+	bBuffer := this.vBuffers[view.Buffer]
+	if view.ByteOffset < 0 || view.ByteLength < 0 || view.ByteOffset+view.ByteLength > len(bBuffer) {
+		return nil, nil, fmt.Errorf("buffer view %d goes past end of buffer", iView)
+	}
+	return bBuffer[view.ByteOffset : view.ByteOffset+view.ByteLength], view, nil
+}
+
+func (this *gltfLoader) loadImages() error {
+	for i, image := range this.doc.Images {
+		gltfImage := GLTFImage{MimeType: image.MimeType}
+		switch {
+		case image.BufferView != nil:
+			bData, _, err := this.getBufferView(*image.BufferView)
+			if err != nil {
+				return fmt.Errorf("image %d: %v", i, err)
+			}
+			gltfImage.Data = bData
+			gltfImage.Path = fmt.Sprintf("%s#image%d", this.sPath, i)
+		case strings.HasPrefix(image.URI, "data:"):
+			bData, err := this.readURI(image.URI)
+			if err != nil {
+				return fmt.Errorf("image %d: %v", i, err)
+			}
+			gltfImage.Data = bData
+			gltfImage.Path = fmt.Sprintf("%s#image%d", this.sPath, i)
+		default:
+			gltfImage.Path = this.resolveURI(image.URI)
+		}
+		this.model.Images = append(this.model.Images, gltfImage)
+	}
+	return nil
+}
+
+// getImageIndex returns image of texture reference, -1 if there's none
+func (this *gltfLoader) getImageIndex(info *gltfTextureInfo) int {
+	if info == nil || info.Index < 0 || info.Index >= len(this.doc.Textures) {
+		return -1
+	}
+	source := this.doc.Textures[info.Index].Source
+	if source == nil || *source < 0 || *source >= len(this.model.Images) {
+		return -1
+	}
+	return *source
+}
+
+func (this *gltfLoader) loadMaterials() {
+	for _, material := range this.doc.Materials {
+		result := DefaultGLTFMaterial()
+		result.Name = material.Name
+		if pbr := material.PbrMetallicRoughness; pbr != nil {
+			if pbr.BaseColorFactor != nil {
+				result.BaseColorFactor = *pbr.BaseColorFactor
+			}
+			if pbr.MetallicFactor != nil {
+				result.MetallicFactor = *pbr.MetallicFactor
+			}
+			if pbr.RoughnessFactor != nil {
+				result.RoughnessFactor = *pbr.RoughnessFactor
+			}
+			result.BaseColorTexture = this.getImageIndex(pbr.BaseColorTexture)
+			result.MetallicRoughnessTexture = this.getImageIndex(pbr.MetallicRoughnessTexture)
+		}
+		result.NormalTexture = this.getImageIndex(material.NormalTexture)
+		if material.NormalTexture != nil && material.NormalTexture.Scale != nil {
+			result.NormalScale = *material.NormalTexture.Scale
+		}
+		result.OcclusionTexture = this.getImageIndex(material.OcclusionTexture)
+		if material.OcclusionTexture != nil && material.OcclusionTexture.Strength != nil {
+			result.OcclusionStrength = *material.OcclusionTexture.Strength
+		}
+		result.EmissiveTexture = this.getImageIndex(material.EmissiveTexture)
+		if material.EmissiveFactor != nil {
+			result.EmissiveFactor = *material.EmissiveFactor
+		}
+		if material.AlphaMode != "" {
+			result.AlphaMode = material.AlphaMode
+		}
+		if material.AlphaCutoff != nil {
+			result.AlphaCutoff = *material.AlphaCutoff
+		}
+		result.DoubleSided = material.DoubleSided
+		this.model.Materials = append(this.model.Materials, result)
+	}
+}
+
+func getGLTFComponentCount(sType string) int {
+	switch sType {
+	case "SCALAR":
+		return 1
+	case "VEC2":
+		return 2
+	case "VEC3":
+		return 3
+	case "VEC4", "MAT2":
+		return 4
+	case "MAT3":
+		return 9
+	case "MAT4":
+		return 16
+	}
+	return 0
+}
+
+func getGLTFComponentSize(iComponentType int) int {
+	switch iComponentType {
+	case gltfComponentByte, gltfComponentUnsignedByte:
+		return 1
+	case gltfComponentShort, gltfComponentUnsignedShort:
+		return 2
+	case gltfComponentUnsignedInt, gltfComponentFloat:
+		return 4
+	}
+	return 0
+}
+
+/*-----------------------------------------------
+
+  Name:	getElements
+
+  Params:	iView - buffer view index
+  		iOffset - offset of the first element
+  		iCount - number of elements
+  		iElementSize - bytes of one element
+  		bStrided - byte stride of view is used,
+  		otherwise elements are tightly packed
+
+  Result:	Returns view from the first element and
+  		stride between elements. Sizes are checked
+  		before callers allocate anything for them.
+
+  /*---------------------------------------------*/
+
+func (this *gltfLoader) getElements(iView, iOffset, iCount, iElementSize int, bStrided bool) ([]byte, int, error) {
+	bView, view, err := this.getBufferView(iView)
+	if err != nil {
+		return nil, 0, err
+	}
+	iStride := iElementSize
+	if bStrided && view.ByteStride > 0 {
+		iStride = view.ByteStride
+	}
+	if iCount < 0 || iOffset < 0 {
+		return nil, 0, errors.New("negative element count or offset")
+	}
+	// Divided rather than multiplied, so huge counts can't overflow
+	if iAvailable := len(bView) - iOffset - iElementSize; iCount > 0 && (iAvailable < 0 || iCount-1 > iAvailable/iStride) {
+		return nil, 0, fmt.Errorf("%d elements go past end of buffer view %d", iCount, iView)
+	}
+	return bView[MIN(iOffset, len(bView)):], iStride, nil
+}
+
+// readGLTFComponent returns component as float, normalized integers are turned into 0..1 or -1..1
+func readGLTFComponent(b []byte, iComponentType int, bNormalized bool) float32 {
+	switch iComponentType {
+	case gltfComponentFloat:
+		return math.Float32frombits(binary.LittleEndian.Uint32(b))
+	case gltfComponentUnsignedByte:
+		if bNormalized {
+			return float32(b[0]) / 255
+		}
+		return float32(b[0])
+	case gltfComponentByte:
+		if bNormalized {
+			return float32(math.Max(float64(int8(b[0]))/127, -1))
+		}
+		return float32(int8(b[0]))
+	case gltfComponentUnsignedShort:
+		if bNormalized {
+			return float32(binary.LittleEndian.Uint16(b)) / 65535
+		}
+		return float32(binary.LittleEndian.Uint16(b))
+	case gltfComponentShort:
+		if bNormalized {
+			return float32(math.Max(float64(int16(binary.LittleEndian.Uint16(b)))/32767, -1))
+		}
+		return float32(int16(binary.LittleEndian.Uint16(b)))
+	case gltfComponentUnsignedInt:
+		return float32(binary.LittleEndian.Uint32(b))
+	}
+	return 0
+}
+
+// readGLTFIndex returns unsigned integer component, zero for other types
+func readGLTFIndex(b []byte, iComponentType int) uint32 {
+	switch iComponentType {
+	case gltfComponentUnsignedByte:
+		return uint32(b[0])
+	case gltfComponentUnsignedShort:
+		return uint32(binary.LittleEndian.Uint16(b))
+	case gltfComponentUnsignedInt:
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+/*-----------------------------------------------
+
+  Name:	readAccessor
+
+  Params:	iAccessor - accessor index
+  		iComponents - components expected per
+  		element
+
+  Result:	Returns elements as floats one after
+  		another, normalized integers are turned
+  		into 0..1 or -1..1. Sparse elements
+  		replace values of the buffer view.
+
+  /*---------------------------------------------*/
+
+func (this *gltfLoader) readAccessor(iAccessor, iComponents int) ([]float32, error) {
+	if iAccessor < 0 || iAccessor >= len(this.doc.Accessors) {
+		return nil, fmt.Errorf("accessor %d doesn't exist", iAccessor)
+	}
+	accessor := &this.doc.Accessors[iAccessor]
+	if getGLTFComponentCount(accessor.Type) != iComponents {
+		return nil, fmt.Errorf("accessor %d is %s, %d components expected", iAccessor, accessor.Type, iComponents)
+	}
+	iComponentSize := getGLTFComponentSize(accessor.ComponentType)
+	if iComponentSize == 0 {
+		return nil, fmt.Errorf("accessor %d has unknown component type %d", iAccessor, accessor.ComponentType)
+	}
+	iElementSize := iComponentSize * iComponents
+	var bElements []byte
+	var iStride int
+	if accessor.BufferView != nil {
+		var err error
+		if bElements, iStride, err = this.getElements(*accessor.BufferView, accessor.ByteOffset, accessor.Count, iElementSize, true); err != nil {
+			return nil, fmt.Errorf("accessor %d: %v", iAccessor, err)
+		}
+	} else if accessor.Count < 0 || accessor.Count > gltfMaxZeroElements {
+		return nil, fmt.Errorf("accessor %d without buffer view has %d elements", iAccessor, accessor.Count)
+	}
+
+	// Accessor without buffer view is all zeros
+	vValues := make([]float32, accessor.Count*iComponents)
+	if bElements != nil {
+		for i := 0; i < accessor.Count; i++ {
+			for c := 0; c < iComponents; c++ {
+				vValues[i*iComponents+c] = readGLTFComponent(bElements[i*iStride+c*iComponentSize:], accessor.ComponentType, accessor.Normalized)
+			}
+		}
+	}
+	if accessor.Sparse == nil {
+		return vValues, nil
+	}
+
+	sparse := accessor.Sparse
+	iIndexSize := getGLTFComponentSize(sparse.Indices.ComponentType)
+	if sparse.Indices.ComponentType != gltfComponentUnsignedByte && sparse.Indices.ComponentType != gltfComponentUnsignedShort &&
+		sparse.Indices.ComponentType != gltfComponentUnsignedInt {
+		return nil, fmt.Errorf("accessor %d has sparse indices of type %d, they must be unsigned", iAccessor, sparse.Indices.ComponentType)
+	}
+	if sparse.Count < 1 || sparse.Count > accessor.Count {
+		return nil, fmt.Errorf("accessor %d has %d sparse elements", iAccessor, sparse.Count)
+	}
+	bIndices, _, err := this.getElements(sparse.Indices.BufferView, sparse.Indices.ByteOffset, sparse.Count, iIndexSize, false)
+	if err != nil {
+		return nil, fmt.Errorf("accessor %d sparse indices: %v", iAccessor, err)
+	}
+	bSparseValues, _, err := this.getElements(sparse.Values.BufferView, sparse.Values.ByteOffset, sparse.Count, iElementSize, false)
+	if err != nil {
+		return nil, fmt.Errorf("accessor %d sparse values: %v", iAccessor, err)
+	}
+	for i := 0; i < sparse.Count; i++ {
+		iIndex := readGLTFIndex(bIndices[i*iIndexSize:], sparse.Indices.ComponentType)
+		if int64(iIndex) >= int64(accessor.Count) {
+			return nil, fmt.Errorf("accessor %d has sparse index %d past %d elements", iAccessor, iIndex, accessor.Count)
+		}
+		for c := 0; c < iComponents; c++ {
+			vValues[int(iIndex)*iComponents+c] = readGLTFComponent(bSparseValues[i*iElementSize+c*iComponentSize:], accessor.ComponentType, accessor.Normalized)
+		}
+	}
+	return vValues, nil
+}
+
+// readIndices returns index accessor as unsigned integers, floats would lose large indices
+func (this *gltfLoader) readIndices(iAccessor int) ([]uint32, error) {
+	if iAccessor < 0 || iAccessor >= len(this.doc.Accessors) {
+		return nil, fmt.Errorf("accessor %d doesn't exist", iAccessor)
+	}
+	accessor := &this.doc.Accessors[iAccessor]
+	if accessor.Type != "SCALAR" || accessor.BufferView == nil || accessor.Sparse != nil {
+		return nil, fmt.Errorf("accessor %d can't hold indices", iAccessor)
+	}
+	iComponentSize := getGLTFComponentSize(accessor.ComponentType)
+	if accessor.ComponentType != gltfComponentUnsignedByte && accessor.ComponentType != gltfComponentUnsignedShort &&
+		accessor.ComponentType != gltfComponentUnsignedInt {
+		return nil, fmt.Errorf("accessor %d has component type %d, indices must be unsigned", iAccessor, accessor.ComponentType)
+	}
+	bElements, iStride, err := this.getElements(*accessor.BufferView, accessor.ByteOffset, accessor.Count, iComponentSize, true)
+	if err != nil {
+		return nil, fmt.Errorf("accessor %d: %v", iAccessor, err)
+	}
+	vIndices := make([]uint32, accessor.Count)
+	for i := range vIndices {
+		vIndices[i] = readGLTFIndex(bElements[i*iStride:], accessor.ComponentType)
+	}
+	return vIndices, nil
+}
+
+// getLocalMatrix returns node's transform relative to its parent
+func (this *gltfNode) getLocalMatrix() mgl32.Mat4 {
+	if this.Matrix != nil {
+		return mgl32.Mat4(*this.Matrix)
+	}
+	mLocal := mgl32.Ident4()
+	if this.Translation != nil {
+		mLocal = mgl32.Translate3D(this.Translation[0], this.Translation[1], this.Translation[2])
+	}
+	if this.Rotation != nil {
+		qRotation := mgl32.Quat{W: this.Rotation[3], V: mgl32.Vec3{this.Rotation[0], this.Rotation[1], this.Rotation[2]}}
+		mLocal = mLocal.Mul4(qRotation.Normalize().Mat4())
+	}
+	if this.Scale != nil {
+		mLocal = mLocal.Mul4(mgl32.Scale3D(this.Scale[0], this.Scale[1], this.Scale[2]))
+	}
+	return mLocal
+}
+
+func (this *gltfLoader) loadScene() error {
+	var vRoots []int
+	if len(this.doc.Scenes) > 0 {
+		iScene := 0
+		if this.doc.Scene != nil {
+			iScene = *this.doc.Scene
+		}
+		if iScene < 0 || iScene >= len(this.doc.Scenes) {
+			return fmt.Errorf("scene %d doesn't exist", iScene)
+		}
+		vRoots = this.doc.Scenes[iScene].Nodes
+	} else {
+		// Without scenes every node nobody has as a child is a root
+		vIsChild := make([]bool, len(this.doc.Nodes))
+		for _, node := range this.doc.Nodes {
+			for _, iChild := range node.Children {
+				if iChild >= 0 && iChild < len(vIsChild) {
+					vIsChild[iChild] = true
+				}
+			}
+		}
+		for i, bChild := range vIsChild {
+			if !bChild {
+				vRoots = append(vRoots, i)
+			}
+		}
+	}
+	vVisited := make([]bool, len(this.doc.Nodes))
+	for _, iRoot := range vRoots {
+		if err := this.loadNode(iRoot, mgl32.Ident4(), vVisited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *gltfLoader) loadNode(iNode int, mParent mgl32.Mat4, vVisited []bool) error {
+	if iNode < 0 || iNode >= len(this.doc.Nodes) {
+		return fmt.Errorf("node %d doesn't exist", iNode)
+	}
+	if vVisited[iNode] {
+		return fmt.Errorf("node %d is in the hierarchy twice", iNode)
+	}
+	vVisited[iNode] = true
+	node := &this.doc.Nodes[iNode]
+	mWorld := mParent.Mul4(node.getLocalMatrix())
+	if node.Mesh != nil {
+		if *node.Mesh < 0 || *node.Mesh >= len(this.doc.Meshes) {
+			return fmt.Errorf("mesh %d doesn't exist", *node.Mesh)
+		}
+		mesh := &this.doc.Meshes[*node.Mesh]
+		sName := node.Name
+		if sName == "" {
+			sName = mesh.Name
+		}
+		// Joints place skinned mesh, transform of its node is ignored
+		iSkin := -1
+		mMesh := mWorld
+		if node.Skin != nil {
+			if *node.Skin < 0 || *node.Skin >= len(this.model.Skins) {
+				return fmt.Errorf("skin %d doesn't exist", *node.Skin)
+			}
+			iSkin = *node.Skin
+			mMesh = mgl32.Ident4()
+		}
+		for i := range mesh.Primitives {
+			if err := this.loadPrimitive(&mesh.Primitives[i], sName, mMesh, iSkin); err != nil {
+				return fmt.Errorf("mesh %d primitive %d: %v", *node.Mesh, i, err)
+			}
+		}
+	}
+	for _, iChild := range node.Children {
+		if err := this.loadNode(iChild, mWorld, vVisited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*-----------------------------------------------
+
+  Name:	loadPrimitive
+
+  Params:	primitive - mesh part
+  		sName - name of node
+  		mWorld - node's transform
+  		iSkin - skin of node, -1 if it has none
+
+  Result:	Reads vertices of primitive, moves them
+  		to model space and turns its triangles
+  		into a list. Points and lines are skipped.
+
+  /*---------------------------------------------*/
+
+func (this *gltfLoader) loadPrimitive(primitive *gltfPrimitive, sName string, mWorld mgl32.Mat4, iSkin int) error {
+	iMode := gltfModeTriangles
+	if primitive.Mode != nil {
+		iMode = *primitive.Mode
+	}
+	if iMode != gltfModeTriangles && iMode != gltfModeTriangleStrip && iMode != gltfModeTriangleFan {
+		return nil
+	}
+	iPositions, bOK := primitive.Attributes["POSITION"]
+	if !bOK {
+		return nil
+	}
+	vPositions, err := this.readAccessor(iPositions, 3)
+	if err != nil {
+		return err
+	}
+	result := GLTFPrimitive{Name: sName, Material: -1, Skin: -1}
+	if primitive.Material != nil && *primitive.Material >= 0 && *primitive.Material < len(this.model.Materials) {
+		result.Material = *primitive.Material
+	}
+	iVertices := len(vPositions) / 3
+	result.Positions = make([][3]float32, iVertices)
+	for i := range result.Positions {
+		vPosition := mWorld.Mul4x1(mgl32.Vec4{vPositions[3*i], vPositions[3*i+1], vPositions[3*i+2], 1})
+		result.Positions[i] = [3]float32{vPosition[0], vPosition[1], vPosition[2]}
+	}
+	if iNormals, bOK := primitive.Attributes["NORMAL"]; bOK {
+		vNormals, err := this.readAccessor(iNormals, 3)
+		if err != nil {
+			return err
+		}
+		if len(vNormals)/3 != iVertices {
+			return errors.New("normals don't match positions")
+		}
+		// Normals go through inverse transpose, so non-uniform scale keeps them perpendicular
+		mNormal := mWorld.Mat3().Inv().Transpose()
+		result.Normals = make([][3]float32, iVertices)
+		for i := range result.Normals {
+			vNormal := mNormal.Mul3x1(mgl32.Vec3{vNormals[3*i], vNormals[3*i+1], vNormals[3*i+2]})
+			if vNormal.Len() > 0 {
+				vNormal = vNormal.Normalize()
+			}
+			result.Normals[i] = vNormal
+		}
+	}
+	if iTangents, bOK := primitive.Attributes["TANGENT"]; bOK && result.Normals != nil {
+		vTangents, err := this.readAccessor(iTangents, 4)
+		if err != nil {
+			return err
+		}
+		if len(vTangents)/4 != iVertices {
+			return errors.New("tangents don't match positions")
+		}
+		// Tangents lie in the surface, so they're transformed as positions are, mirroring flips bitangent
+		mTangent := mWorld.Mat3()
+		var fMirror float32 = 1
+		if mTangent.Det() < 0 {
+			fMirror = -1
+		}
+		result.Tangents = make([][4]float32, iVertices)
+		for i := range result.Tangents {
+			vTangent := mTangent.Mul3x1(mgl32.Vec3{vTangents[4*i], vTangents[4*i+1], vTangents[4*i+2]})
+			if vTangent.Len() > 0 {
+				vTangent = vTangent.Normalize()
+			}
+			result.Tangents[i] = [4]float32{vTangent[0], vTangent[1], vTangent[2], vTangents[4*i+3] * fMirror}
+		}
+	}
+	if iTexCoords, bOK := primitive.Attributes["TEXCOORD_0"]; bOK {
+		vTexCoords, err := this.readAccessor(iTexCoords, 2)
+		if err != nil {
+			return err
+		}
+		if len(vTexCoords)/2 != iVertices {
+			return errors.New("texture coordinates don't match positions")
+		}
+		result.TexCoords = make([][2]float32, iVertices)
+		for i := range result.TexCoords {
+			result.TexCoords[i] = [2]float32{vTexCoords[2*i], vTexCoords[2*i+1]}
+		}
+	}
+	if iSkin >= 0 {
+		if result.Skinning, err = this.readSkinning(primitive, iVertices, len(this.model.Skins[iSkin].Joints)); err != nil {
+			return err
+		}
+		result.Skin = iSkin
+	}
+
+	var vIndices []uint32
+	if primitive.Indices != nil {
+		if vIndices, err = this.readIndices(*primitive.Indices); err != nil {
+			return err
+		}
+	} else {
+		vIndices = make([]uint32, iVertices)
+		for i := range vIndices {
+			vIndices[i] = uint32(i)
+		}
+	}
+	for _, iIndex := range vIndices {
+		if int(iIndex) >= iVertices {
+			return fmt.Errorf("index %d is past %d vertices", iIndex, iVertices)
+		}
+	}
+	result.Indices = getGLTFTriangleList(vIndices, iMode)
+	// Mirroring transform turns triangles inside out
+	if mWorld.Mat3().Det() < 0 {
+		for i := 0; i+2 < len(result.Indices); i += 3 {
+			result.Indices[i+1], result.Indices[i+2] = result.Indices[i+2], result.Indices[i+1]
+		}
+	}
+	if result.Tangents == nil && result.Normals != nil && result.TexCoords != nil {
+		result.Tangents = ComputeTangents(result.Positions, result.Normals, result.TexCoords, result.Indices)
+	}
+	this.model.Primitives = append(this.model.Primitives, result)
+	return nil
+}
+
+// readSkinning returns joints and weights of vertices, nil if primitive has no joints
+func (this *gltfLoader) readSkinning(primitive *gltfPrimitive, iVertices, iJoints int) ([]VertexSkin, error) {
+	iJointsAccessor, bJoints := primitive.Attributes["JOINTS_0"]
+	iWeightsAccessor, bWeights := primitive.Attributes["WEIGHTS_0"]
+	if !bJoints || !bWeights {
+		return nil, nil
+	}
+	vJoints, err := this.readAccessor(iJointsAccessor, 4)
+	if err != nil {
+		return nil, err
+	}
+	vWeights, err := this.readAccessor(iWeightsAccessor, 4)
+	if err != nil {
+		return nil, err
+	}
+	if len(vJoints)/4 != iVertices || len(vWeights)/4 != iVertices {
+		return nil, errors.New("joints or weights don't match positions")
+	}
+	vSkinning := make([]VertexSkin, iVertices)
+	for i := range vSkinning {
+		for j := 0; j < 4; j++ {
+			if fWeight := vWeights[4*i+j]; fWeight > 0 {
+				iJoint := int(vJoints[4*i+j])
+				if iJoint >= iJoints {
+					return nil, fmt.Errorf("vertex %d uses joint %d, skin has %d", i, iJoint, iJoints)
+				}
+				vSkinning[i].AddWeight(iJoint, fWeight)
+			}
+		}
+		vSkinning[i].Normalize()
+	}
+	return vSkinning, nil
+}
+
+/*-----------------------------------------------
+
+  Name:	loadSkeleton
+
+  Params:	none
+
+  Result:	Turns all nodes of the file into skeleton,
+  		parents before children. Nodes of every
+  		scene are taken, skins may point anywhere.
+
+  /*---------------------------------------------*/
+
+func (this *gltfLoader) loadSkeleton() error {
+	iNodes := len(this.doc.Nodes)
+	vParents := make([]int, iNodes)
+	for i := range vParents {
+		vParents[i] = -1
+	}
+	for i, node := range this.doc.Nodes {
+		for _, iChild := range node.Children {
+			if iChild < 0 || iChild >= iNodes {
+				return fmt.Errorf("node %d doesn't exist", iChild)
+			}
+			if vParents[iChild] >= 0 {
+				return fmt.Errorf("node %d has more than one parent", iChild)
+			}
+			vParents[iChild] = i
+		}
+	}
+	this.vNodeToBone = make([]int, iNodes)
+	for i := range this.vNodeToBone {
+		this.vNodeToBone[i] = -1
+	}
+	var vNodes []SkeletonNode
+	var addNode func(iNode, iParent int)
+	addNode = func(iNode, iParent int) {
+		node := &this.doc.Nodes[iNode]
+		this.vNodeToBone[iNode] = len(vNodes)
+		restPose := IdentityPose()
+		if node.Matrix != nil {
+			restPose = PoseFromMatrix(mgl32.Mat4(*node.Matrix))
+		} else {
+			if node.Translation != nil {
+				restPose.Translation = *node.Translation
+			}
+			if node.Rotation != nil {
+				restPose.Rotation = mgl32.Quat{W: node.Rotation[3], V: mgl32.Vec3{node.Rotation[0], node.Rotation[1], node.Rotation[2]}}.Normalize()
+			}
+			if node.Scale != nil {
+				restPose.Scale = *node.Scale
+			}
+		}
+		vNodes = append(vNodes, SkeletonNode{Name: node.Name, Parent: iParent, RestPose: restPose})
+		iBone := len(vNodes) - 1
+		for _, iChild := range node.Children {
+			addNode(iChild, iBone)
+		}
+	}
+	for i := range this.doc.Nodes {
+		if vParents[i] < 0 {
+			addNode(i, -1)
+		}
+	}
+	// Nodes in a cycle have parents, but no root leads to them
+	if len(vNodes) != iNodes {
+		return errors.New("node hierarchy has a cycle")
+	}
+	skeleton, err := NewSkeleton(vNodes)
+	if err != nil {
+		return err
+	}
+	this.model.Skeleton = skeleton
+	return nil
+}
+
+func (this *gltfLoader) loadSkins() error {
+	for i, skin := range this.doc.Skins {
+		result := Skin{Name: skin.Name}
+		for _, iNode := range skin.Joints {
+			if iNode < 0 || iNode >= len(this.vNodeToBone) {
+				return fmt.Errorf("skin %d: node %d doesn't exist", i, iNode)
+			}
+			result.Joints = append(result.Joints, this.vNodeToBone[iNode])
+		}
+		result.InverseBind = make([]mgl32.Mat4, len(skin.Joints))
+		if skin.InverseBindMatrices != nil {
+			vMatrices, err := this.readAccessor(*skin.InverseBindMatrices, 16)
+			if err != nil {
+				return fmt.Errorf("skin %d: %v", i, err)
+			}
+			if len(vMatrices) != 16*len(skin.Joints) {
+				return fmt.Errorf("skin %d has %d inverse bind matrices for %d joints", i, len(vMatrices)/16, len(skin.Joints))
+			}
+			for j := range result.InverseBind {
+				copy(result.InverseBind[j][:], vMatrices[16*j:16*j+16])
+			}
+		} else {
+			for j := range result.InverseBind {
+				result.InverseBind[j] = mgl32.Ident4()
+			}
+		}
+		this.model.Skins = append(this.model.Skins, result)
+	}
+	return nil
+}
+
+// loadAnimations reads node animations, morph target weights aren't supported and are skipped
+func (this *gltfLoader) loadAnimations() error {
+	for i, animation := range this.doc.Animations {
+		var vChannels []AnimationChannel
+		for j, channel := range animation.Channels {
+			var path EChannelPath
+			var iComponents int
+			switch channel.Target.Path {
+			case "translation":
+				path, iComponents = CHANNEL_TRANSLATION, 3
+			case "rotation":
+				path, iComponents = CHANNEL_ROTATION, 4
+			case "scale":
+				path, iComponents = CHANNEL_SCALE, 3
+			default:
+				continue
+			}
+			if channel.Target.Node == nil {
+				continue
+			}
+			if *channel.Target.Node < 0 || *channel.Target.Node >= len(this.vNodeToBone) {
+				return fmt.Errorf("animation %d channel %d: node %d doesn't exist", i, j, *channel.Target.Node)
+			}
+			if channel.Sampler < 0 || channel.Sampler >= len(animation.Samplers) {
+				return fmt.Errorf("animation %d channel %d: sampler %d doesn't exist", i, j, channel.Sampler)
+			}
+			sampler := animation.Samplers[channel.Sampler]
+			result := AnimationChannel{Node: this.vNodeToBone[*channel.Target.Node], Path: path, Interpolation: INTERPOLATION_LINEAR}
+			switch sampler.Interpolation {
+			case "STEP":
+				result.Interpolation = INTERPOLATION_STEP
+			case "CUBICSPLINE":
+				result.Interpolation = INTERPOLATION_CUBIC_SPLINE
+			}
+			var err error
+			if result.Times, err = this.readAccessor(sampler.Input, 1); err != nil {
+				return fmt.Errorf("animation %d channel %d: %v", i, j, err)
+			}
+			if result.Values, err = this.readAccessor(sampler.Output, iComponents); err != nil {
+				return fmt.Errorf("animation %d channel %d: %v", i, j, err)
+			}
+			vChannels = append(vChannels, result)
+		}
+		sName := animation.Name
+		if sName == "" {
+			sName = fmt.Sprintf("animation%d", i)
+		}
+		clip, err := NewAnimationClip(sName, vChannels)
+		if err != nil {
+			return err
+		}
+		this.model.Animations = append(this.model.Animations, clip)
+	}
+	return nil
+}
+
+// FindAnimation returns animation with name, nil if there's none.
+func (this *GLTFModel) FindAnimation(sName string) *AnimationClip {
+	for _, clip := range this.Animations {
+		if clip.Name == sName {
+			return clip
+		}
+	}
+	return nil
+}
+
+// getGLTFTriangleList turns strips and fans into triangle list
+func getGLTFTriangleList(vIndices []uint32, iMode int) []uint32 {
+	switch iMode {
+	case gltfModeTriangleStrip:
+		var vList []uint32
+		for i := 0; i+2 < len(vIndices); i++ {
+			if i%2 == 0 {
+				vList = append(vList, vIndices[i], vIndices[i+1], vIndices[i+2])
+			} else {
+				vList = append(vList, vIndices[i+1], vIndices[i], vIndices[i+2])
+			}
+		}
+		return vList
+	case gltfModeTriangleFan:
+		var vList []uint32
+		for i := 1; i+1 < len(vIndices); i++ {
+			vList = append(vList, vIndices[0], vIndices[i], vIndices[i+1])
+		}
+		return vList
+	}
+	return vIndices[:len(vIndices)/3*3]
+}
+
+// GetNumTriangles returns number of triangles in all primitives.
+func (this *GLTFModel) GetNumTriangles() int {
+	var iTriangles int
+	for i := range this.Primitives {
+		iTriangles += len(this.Primitives[i].Indices) / 3
+	}
+	return iTriangles
+}
+
+// GetMaterial returns material of primitive, primitives without one get default material.
+func (this *GLTFModel) GetMaterial(primitive *GLTFPrimitive) GLTFMaterial {
+	if primitive.Material < 0 || primitive.Material >= len(this.Materials) {
+		return DefaultGLTFMaterial()
+	}
+	return this.Materials[primitive.Material]
+}
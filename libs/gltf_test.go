@@ -0,0 +1,326 @@
+package libs
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"testing"
+)
+
+// gltfFixture builds binary buffer with its views and accessors for test documents
+type gltfFixture struct {
+	bBuffer    []byte
+	vViews     []map[string]any
+	vAccessors []map[string]any
+}
+
+func gltfFloats(vValues ...float32) []byte {
+	var bData []byte
+	for _, fValue := range vValues {
+		bData = binary.LittleEndian.AppendUint32(bData, math.Float32bits(fValue))
+	}
+	return bData
+}
+
+func gltfShorts(vValues ...uint16) []byte {
+	var bData []byte
+	for _, iValue := range vValues {
+		bData = binary.LittleEndian.AppendUint16(bData, iValue)
+	}
+	return bData
+}
+
+// addView appends data aligned to 4 bytes, stride 0 leaves it out
+func (this *gltfFixture) addView(bData []byte, iStride int) int {
+	for len(this.bBuffer)%4 != 0 {
+		this.bBuffer = append(this.bBuffer, 0)
+	}
+	mView := map[string]any{"buffer": 0, "byteOffset": len(this.bBuffer), "byteLength": len(bData)}
+	if iStride > 0 {
+		mView["byteStride"] = iStride
+	}
+	this.bBuffer = append(this.bBuffer, bData...)
+	this.vViews = append(this.vViews, mView)
+	return len(this.vViews) - 1
+}
+
+func (this *gltfFixture) addAccessor(mAccessor map[string]any) int {
+	this.vAccessors = append(this.vAccessors, mAccessor)
+	return len(this.vAccessors) - 1
+}
+
+// getDocument returns document with given nodes and meshes, its buffer has no URI yet
+func (this *gltfFixture) getDocument(vNodes, vMeshes []map[string]any) map[string]any {
+	return map[string]any{
+		"asset":       map[string]any{"version": "2.0"},
+		"nodes":       vNodes,
+		"meshes":      vMeshes,
+		"accessors":   this.vAccessors,
+		"bufferViews": this.vViews,
+		"buffers":     []map[string]any{{"byteLength": len(this.bBuffer)}},
+	}
+}
+
+// encodeGLTF returns .gltf file with buffer in data URI
+func (this *gltfFixture) encodeGLTF(t *testing.T, mDocument map[string]any) []byte {
+	mDocument["buffers"].([]map[string]any)[0]["uri"] = "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(this.bBuffer)
+	bJSON, err := json.Marshal(mDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bJSON
+}
+
+// encodeGLB returns binary glTF with buffer in BIN chunk
+func (this *gltfFixture) encodeGLB(t *testing.T, mDocument map[string]any) []byte {
+	bJSON, err := json.Marshal(mDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for len(bJSON)%4 != 0 {
+		bJSON = append(bJSON, ' ')
+	}
+	bBin := append([]byte(nil), this.bBuffer...)
+	for len(bBin)%4 != 0 {
+		bBin = append(bBin, 0)
+	}
+	bData := binary.LittleEndian.AppendUint32(nil, gltfMagic)
+	bData = binary.LittleEndian.AppendUint32(bData, 2)
+	bData = binary.LittleEndian.AppendUint32(bData, uint32(12+8+len(bJSON)+8+len(bBin)))
+	bData = binary.LittleEndian.AppendUint32(bData, uint32(len(bJSON)))
+	bData = binary.LittleEndian.AppendUint32(bData, gltfChunkJSON)
+	bData = append(bData, bJSON...)
+	bData = binary.LittleEndian.AppendUint32(bData, uint32(len(bBin)))
+	bData = binary.LittleEndian.AppendUint32(bData, gltfChunkBIN)
+	return append(bData, bBin...)
+}
+
+/*-----------------------------------------------
+
+  Name:	newAccessorFixture
+
+  Params:	none
+
+  Result:	Triangle whose positions and texture
+  		coordinates are interleaved in one strided
+  		view. Texture coordinates are normalized
+  		shorts, normals are sparse normalized bytes
+  		over zeros and one position is replaced by
+  		sparse float.
+
+  /*---------------------------------------------*/
+
+func newAccessorFixture() (*gltfFixture, map[string]any) {
+	fixture := &gltfFixture{}
+	var bInterleaved []byte
+	vPositions := [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}
+	vTexCoords := [][2]uint16{{0, 0}, {65535, 0}, {0, 65535}}
+	for i := range vPositions {
+		bInterleaved = append(bInterleaved, gltfFloats(vPositions[i][:]...)...)
+		bInterleaved = append(bInterleaved, gltfShorts(vTexCoords[i][:]...)...)
+	}
+	iInterleaved := fixture.addView(bInterleaved, 16)
+	iSparseIndices := fixture.addView(gltfShorts(1), 0)
+	iSparsePositions := fixture.addView(gltfFloats(5, 6, 7), 0)
+	iNormalIndices := fixture.addView([]byte{0, 2}, 0)
+	iNormalValues := fixture.addView([]byte{0, 0, 127, 0, 0, 0x80}, 0)
+	iIndices := fixture.addView([]byte{0, 1, 2}, 0)
+
+	iPosition := fixture.addAccessor(map[string]any{"bufferView": iInterleaved, "componentType": gltfComponentFloat,
+		"count": 3, "type": "VEC3", "sparse": map[string]any{"count": 1,
+			"indices": map[string]any{"bufferView": iSparseIndices, "componentType": gltfComponentUnsignedShort},
+			"values":  map[string]any{"bufferView": iSparsePositions}}})
+	iTexCoord := fixture.addAccessor(map[string]any{"bufferView": iInterleaved, "byteOffset": 12,
+		"componentType": gltfComponentUnsignedShort, "normalized": true, "count": 3, "type": "VEC2"})
+	iNormal := fixture.addAccessor(map[string]any{"componentType": gltfComponentByte, "normalized": true,
+		"count": 3, "type": "VEC3", "sparse": map[string]any{"count": 2,
+			"indices": map[string]any{"bufferView": iNormalIndices, "componentType": gltfComponentUnsignedByte},
+			"values":  map[string]any{"bufferView": iNormalValues}}})
+	iIndex := fixture.addAccessor(map[string]any{"bufferView": iIndices, "componentType": gltfComponentUnsignedByte,
+		"count": 3, "type": "SCALAR"})
+
+	vMeshes := []map[string]any{{"name": "triangle", "primitives": []map[string]any{{
+		"attributes": map[string]int{"POSITION": iPosition, "TEXCOORD_0": iTexCoord, "NORMAL": iNormal},
+		"indices":    iIndex}}}}
+	return fixture, fixture.getDocument([]map[string]any{{"mesh": 0}}, vMeshes)
+}
+
+func TestGLTFAccessors(t *testing.T) {
+	for _, sPath := range []string{"fixture.gltf", "fixture.glb"} {
+		fixture, mDocument := newAccessorFixture()
+		var bData []byte
+		if sPath == "fixture.glb" {
+			bData = fixture.encodeGLB(t, mDocument)
+		} else {
+			bData = fixture.encodeGLTF(t, mDocument)
+		}
+		model, err := ParseGLTF(bData, sPath)
+		if err != nil {
+			t.Fatalf("%s: %v", sPath, err)
+		}
+		if len(model.Primitives) != 1 {
+			t.Fatalf("%s: %d primitives", sPath, len(model.Primitives))
+		}
+		primitive := model.Primitives[0]
+		if primitive.Name != "triangle" || primitive.Material != -1 || primitive.Skin != -1 {
+			t.Errorf("%s: primitive is %q, material %d, skin %d", sPath, primitive.Name, primitive.Material, primitive.Skin)
+		}
+		vPositions := [][3]float32{{0, 0, 0}, {5, 6, 7}, {0, 1, 0}}
+		vTexCoords := [][2]float32{{0, 0}, {1, 0}, {0, 1}}
+		// Byte -128 is clamped to -1, vertex without sparse normal keeps zero
+		vNormals := [][3]float32{{0, 0, 1}, {0, 0, 0}, {0, 0, -1}}
+		for i := 0; i < 3; i++ {
+			if primitive.Positions[i] != vPositions[i] || primitive.TexCoords[i] != vTexCoords[i] || primitive.Normals[i] != vNormals[i] {
+				t.Errorf("%s: vertex %d is %v, %v, %v", sPath, i, primitive.Positions[i], primitive.TexCoords[i], primitive.Normals[i])
+			}
+		}
+		if len(primitive.Indices) != 3 || primitive.Indices[0] != 0 || primitive.Indices[1] != 1 || primitive.Indices[2] != 2 {
+			t.Errorf("%s: indices are %v", sPath, primitive.Indices)
+		}
+		if len(primitive.Tangents) != 3 {
+			t.Errorf("%s: %d tangents computed", sPath, len(primitive.Tangents))
+		}
+	}
+}
+
+func TestGLTFNodeTransforms(t *testing.T) {
+	fixture := &gltfFixture{}
+	iPosition := fixture.addAccessor(map[string]any{"bufferView": fixture.addView(gltfFloats(0, 0, 0, 1, 0, 0, 0, 1, 0), 0),
+		"componentType": gltfComponentFloat, "count": 3, "type": "VEC3"})
+	iNormal := fixture.addAccessor(map[string]any{"bufferView": fixture.addView(gltfFloats(0, 0, 1, 0, 0, 1, 0, 0, 1), 0),
+		"componentType": gltfComponentFloat, "count": 3, "type": "VEC3"})
+	vMeshes := []map[string]any{{"name": "triangle", "primitives": []map[string]any{{
+		"attributes": map[string]int{"POSITION": iPosition, "NORMAL": iNormal}}}}}
+	fSin := float32(math.Sqrt(0.5))
+	vNodes := []map[string]any{
+		{"name": "root", "translation": []float32{10, 0, 0}, "children": []int{1, 2, 3}},
+		{"name": "rotated", "mesh": 0, "rotation": []float32{0, 0, fSin, fSin}, "scale": []float32{2, 2, 2}},
+		{"name": "mirrored", "mesh": 0, "scale": []float32{-1, 1, 1}},
+		{"mesh": 0, "matrix": []float32{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 5, 1}},
+		// Not in the scene, so it isn't loaded
+		{"name": "unused", "mesh": 0},
+	}
+	mDocument := fixture.getDocument(vNodes, vMeshes)
+	mDocument["scenes"] = []map[string]any{{"nodes": []int{0}}}
+	model, err := ParseGLTF(fixture.encodeGLTF(t, mDocument), "fixture.gltf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vCases := []struct {
+		sName      string
+		vPositions [3]mgl32.Vec3
+		vNormal    mgl32.Vec3
+		vIndices   [3]uint32
+	}{
+		{"rotated", [3]mgl32.Vec3{{10, 0, 0}, {10, 2, 0}, {8, 0, 0}}, mgl32.Vec3{0, 0, 1}, [3]uint32{0, 1, 2}},
+		// Mirroring keeps triangle facing outside by reversing its winding
+		{"mirrored", [3]mgl32.Vec3{{10, 0, 0}, {9, 0, 0}, {10, 1, 0}}, mgl32.Vec3{0, 0, 1}, [3]uint32{0, 2, 1}},
+		{"triangle", [3]mgl32.Vec3{{10, 0, 5}, {11, 0, 5}, {10, 1, 5}}, mgl32.Vec3{0, 0, 1}, [3]uint32{0, 1, 2}},
+	}
+	if len(model.Primitives) != len(vCases) {
+		t.Fatalf("%d primitives loaded, want %d", len(model.Primitives), len(vCases))
+	}
+	for i, testCase := range vCases {
+		primitive := model.Primitives[i]
+		if primitive.Name != testCase.sName {
+			t.Errorf("primitive %d is %q, want %q", i, primitive.Name, testCase.sName)
+		}
+		for v, vPosition := range testCase.vPositions {
+			if !isNearVec3(primitive.Positions[v], vPosition, 1e-5) || !isNearVec3(primitive.Normals[v], testCase.vNormal, 1e-5) {
+				t.Errorf("%s: vertex %d is %v, normal %v, want %v, %v", testCase.sName, v, primitive.Positions[v], primitive.Normals[v],
+					vPosition, testCase.vNormal)
+			}
+		}
+		if len(primitive.Indices) != 3 || [3]uint32(primitive.Indices) != testCase.vIndices {
+			t.Errorf("%s: indices are %v, want %v", testCase.sName, primitive.Indices, testCase.vIndices)
+		}
+	}
+	if model.Skeleton == nil || len(model.Skeleton.Nodes) != len(vNodes) {
+		t.Error("skeleton doesn't hold every node")
+	}
+}
+
+// getFixtureAccessor returns accessor of document for changing it
+func getFixtureAccessor(mDocument map[string]any, iAccessor int) map[string]any {
+	return mDocument["accessors"].([]map[string]any)[iAccessor]
+}
+
+func TestGLTFRejects(t *testing.T) {
+	// Accessors of fixture are position 0, texture coordinates 1, normal 2 and indices 3
+	vCases := []struct {
+		sName    string
+		fnChange func(mDocument map[string]any)
+	}{
+		// Sparse normal values 0, 0, 127 read as indices
+		{"index past vertices", func(mDocument map[string]any) { getFixtureAccessor(mDocument, 3)["bufferView"] = 4 }},
+		{"signed indices", func(mDocument map[string]any) { getFixtureAccessor(mDocument, 3)["componentType"] = gltfComponentByte }},
+		{"indices past view", func(mDocument map[string]any) { getFixtureAccessor(mDocument, 3)["count"] = 4 }},
+		{"negative index count", func(mDocument map[string]any) { getFixtureAccessor(mDocument, 3)["count"] = -1 }},
+		{"negative count", func(mDocument map[string]any) { getFixtureAccessor(mDocument, 1)["count"] = -3 }},
+		{"huge count", func(mDocument map[string]any) { getFixtureAccessor(mDocument, 1)["count"] = 1 << 60 }},
+		{"huge zero accessor", func(mDocument map[string]any) {
+			delete(getFixtureAccessor(mDocument, 1), "bufferView")
+			getFixtureAccessor(mDocument, 1)["count"] = 1 << 40
+		}},
+		{"offset past view", func(mDocument map[string]any) { getFixtureAccessor(mDocument, 1)["byteOffset"] = 40 }},
+		{"stride past view", func(mDocument map[string]any) {
+			mDocument["bufferViews"].([]map[string]any)[0]["byteStride"] = 20
+		}},
+		{"view past buffer", func(mDocument map[string]any) {
+			mDocument["bufferViews"].([]map[string]any)[0]["byteLength"] = 1000
+		}},
+		{"sparse index past count", func(mDocument map[string]any) {
+			getFixtureAccessor(mDocument, 2)["sparse"].(map[string]any)["indices"] = map[string]any{"bufferView": 4, "byteOffset": 2,
+				"componentType": gltfComponentUnsignedByte}
+		}},
+		{"sparse count past count", func(mDocument map[string]any) {
+			getFixtureAccessor(mDocument, 2)["sparse"].(map[string]any)["count"] = 4
+		}},
+		{"sparse values past view", func(mDocument map[string]any) {
+			getFixtureAccessor(mDocument, 0)["sparse"].(map[string]any)["values"] = map[string]any{"bufferView": 2, "byteOffset": 4}
+		}},
+		{"signed sparse indices", func(mDocument map[string]any) {
+			getFixtureAccessor(mDocument, 2)["sparse"].(map[string]any)["indices"].(map[string]any)["componentType"] = gltfComponentByte
+		}},
+		{"component count", func(mDocument map[string]any) { getFixtureAccessor(mDocument, 1)["type"] = "VEC3" }},
+		{"component type", func(mDocument map[string]any) { getFixtureAccessor(mDocument, 1)["componentType"] = 5130 }},
+		{"version", func(mDocument map[string]any) { mDocument["asset"] = map[string]any{"version": "1.0"} }},
+		{"required extension", func(mDocument map[string]any) {
+			mDocument["extensionsRequired"] = []string{"KHR_draco_mesh_compression"}
+		}},
+		{"missing mesh", func(mDocument map[string]any) { mDocument["nodes"] = []map[string]any{{"mesh": 1}} }},
+		{"node cycle", func(mDocument map[string]any) {
+			mDocument["nodes"] = []map[string]any{{"mesh": 0}, {"children": []int{2}}, {"children": []int{1}}}
+		}},
+	}
+	fixture, mDocument := newAccessorFixture()
+	if _, err := ParseGLTF(fixture.encodeGLTF(t, mDocument), "fixture.gltf"); err != nil {
+		t.Fatalf("unchanged fixture: %v", err)
+	}
+	for _, testCase := range vCases {
+		fixture, mDocument := newAccessorFixture()
+		testCase.fnChange(mDocument)
+		if _, err := ParseGLTF(fixture.encodeGLTF(t, mDocument), "fixture.gltf"); err == nil {
+			t.Errorf("%s: file was loaded", testCase.sName)
+		}
+	}
+
+	fixture, mDocument = newAccessorFixture()
+	bGLB := fixture.encodeGLB(t, mDocument)
+	vGLBCases := []struct {
+		sName string
+		bData []byte
+	}{
+		{"GLB version", append(append([]byte(nil), bGLB[:4]...), append([]byte{1, 0, 0, 0}, bGLB[8:]...)...)},
+		{"GLB chunk past end", bGLB[:len(bGLB)-4]},
+		{"GLB without JSON", bGLB[:12]},
+	}
+	for _, testCase := range vGLBCases {
+		if _, err := ParseGLTF(testCase.bData, "fixture.glb"); err == nil {
+			t.Errorf("%s: file was loaded", testCase.sName)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package libs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+)
+
+func init() {
+	image.RegisterFormat("hdr", "#?RADIANCE", DecodeHDR, DecodeHDRConfig)
+	image.RegisterFormat("hdr", "#?RGBE", DecodeHDR, DecodeHDRConfig)
+}
+
+// FloatImage holds linear RGBA texels as float32, HDR images load into it
+type FloatImage struct {
+	Width  int
+	Height int
+	Pix    []float32 // RGBA, rows from top to bottom
+}
+
+func NewFloatImage(iWidth, iHeight int) *FloatImage {
+	return &FloatImage{Width: iWidth, Height: iHeight, Pix: make([]float32, iWidth*iHeight*4)}
+}
+
+func (this *FloatImage) ColorModel() color.Model {
+	return color.RGBA64Model
+}
+
+func (this *FloatImage) Bounds() image.Rectangle {
+	return image.Rect(0, 0, this.Width, this.Height)
+}
+
+// At clamps texel to 0..1, use Pix or GetTexel to get values above one
+func (this *FloatImage) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(this.Bounds())) {
+		return color.RGBA64{}
+	}
+	vTexel := this.GetTexel(x, y)
+	var vResult [4]uint16
+	fAlpha := math.Max(0, math.Min(1, float64(vTexel[3])))
+	for c := 0; c < 4; c++ {
+		fValue := math.Max(0, math.Min(1, float64(vTexel[c])))
+		if c < 3 {
+			fValue = math.Min(fValue, fAlpha) // RGBA64 is premultiplied
+		}
+		vResult[c] = uint16(fValue*65535 + 0.5)
+	}
+	return color.RGBA64{vResult[0], vResult[1], vResult[2], vResult[3]}
+}
+
+func (this *FloatImage) GetTexel(x, y int) [4]float32 {
+	i := (y*this.Width + x) * 4
+	return [4]float32{this.Pix[i], this.Pix[i+1], this.Pix[i+2], this.Pix[i+3]}
+}
+
+func (this *FloatImage) SetTexel(x, y int, vTexel [4]float32) {
+	copy(this.Pix[(y*this.Width+x)*4:], vTexel[:])
+}
+
+/*-----------------------------------------------
+
+  Name:	Sample
+
+  Params:	u, v - texture coordinates, 0,0 is top
+  		left corner
+  		bWrapU - repeat horizontally, otherwise
+  		coordinates are clamped
+
+  Result:	Returns bilinearly filtered texel.
+
+  /*---------------------------------------------*/
+
+func (this *FloatImage) Sample(u, v float64, bWrapU bool) [4]float32 {
+	fX := u*float64(this.Width) - 0.5
+	fY := v*float64(this.Height) - 0.5
+	x0, y0 := int(math.Floor(fX)), int(math.Floor(fY))
+	fFracX, fFracY := float32(fX-float64(x0)), float32(fY-float64(y0))
+
+	fixX := func(x int) int {
+		if bWrapU {
+			return ((x % this.Width) + this.Width) % this.Width
+		}
+		return MIN(MAX(x, 0), this.Width-1)
+	}
+	fixY := func(y int) int {
+		return MIN(MAX(y, 0), this.Height-1)
+	}
+	t00 := this.GetTexel(fixX(x0), fixY(y0))
+	t10 := this.GetTexel(fixX(x0+1), fixY(y0))
+	t01 := this.GetTexel(fixX(x0), fixY(y0+1))
+	t11 := this.GetTexel(fixX(x0+1), fixY(y0+1))
+	var vResult [4]float32
+	for c := 0; c < 4; c++ {
+		fTop := t00[c] + (t10[c]-t00[c])*fFracX
+		fBottom := t01[c] + (t11[c]-t01[c])*fFracX
+		vResult[c] = fTop + (fBottom-fTop)*fFracY
+	}
+	return vResult
+}
+
+// SubImage returns copy of given rectangle.
+func (this *FloatImage) SubImage(rect image.Rectangle) *FloatImage {
+	rect = rect.Intersect(this.Bounds())
+	result := NewFloatImage(rect.Dx(), rect.Dy())
+	for y := 0; y < rect.Dy(); y++ {
+		iSrc := ((rect.Min.Y+y)*this.Width + rect.Min.X) * 4
+		copy(result.Pix[y*result.Width*4:(y+1)*result.Width*4], this.Pix[iSrc:])
+	}
+	return result
+}
+
+// ToNRGBA clamps texels to 0..1 and converts them to 8 bits, no tone mapping is done.
+func (this *FloatImage) ToNRGBA() *image.NRGBA {
+	nrgba := image.NewNRGBA(this.Bounds())
+	for i, fValue := range this.Pix {
+		nrgba.Pix[i] = byte(math.Max(0, math.Min(1, float64(fValue)))*255 + 0.5)
+	}
+	return nrgba
+}
+
+// ToFloatImage converts img to FloatImage, 8 bit values are only scaled to 0..1, not linearized.
+func ToFloatImage(img image.Image) *FloatImage {
+	if floatImage, bOK := img.(*FloatImage); bOK {
+		return floatImage
+	}
+	nrgba := ToNRGBA(img)
+	result := NewFloatImage(nrgba.Rect.Dx(), nrgba.Rect.Dy())
+	for i, bValue := range nrgba.Pix {
+		result.Pix[i] = float32(bValue) / 255
+	}
+	return result
+}
+
+// hdrHeader is what Radiance header and resolution line tell
+type hdrHeader struct {
+	iWidth, iHeight int
+	bFlipY          bool // Resolution is +Y, rows go from bottom to top
+}
+
+func hdrReadHeader(r *bufio.Reader) (hdrHeader, error) {
+	var header hdrHeader
+	sLine, err := r.ReadString('\n')
+	if err != nil {
+		return header, err
+	}
+	if !strings.HasPrefix(sLine, "#?RADIANCE") && !strings.HasPrefix(sLine, "#?RGBE") {
+		return header, errors.New("not a Radiance HDR file")
+	}
+	// Variables until empty line
+	for {
+		sLine, err = r.ReadString('\n')
+		if err != nil {
+			return header, err
+		}
+		sLine = strings.TrimSpace(sLine)
+		if sLine == "" {
+			break
+		}
+		if strings.HasPrefix(sLine, "FORMAT=") && sLine != "FORMAT=32-bit_rle_rgbe" {
+			return header, fmt.Errorf("unsupported HDR format %s", strings.TrimPrefix(sLine, "FORMAT="))
+		}
+	}
+
+	sLine, err = r.ReadString('\n')
+	if err != nil {
+		return header, err
+	}
+	var sY, sX string
+	if _, err := fmt.Sscanf(sLine, "%s %d %s %d", &sY, &header.iHeight, &sX, &header.iWidth); err != nil {
+		return header, fmt.Errorf("invalid HDR resolution line %q", strings.TrimSpace(sLine))
+	}
+	if (sY != "-Y" && sY != "+Y") || sX != "+X" {
+		return header, fmt.Errorf("unsupported HDR orientation %s %s", sY, sX)
+	}
+	if header.iWidth <= 0 || header.iHeight <= 0 || header.iWidth > 1<<16 || header.iHeight > 1<<16 {
+		return header, errors.New("invalid HDR image size")
+	}
+	header.bFlipY = sY == "+Y"
+	return header, nil
+}
+
+// hdrReadScanline reads one row of RGBE texels, either flat or run length encoded.
+func hdrReadScanline(r *bufio.Reader, vScanline []byte) error {
+	iWidth := len(vScanline) / 4
+	var vStart [4]byte
+	if _, err := io.ReadFull(r, vStart[:]); err != nil {
+		return err
+	}
+	if iWidth < 8 || iWidth > 0x7FFF || vStart[0] != 2 || vStart[1] != 2 || vStart[2]&0x80 != 0 {
+		// Flat texels, possibly with old style runs (1, 1, 1, count) repeating previous texel
+		copy(vScanline, vStart[:])
+		var iShift uint
+		for x := 1; x < iWidth; {
+			if _, err := io.ReadFull(r, vScanline[x*4:x*4+4]); err != nil {
+				return err
+			}
+			texel := vScanline[x*4 : x*4+4]
+			if texel[0] == 1 && texel[1] == 1 && texel[2] == 1 {
+				iCount := int(texel[3]) << iShift
+				if x+iCount > iWidth {
+					return errors.New("HDR run is too long")
+				}
+				for i := 0; i < iCount; i++ {
+					copy(vScanline[(x+i)*4:], vScanline[(x-1)*4:x*4])
+				}
+				x += iCount
+				iShift += 8
+				continue
+			}
+			iShift = 0
+			x++
+		}
+		return nil
+	}
+	if int(vStart[2])<<8|int(vStart[3]) != iWidth {
+		return errors.New("HDR scanline width doesn't match")
+	}
+	// Each channel is encoded separately, as runs and literal spans
+	for c := 0; c < 4; c++ {
+		for x := 0; x < iWidth; {
+			iCount, err := r.ReadByte()
+			if err != nil {
+				return err
+			}
+			if iCount > 128 {
+				iRun := int(iCount) - 128
+				bValue, err := r.ReadByte()
+				if err != nil {
+					return err
+				}
+				if x+iRun > iWidth {
+					return errors.New("HDR run is too long")
+				}
+				for i := 0; i < iRun; i++ {
+					vScanline[(x+i)*4+c] = bValue
+				}
+				x += iRun
+			} else {
+				if iCount == 0 || x+int(iCount) > iWidth {
+					return errors.New("invalid HDR span")
+				}
+				for i := 0; i < int(iCount); i++ {
+					bValue, err := r.ReadByte()
+					if err != nil {
+						return err
+					}
+					vScanline[(x+i)*4+c] = bValue
+				}
+				x += int(iCount)
+			}
+		}
+	}
+	return nil
+}
+
+/*-----------------------------------------------
+
+  Name:	LoadHDR
+
+  Params:	r - Radiance RGBE (.hdr) file contents
+
+  Result:	Returns linear RGB texels with alpha one,
+  		rows from top to bottom.
+
+  /*---------------------------------------------*/
+
+func LoadHDR(r io.Reader) (*FloatImage, error) {
+	bufReader := bufio.NewReader(r)
+	header, err := hdrReadHeader(bufReader)
+	if err != nil {
+		return nil, err
+	}
+	result := NewFloatImage(header.iWidth, header.iHeight)
+	vScanline := make([]byte, header.iWidth*4)
+	for y := 0; y < header.iHeight; y++ {
+		if err := hdrReadScanline(bufReader, vScanline); err != nil {
+			return nil, fmt.Errorf("HDR scanline %d: %v", y, err)
+		}
+		iRow := y
+		if header.bFlipY {
+			iRow = header.iHeight - 1 - y
+		}
+		for x := 0; x < header.iWidth; x++ {
+			rgbe := vScanline[x*4 : x*4+4]
+			var vTexel [4]float32 = [4]float32{0, 0, 0, 1}
+			if rgbe[3] != 0 {
+				fScale := float32(math.Ldexp(1, int(rgbe[3])-(128+8)))
+				for c := 0; c < 3; c++ {
+					vTexel[c] = float32(rgbe[c]) * fScale
+				}
+			}
+			result.SetTexel(x, iRow, vTexel)
+		}
+	}
+	return result, nil
+}
+
+func LoadHDRFile(sPath string) (*FloatImage, error) {
+	file, err := OpenAsset(sPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadHDR(file)
+}
+
+func DecodeHDR(r io.Reader) (image.Image, error) {
+	return LoadHDR(r)
+}
+
+func DecodeHDRConfig(r io.Reader) (image.Config, error) {
+	header, err := hdrReadHeader(bufio.NewReader(r))
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{ColorModel: color.RGBA64Model, Width: header.iWidth, Height: header.iHeight}, nil
+}
@@ -0,0 +1,92 @@
+package libs
+
+import (
+	"bytes"
+	"testing"
+)
+
+// newHDRFile returns Radiance file with given resolution line and scanline bytes
+func newHDRFile(sResolution string, vScanlines ...[]byte) []byte {
+	data := []byte("#?RADIANCE\nFORMAT=32-bit_rle_rgbe\nEXPOSURE=1.0\n\n" + sResolution + "\n")
+	for _, scanline := range vScanlines {
+		data = append(data, scanline...)
+	}
+	return data
+}
+
+// Exponent 136 scales mantissa by one, so texels read back as the mantissas
+const iHDRUnitExponent = 136
+
+func TestHDRScanlines(t *testing.T) {
+	// New style row of 10 texels: run of 6 and 4 literals for red, other channels as one run or literals
+	bRLE := []byte{2, 2, 0, 10,
+		128 + 6, 7, 4, 1, 2, 3, 4,
+		10, 0, 10, 20, 30, 40, 50, 60, 70, 80, 90,
+		128 + 10, 5,
+		128 + 3, iHDRUnitExponent, 128 + 7, iHDRUnitExponent}
+	// Flat row with old style run repeating texel three times and texel of zero exponent
+	bFlat := []byte{9, 8, 7, iHDRUnitExponent, 1, 1, 1, 3, 100, 50, 25, iHDRUnitExponent + 1, 200, 200, 200, 0,
+		1, 2, 3, iHDRUnitExponent, 4, 5, 6, iHDRUnitExponent, 1, 1, 1, 2}
+	vRows := [2][10][3]float32{
+		{{7, 0, 5}, {7, 10, 5}, {7, 20, 5}, {7, 30, 5}, {7, 40, 5}, {7, 50, 5}, {1, 60, 5}, {2, 70, 5}, {3, 80, 5}, {4, 90, 5}},
+		{{9, 8, 7}, {9, 8, 7}, {9, 8, 7}, {9, 8, 7}, {200, 100, 50}, {0, 0, 0}, {1, 2, 3}, {4, 5, 6}, {4, 5, 6}, {4, 5, 6}},
+	}
+
+	for _, sResolution := range []string{"-Y 2 +X 10", "+Y 2 +X 10"} {
+		img, err := LoadHDR(bytes.NewReader(newHDRFile(sResolution, bRLE, bFlat)))
+		if err != nil {
+			t.Fatalf("%s: %v", sResolution, err)
+		}
+		if img.Width != 10 || img.Height != 2 {
+			t.Fatalf("%s: image is %dx%d", sResolution, img.Width, img.Height)
+		}
+		for iRow, vRow := range vRows {
+			// +Y stores bottom row first
+			y := iRow
+			if sResolution[0] == '+' {
+				y = 1 - iRow
+			}
+			for x, vTexel := range vRow {
+				if img.GetTexel(x, y) != [4]float32{vTexel[0], vTexel[1], vTexel[2], 1} {
+					t.Errorf("%s: texel %d,%d is %v, want %v", sResolution, x, y, img.GetTexel(x, y), vTexel)
+				}
+			}
+		}
+	}
+}
+
+// Consecutive old style runs shift their counts by 8 bits more each
+func TestHDRLongRun(t *testing.T) {
+	bFlat := []byte{1, 2, 3, iHDRUnitExponent, 1, 1, 1, 43, 1, 1, 1, 1}
+	img, err := LoadHDR(bytes.NewReader(newHDRFile("-Y 1 +X 300", bFlat)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := 0; x < img.Width; x++ {
+		if img.GetTexel(x, 0) != [4]float32{1, 2, 3, 1} {
+			t.Fatalf("texel %d is %v", x, img.GetTexel(x, 0))
+		}
+	}
+}
+
+func TestHDRRejects(t *testing.T) {
+	vCases := []struct {
+		sName string
+		data  []byte
+	}{
+		{"run past row", newHDRFile("-Y 1 +X 8", []byte{2, 2, 0, 8, 128 + 9, 1})},
+		{"span past row", newHDRFile("-Y 1 +X 8", []byte{2, 2, 0, 8, 9, 1, 2, 3, 4, 5, 6, 7, 8, 9})},
+		{"empty span", newHDRFile("-Y 1 +X 8", []byte{2, 2, 0, 8, 0})},
+		{"width mismatch", newHDRFile("-Y 1 +X 8", []byte{2, 2, 0, 9, 128 + 9, 1})},
+		{"old run past row", newHDRFile("-Y 1 +X 4", []byte{1, 2, 3, 4, 1, 1, 1, 4})},
+		{"truncated", newHDRFile("-Y 2 +X 8", []byte{2, 2, 0, 8, 128 + 8, 1, 128 + 8, 2, 128 + 8, 3, 128 + 8, 4})},
+		{"orientation", newHDRFile("+X 8 -Y 1", make([]byte, 32))},
+		{"format", []byte("#?RADIANCE\nFORMAT=32-bit_rle_xyze\n\n-Y 1 +X 1\n\x00\x00\x00\x00")},
+		{"size", newHDRFile("-Y 0 +X 8")},
+	}
+	for _, testCase := range vCases {
+		if _, err := LoadHDR(bytes.NewReader(testCase.data)); err == nil {
+			t.Errorf("%s: file was loaded", testCase.sName)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package libs
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"unsafe"
+)
+
+// ImageTexels are texels of image converted to layout OpenGL can take as is
+type ImageTexels struct {
+	Width    int
+	Height   int
+	Channels int      // 1 for grey images, 4 for everything else
+	Pix      []byte   // 8 bit channels, rows tightly packed from top to bottom, nil if Pix16 is used
+	Pix16    []uint16 // 16 bit channels in native byte order, alpha isn't premultiplied
+}
+
+func (this *ImageTexels) Is16Bit() bool {
+	return this.Pix16 != nil
+}
+
+// GetBytesPerTexel returns size of one texel.
+func (this *ImageTexels) GetBytesPerTexel() int {
+	if this.Is16Bit() {
+		return this.Channels * 2
+	}
+	return this.Channels
+}
+
+// GetPointer returns address of first texel for upload.
+func (this *ImageTexels) GetPointer() unsafe.Pointer {
+	if this.Is16Bit() {
+		return unsafe.Pointer(&this.Pix16[0])
+	}
+	return unsafe.Pointer(&this.Pix[0])
+}
+
+/*-----------------------------------------------
+
+  Name:	ConvertImage
+
+  Params:	img - image of any colour model
+  		bKeep16Bit - 16 bit images keep their
+  		precision, otherwise they are reduced to 8
+
+  Result:	Returns texels with non-premultiplied
+  		alpha, grey images stay single channel.
+  		Float images aren't accepted, they are
+  		uploaded as half floats instead.
+
+  /*---------------------------------------------*/
+
+func ConvertImage(img image.Image, bKeep16Bit bool) (*ImageTexels, error) {
+	if img == nil {
+		return nil, errors.New("image is nil")
+	}
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return nil, errors.New("image is empty")
+	}
+	texels := &ImageTexels{Width: bounds.Dx(), Height: bounds.Dy(), Channels: 4}
+	switch src := img.(type) {
+	case *FloatImage:
+		return nil, errors.New("float image can't be converted to integer texels")
+	case *image.Gray:
+		texels.Channels = 1
+		texels.Pix = copyRows(src.Pix, src.PixOffset(bounds.Min.X, bounds.Min.Y), src.Stride, texels.Width, texels.Height)
+	case *image.Gray16:
+		texels.Channels = 1
+		vRows := copyRows(src.Pix, src.PixOffset(bounds.Min.X, bounds.Min.Y), src.Stride, texels.Width*2, texels.Height)
+		if bKeep16Bit {
+			texels.Pix16 = bigEndianTo16(vRows)
+		} else {
+			texels.Pix = bigEndianTo8(vRows)
+		}
+	case *image.NRGBA64:
+		vRows := copyRows(src.Pix, src.PixOffset(bounds.Min.X, bounds.Min.Y), src.Stride, texels.Width*8, texels.Height)
+		if bKeep16Bit {
+			texels.Pix16 = bigEndianTo16(vRows)
+		} else {
+			texels.Pix = bigEndianTo8(vRows)
+		}
+	case *image.RGBA64:
+		if bKeep16Bit {
+			texels.Pix16 = toNRGBA16(src)
+		} else {
+			texels.Pix = ToNRGBA(src).Pix
+		}
+	case *image.YCbCr:
+		texels.Pix = toOpaqueRGBA(src)
+	case *image.CMYK:
+		texels.Pix = toOpaqueRGBA(src)
+	case *image.Paletted:
+		texels.Pix = fromPalette(src)
+	default:
+		// RGBA, NRGBA, Alpha, TGA and container images, ToNRGBA removes premultiplication
+		texels.Pix = ToNRGBA(img).Pix
+	}
+	return texels, nil
+}
+
+// copyRows returns iRows rows of iRowBytes bytes starting at iOffset without stride padding
+func copyRows(bPix []byte, iOffset, iStride, iRowBytes, iRows int) []byte {
+	if iStride == iRowBytes && iOffset == 0 && len(bPix) == iRowBytes*iRows {
+		return bPix
+	}
+	bRows := make([]byte, iRowBytes*iRows)
+	for y := 0; y < iRows; y++ {
+		copy(bRows[y*iRowBytes:(y+1)*iRowBytes], bPix[iOffset+y*iStride:])
+	}
+	return bRows
+}
+
+// bigEndianTo16 turns big endian channels of Go's 16 bit images to native ones
+func bigEndianTo16(bPix []byte) []uint16 {
+	vPix := make([]uint16, len(bPix)/2)
+	for i := range vPix {
+		vPix[i] = uint16(bPix[2*i])<<8 | uint16(bPix[2*i+1])
+	}
+	return vPix
+}
+
+// bigEndianTo8 keeps high bytes of big endian 16 bit channels
+func bigEndianTo8(bPix []byte) []byte {
+	bTexels := make([]byte, len(bPix)/2)
+	for i := range bTexels {
+		bTexels[i] = bPix[2*i]
+	}
+	return bTexels
+}
+
+func toNRGBA16(img *image.RGBA64) []uint16 {
+	bounds := img.Bounds()
+	vPix := make([]uint16, 0, bounds.Dx()*bounds.Dy()*4)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBA64Model.Convert(img.RGBA64At(x, y)).(color.NRGBA64)
+			vPix = append(vPix, c.R, c.G, c.B, c.A)
+		}
+	}
+	return vPix
+}
+
+// toOpaqueRGBA converts image without alpha, premultiplied and straight alpha are the same then
+func toOpaqueRGBA(img image.Image) []byte {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba.Pix
+}
+
+// fromPalette looks every texel up in palette converted once
+func fromPalette(img *image.Paletted) []byte {
+	var vPalette [256][4]byte
+	for i, c := range img.Palette {
+		nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+		vPalette[i] = [4]byte{nrgba.R, nrgba.G, nrgba.B, nrgba.A}
+	}
+	bounds := img.Bounds()
+	bPix := make([]byte, 0, bounds.Dx()*bounds.Dy()*4)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for _, iIndex := range img.Pix[img.PixOffset(bounds.Min.X, y):img.PixOffset(bounds.Max.X, y)] {
+			bPix = append(bPix, vPalette[iIndex][:]...)
+		}
+	}
+	return bPix
+}
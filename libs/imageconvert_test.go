@@ -0,0 +1,155 @@
+package libs
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// fillTestImage sets every texel to different colour, alpha changes too if bAlpha
+func fillTestImage(img image.Image, bAlpha bool) {
+	dst := img.(interface{ Set(x, y int, c color.Color) })
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var iAlpha uint16 = 0xFFFF
+			if bAlpha {
+				iAlpha = uint16(0x3000 + 0x1234*(x+y))
+			}
+			dst.Set(x, y, color.NRGBA64{uint16(0x1111 * x), uint16(0x0F0F * y), uint16(0x2222*x + 0x0101*y), iAlpha})
+		}
+	}
+}
+
+/*-----------------------------------------------
+
+  Name:	expectTexels
+
+  Params:	img - converted image
+  		iChannels - 1 for grey, 4 otherwise
+  		b16Bit - whether 16 bit channels are kept
+
+  Result:	Converts image texel by texel through
+  		colour models, ConvertImage must give the
+  		same.
+
+  /*---------------------------------------------*/
+
+func expectTexels(img image.Image, iChannels int, b16Bit bool) ([]byte, []uint16) {
+	var bPix []byte
+	var vPix16 []uint16
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var vChannels []uint16
+			if iChannels == 1 {
+				vChannels = []uint16{color.Gray16Model.Convert(img.At(x, y)).(color.Gray16).Y}
+			} else if c, bOK := img.At(x, y).(color.NRGBA); bOK {
+				// Straight alpha colours keep channels of transparent texels, premultiplying would lose them
+				vChannels = []uint16{uint16(c.R) * 0x101, uint16(c.G) * 0x101, uint16(c.B) * 0x101, uint16(c.A) * 0x101}
+			} else {
+				c := color.NRGBA64Model.Convert(img.At(x, y)).(color.NRGBA64)
+				vChannels = []uint16{c.R, c.G, c.B, c.A}
+			}
+			for _, iChannel := range vChannels {
+				if b16Bit {
+					vPix16 = append(vPix16, iChannel)
+				} else {
+					bPix = append(bPix, uint8(iChannel>>8))
+				}
+			}
+		}
+	}
+	return bPix, vPix16
+}
+
+func TestConvertImage(t *testing.T) {
+	// Sub-images start away from 0,0, so offsets and strides of the source are tested as well
+	rect := image.Rect(0, 0, 9, 7)
+	subRect := image.Rect(1, 2, 8, 7)
+
+	gray16 := image.NewGray16(rect)
+	fillTestImage(gray16, false)
+	nrgba64 := image.NewNRGBA64(rect)
+	fillTestImage(nrgba64, true)
+	rgba64 := image.NewRGBA64(rect)
+	fillTestImage(rgba64, true)
+	cmyk := image.NewCMYK(rect)
+	fillTestImage(cmyk, false)
+	gray := image.NewGray(rect)
+	fillTestImage(gray, false)
+	nrgba := image.NewNRGBA(rect)
+	fillTestImage(nrgba, true)
+
+	ycbcr := image.NewYCbCr(rect, image.YCbCrSubsampleRatio420)
+	for i := range ycbcr.Y {
+		ycbcr.Y[i] = uint8(i * 7)
+	}
+	for i := range ycbcr.Cb {
+		ycbcr.Cb[i] = uint8(64 + i*13)
+		ycbcr.Cr[i] = uint8(192 - i*11)
+	}
+	paletted := image.NewPaletted(rect, color.Palette{color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 128, 255, 128}, color.NRGBA{10, 20, 30, 0}, color.Gray{200}})
+	for i := range paletted.Pix {
+		paletted.Pix[i] = uint8(i % len(paletted.Palette))
+	}
+
+	vCases := []struct {
+		sName     string
+		img       image.Image
+		iChannels int
+	}{
+		{"YCbCr", ycbcr, 4},
+		{"Paletted", paletted, 4},
+		{"Gray", gray, 1},
+		{"Gray16", gray16, 1},
+		{"CMYK", cmyk, 4},
+		{"RGBA64", rgba64, 4},
+		{"NRGBA64", nrgba64, 4},
+		{"NRGBA", nrgba, 4},
+	}
+	for _, testCase := range vCases {
+		imgSub := testCase.img.(interface {
+			SubImage(r image.Rectangle) image.Image
+		}).SubImage(subRect)
+		for _, imgSource := range []image.Image{testCase.img, imgSub} {
+			for _, bKeep16Bit := range []bool{false, true} {
+				texels, err := ConvertImage(imgSource, bKeep16Bit)
+				if err != nil {
+					t.Fatalf("%s: %v", testCase.sName, err)
+				}
+				bounds := imgSource.Bounds()
+				if texels.Width != bounds.Dx() || texels.Height != bounds.Dy() || texels.Channels != testCase.iChannels {
+					t.Fatalf("%s %v: got %dx%d with %d channels", testCase.sName, bounds, texels.Width, texels.Height, texels.Channels)
+				}
+				// Only 16 bit models keep their precision
+				b16Bit := bKeep16Bit && (testCase.img == gray16 || testCase.img == rgba64 || testCase.img == nrgba64)
+				if texels.Is16Bit() != b16Bit {
+					t.Fatalf("%s %v: 16 bit %v, keep %v", testCase.sName, bounds, texels.Is16Bit(), bKeep16Bit)
+				}
+				bPix, vPix16 := expectTexels(imgSource, testCase.iChannels, b16Bit)
+				if len(texels.Pix) != len(bPix) || len(texels.Pix16) != len(vPix16) {
+					t.Fatalf("%s %v: got %d and %d channels, want %d and %d", testCase.sName, bounds, len(texels.Pix), len(texels.Pix16), len(bPix), len(vPix16))
+				}
+				for i := range bPix {
+					if texels.Pix[i] != bPix[i] {
+						t.Fatalf("%s %v: texel %d channel %d is %d, want %d", testCase.sName, bounds, i/testCase.iChannels, i%testCase.iChannels, texels.Pix[i], bPix[i])
+					}
+				}
+				for i := range vPix16 {
+					if texels.Pix16[i] != vPix16[i] {
+						t.Fatalf("%s %v: texel %d channel %d is %d, want %d", testCase.sName, bounds, i/testCase.iChannels, i%testCase.iChannels, texels.Pix16[i], vPix16[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestConvertImageRejects(t *testing.T) {
+	for _, img := range []image.Image{nil, image.NewNRGBA(image.Rect(2, 2, 2, 5)), NewFloatImage(2, 2)} {
+		if _, err := ConvertImage(img, false); err == nil {
+			t.Errorf("%T was converted", img)
+		}
+	}
+}
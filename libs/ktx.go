@@ -0,0 +1,665 @@
+package libs
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+)
+
+var ktx1Identifier = []byte{0xAB, 'K', 'T', 'X', ' ', '1', '1', 0xBB, '\r', '\n', 0x1A, '\n'}
+var ktx2Identifier = []byte{0xAB, 'K', 'T', 'X', ' ', '2', '0', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+func init() {
+	image.RegisterFormat("ktx", string(ktx1Identifier), DecodeKTX, DecodeKTXConfig)
+	image.RegisterFormat("ktx2", string(ktx2Identifier), DecodeKTX, DecodeKTXConfig)
+}
+
+// KTX1 header after the identifier
+type ktxHeader struct {
+	Endianness            uint32
+	GLType                uint32
+	GLTypeSize            uint32
+	GLFormat              uint32
+	GLInternalFormat      uint32
+	GLBaseInternalFormat  uint32
+	PixelWidth            uint32
+	PixelHeight           uint32
+	PixelDepth            uint32
+	NumberOfArrayElements uint32
+	NumberOfFaces         uint32
+	NumberOfMipmapLevels  uint32
+	BytesOfKeyValueData   uint32
+}
+
+// KTX2 header and index after the identifier
+type ktx2Header struct {
+	VkFormat               uint32
+	TypeSize               uint32
+	PixelWidth             uint32
+	PixelHeight            uint32
+	PixelDepth             uint32
+	LayerCount             uint32
+	FaceCount              uint32
+	LevelCount             uint32
+	SupercompressionScheme uint32
+	DFDByteOffset          uint32
+	DFDByteLength          uint32
+	KVDByteOffset          uint32
+	KVDByteLength          uint32
+	SGDByteOffset          uint64
+	SGDByteLength          uint64
+}
+
+type ktx2LevelIndex struct {
+	ByteOffset             uint64
+	ByteLength             uint64
+	UncompressedByteLength uint64
+}
+
+const KTX_ENDIANNESS = 0x04030201
+
+// OpenGL enums KTX1 stores, libs doesn't depend on gl
+const (
+	KTX_GL_UNSIGNED_BYTE          = 0x1401
+	KTX_GL_UNSIGNED_SHORT_4_4_4_4 = 0x8033
+	KTX_GL_UNSIGNED_SHORT_5_5_5_1 = 0x8034
+	KTX_GL_UNSIGNED_SHORT_5_6_5   = 0x8363
+
+	KTX_GL_RED             = 0x1903
+	KTX_GL_ALPHA           = 0x1906
+	KTX_GL_RGB             = 0x1907
+	KTX_GL_RGBA            = 0x1908
+	KTX_GL_LUMINANCE       = 0x1909
+	KTX_GL_LUMINANCE_ALPHA = 0x190A
+	KTX_GL_RG              = 0x8227
+	KTX_GL_BGR             = 0x80E0
+	KTX_GL_BGRA            = 0x80E1
+	KTX_GL_SRGB8           = 0x8C41
+	KTX_GL_SRGB8_ALPHA8    = 0x8C43
+
+	KTX_GL_COMPRESSED_RGB_S3TC_DXT1        = 0x83F0
+	KTX_GL_COMPRESSED_RGBA_S3TC_DXT1       = 0x83F1
+	KTX_GL_COMPRESSED_RGBA_S3TC_DXT3       = 0x83F2
+	KTX_GL_COMPRESSED_RGBA_S3TC_DXT5       = 0x83F3
+	KTX_GL_COMPRESSED_SRGB_S3TC_DXT1       = 0x8C4C
+	KTX_GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT1 = 0x8C4D
+	KTX_GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT3 = 0x8C4E
+	KTX_GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT5 = 0x8C4F
+	KTX_GL_COMPRESSED_RED_RGTC1            = 0x8DBB
+	KTX_GL_COMPRESSED_SIGNED_RED_RGTC1     = 0x8DBC
+	KTX_GL_COMPRESSED_RG_RGTC2             = 0x8DBD
+	KTX_GL_COMPRESSED_SIGNED_RG_RGTC2      = 0x8DBE
+	KTX_GL_COMPRESSED_RGBA_BPTC_UNORM      = 0x8E8C
+	KTX_GL_COMPRESSED_SRGB_ALPHA_BPTC      = 0x8E8D
+	KTX_GL_COMPRESSED_RGB_BPTC_SIGNED      = 0x8E8E
+	KTX_GL_COMPRESSED_RGB_BPTC_UNSIGNED    = 0x8E8F
+)
+
+// VkFormat values KTX2 files can use with us
+const (
+	VK_FORMAT_R4G4B4A4_UNORM_PACK16 = 2
+	VK_FORMAT_R5G6B5_UNORM_PACK16   = 4
+	VK_FORMAT_B5G6R5_UNORM_PACK16   = 5
+	VK_FORMAT_R5G5B5A1_UNORM_PACK16 = 6
+	VK_FORMAT_R8_UNORM              = 9
+	VK_FORMAT_R8_SRGB               = 15
+	VK_FORMAT_R8G8_UNORM            = 16
+	VK_FORMAT_R8G8_SRGB             = 22
+	VK_FORMAT_R8G8B8_UNORM          = 23
+	VK_FORMAT_R8G8B8_SRGB           = 29
+	VK_FORMAT_B8G8R8_UNORM          = 30
+	VK_FORMAT_B8G8R8_SRGB           = 36
+	VK_FORMAT_R8G8B8A8_UNORM        = 37
+	VK_FORMAT_R8G8B8A8_SRGB         = 43
+	VK_FORMAT_B8G8R8A8_UNORM        = 44
+	VK_FORMAT_B8G8R8A8_SRGB         = 50
+	VK_FORMAT_BC1_RGB_UNORM_BLOCK   = 131
+	VK_FORMAT_BC1_RGB_SRGB_BLOCK    = 132
+	VK_FORMAT_BC1_RGBA_UNORM_BLOCK  = 133
+	VK_FORMAT_BC1_RGBA_SRGB_BLOCK   = 134
+	VK_FORMAT_BC2_UNORM_BLOCK       = 135
+	VK_FORMAT_BC2_SRGB_BLOCK        = 136
+	VK_FORMAT_BC3_UNORM_BLOCK       = 137
+	VK_FORMAT_BC3_SRGB_BLOCK        = 138
+	VK_FORMAT_BC4_UNORM_BLOCK       = 139
+	VK_FORMAT_BC4_SNORM_BLOCK       = 140
+	VK_FORMAT_BC5_UNORM_BLOCK       = 141
+	VK_FORMAT_BC5_SNORM_BLOCK       = 142
+	VK_FORMAT_BC6H_UFLOAT_BLOCK     = 143
+	VK_FORMAT_BC6H_SFLOAT_BLOCK     = 144
+	VK_FORMAT_BC7_UNORM_BLOCK       = 145
+	VK_FORMAT_BC7_SRGB_BLOCK        = 146
+)
+
+// KTX2 supercompression schemes
+const (
+	KTX2_SUPERCOMPRESSION_NONE    = 0
+	KTX2_SUPERCOMPRESSION_BASISLZ = 1
+	KTX2_SUPERCOMPRESSION_ZSTD    = 2
+	KTX2_SUPERCOMPRESSION_ZLIB    = 3
+)
+
+var sKTX2SupercompressionNames = []string{"none", "BasisLZ", "Zstandard", "ZLIB"}
+
+// ktxFormat describes stored texels, uncompressed ones are converted to RGBA8 with DDS mask decoder
+type ktxFormat struct {
+	format         TexelFormat
+	bSRGB          bool
+	pf             DDSPixelFormat // Masks of uncompressed format
+	iBytesPerPixel int
+}
+
+func ktxCompressedFormat(format TexelFormat, bSRGB bool) (ktxFormat, bool) {
+	return ktxFormat{format: format, bSRGB: bSRGB}, true
+}
+
+func ktxPixelFormat(iBytesPerPixel int, bSRGB bool, iFlags, r, g, b, a DDSUint) (ktxFormat, bool) {
+	pf := DDSPixelFormat{Size: 32, Flags: iFlags, RGBBitCount: DDSUint(iBytesPerPixel * 8), RBitMask: r, GBitMask: g, BBitMask: b, ABitMask: a}
+	return ktxFormat{format: TEXEL_FORMAT_RGBA8, bSRGB: bSRGB, pf: pf, iBytesPerPixel: iBytesPerPixel}, true
+}
+
+func ktxGetGLFormat(header *ktxHeader) (ktxFormat, bool) {
+	if header.GLType == 0 {
+		switch header.GLInternalFormat {
+		case KTX_GL_COMPRESSED_RGB_S3TC_DXT1, KTX_GL_COMPRESSED_RGBA_S3TC_DXT1:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC1, false)
+		case KTX_GL_COMPRESSED_SRGB_S3TC_DXT1, KTX_GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT1:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC1, true)
+		case KTX_GL_COMPRESSED_RGBA_S3TC_DXT3:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC2, false)
+		case KTX_GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT3:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC2, true)
+		case KTX_GL_COMPRESSED_RGBA_S3TC_DXT5:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC3, false)
+		case KTX_GL_COMPRESSED_SRGB_ALPHA_S3TC_DXT5:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC3, true)
+		case KTX_GL_COMPRESSED_RED_RGTC1:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC4, false)
+		case KTX_GL_COMPRESSED_SIGNED_RED_RGTC1:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC4_SIGNED, false)
+		case KTX_GL_COMPRESSED_RG_RGTC2:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC5, false)
+		case KTX_GL_COMPRESSED_SIGNED_RG_RGTC2:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC5_SIGNED, false)
+		case KTX_GL_COMPRESSED_RGBA_BPTC_UNORM:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC7, false)
+		case KTX_GL_COMPRESSED_SRGB_ALPHA_BPTC:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC7, true)
+		case KTX_GL_COMPRESSED_RGB_BPTC_UNSIGNED:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC6H, false)
+		case KTX_GL_COMPRESSED_RGB_BPTC_SIGNED:
+			return ktxCompressedFormat(TEXEL_FORMAT_BC6H_SIGNED, false)
+		}
+		return ktxFormat{}, false
+	}
+
+	var bSRGB bool = header.GLInternalFormat == KTX_GL_SRGB8 || header.GLInternalFormat == KTX_GL_SRGB8_ALPHA8
+	switch header.GLType {
+	case KTX_GL_UNSIGNED_BYTE:
+		switch header.GLFormat {
+		case KTX_GL_RGBA:
+			return ktxPixelFormat(4, bSRGB, DDPF_RGB|DDPF_ALPHAPIXELS, 0xFF, 0xFF00, 0xFF0000, 0xFF000000)
+		case KTX_GL_BGRA:
+			return ktxPixelFormat(4, bSRGB, DDPF_RGB|DDPF_ALPHAPIXELS, 0xFF0000, 0xFF00, 0xFF, 0xFF000000)
+		case KTX_GL_RGB:
+			return ktxPixelFormat(3, bSRGB, DDPF_RGB, 0xFF, 0xFF00, 0xFF0000, 0)
+		case KTX_GL_BGR:
+			return ktxPixelFormat(3, bSRGB, DDPF_RGB, 0xFF0000, 0xFF00, 0xFF, 0)
+		case KTX_GL_RG:
+			return ktxPixelFormat(2, false, DDPF_RGB, 0xFF, 0xFF00, 0, 0)
+		case KTX_GL_RED:
+			return ktxPixelFormat(1, false, DDPF_RGB, 0xFF, 0, 0, 0)
+		case KTX_GL_LUMINANCE:
+			return ktxPixelFormat(1, false, DDPF_LUMINANCE, 0xFF, 0, 0, 0)
+		case KTX_GL_LUMINANCE_ALPHA:
+			return ktxPixelFormat(2, false, DDPF_LUMINANCE|DDPF_ALPHAPIXELS, 0xFF, 0, 0, 0xFF00)
+		case KTX_GL_ALPHA:
+			return ktxPixelFormat(1, false, DDPF_ALPHA, 0, 0, 0, 0xFF)
+		}
+	case KTX_GL_UNSIGNED_SHORT_5_6_5:
+		if header.GLFormat == KTX_GL_RGB {
+			return ktxPixelFormat(2, false, DDPF_RGB, 0xF800, 0x7E0, 0x1F, 0)
+		}
+	case KTX_GL_UNSIGNED_SHORT_4_4_4_4:
+		if header.GLFormat == KTX_GL_RGBA {
+			return ktxPixelFormat(2, false, DDPF_RGB|DDPF_ALPHAPIXELS, 0xF000, 0xF00, 0xF0, 0xF)
+		}
+	case KTX_GL_UNSIGNED_SHORT_5_5_5_1:
+		if header.GLFormat == KTX_GL_RGBA {
+			return ktxPixelFormat(2, false, DDPF_RGB|DDPF_ALPHAPIXELS, 0xF800, 0x7C0, 0x3E, 0x1)
+		}
+	}
+	return ktxFormat{}, false
+}
+
+func ktxGetVkFormat(iVkFormat uint32) (ktxFormat, bool) {
+	switch iVkFormat {
+	case VK_FORMAT_BC1_RGB_UNORM_BLOCK, VK_FORMAT_BC1_RGBA_UNORM_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC1, false)
+	case VK_FORMAT_BC1_RGB_SRGB_BLOCK, VK_FORMAT_BC1_RGBA_SRGB_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC1, true)
+	case VK_FORMAT_BC2_UNORM_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC2, false)
+	case VK_FORMAT_BC2_SRGB_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC2, true)
+	case VK_FORMAT_BC3_UNORM_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC3, false)
+	case VK_FORMAT_BC3_SRGB_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC3, true)
+	case VK_FORMAT_BC4_UNORM_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC4, false)
+	case VK_FORMAT_BC4_SNORM_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC4_SIGNED, false)
+	case VK_FORMAT_BC5_UNORM_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC5, false)
+	case VK_FORMAT_BC5_SNORM_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC5_SIGNED, false)
+	case VK_FORMAT_BC6H_UFLOAT_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC6H, false)
+	case VK_FORMAT_BC6H_SFLOAT_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC6H_SIGNED, false)
+	case VK_FORMAT_BC7_UNORM_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC7, false)
+	case VK_FORMAT_BC7_SRGB_BLOCK:
+		return ktxCompressedFormat(TEXEL_FORMAT_BC7, true)
+	case VK_FORMAT_R8G8B8A8_UNORM, VK_FORMAT_R8G8B8A8_SRGB:
+		return ktxPixelFormat(4, iVkFormat == VK_FORMAT_R8G8B8A8_SRGB, DDPF_RGB|DDPF_ALPHAPIXELS, 0xFF, 0xFF00, 0xFF0000, 0xFF000000)
+	case VK_FORMAT_B8G8R8A8_UNORM, VK_FORMAT_B8G8R8A8_SRGB:
+		return ktxPixelFormat(4, iVkFormat == VK_FORMAT_B8G8R8A8_SRGB, DDPF_RGB|DDPF_ALPHAPIXELS, 0xFF0000, 0xFF00, 0xFF, 0xFF000000)
+	case VK_FORMAT_R8G8B8_UNORM, VK_FORMAT_R8G8B8_SRGB:
+		return ktxPixelFormat(3, iVkFormat == VK_FORMAT_R8G8B8_SRGB, DDPF_RGB, 0xFF, 0xFF00, 0xFF0000, 0)
+	case VK_FORMAT_B8G8R8_UNORM, VK_FORMAT_B8G8R8_SRGB:
+		return ktxPixelFormat(3, iVkFormat == VK_FORMAT_B8G8R8_SRGB, DDPF_RGB, 0xFF0000, 0xFF00, 0xFF, 0)
+	case VK_FORMAT_R8G8_UNORM, VK_FORMAT_R8G8_SRGB:
+		return ktxPixelFormat(2, iVkFormat == VK_FORMAT_R8G8_SRGB, DDPF_RGB, 0xFF, 0xFF00, 0, 0)
+	case VK_FORMAT_R8_UNORM, VK_FORMAT_R8_SRGB:
+		return ktxPixelFormat(1, iVkFormat == VK_FORMAT_R8_SRGB, DDPF_RGB, 0xFF, 0, 0, 0)
+	case VK_FORMAT_R5G6B5_UNORM_PACK16:
+		return ktxPixelFormat(2, false, DDPF_RGB, 0xF800, 0x7E0, 0x1F, 0)
+	case VK_FORMAT_B5G6R5_UNORM_PACK16:
+		return ktxPixelFormat(2, false, DDPF_RGB, 0x1F, 0x7E0, 0xF800, 0)
+	case VK_FORMAT_R4G4B4A4_UNORM_PACK16:
+		return ktxPixelFormat(2, false, DDPF_RGB|DDPF_ALPHAPIXELS, 0xF000, 0xF00, 0xF0, 0xF)
+	case VK_FORMAT_R5G5B5A1_UNORM_PACK16:
+		return ktxPixelFormat(2, false, DDPF_RGB|DDPF_ALPHAPIXELS, 0xF800, 0x7C0, 0x3E, 0x1)
+	}
+	return ktxFormat{}, false
+}
+
+// getSliceSize returns bytes of one 2D slice as stored, iRowAlignment pads uncompressed rows (4 in KTX1, 1 in KTX2).
+func (this *ktxFormat) getSliceSize(iWidth, iHeight, iRowAlignment int) int {
+	if this.format.IsCompressed() {
+		return this.format.GetDataSize(iWidth, iHeight)
+	}
+	return ktxAlign(iWidth*this.iBytesPerPixel, iRowAlignment) * iHeight
+}
+
+/*-----------------------------------------------
+
+  Name:	convertSurface
+
+  Params:	data - stored slices of one surface
+  		surface - surface with size set, gets Data
+  		iRowAlignment - row padding of the data
+  		iSwapSize - size of texel components whose
+  		bytes need swapping, 1 for none
+
+  Result:	Keeps compressed data as it is, turns
+  		uncompressed one into RGBA8. Rows stay in
+  		stored order, TextureData.BottomUp tells it.
+
+  /*---------------------------------------------*/
+
+func (this *ktxFormat) convertSurface(data []byte, surface *TextureSurface, iRowAlignment int, iSwapSize int) error {
+	if this.format.IsCompressed() {
+		surface.Data = data
+		return nil
+	}
+	iRowSize := surface.Width * this.iBytesPerPixel
+	iPitch := ktxAlign(iRowSize, iRowAlignment)
+	tight := make([]byte, 0, iRowSize*surface.Height*surface.Depth)
+	for z := 0; z < surface.Depth; z++ {
+		for y := 0; y < surface.Height; y++ {
+			iStart := (z*surface.Height + y) * iPitch
+			tight = append(tight, data[iStart:iStart+iRowSize]...)
+		}
+	}
+	// Packed 16 bit texels follow file endianness, mask decoder reads little endian
+	if iSwapSize == 2 {
+		for i := 0; i+1 < len(tight); i += 2 {
+			tight[i], tight[i+1] = tight[i+1], tight[i]
+		}
+	}
+	pixels, err := ddsConvertUncompressed(&this.pf, tight, surface.Width, surface.Height, surface.Depth)
+	if err != nil {
+		return err
+	}
+	surface.Data = pixels
+	return nil
+}
+
+func ktxAlign(iValue, iAlignment int) int {
+	return (iValue + iAlignment - 1) / iAlignment * iAlignment
+}
+
+// ktxParseKeyValues reads key/value data shared by both versions, each entry is padded to 4 bytes.
+func ktxParseKeyValues(data []byte, byteOrder binary.ByteOrder) map[string]string {
+	mValues := make(map[string]string)
+	for len(data) >= 4 {
+		iSize := int(byteOrder.Uint32(data))
+		data = data[4:]
+		if iSize > len(data) {
+			break
+		}
+		entry := data[:iSize]
+		if iEnd := bytes.IndexByte(entry, 0); iEnd >= 0 {
+			mValues[string(entry[:iEnd])] = strings.TrimRight(string(entry[iEnd+1:]), "\x00")
+		}
+		data = data[MIN(len(data), ktxAlign(iSize, 4)):]
+	}
+	return mValues
+}
+
+// ktxIsBottomUp tells whether rows are stored from bottom to top, KTX1 writes "S=r,T=u", KTX2 "ru".
+// Files without orientation are taken as top-down, as toktx and most other tools write them.
+func ktxIsBottomUp(mValues map[string]string) bool {
+	sOrientation := mValues["KTXorientation"]
+	return strings.Contains(sOrientation, "T=u") || (len(sOrientation) >= 2 && sOrientation[1] == 'u')
+}
+
+func ktxNewTextureData(format *ktxFormat, iWidth, iHeight, iDepth, iLayers, iFaces, iLevels uint32) (*TextureData, error) {
+	texData := &TextureData{Format: format.format, SRGB: format.bSRGB, SRGBKnown: true, Width: int(iWidth), Height: MAX(1, int(iHeight)),
+		Depth: MAX(1, int(iDepth)), Faces: int(iFaces), Layers: MAX(1, int(iLayers)), MipLevels: MAX(1, int(iLevels))}
+	if err := texData.validate(); err != nil {
+		return nil, err
+	}
+	if texData.MipLevels > 32 || texData.Width > 1<<16 || texData.Height > 1<<16 || texData.Depth > 1<<16 {
+		return nil, errors.New("KTX texture is too large")
+	}
+	texData.Surfaces = make([]TextureSurface, texData.Layers*texData.Faces*texData.MipLevels)
+	return texData, nil
+}
+
+/*-----------------------------------------------
+
+  Name:	ktxParse
+
+  Params:	data - whole KTX1 file
+
+  Result:	Returns every surface of the texture.
+  		Files store levels first, then layers and
+  		faces, so surfaces are reordered.
+
+  /*---------------------------------------------*/
+
+func ktxParse(data []byte) (*TextureData, error) {
+	if !bytes.HasPrefix(data, ktx1Identifier) {
+		return nil, errors.New("not a KTX file")
+	}
+	data = data[len(ktx1Identifier):]
+	var header ktxHeader
+	var byteOrder binary.ByteOrder = binary.LittleEndian
+	if len(data) < 4 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if binary.LittleEndian.Uint32(data) != KTX_ENDIANNESS {
+		byteOrder = binary.BigEndian
+	}
+	dataReader := bytes.NewReader(data)
+	if err := binary.Read(dataReader, byteOrder, &header); err != nil {
+		return nil, err
+	}
+	if header.Endianness != KTX_ENDIANNESS {
+		return nil, errors.New("invalid KTX endianness")
+	}
+	format, bFound := ktxGetGLFormat(&header)
+	if !bFound {
+		return nil, fmt.Errorf("unsupported KTX format (type 0x%X, format 0x%X, internal format 0x%X)", header.GLType, header.GLFormat, header.GLInternalFormat)
+	}
+	texData, err := ktxNewTextureData(&format, header.PixelWidth, header.PixelHeight, header.PixelDepth,
+		header.NumberOfArrayElements, header.NumberOfFaces, header.NumberOfMipmapLevels)
+	if err != nil {
+		return nil, err
+	}
+	// Unsized internal format like GL_RGBA doesn't say whether texels are sRGB
+	texData.SRGBKnown = header.GLType == 0 || header.GLInternalFormat != header.GLFormat
+
+	data = data[len(data)-dataReader.Len():]
+	if int(header.BytesOfKeyValueData) > len(data) {
+		return nil, errors.New("KTX key/value data is truncated")
+	}
+	texData.BottomUp = ktxIsBottomUp(ktxParseKeyValues(data[:header.BytesOfKeyValueData], byteOrder))
+	data = data[header.BytesOfKeyValueData:]
+
+	iSwapSize := 1
+	if byteOrder == binary.BigEndian {
+		iSwapSize = int(header.GLTypeSize)
+	}
+	// Only faces of non-array cubemaps are padded on their own
+	bCubePadding := texData.IsCubemap() && header.NumberOfArrayElements == 0
+	for iMip := 0; iMip < texData.MipLevels; iMip++ {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("KTX data is truncated at mip level %d", iMip)
+		}
+		iImageSize := int(byteOrder.Uint32(data))
+		data = data[4:]
+		level := TextureSurface{Width: GetMipSize(texData.Width, iMip), Height: GetMipSize(texData.Height, iMip), Depth: GetMipSize(texData.Depth, iMip)}
+		iSurfaceSize := format.getSliceSize(level.Width, level.Height, 4) * level.Depth
+		iLevelSize := iSurfaceSize * texData.Layers * texData.Faces
+		if bCubePadding {
+			iLevelSize = ktxAlign(iSurfaceSize, 4) * texData.Faces
+		}
+		if len(data) < iLevelSize || (!bCubePadding && iImageSize < iLevelSize) {
+			return nil, fmt.Errorf("KTX data is truncated at mip level %d", iMip)
+		}
+		levelData := data
+		for iLayer := 0; iLayer < texData.Layers; iLayer++ {
+			for iFace := 0; iFace < texData.Faces; iFace++ {
+				surface := texData.GetSurface(iLayer, iFace, iMip)
+				*surface = level
+				if err := format.convertSurface(levelData[:iSurfaceSize], surface, 4, iSwapSize); err != nil {
+					return nil, err
+				}
+				if bCubePadding {
+					levelData = levelData[ktxAlign(iSurfaceSize, 4):]
+				} else {
+					levelData = levelData[iSurfaceSize:]
+				}
+			}
+		}
+		data = data[MIN(len(data), ktxAlign(iLevelSize, 4)):]
+	}
+	return texData, nil
+}
+
+/*-----------------------------------------------
+
+  Name:	ktx2Parse
+
+  Params:	data - whole KTX2 file
+
+  Result:	Returns every surface of the texture.
+  		ZLIB supercompression is inflated, other
+  		schemes and Basis Universal payloads are
+  		reported as unsupported.
+
+  /*---------------------------------------------*/
+
+func ktx2Parse(data []byte) (*TextureData, error) {
+	if !bytes.HasPrefix(data, ktx2Identifier) {
+		return nil, errors.New("not a KTX2 file")
+	}
+	dataReader := bytes.NewReader(data[len(ktx2Identifier):])
+	var header ktx2Header
+	if err := binary.Read(dataReader, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.SupercompressionScheme != KTX2_SUPERCOMPRESSION_NONE && header.SupercompressionScheme != KTX2_SUPERCOMPRESSION_ZLIB {
+		sScheme := fmt.Sprint(header.SupercompressionScheme)
+		if int(header.SupercompressionScheme) < len(sKTX2SupercompressionNames) {
+			sScheme = sKTX2SupercompressionNames[header.SupercompressionScheme]
+		}
+		return nil, fmt.Errorf("unsupported KTX2 supercompression scheme %s", sScheme)
+	}
+	if header.VkFormat == 0 {
+		return nil, errors.New("unsupported KTX2 format: Basis Universal and other undefined formats can't be loaded")
+	}
+	format, bFound := ktxGetVkFormat(header.VkFormat)
+	if !bFound {
+		return nil, fmt.Errorf("unsupported KTX2 format (VkFormat %d)", header.VkFormat)
+	}
+	texData, err := ktxNewTextureData(&format, header.PixelWidth, header.PixelHeight, header.PixelDepth,
+		header.LayerCount, header.FaceCount, header.LevelCount)
+	if err != nil {
+		return nil, err
+	}
+
+	vLevels := make([]ktx2LevelIndex, texData.MipLevels)
+	if err := binary.Read(dataReader, binary.LittleEndian, vLevels); err != nil {
+		return nil, err
+	}
+	if header.KVDByteLength > 0 {
+		if uint64(header.KVDByteOffset)+uint64(header.KVDByteLength) > uint64(len(data)) {
+			return nil, errors.New("KTX2 key/value data is truncated")
+		}
+		mValues := ktxParseKeyValues(data[header.KVDByteOffset:header.KVDByteOffset+header.KVDByteLength], binary.LittleEndian)
+		texData.BottomUp = ktxIsBottomUp(mValues)
+	}
+
+	for iMip, index := range vLevels {
+		// Sum of offset and length could wrap around
+		if index.ByteOffset > uint64(len(data)) || index.ByteLength > uint64(len(data))-index.ByteOffset {
+			return nil, fmt.Errorf("KTX2 data is truncated at mip level %d", iMip)
+		}
+		level := TextureSurface{Width: GetMipSize(texData.Width, iMip), Height: GetMipSize(texData.Height, iMip), Depth: GetMipSize(texData.Depth, iMip)}
+		iSurfaceSize := format.getSliceSize(level.Width, level.Height, 1) * level.Depth
+		iLevelSize := iSurfaceSize * texData.Layers * texData.Faces
+		levelData := data[index.ByteOffset : index.ByteOffset+index.ByteLength]
+		if header.SupercompressionScheme == KTX2_SUPERCOMPRESSION_ZLIB {
+			// Size comes from the file, level can't be bigger than its surfaces
+			if index.UncompressedByteLength > uint64(iLevelSize) {
+				return nil, fmt.Errorf("KTX2 mip level %d: uncompressed size %d is bigger than %d bytes of its surfaces", iMip,
+					index.UncompressedByteLength, iLevelSize)
+			}
+			if levelData, err = ktx2Inflate(levelData, index.UncompressedByteLength); err != nil {
+				return nil, fmt.Errorf("KTX2 mip level %d: %v", iMip, err)
+			}
+		}
+		if len(levelData) < iLevelSize {
+			return nil, fmt.Errorf("KTX2 data is truncated at mip level %d", iMip)
+		}
+		for iLayer := 0; iLayer < texData.Layers; iLayer++ {
+			for iFace := 0; iFace < texData.Faces; iFace++ {
+				surface := texData.GetSurface(iLayer, iFace, iMip)
+				*surface = level
+				if err := format.convertSurface(levelData[:iSurfaceSize], surface, 1, 1); err != nil {
+					return nil, err
+				}
+				levelData = levelData[iSurfaceSize:]
+			}
+		}
+	}
+	return texData, nil
+}
+
+func ktx2Inflate(data []byte, iSize uint64) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	result := make([]byte, iSize)
+	if _, err := io.ReadFull(reader, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*-----------------------------------------------
+
+  Name:	LoadKTX
+
+  Params:	r - KTX or KTX2 file contents
+
+  Result:	Returns every surface of the texture,
+  		version is told by the identifier.
+
+  /*---------------------------------------------*/
+
+func LoadKTX(r io.Reader) (*TextureData, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, ktx2Identifier) {
+		return ktx2Parse(data)
+	}
+	return ktxParse(data)
+}
+
+func LoadKTXFile(sPath string) (*TextureData, error) {
+	file, err := OpenAsset(sPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadKTX(file)
+}
+
+// KTXimg is top mip level of first face or layer decoded to RGBA, what image.Decode gives for KTX files
+type KTXimg struct {
+	*image.NRGBA
+	Data *TextureData
+}
+
+func (this *KTXimg) GetTextureData() *TextureData {
+	return this.Data
+}
+
+func DecodeKTX(r io.Reader) (image.Image, error) {
+	texData, err := LoadKTX(r)
+	if err != nil {
+		return nil, err
+	}
+	pixels, err := texData.DecompressSurface(&texData.Surfaces[0])
+	if err != nil {
+		return nil, err
+	}
+	img := &image.NRGBA{Pix: pixels[:texData.Width*texData.Height*4], Stride: texData.Width * 4, Rect: image.Rect(0, 0, texData.Width, texData.Height)}
+	return &KTXimg{NRGBA: img, Data: texData}, nil
+}
+
+func DecodeKTXConfig(r io.Reader) (image.Config, error) {
+	var identifier [12]byte
+	if _, err := io.ReadFull(r, identifier[:]); err != nil {
+		return image.Config{}, err
+	}
+	if bytes.Equal(identifier[:], ktx2Identifier) {
+		var vFields [4]uint32 // VkFormat, type size, width and height
+		if err := binary.Read(r, binary.LittleEndian, &vFields); err != nil {
+			return image.Config{}, err
+		}
+		return image.Config{ColorModel: color.NRGBAModel, Width: int(vFields[2]), Height: MAX(1, int(vFields[3]))}, nil
+	}
+	if !bytes.Equal(identifier[:], ktx1Identifier) {
+		return image.Config{}, errors.New("not a KTX file")
+	}
+	var header ktxHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return image.Config{}, err
+	}
+	if header.Endianness != KTX_ENDIANNESS {
+		header.PixelWidth, header.PixelHeight = swapUint32(header.PixelWidth), swapUint32(header.PixelHeight)
+	}
+	return image.Config{ColorModel: color.NRGBAModel, Width: int(header.PixelWidth), Height: MAX(1, int(header.PixelHeight))}, nil
+}
+
+func swapUint32(iValue uint32) uint32 {
+	return iValue>>24 | (iValue>>8)&0xFF00 | (iValue<<8)&0xFF0000 | iValue<<24
+}
@@ -0,0 +1,183 @@
+package libs
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// newKTX1File returns KTX1 file holding one surface, sOrientation goes to KTXorientation key if not empty
+func newKTX1File(iGLType, iGLFormat, iGLInternalFormat uint32, iWidth, iHeight int, sOrientation string, data []byte) []byte {
+	var keyValues bytes.Buffer
+	if sOrientation != "" {
+		entry := append([]byte("KTXorientation\x00"+sOrientation), 0)
+		binary.Write(&keyValues, binary.LittleEndian, uint32(len(entry)))
+		keyValues.Write(entry)
+		keyValues.Write(make([]byte, ktxAlign(len(entry), 4)-len(entry)))
+	}
+	header := ktxHeader{Endianness: KTX_ENDIANNESS, GLType: iGLType, GLFormat: iGLFormat, GLInternalFormat: iGLInternalFormat,
+		GLBaseInternalFormat: iGLFormat, PixelWidth: uint32(iWidth), PixelHeight: uint32(iHeight), NumberOfFaces: 1,
+		NumberOfMipmapLevels: 1, BytesOfKeyValueData: uint32(keyValues.Len())}
+	if iGLType != 0 {
+		header.GLTypeSize = 1
+	}
+	var buf bytes.Buffer
+	buf.Write(ktx1Identifier)
+	binary.Write(&buf, binary.LittleEndian, &header)
+	buf.Write(keyValues.Bytes())
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// newKTX2File returns KTX2 file of RGBA8 texture with one mip level, level index points past the header
+func newKTX2File(iWidth, iHeight int, iScheme uint32, data []byte, iUncompressedSize uint64) []byte {
+	header := ktx2Header{VkFormat: VK_FORMAT_R8G8B8A8_UNORM, TypeSize: 1, PixelWidth: uint32(iWidth), PixelHeight: uint32(iHeight),
+		FaceCount: 1, LevelCount: 1, SupercompressionScheme: iScheme}
+	iOffset := len(ktx2Identifier) + binary.Size(header) + binary.Size(ktx2LevelIndex{})
+	var buf bytes.Buffer
+	buf.Write(ktx2Identifier)
+	binary.Write(&buf, binary.LittleEndian, &header)
+	binary.Write(&buf, binary.LittleEndian, &ktx2LevelIndex{ByteOffset: uint64(iOffset), ByteLength: uint64(len(data)),
+		UncompressedByteLength: iUncompressedSize})
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// newVerticalGradientImage returns image whose colour changes along y, so turning it over is noticed
+func newVerticalGradientImage(iWidth, iHeight int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, iWidth, iHeight))
+	for y := 0; y < iHeight; y++ {
+		for x := 0; x < iWidth; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(255 * y / (iHeight - 1)), uint8(255 - 255*y/(iHeight-1)), 128, 255})
+		}
+	}
+	return img
+}
+
+func TestKTXOrientation(t *testing.T) {
+	const iGLUnsignedByte, iGLRGBA, iGLRGBA8 = 0x1401, 0x1908, 0x8058
+	// Rows as stored: red first, then blue
+	bStored := []byte{255, 0, 0, 255, 0, 0, 255, 255}
+	vCases := []struct {
+		sOrientation string
+		bBottomUp    bool
+	}{
+		{"", false}, // Top-down, as toktx writes
+		{"S=r,T=u", true},
+		{"S=r,T=d", false},
+	}
+	for _, testCase := range vCases {
+		texData, err := LoadKTX(bytes.NewReader(newKTX1File(iGLUnsignedByte, iGLRGBA, iGLRGBA8, 1, 2, testCase.sOrientation, bStored)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if texData.BottomUp != testCase.bBottomUp || !bytes.Equal(texData.Surfaces[0].Data, bStored) {
+			t.Fatalf("%q: bottom-up %v, data %v", testCase.sOrientation, texData.BottomUp, texData.Surfaces[0].Data)
+		}
+		bPixels, err := texData.DecompressSurface(&texData.Surfaces[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		bTop := bStored[:4]
+		if testCase.bBottomUp {
+			bTop = bStored[4:]
+		}
+		if !bytes.Equal(bPixels[:4], bTop) {
+			t.Errorf("%q: top row is %v, want %v", testCase.sOrientation, bPixels[:4], bTop)
+		}
+	}
+}
+
+// Compressed blocks stay as stored, decoding has to give the same image for both orientations
+func TestKTXBottomUpCompressed(t *testing.T) {
+	img := newVerticalGradientImage(4, 8)
+	imgFlipped := image.NewNRGBA(img.Rect)
+	imgFlipped.Pix = flipRows(img.Pix, img.Stride, 8, 1)
+	texData, err := CompressImage(imgFlipped, DDSEncodeOptions{Format: TEXEL_FORMAT_BC1, NoMipmaps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bBlocks := texData.Surfaces[0].Data
+	result, err := LoadKTX(bytes.NewReader(newKTX1File(0, 0, KTX_GL_COMPRESSED_RGB_S3TC_DXT1, 4, 8, "S=r,T=u", bBlocks)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.BottomUp || !bytes.Equal(result.Surfaces[0].Data, bBlocks) {
+		t.Fatalf("bottom-up %v, blocks changed on load", result.BottomUp)
+	}
+	bPixels, err := result.DecompressSurface(&result.Surfaces[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range bPixels {
+		if iDiff := int(bPixels[i]) - int(img.Pix[i]); iDiff < -8 || iDiff > 8 {
+			t.Fatalf("texel %d channel %d is %d, source %d", i/4, i%4, bPixels[i], img.Pix[i])
+		}
+	}
+
+	decompressed, err := result.Decompress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decompressed.BottomUp || !bytes.Equal(decompressed.Surfaces[0].Data, bPixels) {
+		t.Fatal("Decompress didn't give top-down texels")
+	}
+	if err := WriteDDS(&bytes.Buffer{}, result); err == nil {
+		t.Fatal("bottom-up compressed texture was written to DDS")
+	}
+}
+
+func TestKTX2Levels(t *testing.T) {
+	bTexels := bytes.Repeat([]byte{10, 20, 30, 255}, 4)
+	var deflated bytes.Buffer
+	writer := zlib.NewWriter(&deflated)
+	writer.Write(bTexels)
+	writer.Close()
+
+	for _, iScheme := range []uint32{KTX2_SUPERCOMPRESSION_NONE, KTX2_SUPERCOMPRESSION_ZLIB} {
+		data := bTexels
+		if iScheme == KTX2_SUPERCOMPRESSION_ZLIB {
+			data = deflated.Bytes()
+		}
+		texData, err := LoadKTX(bytes.NewReader(newKTX2File(2, 2, iScheme, data, uint64(len(bTexels)))))
+		if err != nil {
+			t.Fatalf("scheme %d: %v", iScheme, err)
+		}
+		if texData.BottomUp || !bytes.Equal(texData.Surfaces[0].Data, bTexels) {
+			t.Fatalf("scheme %d: bottom-up %v, data %v", iScheme, texData.BottomUp, texData.Surfaces[0].Data)
+		}
+	}
+
+	// Level index sits right after 80 bytes of identifier and header
+	const iIndexOffset = 80
+	vCases := []struct {
+		sName                           string
+		iScheme                         uint32
+		iOffset, iLength, iUncompressed uint64
+	}{
+		{"offset past end", KTX2_SUPERCOMPRESSION_NONE, 1 << 20, 16, 16},
+		{"length past end", KTX2_SUPERCOMPRESSION_NONE, 104, 17, 16},
+		{"wrapping length", KTX2_SUPERCOMPRESSION_NONE, 104, math.MaxUint64 - 50, 16},
+		{"short level", KTX2_SUPERCOMPRESSION_NONE, 104, 12, 16},
+		{"huge uncompressed size", KTX2_SUPERCOMPRESSION_ZLIB, 104, uint64(deflated.Len()), 1 << 40},
+		{"short uncompressed size", KTX2_SUPERCOMPRESSION_ZLIB, 104, uint64(deflated.Len()), 12},
+	}
+	for _, testCase := range vCases {
+		data := bTexels
+		if testCase.iScheme == KTX2_SUPERCOMPRESSION_ZLIB {
+			data = deflated.Bytes()
+		}
+		file := newKTX2File(2, 2, testCase.iScheme, data, 0)
+		binary.LittleEndian.PutUint64(file[iIndexOffset:], testCase.iOffset)
+		binary.LittleEndian.PutUint64(file[iIndexOffset+8:], testCase.iLength)
+		binary.LittleEndian.PutUint64(file[iIndexOffset+16:], testCase.iUncompressed)
+		if _, err := LoadKTX(bytes.NewReader(file)); err == nil {
+			t.Errorf("%s: file was loaded", testCase.sName)
+		}
+	}
+}
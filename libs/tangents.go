@@ -0,0 +1,68 @@
+package libs
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*-----------------------------------------------
+
+  Name:	ComputeTangents
+
+  Params:	vPositions, vNormals, vTexCoords - per
+  		vertex attributes
+  		vIndices - triangle list
+
+  Result:	Returns tangent of every vertex along
+  		growing U, perpendicular to its normal. W is
+  		sign of bitangent, which is
+  		cross(normal, tangent)*w, as in glTF.
+
+  /*---------------------------------------------*/
+
+func ComputeTangents(vPositions, vNormals [][3]float32, vTexCoords [][2]float32, vIndices []uint32) [][4]float32 {
+	vTangentSums := make([]mgl32.Vec3, len(vPositions))
+	vBitangentSums := make([]mgl32.Vec3, len(vPositions))
+	for i := 0; i+2 < len(vIndices); i += 3 {
+		iA, iB, iC := vIndices[i], vIndices[i+1], vIndices[i+2]
+		vEdge1 := mgl32.Vec3(vPositions[iB]).Sub(vPositions[iA])
+		vEdge2 := mgl32.Vec3(vPositions[iC]).Sub(vPositions[iA])
+		fDU1, fDV1 := vTexCoords[iB][0]-vTexCoords[iA][0], vTexCoords[iB][1]-vTexCoords[iA][1]
+		fDU2, fDV2 := vTexCoords[iC][0]-vTexCoords[iA][0], vTexCoords[iC][1]-vTexCoords[iA][1]
+		fDet := fDU1*fDV2 - fDU2*fDV1
+		if fDet == 0 {
+			continue
+		}
+		// Triangles add up weighted by their area in texture space
+		vTangent := vEdge1.Mul(fDV2).Sub(vEdge2.Mul(fDV1)).Mul(1 / fDet)
+		vBitangent := vEdge2.Mul(fDU1).Sub(vEdge1.Mul(fDU2)).Mul(1 / fDet)
+		for _, iIndex := range []uint32{iA, iB, iC} {
+			vTangentSums[iIndex] = vTangentSums[iIndex].Add(vTangent)
+			vBitangentSums[iIndex] = vBitangentSums[iIndex].Add(vBitangent)
+		}
+	}
+	vTangents := make([][4]float32, len(vPositions))
+	for i := range vTangents {
+		vNormal := mgl32.Vec3(vNormals[i])
+		// Gram-Schmidt, tangent loses its part along normal
+		vTangent := vTangentSums[i].Sub(vNormal.Mul(vNormal.Dot(vTangentSums[i])))
+		if vTangent.Len() < 1e-6 {
+			vTangent = getPerpendicular(vNormal)
+		}
+		vTangent = vTangent.Normalize()
+		var fSign float32 = 1
+		if vNormal.Cross(vTangent).Dot(vBitangentSums[i]) < 0 {
+			fSign = -1
+		}
+		vTangents[i] = [4]float32{vTangent[0], vTangent[1], vTangent[2], fSign}
+	}
+	return vTangents
+}
+
+// getPerpendicular returns some vector perpendicular to the given one
+func getPerpendicular(vVector mgl32.Vec3) mgl32.Vec3 {
+	vAxis := mgl32.Vec3{1, 0, 0}
+	if vVector[0]*vVector[0] > 0.5*vVector.Dot(vVector) {
+		vAxis = mgl32.Vec3{0, 1, 0}
+	}
+	return vAxis.Sub(vVector.Mul(vVector.Dot(vAxis)))
+}
@@ -0,0 +1,373 @@
+#!/usr/bin/env python3
+"""Generates DDS fixtures and reference PNGs for dds_test.go.
+
+Reference images come from the small spec-based decoders below, written
+independently from libs/bcn.go. BC7 covers the single-subset modes 4, 5
+and 6 and the two-subset modes 1 and 7, BC6H covers mode 11 (10-bit
+endpoints, no transform) and the two-region modes 1 (transformed) and 10.
+
+Run from libs/testdata: python3 gen_dds.py
+"""
+import random
+import struct
+import zlib
+
+random.seed(29)
+
+WEIGHTS = {2: [0, 21, 43, 64], 3: [0, 9, 18, 27, 37, 46, 55, 64],
+           4: [0, 4, 9, 13, 17, 21, 26, 30, 34, 38, 43, 47, 51, 55, 60, 64]}
+
+
+def interp(e0, e1, w):
+    return ((64 - w) * e0 + w * e1 + 32) >> 6
+
+
+class Bits:
+    def __init__(self, block):
+        self.v = int.from_bytes(block, "little")
+        self.pos = 0
+
+    def read(self, n):
+        r = (self.v >> self.pos) & ((1 << n) - 1)
+        self.pos += n
+        return r
+
+
+# ---- BC4 / BC5
+
+def bc4_block(rng):
+    a, b = rng.randrange(256), rng.randrange(256)
+    codes = rng.getrandbits(48)
+    return bytes([a, b]) + codes.to_bytes(6, "little")
+
+
+def bc4_decode(block):
+    v0, v1 = block[0], block[1]
+    pal = [v0, v1]
+    if v0 > v1:
+        pal += [((7 - i) * v0 + i * v1) // 7 for i in range(1, 7)]
+    else:
+        pal += [((5 - i) * v0 + i * v1) // 5 for i in range(1, 5)] + [0, 255]
+    codes = int.from_bytes(block[2:8], "little")
+    return [pal[(codes >> (3 * i)) & 7] for i in range(16)]
+
+
+def bc4_texels(block):
+    return [(v, v, v, 255) for v in bc4_decode(block)]
+
+
+def bc5_block(rng):
+    return bc4_block(rng) + bc4_block(rng)
+
+
+def bc5_texels(block):
+    r, g = bc4_decode(block[:8]), bc4_decode(block[8:])
+    return [(r[i], g[i], 0, 255) for i in range(16)]
+
+
+# ---- Two subset partitions, shared by BC7 and BC6H
+
+# Bit i is the subset of texel i
+PARTITIONS2 = [
+    0xCCCC, 0x8888, 0xEEEE, 0xECC8, 0xC880, 0xFEEC, 0xFEC8, 0xEC80, 0xC800, 0xFFEC, 0xFE80, 0xE800, 0xFFE8, 0xFF00, 0xFFF0, 0xF000,
+    0xF710, 0x008E, 0x7100, 0x08CE, 0x008C, 0x7310, 0x3100, 0x8CCE, 0x088C, 0x3110, 0x6666, 0x366C, 0x17E8, 0x0FF0, 0x718E, 0x399C,
+    0xAAAA, 0xF0F0, 0x5A5A, 0x33CC, 0x3C3C, 0x55AA, 0x9696, 0xA55A, 0x73CE, 0x13C8, 0x324C, 0x3BDC, 0x6996, 0xC33C, 0x9966, 0x0660,
+    0x0272, 0x04E4, 0x4E40, 0x2720, 0xC936, 0x936C, 0x39C6, 0x639C, 0x9336, 0x9CC6, 0x817E, 0xE718, 0xCCF0, 0x0FCC, 0x7744, 0xEE22]
+
+# Anchor texel of subset 1, subset 0 is anchored at texel 0
+ANCHORS2 = [
+    15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15,
+    15, 2, 8, 2, 2, 8, 8, 15, 2, 8, 2, 2, 8, 8, 2, 2,
+    15, 15, 6, 8, 2, 8, 15, 15, 2, 8, 2, 2, 2, 15, 15, 6,
+    6, 2, 6, 8, 15, 15, 2, 2, 15, 15, 15, 15, 15, 2, 2, 15]
+
+
+def read_indices(br, bits, partition):
+    """Reads 16 indices, anchors of both subsets have one bit less."""
+    anchors = (0, ANCHORS2[partition])
+    return [br.read(bits - 1 if i in anchors else bits) for i in range(16)]
+
+
+def subset_of(partition, i):
+    return (PARTITIONS2[partition] >> i) & 1
+
+
+def with_field(block, pos, bits, value):
+    v = int.from_bytes(block, "little")
+    v = (v & ~(((1 << bits) - 1) << pos)) | (value << pos)
+    return v.to_bytes(16, "little")
+
+
+# ---- BC7, single subset modes
+
+def bc7_block(rng, mode):
+    v = rng.getrandbits(128)
+    v &= ~((1 << (mode + 1)) - 1)
+    v |= 1 << mode
+    return v.to_bytes(16, "little")
+
+
+def expand(v, bits):
+    v <<= 8 - bits
+    return v | (v >> bits)
+
+
+def bc7_texels(block):
+    br = Bits(block)
+    mode = 0
+    while br.read(1) == 0:
+        mode += 1
+    rotation, idx_mode = 0, 0
+    if mode == 4:
+        rotation, idx_mode = br.read(2), br.read(1)
+        cbits, abits = 5, 6
+    elif mode == 5:
+        rotation = br.read(2)
+        cbits, abits = 7, 8
+    elif mode == 6:
+        cbits, abits = 7, 7
+    else:
+        raise ValueError("mode %d not covered" % mode)
+    ends = [[0, 0, 0, 255], [0, 0, 0, 255]]
+    for c in range(3):
+        for e in range(2):
+            ends[e][c] = br.read(cbits)
+    for e in range(2):
+        ends[e][3] = br.read(abits)
+    if mode == 6:
+        for e in range(2):
+            p = br.read(1)
+            ends[e] = [(x << 1) | p for x in ends[e]]
+    else:
+        for e in range(2):
+            ends[e] = [expand(x, cbits) for x in ends[e][:3]] + [expand(ends[e][3], abits)]
+    if mode == 6:
+        idx = [br.read(3 if i == 0 else 4) for i in range(16)]
+        cidx, aidx, cw, aw = idx, idx, 4, 4
+    else:
+        first = [br.read(1 if i == 0 else 2) for i in range(16)]
+        second = [br.read((1 if i == 0 else 2) if mode == 5 else (2 if i == 0 else 3)) for i in range(16)]
+        sbits = 2 if mode == 5 else 3
+        if idx_mode == 0:
+            cidx, cw, aidx, aw = first, 2, second, sbits
+        else:
+            cidx, cw, aidx, aw = second, sbits, first, 2
+    out = []
+    for i in range(16):
+        px = [interp(ends[0][c], ends[1][c], WEIGHTS[cw][cidx[i]]) for c in range(3)]
+        px.append(interp(ends[0][3], ends[1][3], WEIGHTS[aw][aidx[i]]))
+        if rotation:
+            px[3], px[rotation - 1] = px[rotation - 1], px[3]
+        out.append(tuple(px))
+    return out
+
+
+# ---- BC7, two subset modes 1 and 7
+
+def bc7_texels_subsets(block):
+    br = Bits(block)
+    mode = 0
+    while br.read(1) == 0:
+        mode += 1
+    partition = br.read(6)
+    if mode == 1:
+        channels, cbits = 3, 6
+    elif mode == 7:
+        channels, cbits = 4, 5
+    else:
+        raise ValueError("mode %d not covered" % mode)
+    # Four endpoints, channel by channel
+    ends = [[0, 0, 0, 255] for _ in range(4)]
+    for c in range(channels):
+        for e in range(4):
+            ends[e][c] = br.read(cbits)
+    if mode == 1:
+        shared = [br.read(1) for _ in range(2)]
+        pbits = [shared[0], shared[0], shared[1], shared[1]]
+    else:
+        pbits = [br.read(1) for _ in range(4)]
+    for e in range(4):
+        ends[e] = [expand((x << 1) | pbits[e], cbits + 1) for x in ends[e][:channels]] + ends[e][channels:]
+    ibits = 3 if mode == 1 else 2
+    idx = read_indices(br, ibits, partition)
+    out = []
+    for i in range(16):
+        s = subset_of(partition, i)
+        out.append(tuple(interp(ends[2 * s][c], ends[2 * s + 1][c], WEIGHTS[ibits][idx[i]]) for c in range(4)))
+    return out
+
+
+# ---- BC6H unsigned, mode 11
+
+def bc6h_block(rng):
+    v = 0x03
+    pos = 5
+    for _ in range(6):
+        v |= rng.randrange(496) << pos
+        pos += 10
+    v |= rng.getrandbits(63) << pos
+    return v.to_bytes(16, "little")
+
+
+def bc6h_unquantize(v):
+    if v == 0:
+        return 0
+    if v == 1023:
+        return 0xFFFF
+    return ((v << 16) + 0x8000) >> 10
+
+
+def bc6h_texels(block):
+    br = Bits(block)
+    assert br.read(5) == 0x03
+    w = [br.read(10) for _ in range(3)]
+    x = [br.read(10) for _ in range(3)]
+    e0, e1 = [bc6h_unquantize(v) for v in w], [bc6h_unquantize(v) for v in x]
+    out = []
+    for i in range(16):
+        wt = WEIGHTS[4][br.read(3 if i == 0 else 4)]
+        px = []
+        for c in range(3):
+            half = (interp(e0[c], e1[c], wt) * 31) >> 6
+            f = struct.unpack("<e", struct.pack("<H", half))[0]
+            px.append(int(max(0.0, min(1.0, f)) * 255 + 0.5))
+        out.append(tuple(px) + (255,))
+    return out
+
+
+# ---- BC6H unsigned, two region modes 1 and 10
+
+# Header fields after the mode bits as (channel, endpoint, first bit, last bit), endpoints 0-3 are w, x, y, z
+# of the spec; BC6H mode 1 stores x, y and z as deltas from w
+BC6H_LAYOUTS = {
+    1: (2, 0x00, True, 10, 5, [
+        ("g", 2, 4, 4), ("b", 2, 4, 4), ("b", 3, 4, 4), ("r", 0, 0, 9), ("g", 0, 0, 9), ("b", 0, 0, 9),
+        ("r", 1, 0, 4), ("g", 3, 4, 4), ("g", 2, 0, 3), ("g", 1, 0, 4), ("b", 3, 0, 0), ("g", 3, 0, 3),
+        ("b", 1, 0, 4), ("b", 3, 1, 1), ("b", 2, 0, 3), ("r", 2, 0, 4), ("b", 3, 2, 2), ("r", 3, 0, 4),
+        ("b", 3, 3, 3)]),
+    10: (5, 0x1E, False, 6, 6, [
+        ("r", 0, 0, 5), ("g", 3, 4, 4), ("b", 3, 0, 1), ("b", 2, 4, 4), ("g", 0, 0, 5), ("g", 2, 5, 5),
+        ("b", 2, 5, 5), ("b", 3, 2, 2), ("g", 2, 4, 4), ("b", 0, 0, 5), ("g", 3, 5, 5), ("b", 3, 3, 3),
+        ("b", 3, 5, 5), ("b", 3, 4, 4), ("r", 1, 0, 5), ("g", 2, 0, 3), ("g", 1, 0, 5), ("g", 3, 0, 3),
+        ("b", 1, 0, 5), ("b", 2, 0, 3), ("r", 2, 0, 5), ("r", 3, 0, 5)]),
+}
+
+
+def bc6h_block_regions(rng, mode, partition):
+    mode_bits, mode_value = BC6H_LAYOUTS[mode][:2]
+    v = rng.getrandbits(128)
+    v = (v & ~((1 << mode_bits) - 1)) | mode_value
+    return with_field(v.to_bytes(16, "little"), 77, 5, partition)
+
+
+def sign_extend(v, bits):
+    return v - (1 << bits) if v & (1 << (bits - 1)) else v
+
+
+def bc6h_unquantize_bits(v, bits):
+    if v == 0:
+        return 0
+    if v == (1 << bits) - 1:
+        return 0xFFFF
+    return ((v << 16) + 0x8000) >> bits
+
+
+def bc6h_texels_regions(block):
+    v = int.from_bytes(block, "little")
+    mode = 1 if v & 0x3 == 0 else 10
+    mode_bits, mode_value, transformed, ebits, dbits, layout = BC6H_LAYOUTS[mode]
+    br = Bits(block)
+    assert br.read(mode_bits) == mode_value
+    ends = [{"r": 0, "g": 0, "b": 0} for _ in range(4)]
+    for channel, e, first, last in layout:
+        for bit in range(first, last + 1):
+            ends[e][channel] |= br.read(1) << bit
+    partition = br.read(5)
+    if transformed:
+        for e in range(1, 4):
+            for c in "rgb":
+                ends[e][c] = (ends[0][c] + sign_extend(ends[e][c], dbits)) & ((1 << ebits) - 1)
+    ends = [[bc6h_unquantize_bits(end[c], ebits) for c in "rgb"] for end in ends]
+    idx = read_indices(br, 3, partition)
+    out = []
+    for i in range(16):
+        s = subset_of(partition, i)
+        px = []
+        for c in range(3):
+            half = (interp(ends[2 * s][c], ends[2 * s + 1][c], WEIGHTS[3][idx[i]]) * 31) >> 6
+            f = struct.unpack("<e", struct.pack("<H", half))[0]
+            px.append(int(max(0.0, min(1.0, f)) * 255 + 0.5))
+        out.append(tuple(px) + (255,))
+    return out
+
+
+# ---- Containers
+
+def dds_header(width, height, fourcc, mips=1):
+    flags = 0x1 | 0x2 | 0x4 | 0x1000 | (0x20000 if mips > 1 else 0)
+    pf = struct.pack("<8I", 32, 0x4, struct.unpack("<I", fourcc)[0], 0, 0, 0, 0, 0)
+    hdr = struct.pack("<7I", 124, flags, height, width, 0, 0, mips) + b"\0" * 44 + pf
+    hdr += struct.pack("<5I", 0x1000, 0, 0, 0, 0)
+    return b"DDS " + hdr
+
+
+def dx10_header(dxgi, array_size=1):
+    return struct.pack("<5I", dxgi, 3, 0, array_size, 0)
+
+
+def png(path, width, height, texels):
+    raw = b"".join(b"\0" + bytes(c for px in texels[y * width:(y + 1) * width] for c in px) for y in range(height))
+
+    def chunk(kind, data):
+        return struct.pack(">I", len(data)) + kind + data + struct.pack(">I", zlib.crc32(kind + data))
+    with open(path, "wb") as f:
+        f.write(b"\x89PNG\r\n\x1a\n" + chunk(b"IHDR", struct.pack(">IIBBBBB", width, height, 8, 6, 0, 0, 0)) +
+                chunk(b"IDAT", zlib.compress(raw)) + chunk(b"IEND", b""))
+
+
+def blocks_to_texels(width, height, blocks, decode):
+    texels = [None] * (width * height)
+    bw = (width + 3) // 4
+    for bi, block in enumerate(blocks):
+        bx, by = bi % bw, bi // bw
+        for i, px in enumerate(decode(block)):
+            x, y = bx * 4 + i % 4, by * 4 + i // 4
+            if x < width and y < height:
+                texels[y * width + x] = px
+    return texels
+
+
+def write_compressed(name, width, height, header, blocks, decode):
+    with open(name + ".dds", "wb") as f:
+        f.write(header + b"".join(blocks))
+    png(name + ".png", width, height, blocks_to_texels(width, height, blocks, decode))
+
+
+rng = random.Random(29)
+blocks = [bc4_block(rng) for _ in range(4)]
+# One block of each palette kind for sure
+blocks[0] = bytes([200, 10]) + blocks[0][2:]
+blocks[1] = bytes([10, 200]) + blocks[1][2:]
+write_compressed("bc4", 8, 8, dds_header(8, 8, b"ATI1"), blocks, bc4_texels)
+
+blocks = [bc5_block(rng) for _ in range(4)]
+write_compressed("bc5", 8, 8, dds_header(8, 8, b"ATI2"), blocks, bc5_texels)
+
+blocks = [bc6h_block(rng) for _ in range(4)]
+write_compressed("bc6h", 8, 8, dds_header(8, 8, b"DX10") + dx10_header(95), blocks, bc6h_texels)
+
+blocks = [bc7_block(rng, m) for m in (4, 4, 4, 5, 5, 6)]
+write_compressed("bc7", 12, 8, dds_header(12, 8, b"DX10") + dx10_header(98), blocks, bc7_texels)
+
+# DX10 RGBA8 array with two layers, reference stacks the layers vertically
+texels = [tuple(rng.randrange(256) for _ in range(4)) for _ in range(4 * 4 * 2)]
+with open("dx10_rgba8_array.dds", "wb") as f:
+    f.write(dds_header(4, 4, b"DX10") + dx10_header(28, 2) + bytes(c for px in texels for c in px))
+png("dx10_rgba8_array.png", 4, 8, texels)
+
+# Partitions of both anchor kinds: anchor of subset 1 at texel 15, 2, 6 and 8
+blocks = [with_field(bc7_block(rng, m), m + 1, 6, p) for m, p in ((1, 0), (1, 17), (1, 34), (1, 63), (7, 15), (7, 18), (7, 45), (7, 60))]
+write_compressed("bc7_subsets", 16, 8, dds_header(16, 8, b"DX10") + dx10_header(98), blocks, bc7_texels_subsets)
+
+blocks = [bc6h_block_regions(rng, m, p) for m, p in ((1, 0), (1, 17), (1, 18), (1, 31), (10, 13), (10, 20), (10, 24), (10, 29))]
+write_compressed("bc6h_regions", 16, 8, dds_header(16, 8, b"DX10") + dx10_header(95), blocks, bc6h_texels_regions)
@@ -0,0 +1,242 @@
+package libs
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// TexelFormat tells how texels of TextureSurface are stored
+type TexelFormat int
+
+const (
+	TEXEL_FORMAT_RGBA8       TexelFormat = iota // Uncompressed, 4 bytes per texel
+	TEXEL_FORMAT_BC1                            // DXT1, with optional 1 bit alpha
+	TEXEL_FORMAT_BC2                            // DXT3
+	TEXEL_FORMAT_BC3                            // DXT5
+	TEXEL_FORMAT_BC4                            // ATI1, single channel
+	TEXEL_FORMAT_BC4_SIGNED                     // BC4 in -1..1
+	TEXEL_FORMAT_BC5                            // ATI2, two channels
+	TEXEL_FORMAT_BC5_SIGNED                     // BC5 in -1..1
+	TEXEL_FORMAT_BC6H                           // Unsigned half float RGB
+	TEXEL_FORMAT_BC6H_SIGNED                    // Signed half float RGB
+	TEXEL_FORMAT_BC7                            // BPTC RGBA
+)
+
+var sTexelFormatNames = []string{"RGBA8", "BC1", "BC2", "BC3", "BC4", "BC4_SNORM", "BC5", "BC5_SNORM", "BC6H_UF16", "BC6H_SF16", "BC7"}
+
+func (this TexelFormat) String() string {
+	if int(this) < len(sTexelFormatNames) {
+		return sTexelFormatNames[this]
+	}
+	return fmt.Sprintf("TexelFormat(%d)", int(this))
+}
+
+func (this TexelFormat) IsCompressed() bool {
+	return this != TEXEL_FORMAT_RGBA8
+}
+
+// GetBlockSize returns bytes per 4x4 block of compressed format, 0 for uncompressed one.
+func (this TexelFormat) GetBlockSize() int {
+	switch this {
+	case TEXEL_FORMAT_BC1, TEXEL_FORMAT_BC4, TEXEL_FORMAT_BC4_SIGNED:
+		return 8
+	case TEXEL_FORMAT_RGBA8:
+		return 0
+	}
+	return 16
+}
+
+// GetDataSize returns bytes taken by one 2D image of given size.
+func (this TexelFormat) GetDataSize(iWidth, iHeight int) int {
+	if !this.IsCompressed() {
+		return iWidth * iHeight * 4
+	}
+	return ((iWidth + 3) / 4) * ((iHeight + 3) / 4) * this.GetBlockSize()
+}
+
+// TextureSurface is one mip level of one face or array layer, volume textures keep all slices of the level in it
+type TextureSurface struct {
+	Width  int
+	Height int
+	Depth  int
+	Data   []byte // Slices one after another, rows in order of TextureData.BottomUp
+}
+
+/*-----------------------------------------------
+
+  Name:	GetSlice
+
+  Params:	format - format of owning TextureData
+  		iSlice - depth slice, 0 for 2D surfaces
+
+  Result:	Returns data of one 2D slice.
+
+  /*---------------------------------------------*/
+
+func (this *TextureSurface) GetSlice(format TexelFormat, iSlice int) []byte {
+	iSize := format.GetDataSize(this.Width, this.Height)
+	return this.Data[iSlice*iSize : (iSlice+1)*iSize]
+}
+
+// TextureData holds every surface of a texture as stored by its container, it doesn't depend on OpenGL
+type TextureData struct {
+	Format    TexelFormat
+	SRGB      bool // Color data is sRGB encoded
+	SRGBKnown bool // Container tells colour space, otherwise SRGB means nothing and usage decides
+
+	Width     int
+	Height    int
+	Depth     int // Slices of volume texture, 1 otherwise
+	Faces     int // 6 for cubemaps (+X, -X, +Y, -Y, +Z, -Z), 1 otherwise
+	Layers    int // Array layers
+	MipLevels int
+	BottomUp  bool // Rows of Surfaces go from bottom to top as container stored them, DecompressSurface turns them over
+
+	Surfaces []TextureSurface // Ordered by layer, then face, then mip level
+}
+
+func (this *TextureData) IsCubemap() bool {
+	return this.Faces == 6
+}
+
+func (this *TextureData) IsVolume() bool {
+	return this.Depth > 1
+}
+
+func (this *TextureData) GetSurface(iLayer, iFace, iMip int) *TextureSurface {
+	return &this.Surfaces[(iLayer*this.Faces+iFace)*this.MipLevels+iMip]
+}
+
+// GetMipSize returns size of a dimension at given mip level, it never drops below one texel.
+func GetMipSize(iSize, iMip int) int {
+	return MAX(1, iSize>>iMip)
+}
+
+/*-----------------------------------------------
+
+  Name:	ReadTextureSurfaces
+
+  Params:	data - surfaces stored one after another
+  		in layer, face, mip order
+
+  Result:	Fills Surfaces from the data, header
+  		fields must be set before. Returns error
+  		if data is too short.
+
+  /*---------------------------------------------*/
+
+func (this *TextureData) ReadTextureSurfaces(data []byte) error {
+	// Every surface takes at least a byte, so counts data can't hold are rejected before allocating
+	if this.Layers*this.Faces*this.MipLevels > len(data) {
+		return errors.New("texture data is truncated")
+	}
+	this.Surfaces = make([]TextureSurface, 0, this.Layers*this.Faces*this.MipLevels)
+	for iLayer := 0; iLayer < this.Layers; iLayer++ {
+		for iFace := 0; iFace < this.Faces; iFace++ {
+			for iMip := 0; iMip < this.MipLevels; iMip++ {
+				surface := TextureSurface{Width: GetMipSize(this.Width, iMip), Height: GetMipSize(this.Height, iMip), Depth: GetMipSize(this.Depth, iMip)}
+				iSize := this.Format.GetDataSize(surface.Width, surface.Height) * surface.Depth
+				if len(data) < iSize {
+					return fmt.Errorf("texture data is truncated at layer %d, face %d, mip level %d", iLayer, iFace, iMip)
+				}
+				surface.Data, data = data[:iSize], data[iSize:]
+				this.Surfaces = append(this.Surfaces, surface)
+			}
+		}
+	}
+	return nil
+}
+
+/*-----------------------------------------------
+
+  Name:	DecompressSurface
+
+  Params:	surface - one of Surfaces
+
+  Result:	Returns RGBA8 texels of all slices of the
+  		surface, BC6H gets clamped to 0..1. Rows
+  		always go from top to bottom.
+
+  /*---------------------------------------------*/
+
+func (this *TextureData) DecompressSurface(surface *TextureSurface) ([]byte, error) {
+	bPixels := surface.Data
+	if this.Format.IsCompressed() {
+		fnDecode := bcGetBlockDecoder(this.Format)
+		if fnDecode == nil {
+			return nil, fmt.Errorf("no decoder for %v", this.Format)
+		}
+		var err error
+		if bPixels, err = bcDecompress(surface.Data, surface.Width, surface.Height, surface.Depth, this.Format.GetBlockSize(), fnDecode); err != nil {
+			return nil, err
+		}
+	}
+	if this.BottomUp {
+		bPixels = flipRows(bPixels, surface.Width*4, surface.Height, surface.Depth)
+	}
+	return bPixels, nil
+}
+
+// flipRows returns copy of slices with their rows in reverse order.
+func flipRows(data []byte, iRowSize, iHeight, iDepth int) []byte {
+	result := make([]byte, iRowSize*iHeight*iDepth)
+	for z := 0; z < iDepth; z++ {
+		for y := 0; y < iHeight; y++ {
+			iSource := (z*iHeight + iHeight - 1 - y) * iRowSize
+			copy(result[(z*iHeight+y)*iRowSize:], data[iSource:iSource+iRowSize])
+		}
+	}
+	return result
+}
+
+// Decompress returns copy of the texture with every surface decoded to top-down RGBA8, used when driver can't sample the format.
+func (this *TextureData) Decompress() (*TextureData, error) {
+	if !this.Format.IsCompressed() && !this.BottomUp {
+		return this, nil
+	}
+	result := *this
+	result.Format = TEXEL_FORMAT_RGBA8
+	result.BottomUp = false
+	result.Surfaces = make([]TextureSurface, len(this.Surfaces))
+	for i := range this.Surfaces {
+		bPixels, err := this.DecompressSurface(&this.Surfaces[i])
+		if err != nil {
+			return nil, err
+		}
+		result.Surfaces[i] = this.Surfaces[i]
+		result.Surfaces[i].Data = bPixels
+	}
+	return &result, nil
+}
+
+func (this *TextureData) validate() error {
+	if this.Width <= 0 || this.Height <= 0 || this.Depth <= 0 || this.Layers <= 0 || this.MipLevels <= 0 {
+		return errors.New("invalid texture dimensions")
+	}
+	if this.Faces != 1 && this.Faces != 6 {
+		return errors.New("texture must have 1 or 6 faces")
+	}
+	return nil
+}
+
+// IsTextureContainerFile tells whether file keeps whole texture with its mip levels, so it's loaded with LoadTextureFile.
+func IsTextureContainerFile(sPath string) bool {
+	switch strings.ToLower(filepath.Ext(sPath)) {
+	case ".dds", ".ktx", ".ktx2":
+		return true
+	}
+	return false
+}
+
+// LoadTextureFile loads DDS, KTX or KTX2 file, telling the container by extension.
+func LoadTextureFile(sPath string) (*TextureData, error) {
+	switch strings.ToLower(filepath.Ext(sPath)) {
+	case ".dds":
+		return LoadDDSFile(sPath)
+	case ".ktx", ".ktx2":
+		return LoadKTXFile(sPath)
+	}
+	return nil, fmt.Errorf("%s is not a texture container", sPath)
+}
@@ -0,0 +1,417 @@
+package libs
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// vfsMount is directory or archive visible under a mount point
+type vfsMount struct {
+	sMountPoint string // Clean asset path, "." for root
+	sSource     string // Directory or archive it comes from, used by Unmount
+	sDirectory  string // OS directory for directory mounts, empty otherwise
+	fsys        fs.FS
+	closer      io.Closer
+	iPriority   int
+}
+
+// relative returns path inside mount, or false if the name isn't under mount point
+func (this *vfsMount) relative(sName string) (string, bool) {
+	if this.sMountPoint == "." {
+		return sName, true
+	}
+	if sName == this.sMountPoint {
+		return ".", true
+	}
+	if strings.HasPrefix(sName, this.sMountPoint+"/") {
+		return sName[len(this.sMountPoint)+1:], true
+	}
+	return "", false
+}
+
+/*-----------------------------------------------
+
+  Name:	VFS
+
+  Result:	Virtual file system of assets. Directories
+  		and zip archives are mounted under mount
+  		points, a file is taken from the mount with
+  		the highest priority that has it. Among
+  		mounts of equal priority the latest wins.
+  		Implements fs.FS, so io/fs helpers work.
+
+  /*---------------------------------------------*/
+
+type VFS struct {
+	mutex   sync.RWMutex
+	vMounts []*vfsMount // Searched in this order
+}
+
+func NewVFS() *VFS {
+	return &VFS{}
+}
+
+// Assets of the application, current directory is mounted at the root with priority 0
+var vfsAssets *VFS = newDefaultVFS()
+
+func newDefaultVFS() *VFS {
+	this := NewVFS()
+	this.MountDirectory(".", "", 0)
+	return this
+}
+
+func GetAssetVFS() *VFS {
+	return vfsAssets
+}
+
+// CleanAssetPath turns backslashes into slashes and removes ./, ../ and leading slashes, root is ".".
+func CleanAssetPath(sPath string) string {
+	// Rooted clean path can't climb above root
+	sPath = path.Clean("/" + strings.ReplaceAll(sPath, "\\", "/"))
+	if sPath == "/" {
+		return "."
+	}
+	return sPath[1:]
+}
+
+func (this *VFS) addMount(mount *vfsMount) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.vMounts = append([]*vfsMount{mount}, this.vMounts...)
+	sort.SliceStable(this.vMounts, func(i, j int) bool {
+		return this.vMounts[i].iPriority > this.vMounts[j].iPriority
+	})
+}
+
+// MountDirectory makes files of OS directory visible under mount point, empty mount point is the root.
+func (this *VFS) MountDirectory(sDirectory, sMountPoint string, iPriority int) error {
+	info, err := os.Stat(sDirectory)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s isn't a directory", sDirectory)
+	}
+	this.addMount(&vfsMount{sMountPoint: CleanAssetPath(sMountPoint), sSource: sDirectory, sDirectory: sDirectory,
+		fsys: os.DirFS(sDirectory), iPriority: iPriority})
+	return nil
+}
+
+// MountZip makes files of zip archive visible under mount point, archive stays open until it's unmounted.
+func (this *VFS) MountZip(sArchive, sMountPoint string, iPriority int) error {
+	archive, err := zip.OpenReader(sArchive)
+	if err != nil {
+		return fmt.Errorf("%s: %v", sArchive, err)
+	}
+	this.addMount(&vfsMount{sMountPoint: CleanAssetPath(sMountPoint), sSource: sArchive, fsys: archive,
+		closer: archive, iPriority: iPriority})
+	return nil
+}
+
+// MountFS makes any file system visible under mount point, sName identifies it for Unmount.
+func (this *VFS) MountFS(fsys fs.FS, sName, sMountPoint string, iPriority int) {
+	this.addMount(&vfsMount{sMountPoint: CleanAssetPath(sMountPoint), sSource: sName, fsys: fsys, iPriority: iPriority})
+}
+
+// Mount mounts directory, or zip archive when the path ends with .zip.
+func (this *VFS) Mount(sSource, sMountPoint string, iPriority int) error {
+	if strings.EqualFold(filepath.Ext(sSource), ".zip") {
+		return this.MountZip(sSource, sMountPoint, iPriority)
+	}
+	return this.MountDirectory(sSource, sMountPoint, iPriority)
+}
+
+// Unmount removes every mount of directory or archive and closes archives. Returns false if nothing was mounted from it.
+func (this *VFS) Unmount(sSource string) bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	var bFound bool = false
+	vMounts := this.vMounts[:0]
+	for _, mount := range this.vMounts {
+		if mount.sSource != sSource {
+			vMounts = append(vMounts, mount)
+			continue
+		}
+		bFound = true
+		if mount.closer != nil {
+			mount.closer.Close()
+		}
+	}
+	this.vMounts = vMounts
+	return bFound
+}
+
+// UnmountAll removes every mount, including the default one of current directory.
+func (this *VFS) UnmountAll() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	for _, mount := range this.vMounts {
+		if mount.closer != nil {
+			mount.closer.Close()
+		}
+	}
+	this.vMounts = nil
+}
+
+func (this *VFS) getMounts() []*vfsMount {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+	return append([]*vfsMount(nil), this.vMounts...)
+}
+
+// find returns mount holding the file and path inside it
+func (this *VFS) find(sName string) (*vfsMount, string, error) {
+	for _, mount := range this.getMounts() {
+		sRelative, bOK := mount.relative(sName)
+		if !bOK {
+			continue
+		}
+		if _, err := fs.Stat(mount.fsys, sRelative); err == nil {
+			return mount, sRelative, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, "", err
+		}
+	}
+	return nil, "", fs.ErrNotExist
+}
+
+/*-----------------------------------------------
+
+  Name:	Open
+
+  Params:	sName - asset path, backslashes and ./
+  		are accepted
+
+  Result:	Opens file from the mount with the
+  		highest priority that has it.
+
+  /*---------------------------------------------*/
+
+func (this *VFS) Open(sName string) (fs.File, error) {
+	sName = CleanAssetPath(sName)
+	mount, sRelative, err := this.find(sName)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: sName, Err: err}
+	}
+	return mount.fsys.Open(sRelative)
+}
+
+func (this *VFS) ReadFile(sName string) ([]byte, error) {
+	file, err := this.Open(sName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+func (this *VFS) Stat(sName string) (fs.FileInfo, error) {
+	sName = CleanAssetPath(sName)
+	mount, sRelative, err := this.find(sName)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: sName, Err: err}
+	}
+	return fs.Stat(mount.fsys, sRelative)
+}
+
+// Exists tells whether any mount has the file or directory.
+func (this *VFS) Exists(sName string) bool {
+	_, err := this.Stat(sName)
+	return err == nil
+}
+
+// mountPointEntry is directory made up by mount point that doesn't exist in any mount
+type mountPointEntry string
+
+func (this mountPointEntry) Name() string               { return string(this) }
+func (this mountPointEntry) IsDir() bool                { return true }
+func (this mountPointEntry) Type() fs.FileMode          { return fs.ModeDir }
+func (this mountPointEntry) Info() (fs.FileInfo, error) { return this, nil }
+func (this mountPointEntry) Size() int64                { return 0 }
+func (this mountPointEntry) Mode() fs.FileMode          { return fs.ModeDir | 0555 }
+func (this mountPointEntry) ModTime() time.Time         { return time.Time{} }
+func (this mountPointEntry) Sys() any                   { return nil }
+
+/*-----------------------------------------------
+
+  Name:	ReadDir
+
+  Params:	sName - asset directory
+
+  Result:	Returns entries of directory merged from
+  		all mounts sorted by name, entry of mount
+  		with higher priority wins.
+
+  /*---------------------------------------------*/
+
+func (this *VFS) ReadDir(sName string) ([]fs.DirEntry, error) {
+	sName = CleanAssetPath(sName)
+	mEntries := make(map[string]fs.DirEntry)
+	var bFound bool = false
+	for _, mount := range this.getMounts() {
+		if sRelative, bOK := mount.relative(sName); bOK {
+			vEntries, err := fs.ReadDir(mount.fsys, sRelative)
+			if err != nil {
+				continue
+			}
+			bFound = true
+			for _, entry := range vEntries {
+				if _, bTaken := mEntries[entry.Name()]; !bTaken {
+					mEntries[entry.Name()] = entry
+				}
+			}
+			continue
+		}
+		// Mount point deeper in the tree shows up as directory
+		var sBelow string
+		if sName == "." {
+			sBelow = mount.sMountPoint
+		} else if strings.HasPrefix(mount.sMountPoint, sName+"/") {
+			sBelow = mount.sMountPoint[len(sName)+1:]
+		}
+		if sBelow != "" && sBelow != "." {
+			sChild := strings.SplitN(sBelow, "/", 2)[0]
+			bFound = true
+			if _, bTaken := mEntries[sChild]; !bTaken {
+				mEntries[sChild] = mountPointEntry(sChild)
+			}
+		}
+	}
+	if !bFound {
+		return nil, &fs.PathError{Op: "readdir", Path: sName, Err: fs.ErrNotExist}
+	}
+	vEntries := make([]fs.DirEntry, 0, len(mEntries))
+	for _, entry := range mEntries {
+		vEntries = append(vEntries, entry)
+	}
+	sort.Slice(vEntries, func(i, j int) bool { return vEntries[i].Name() < vEntries[j].Name() })
+	return vEntries, nil
+}
+
+// GetLocalPath returns OS path of file when it comes from mounted directory, false for archives.
+func (this *VFS) GetLocalPath(sName string) (string, bool) {
+	mount, sRelative, err := this.find(CleanAssetPath(sName))
+	if err != nil || mount.sDirectory == "" {
+		return "", false
+	}
+	return filepath.Join(mount.sDirectory, filepath.FromSlash(sRelative)), true
+}
+
+// Material statements of .mtl files naming texture, options come before file name
+var mtlTextureKeywords = map[string]bool{
+	"map_ka": true, "map_kd": true, "map_ks": true, "map_ke": true, "map_ns": true, "map_d": true,
+	"map_bump": true, "bump": true, "map_disp": true, "disp": true, "decal": true, "refl": true,
+	"norm": true, "map_pr": true, "map_pm": true, "map_ps": true,
+}
+
+// getReferencedFiles returns asset paths of files that .obj or .mtl file refers to
+func getReferencedFiles(sName string, bData []byte) []string {
+	sExtension := strings.ToLower(path.Ext(sName))
+	if sExtension != ".obj" && sExtension != ".mtl" {
+		return nil
+	}
+	var vFiles []string
+	for _, sLine := range strings.Split(string(bData), "\n") {
+		vFields := strings.Fields(sLine)
+		if len(vFields) < 2 {
+			continue
+		}
+		sKeyword := strings.ToLower(vFields[0])
+		var sFile string
+		if sExtension == ".obj" && sKeyword == "mtllib" {
+			// Rest of line is one file name, as assimp reads it
+			sFile = strings.TrimSpace(strings.TrimSpace(sLine)[len(vFields[0]):])
+		} else if sExtension == ".mtl" && mtlTextureKeywords[sKeyword] {
+			sFile = vFields[len(vFields)-1]
+		} else {
+			continue
+		}
+		vFiles = append(vFiles, CleanAssetPath(path.Join(path.Dir(sName), strings.ReplaceAll(sFile, "\\", "/"))))
+	}
+	return vFiles
+}
+
+/*-----------------------------------------------
+
+  Name:	ExtractLocal
+
+  Params:	sName - asset file
+
+  Result:	Returns OS path of file for libraries
+  		that read only real files. Files from
+  		archives are copied into temporary
+  		directory under their asset paths, together
+  		with materials and textures they refer to.
+  		Cleanup function removes them.
+
+  /*---------------------------------------------*/
+
+func (this *VFS) ExtractLocal(sName string) (string, func(), error) {
+	if sLocalPath, bOK := this.GetLocalPath(sName); bOK {
+		return sLocalPath, func() {}, nil
+	}
+	sName = CleanAssetPath(sName)
+	bData, err := this.ReadFile(sName)
+	if err != nil {
+		return "", nil, err
+	}
+	sTempDir, err := os.MkdirTemp("", "antry-asset-")
+	if err != nil {
+		return "", nil, err
+	}
+	fnCleanup := func() { os.RemoveAll(sTempDir) }
+	mVisited := map[string]bool{sName: true}
+	vQueue := []string{sName}
+	for len(vQueue) > 0 {
+		sFile := vQueue[0]
+		vQueue = vQueue[1:]
+		if sFile != sName {
+			// Missing references are left to the importer to report
+			if bData, err = this.ReadFile(sFile); err != nil {
+				continue
+			}
+		}
+		sLocalPath := filepath.Join(sTempDir, filepath.FromSlash(sFile))
+		err = os.MkdirAll(filepath.Dir(sLocalPath), 0755)
+		if err == nil {
+			err = os.WriteFile(sLocalPath, bData, 0644)
+		}
+		if err != nil {
+			fnCleanup()
+			return "", nil, err
+		}
+		for _, sReference := range getReferencedFiles(sFile, bData) {
+			if !mVisited[sReference] {
+				mVisited[sReference] = true
+				vQueue = append(vQueue, sReference)
+			}
+		}
+	}
+	return filepath.Join(sTempDir, filepath.FromSlash(sName)), fnCleanup, nil
+}
+
+// OpenAsset opens file through asset VFS, absolute OS paths are opened directly.
+func OpenAsset(sPath string) (fs.File, error) {
+	if filepath.IsAbs(sPath) {
+		return os.Open(sPath)
+	}
+	return vfsAssets.Open(sPath)
+}
+
+// ReadAsset reads whole file through asset VFS, absolute OS paths are read directly.
+func ReadAsset(sPath string) ([]byte, error) {
+	if filepath.IsAbs(sPath) {
+		return os.ReadFile(sPath)
+	}
+	return vfsAssets.ReadFile(sPath)
+}
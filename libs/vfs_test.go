@@ -0,0 +1,177 @@
+package libs
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+// newZipFile writes zip archive with given files into test's temporary directory
+func newZipFile(t *testing.T, mFiles map[string]string) string {
+	sArchive := filepath.Join(t.TempDir(), "assets.zip")
+	file, err := os.Create(sArchive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := zip.NewWriter(file)
+	for sName, sData := range mFiles {
+		entry, err := writer.Create(sName)
+		if err == nil {
+			_, err = entry.Write([]byte(sData))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return sArchive
+}
+
+func TestCleanAssetPath(t *testing.T) {
+	vCases := [][2]string{
+		{"models/box.obj", "models/box.obj"},
+		{"models\\textures\\box.png", "models/textures/box.png"},
+		{"./models//box.obj", "models/box.obj"},
+		{"/models/box.obj", "models/box.obj"},
+		{"models/../box.obj", "box.obj"},
+		{"../../box.obj", "box.obj"},
+		{"", "."},
+		{"/", "."},
+		{"..", "."},
+	}
+	for _, testCase := range vCases {
+		if sClean := CleanAssetPath(testCase[0]); sClean != testCase[1] {
+			t.Errorf("%q is cleaned to %q, want %q", testCase[0], sClean, testCase[1])
+		}
+	}
+}
+
+func TestVFSPrecedence(t *testing.T) {
+	vfs := NewVFS()
+	vfs.MountFS(fstest.MapFS{"a.txt": {Data: []byte("low")}, "low.txt": {Data: []byte("low")}}, "low", "", 0)
+	vfs.MountFS(fstest.MapFS{"a.txt": {Data: []byte("high")}}, "high", "", 10)
+	vfs.MountFS(fstest.MapFS{"a.txt": {Data: []byte("same")}, "b.txt": {Data: []byte("first")}}, "first", "", 0)
+	vfs.MountFS(fstest.MapFS{"b.txt": {Data: []byte("latest")}}, "latest", "", 0)
+	vfs.MountFS(fstest.MapFS{"a.txt": {Data: []byte("mounted")}}, "mounted", "data/sub", 20)
+
+	vCases := [][2]string{
+		{"a.txt", "high"},
+		{"b.txt", "latest"},
+		{"low.txt", "low"},
+		{"data/sub/a.txt", "mounted"},
+		{"data\\sub\\..\\sub\\a.txt", "mounted"},
+	}
+	for _, testCase := range vCases {
+		bData, err := vfs.ReadFile(testCase[0])
+		if err != nil || string(bData) != testCase[1] {
+			t.Errorf("%s reads %q, %v, want %q", testCase[0], bData, err, testCase[1])
+		}
+	}
+	if vfs.Exists("data/a.txt") || vfs.Exists("missing.txt") {
+		t.Error("file outside of mounts exists")
+	}
+
+	vEntries, err := vfs.ReadDir("data")
+	if err != nil || len(vEntries) != 1 || vEntries[0].Name() != "sub" || !vEntries[0].IsDir() {
+		t.Errorf("mount point directory lists %v, %v", vEntries, err)
+	}
+
+	if !vfs.Unmount("high") || vfs.Unmount("high") {
+		t.Fatal("unmount didn't remove mount exactly once")
+	}
+	if bData, _ := vfs.ReadFile("a.txt"); string(bData) != "same" {
+		t.Errorf("a.txt reads %q after unmount, want later of equal priority", bData)
+	}
+}
+
+func TestVFSZip(t *testing.T) {
+	sArchive := newZipFile(t, map[string]string{
+		"models/box.obj":          "o box",
+		"models/textures/box.png": "png",
+	})
+	vfs := NewVFS()
+	if err := vfs.Mount(sArchive, "pack", 0); err != nil {
+		t.Fatal(err)
+	}
+	defer vfs.UnmountAll()
+
+	if bData, err := vfs.ReadFile("pack/models/textures/../box.obj"); err != nil || string(bData) != "o box" {
+		t.Errorf("box.obj reads %q, %v", bData, err)
+	}
+	if info, err := vfs.Stat("pack/models/textures"); err != nil || !info.IsDir() {
+		t.Errorf("textures directory stats %v, %v", info, err)
+	}
+	if vfs.Exists("models/box.obj") {
+		t.Error("archive file is visible outside of mount point")
+	}
+	if _, bOK := vfs.GetLocalPath("pack/models/box.obj"); bOK {
+		t.Error("archive file has local path")
+	}
+
+	vEntries, err := vfs.ReadDir("pack/models")
+	if err != nil || len(vEntries) != 2 || vEntries[0].Name() != "box.obj" || vEntries[1].Name() != "textures" {
+		t.Errorf("models directory lists %v, %v", vEntries, err)
+	}
+}
+
+func TestVFSExtractLocal(t *testing.T) {
+	sArchive := newZipFile(t, map[string]string{
+		"models/box.obj":              "mtllib box.mtl\r\nmtllib materials\\shared.mtl\r\no box\r\n",
+		"models/box.mtl":              "newmtl box\nmap_Kd textures/box.png\nbump -bm 0.5 textures/box_n.png\nnewmtl other\nmap_Kd textures/box.png\n",
+		"models/materials/shared.mtl": "newmtl shared\nmap_Ks ../../common/spec.png\nmap_d missing.png\n",
+		"models/textures/box.png":     "png",
+		"models/textures/box_n.png":   "normal",
+		"models/textures/other.png":   "other",
+		"models/other.obj":            "o other",
+		"common/spec.png":             "spec",
+	})
+	vfs := NewVFS()
+	if err := vfs.MountZip(sArchive, "", 0); err != nil {
+		t.Fatal(err)
+	}
+	defer vfs.UnmountAll()
+
+	sLocalPath, fnCleanup, err := vfs.ExtractLocal("models\\box.obj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sTempDir := filepath.Dir(filepath.Dir(sLocalPath))
+	var vFiles []string
+	filepath.WalkDir(sTempDir, func(sPath string, entry os.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			sRelative, _ := filepath.Rel(sTempDir, sPath)
+			vFiles = append(vFiles, filepath.ToSlash(sRelative))
+		}
+		return err
+	})
+	sort.Strings(vFiles)
+	vExpected := []string{"common/spec.png", "models/box.mtl", "models/box.obj", "models/materials/shared.mtl",
+		"models/textures/box.png", "models/textures/box_n.png"}
+	if len(vFiles) != len(vExpected) {
+		t.Fatalf("extracted %v, want %v", vFiles, vExpected)
+	}
+	for i := range vFiles {
+		if vFiles[i] != vExpected[i] {
+			t.Fatalf("extracted %v, want %v", vFiles, vExpected)
+		}
+	}
+	if bData, err := os.ReadFile(filepath.Join(sTempDir, "models", "textures", "box_n.png")); err != nil || string(bData) != "normal" {
+		t.Errorf("extracted texture holds %q, %v", bData, err)
+	}
+
+	fnCleanup()
+	if _, err := os.Stat(sTempDir); !os.IsNotExist(err) {
+		t.Errorf("temporary directory is left after cleanup: %v", err)
+	}
+	if _, _, err := vfs.ExtractLocal("models/missing.obj"); err == nil {
+		t.Error("missing file was extracted")
+	}
+}
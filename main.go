@@ -1,8 +1,39 @@
 package main
 
-import "antry/graphic"
+import (
+	"antry/graphic"
+	"antry/libs"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+var bClearShaderCache = flag.Bool("clear-shader-cache", false, "delete cached shader program binaries before start")
+var bSRGB = flag.Bool("srgb", false, "use sRGB framebuffer and sRGB colour textures")
+var bTerrainTextureArray = flag.Bool("terrain-array", false, "sample terrain layers from one texture array instead of separate textures")
+var sMounts = flag.String("mount", "", "comma separated directories or zip archives mounted over assets, as source or source=mountpoint, later ones win")
 
 func main() {
+	flag.Parse()
+	if *bClearShaderCache {
+		if err := graphic.ClearProgramCache(); err != nil {
+			fmt.Println("Couldn't clear shader cache:", err)
+		}
+	}
+
+	graphic.RequestSRGBFramebuffer(*bSRGB)
+	graphic.EnableTerrainTextureArray(*bTerrainTextureArray)
+
+	for i, sMount := range strings.Split(*sMounts, ",") {
+		if sMount == "" {
+			continue
+		}
+		sSource, sMountPoint, _ := strings.Cut(sMount, "=")
+		if err := libs.GetAssetVFS().Mount(sSource, sMountPoint, 1+i); err != nil {
+			fmt.Println("Couldn't mount", sSource+":", err)
+		}
+	}
+
 	if !graphic.AppMain.InitializeApp("21_opengl_3_3") {
 		return
 	}